@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,20 +12,35 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/julienschmidt/httprouter"
 
+	"github.com/swallowstalker/online-book-store/modules/bookstore/blobstore"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/middleware"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/payment"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/repository/db"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
 )
 
 type Config struct {
-	AppPort    int    `env:"APP_PORT,default=8080"`
-	DBHost     string `env:"DB_HOST"`
-	DBPort     int    `env:"DB_PORT"`
-	DBUser     string `env:"DB_USER"`
-	DBPassword string `env:"DB_PASSWORD"`
-	DBName     string `env:"DB_NAME"`
+	AppEnv               string        `env:"APP_ENV,default=production"`
+	AppPort              int           `env:"APP_PORT,default=8080"`
+	DBHost               string        `env:"DB_HOST"`
+	DBPort               int           `env:"DB_PORT"`
+	DBUser               string        `env:"DB_USER"`
+	DBPassword           string        `env:"DB_PASSWORD"`
+	DBName               string        `env:"DB_NAME"`
+	CoverDir             string        `env:"COVER_DIR,default=./covers"`
+	IdempotencyKeyTTL    time.Duration `env:"IDEMPOTENCY_KEY_TTL,default=24h"`
+	IdempotencyKeyLease  time.Duration `env:"IDEMPOTENCY_KEY_LEASE,default=1m"`
+	OrderMaxLineQty      int64         `env:"ORDER_MAX_LINE_QUANTITY,default=20"`
+	OrderMaxQty          int64         `env:"ORDER_MAX_QUANTITY,default=100"`
+	OrderMaxLines        int           `env:"ORDER_MAX_LINES,default=50"`
+	OrderReservationTTL  time.Duration `env:"ORDER_RESERVATION_TTL,default=30m"`
+	OrderSweepInterval   time.Duration `env:"ORDER_SWEEP_INTERVAL,default=1m"`
+	OrderSweepBatchSize  int           `env:"ORDER_SWEEP_BATCH_SIZE,default=100"`
+	PaymentProvider      string        `env:"PAYMENT_PROVIDER"`
+	PaymentWebhookSecret string        `env:"PAYMENT_WEBHOOK_SECRET"`
 }
 
 func main() {
@@ -62,15 +78,81 @@ func main() {
 	repoWrapper := repository.NewDbWrapperRepo(querier)
 	userService := service.NewUserService(repoWrapper)
 	bookService := service.NewBookService(repoWrapper)
-	orderService := service.NewOrderService(repoWrapper, txFunc)
+	// the store keeps running without payments, orders then wait for payment until a provider is configured
+	paymentProvider, err := payment.New(config.PaymentProvider, config.AppEnv, config.PaymentWebhookSecret)
+	if err != nil {
+		fmt.Println("payments are disabled:", err)
+	}
+	orderService := service.NewOrderService(repoWrapper, txFunc, paymentProvider, service.OrderLimits{
+		MaxLineQuantity:  config.OrderMaxLineQty,
+		MaxOrderQuantity: config.OrderMaxQty,
+		MaxLines:         config.OrderMaxLines,
+		ReservationTTL:   config.OrderReservationTTL,
+	})
+	sweeper := service.NewReservationSweeper(orderService, repoWrapper, config.OrderSweepInterval, config.OrderSweepBatchSize)
+	cartService := service.NewCartService(repoWrapper, orderService, txFunc)
+	returnService := service.NewReturnService(repoWrapper, orderService, txFunc)
+	importService := service.NewImportService(repoWrapper, txFunc)
+	exportService := service.NewExportService(repoWrapper)
+	categoryService := service.NewCategoryService(repoWrapper, txFunc)
+	seriesService := service.NewSeriesService(repoWrapper)
+	coverStore := blobstore.NewLocalStore(config.CoverDir)
+	coverService := service.NewCoverService(repoWrapper, coverStore)
 	h := handler.NewHandler(userService, bookService, orderService)
+	ih := handler.NewImportHandler(importService)
+	eh := handler.NewExportHandler(exportService)
+	ch := handler.NewCategoryHandler(categoryService, bookService)
+	sh := handler.NewSeriesHandler(seriesService)
+	cvh := handler.NewCoverHandler(coverService)
+	cth := handler.NewCartHandler(cartService)
+	rh := handler.NewReturnHandler(returnService)
 	m := middleware.NewAuthMiddleware(repoWrapper)
+	im := middleware.NewIdempotencyMiddleware(repoWrapper, config.IdempotencyKeyTTL, config.IdempotencyKeyLease)
 
 	router := httprouter.New()
 	router.HandlerFunc(http.MethodPost, "/v1/users", h.CreateUser)
 	router.HandlerFunc(http.MethodGet, "/v1/books", h.GetBooks)
-	router.HandlerFunc(http.MethodPost, "/v1/orders", m.CheckTokenMiddleware(h.CreateOrder))
+	router.HandlerFunc(http.MethodGet, "/v1/books/suggest", h.SuggestBooks)
+	router.HandlerFunc(http.MethodGet, "/v1/categories", ch.GetCategories)
+	router.HandlerFunc(http.MethodGet, "/v1/categories/:id/books", ch.GetCategoryBooks)
+	router.HandlerFunc(http.MethodGet, "/v1/series/:id", sh.GetSeries)
+	router.HandlerFunc(http.MethodGet, "/v1/works/:id", h.GetWork)
+	router.Handler(http.MethodGet, entity.CoverURLPrefix+"*key", http.StripPrefix(entity.CoverURLPrefix, coverStore))
+	router.HandlerFunc(http.MethodGet, "/v1/users/me/incomplete-series", m.CheckTokenMiddleware(sh.GetSeriesToComplete))
+	router.HandlerFunc(http.MethodPost, "/v1/orders", m.CheckTokenMiddleware(im.IdempotencyMiddleware(h.CreateOrder)))
 	router.HandlerFunc(http.MethodGet, "/v1/orders", m.CheckTokenMiddleware(h.GetMyOrders))
+	router.HandlerFunc(http.MethodGet, "/v1/orders/:id", m.CheckTokenMiddleware(h.GetOrder))
+	router.HandlerFunc(http.MethodPost, "/v1/orders/:id/cancel", m.CheckTokenMiddleware(h.CancelOrder))
+	router.HandlerFunc(http.MethodPost, "/v1/orders/:id/returns", m.CheckTokenMiddleware(im.IdempotencyMiddleware(rh.RequestReturn)))
+	router.HandlerFunc(http.MethodGet, "/v1/cart/items", m.OptionalTokenMiddleware(cth.GetCart))
+	router.HandlerFunc(http.MethodDelete, "/v1/cart/items", m.OptionalTokenMiddleware(cth.ClearCart))
+	router.HandlerFunc(http.MethodPut, "/v1/cart/items/:sku", m.OptionalTokenMiddleware(cth.SetCartItem))
+	router.HandlerFunc(http.MethodDelete, "/v1/cart/items/:sku", m.OptionalTokenMiddleware(cth.RemoveCartItem))
+	router.HandlerFunc(http.MethodPost, "/v1/cart/checkout", m.CheckTokenMiddleware(im.IdempotencyMiddleware(cth.Checkout)))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/books", m.RequireRoleMiddleware(entity.UserRoleAdmin, h.CreateBook))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/books/export", m.RequireRoleMiddleware(entity.UserRoleAdmin, eh.ExportBooks))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/books/import", m.RequireRoleMiddleware(entity.UserRoleAdmin, ih.ImportBooksCSV))
+	router.HandlerFunc(http.MethodPatch, "/v1/admin/books/:id", m.RequireRoleMiddleware(entity.UserRoleAdmin, h.UpdateBook))
+	router.HandlerFunc(http.MethodDelete, "/v1/admin/books/:id", m.RequireRoleMiddleware(entity.UserRoleAdmin, h.DeleteBook))
+	router.HandlerFunc(http.MethodPut, "/v1/admin/books/:id/cover", m.RequireRoleMiddleware(entity.UserRoleAdmin, cvh.UploadCover))
+	router.HandlerFunc(http.MethodPut, "/v1/admin/books/:id/categories", m.RequireRoleMiddleware(entity.UserRoleAdmin, ch.SetBookCategories))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/categories", m.RequireRoleMiddleware(entity.UserRoleAdmin, ch.CreateCategory))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/orders/:id/status", m.RequireRoleMiddleware(entity.UserRoleAdmin, h.UpdateOrderStatus))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/returns", m.RequireRoleMiddleware(entity.UserRoleAdmin, rh.GetReturns))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/returns/:id/status", m.RequireRoleMiddleware(entity.UserRoleAdmin, rh.TransitionReturn))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/series", m.RequireRoleMiddleware(entity.UserRoleAdmin, sh.CreateSeries))
+	router.HandlerFunc(http.MethodGet, "/v2/books", handler.WithPageEnvelope(h.GetBooks))
+	router.HandlerFunc(http.MethodGet, "/v2/orders", m.CheckTokenMiddleware(handler.WithPageEnvelope(h.GetMyOrders)))
+
+	if paymentProvider != nil {
+		paymentService := service.NewPaymentService(repoWrapper, orderService, paymentProvider, txFunc)
+		ph := handler.NewPaymentHandler(paymentService)
+		router.HandlerFunc(http.MethodPost, "/v1/orders/:id/pay", m.CheckTokenMiddleware(im.IdempotencyMiddleware(ph.PayOrder)))
+		router.HandlerFunc(http.MethodPost, "/v1/payments/webhook", ph.Webhook)
+	}
+
+	// unpaid orders give their stock back once their reservation expires
+	go sweeper.Run(ctx)
 
 	fmt.Println("server started")
 	if err := http.ListenAndServe(fmt.Sprintf(":%d", config.AppPort), router); err != nil {
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joeshaw/envdecode"
+	"github.com/joho/godotenv"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository/db"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+)
+
+type Config struct {
+	DBHost     string `env:"DB_HOST"`
+	DBPort     int    `env:"DB_PORT"`
+	DBUser     string `env:"DB_USER"`
+	DBPassword string `env:"DB_PASSWORD"`
+	DBName     string `env:"DB_NAME"`
+}
+
+const usage = `usage: export [-format csv|jsonl] [-updated-since TIME] [-o FILE]
+
+Writes the whole catalog, hidden and discontinued books included, to stdout or FILE.
+-updated-since takes an RFC 3339 time or a date and only exports books changed since then.
+`
+
+func main() {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	format := flags.String("format", entity.ExportFormatCSV, "csv or jsonl")
+	updatedSince := flags.String("updated-since", "", "only books updated since this time")
+	output := flags.String("o", "", "write to this file instead of stdout")
+	_ = flags.Parse(os.Args[1:])
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	params := entity.ExportBooksParams{Format: *format}
+	if *updatedSince != "" {
+		since, err := time.Parse(time.RFC3339, *updatedSince)
+		if err != nil {
+			since, err = time.Parse(time.DateOnly, *updatedSince)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "updated-since invalid")
+			os.Exit(2)
+		}
+		params.UpdatedSince = &since
+	}
+
+	_ = godotenv.Load(".env")
+
+	var config Config
+	if err := envdecode.Decode(&config); err != nil {
+		panic(err)
+	}
+
+	connString := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		config.DBHost,
+		config.DBPort,
+		config.DBUser,
+		config.DBPassword,
+		config.DBName,
+	)
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		panic(err)
+	}
+	defer pool.Close()
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	buffered := bufio.NewWriter(out)
+	exportService := service.NewExportService(repository.NewDbWrapperRepo(db.New(pool)))
+	written, err := exportService.ExportBooks(ctx, params, buffered)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d books\n", written)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joeshaw/envdecode"
+	"github.com/joho/godotenv"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository/db"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+)
+
+type Config struct {
+	DBHost     string `env:"DB_HOST"`
+	DBPort     int    `env:"DB_PORT"`
+	DBUser     string `env:"DB_USER"`
+	DBPassword string `env:"DB_PASSWORD"`
+	DBName     string `env:"DB_NAME"`
+}
+
+const usage = `usage:
+  import csv [-dry-run] <file>
+  import onix [-dry-run] [-currency CODE] <file>...
+
+csv needs a header with the columns title, isbn, authors, price and stock.
+onix reads ONIX 3.0 messages with reference tags, delete notifications discontinue the book.
+Books are upserted by ISBN and a JSON report is printed to stdout for every file.
+`
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "csv" && os.Args[1] != "onix") {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	dryRun := flags.Bool("dry-run", false, "validate and count without committing")
+	currency := flags.String("currency", "", "ONIX price currency, the first quoted price when empty")
+	_ = flags.Parse(os.Args[2:])
+	if flags.NArg() == 0 || (command == "csv" && flags.NArg() != 1) {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	_ = godotenv.Load(".env")
+
+	var config Config
+	if err := envdecode.Decode(&config); err != nil {
+		panic(err)
+	}
+
+	connString := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		config.DBHost,
+		config.DBPort,
+		config.DBUser,
+		config.DBPassword,
+		config.DBName,
+	)
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		panic(err)
+	}
+	defer pool.Close()
+
+	txFunc := func(ctx context.Context) (pgx.Tx, error) {
+		return pool.Begin(ctx)
+	}
+
+	importService := service.NewImportService(repository.NewDbWrapperRepo(db.New(pool)), txFunc)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	failed := false
+	for _, path := range flags.Args() {
+		report, err := importFile(ctx, importService, command, path, *currency, *dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, err)
+			failed = true
+			continue
+		}
+
+		_ = encoder.Encode(report)
+		if report.Failed > 0 {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// importFile imports each file in its own transaction, so one broken feed does not hold back the others.
+func importFile(ctx context.Context, importService *service.ImportService, command, path, currency string, dryRun bool) (*entity.BookImportReport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if command == "onix" {
+		return importService.ImportBooksONIX(ctx, file, currency, dryRun)
+	}
+
+	return importService.ImportBooksCSV(ctx, file, dryRun)
+}
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_books_search;
+DROP FUNCTION IF EXISTS books_search_vector(TEXT, TEXT, TEXT);
+
+ALTER TABLE books DROP COLUMN "authors",
+    DROP COLUMN "description";
+
+COMMIT;
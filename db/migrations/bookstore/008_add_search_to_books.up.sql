@@ -0,0 +1,14 @@
+BEGIN;
+
+ALTER TABLE books ADD COLUMN "authors" VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN "description" TEXT NOT NULL DEFAULT '';
+
+CREATE OR REPLACE FUNCTION books_search_vector(name TEXT, authors TEXT, description TEXT) RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(authors, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C');
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE INDEX IF NOT EXISTS idx_books_search ON books USING GIN(books_search_vector(name, authors, description));
+
+COMMIT;
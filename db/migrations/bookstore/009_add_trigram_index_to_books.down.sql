@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_books_authors_trgm;
+DROP INDEX IF EXISTS idx_books_name_trgm;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_books_name_trgm ON books USING GIN(name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_books_authors_trgm ON books USING GIN(authors gin_trgm_ops);
+
+COMMIT;
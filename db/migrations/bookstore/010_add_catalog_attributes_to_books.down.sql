@@ -0,0 +1,19 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_order_items_sold_count ON order_items;
+DROP FUNCTION IF EXISTS books_increment_sold_count();
+
+DROP INDEX IF EXISTS idx_books_sold_count;
+DROP INDEX IF EXISTS idx_books_published_at;
+DROP INDEX IF EXISTS idx_books_price;
+DROP INDEX IF EXISTS idx_books_category;
+
+ALTER TABLE books DROP COLUMN "category",
+    DROP COLUMN "language",
+    DROP COLUMN "format",
+    DROP COLUMN "price",
+    DROP COLUMN "stock",
+    DROP COLUMN "published_at",
+    DROP COLUMN "sold_count";
+
+COMMIT;
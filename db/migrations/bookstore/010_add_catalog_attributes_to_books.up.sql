@@ -0,0 +1,34 @@
+BEGIN;
+
+ALTER TABLE books ADD COLUMN "category" VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN "language" VARCHAR(8) NOT NULL DEFAULT 'en',
+    ADD COLUMN "format" VARCHAR(20) NOT NULL DEFAULT 'paperback',
+    ADD COLUMN "price" BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN "stock" BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN "published_at" DATE NULL,
+    ADD COLUMN "sold_count" BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE books ADD CONSTRAINT chk_books_format CHECK ("format" IN ('hardcover', 'paperback', 'ebook', 'audiobook')),
+    ADD CONSTRAINT chk_books_price CHECK ("price" >= 0),
+    ADD CONSTRAINT chk_books_stock CHECK ("stock" >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_books_category ON books(category);
+CREATE INDEX IF NOT EXISTS idx_books_price ON books(price);
+CREATE INDEX IF NOT EXISTS idx_books_published_at ON books(published_at);
+CREATE INDEX IF NOT EXISTS idx_books_sold_count ON books(sold_count);
+
+UPDATE books b SET sold_count = s.total
+FROM (SELECT book_id, SUM(amount) AS total FROM order_items GROUP BY book_id) s
+WHERE s.book_id = b.id;
+
+CREATE OR REPLACE FUNCTION books_increment_sold_count() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE books SET sold_count = sold_count + NEW.amount WHERE id = NEW.book_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_order_items_sold_count AFTER INSERT ON order_items
+    FOR EACH ROW EXECUTE FUNCTION books_increment_sold_count();
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders(user_id, id DESC);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+
+ALTER TABLE users DROP COLUMN "role";
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN "role" VARCHAR(20) NOT NULL DEFAULT 'customer';
+
+ALTER TABLE users ADD CONSTRAINT chk_users_role CHECK ("role" IN ('customer', 'admin'));
+
+COMMIT;
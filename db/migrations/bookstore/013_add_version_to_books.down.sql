@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE books DROP COLUMN "version",
+    DROP COLUMN "updated_at";
+
+COMMIT;
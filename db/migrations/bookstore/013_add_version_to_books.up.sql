@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE books ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_books_status;
+
+ALTER TABLE books DROP CONSTRAINT IF EXISTS chk_books_status;
+
+ALTER TABLE books DROP COLUMN "status";
+
+COMMIT;
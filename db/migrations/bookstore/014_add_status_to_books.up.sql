@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE books ADD COLUMN "status" VARCHAR(20) NOT NULL DEFAULT 'active';
+
+ALTER TABLE books ADD CONSTRAINT chk_books_status CHECK ("status" IN ('active', 'discontinued', 'hidden'));
+
+CREATE INDEX IF NOT EXISTS idx_books_status ON books(status);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_books_isbn;
+
+ALTER TABLE books DROP COLUMN "isbn";
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE books ADD COLUMN "isbn" VARCHAR(13) NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_isbn ON books(isbn) WHERE isbn IS NOT NULL;
+
+COMMIT;
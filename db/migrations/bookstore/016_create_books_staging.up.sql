@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE UNLOGGED TABLE IF NOT EXISTS books_staging (
+    "batch_id" VARCHAR(32) NOT NULL,
+    "line" INT NOT NULL,
+    "isbn" VARCHAR(13) NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    "authors" VARCHAR(255) NOT NULL DEFAULT '',
+    "price" BIGINT NOT NULL,
+    "stock" BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_books_staging_batch_id ON books_staging(batch_id);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+DELETE FROM books_staging WHERE "authors" IS NULL OR "price" IS NULL OR "stock" IS NULL;
+
+ALTER TABLE books_staging DROP COLUMN IF EXISTS "description",
+    DROP COLUMN IF EXISTS "category",
+    DROP COLUMN IF EXISTS "language",
+    DROP COLUMN IF EXISTS "format",
+    DROP COLUMN IF EXISTS "published_at",
+    DROP COLUMN IF EXISTS "status",
+    ALTER COLUMN "authors" SET DEFAULT '',
+    ALTER COLUMN "authors" SET NOT NULL,
+    ALTER COLUMN "price" SET NOT NULL,
+    ALTER COLUMN "stock" SET NOT NULL;
+
+COMMIT;
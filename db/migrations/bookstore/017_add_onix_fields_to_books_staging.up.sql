@@ -0,0 +1,14 @@
+BEGIN;
+
+ALTER TABLE books_staging ADD COLUMN "description" TEXT NULL,
+    ADD COLUMN "category" VARCHAR(100) NULL,
+    ADD COLUMN "language" VARCHAR(8) NULL,
+    ADD COLUMN "format" VARCHAR(20) NULL,
+    ADD COLUMN "published_at" DATE NULL,
+    ADD COLUMN "status" VARCHAR(20) NULL,
+    ALTER COLUMN "authors" DROP NOT NULL,
+    ALTER COLUMN "authors" DROP DEFAULT,
+    ALTER COLUMN "price" DROP NOT NULL,
+    ALTER COLUMN "stock" DROP NOT NULL;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_books_updated_at ON books(updated_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP FUNCTION IF EXISTS category_subtree(BIGINT);
+DROP TABLE IF EXISTS book_categories;
+DROP TABLE IF EXISTS categories;
+
+COMMIT;
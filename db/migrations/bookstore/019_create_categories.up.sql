@@ -0,0 +1,52 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS categories (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "parent_id" BIGINT NULL REFERENCES categories(id),
+    "name" VARCHAR(100) NOT NULL,
+    "slug" VARCHAR(100) NOT NULL,
+    "bisac_code" VARCHAR(9) NULL,
+    "thema_code" VARCHAR(20) NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_id_slug ON categories(COALESCE(parent_id, 0), slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_bisac_code ON categories(bisac_code) WHERE bisac_code IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_thema_code ON categories(thema_code) WHERE thema_code IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS book_categories (
+    "book_id" BIGINT NOT NULL REFERENCES books(id),
+    "category_id" BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY ("book_id", "category_id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_categories_category_id ON book_categories(category_id);
+
+-- category_subtree returns root and every category below it
+CREATE OR REPLACE FUNCTION category_subtree(root BIGINT) RETURNS SETOF BIGINT AS $$
+    WITH RECURSIVE tree AS (
+        SELECT id FROM categories WHERE id = root
+        UNION ALL
+        SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+    )
+    SELECT id FROM tree;
+$$ LANGUAGE SQL STABLE;
+
+-- the free text categories books already have become top level categories
+INSERT INTO categories (name, slug)
+SELECT DISTINCT ON (slug) category, slug
+FROM (
+    SELECT category, trim(BOTH '-' FROM regexp_replace(lower(category), '[^a-z0-9]+', '-', 'g')) AS slug
+    FROM books
+    WHERE category <> ''
+) c
+WHERE slug <> ''
+ORDER BY slug, category;
+
+INSERT INTO book_categories (book_id, category_id)
+SELECT b.id, c.id
+FROM books b
+JOIN categories c ON c.parent_id IS NULL
+    AND c.slug = trim(BOTH '-' FROM regexp_replace(lower(b.category), '[^a-z0-9]+', '-', 'g'));
+
+COMMIT;
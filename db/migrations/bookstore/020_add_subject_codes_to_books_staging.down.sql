@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE books_staging DROP COLUMN IF EXISTS "bisac_codes",
+    DROP COLUMN IF EXISTS "thema_codes";
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE books_staging ADD COLUMN "bisac_codes" TEXT[] NULL,
+    ADD COLUMN "thema_codes" TEXT[] NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_books_series_id_series_volume;
+ALTER TABLE books DROP COLUMN IF EXISTS "series_volume",
+    DROP COLUMN IF EXISTS "series_id";
+DROP TABLE IF EXISTS series;
+
+COMMIT;
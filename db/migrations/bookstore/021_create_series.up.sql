@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS series (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+ALTER TABLE books ADD COLUMN "series_id" BIGINT NULL REFERENCES series(id),
+    ADD COLUMN "series_volume" INT NULL CHECK ("series_volume" > 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_series_id_series_volume ON books(series_id, series_volume)
+    WHERE series_id IS NOT NULL AND series_volume IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS "sku";
+
+DROP TRIGGER IF EXISTS trg_books_create_work ON books;
+DROP FUNCTION IF EXISTS books_create_work();
+
+DROP INDEX IF EXISTS idx_books_sku;
+ALTER TABLE books DROP COLUMN IF EXISTS "sku";
+DROP SEQUENCE IF EXISTS books_sku_seq;
+
+-- the first edition of every work takes its place in the series back
+ALTER TABLE books ADD COLUMN IF NOT EXISTS "series_id" BIGINT NULL REFERENCES series(id),
+    ADD COLUMN IF NOT EXISTS "series_volume" INT NULL CHECK ("series_volume" > 0);
+UPDATE books b SET series_id = w.series_id, series_volume = w.series_volume
+FROM works w
+WHERE w.id = b.work_id AND w.series_id IS NOT NULL
+    AND b.id = (SELECT MIN(e.id) FROM books e WHERE e.work_id = w.id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_series_id_series_volume ON books(series_id, series_volume)
+    WHERE series_id IS NOT NULL AND series_volume IS NOT NULL;
+DROP INDEX IF EXISTS idx_works_series_id_series_volume;
+
+DROP INDEX IF EXISTS idx_books_work_id;
+ALTER TABLE books DROP COLUMN IF EXISTS "work_id";
+DROP TABLE IF EXISTS works;
+
+COMMIT;
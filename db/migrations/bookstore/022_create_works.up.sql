@@ -0,0 +1,60 @@
+BEGIN;
+
+-- a work is the title, every books row is one of its editions and the unit that is ordered
+CREATE TABLE IF NOT EXISTS works (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "title" VARCHAR(255) NOT NULL,
+    "authors" VARCHAR(255) NOT NULL DEFAULT '',
+    "description" TEXT NOT NULL DEFAULT '',
+    "series_id" BIGINT NULL REFERENCES series(id),
+    "series_volume" INT NULL CHECK ("series_volume" > 0),
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- every existing book becomes a work with a single edition, keeping its id and its place in a series
+INSERT INTO works (id, title, authors, description, series_id, series_volume, created_at)
+SELECT id, name, authors, description, series_id, series_volume, created_at FROM books;
+
+SELECT setval('works_id_seq', COALESCE((SELECT MAX(id) FROM works), 0) + 1, false);
+
+ALTER TABLE books ADD COLUMN "work_id" BIGINT NULL REFERENCES works(id);
+UPDATE books SET work_id = id;
+ALTER TABLE books ALTER COLUMN "work_id" SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_books_work_id ON books(work_id);
+
+-- a volume of a series is the work, all of its editions are that volume
+DROP INDEX IF EXISTS idx_books_series_id_series_volume;
+ALTER TABLE books DROP COLUMN IF EXISTS "series_volume",
+    DROP COLUMN IF EXISTS "series_id";
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_works_series_id_series_volume ON works(series_id, series_volume)
+    WHERE series_id IS NOT NULL AND series_volume IS NOT NULL;
+
+CREATE SEQUENCE IF NOT EXISTS books_sku_seq;
+ALTER TABLE books ADD COLUMN "sku" VARCHAR(32) NOT NULL DEFAULT ('BK' || lpad(nextval('books_sku_seq')::text, 8, '0'));
+ALTER SEQUENCE books_sku_seq OWNED BY books.sku;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_sku ON books(sku);
+
+-- books inserted without a work, by the admin endpoints or an import, start a work of their own
+CREATE OR REPLACE FUNCTION books_create_work() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.work_id IS NULL THEN
+        INSERT INTO works (title, authors, description)
+        VALUES (NEW.name, COALESCE(NEW.authors, ''), COALESCE(NEW.description, ''))
+        RETURNING id INTO NEW.work_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_books_create_work BEFORE INSERT ON books
+    FOR EACH ROW EXECUTE FUNCTION books_create_work();
+
+-- items of books removed before books were soft-deleted have nothing to point at and keep a NULL sku
+ALTER TABLE order_items ADD COLUMN "sku" VARCHAR(32) NULL;
+UPDATE order_items oi SET sku = b.sku FROM books b WHERE b.id = oi.book_id;
+ALTER TABLE order_items ADD CONSTRAINT order_items_sku_fkey FOREIGN KEY ("sku") REFERENCES books(sku);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE books DROP COLUMN IF EXISTS "cover_key";
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- sha256 of the uploaded cover, renditions are stored under it so their URLs never change
+ALTER TABLE books ADD COLUMN "cover_key" VARCHAR(64) NULL;
+
+COMMIT;
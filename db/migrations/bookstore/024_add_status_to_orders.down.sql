@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS order_status_changes;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS "status",
+    DROP COLUMN IF EXISTS "updated_at";
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+-- orders placed before statuses existed were final once created, they are treated as paid
+ALTER TABLE orders ADD COLUMN "status" VARCHAR(20) NOT NULL DEFAULT 'paid'
+    CHECK ("status" IN ('pending_payment', 'paid', 'fulfilling', 'shipped', 'delivered', 'cancelled', 'refunded')),
+    ADD COLUMN "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+ALTER TABLE orders ALTER COLUMN "status" SET DEFAULT 'pending_payment';
+UPDATE orders SET updated_at = created_at;
+
+-- every status an order has been in, the actor is the user who made the change or NULL for the system
+CREATE TABLE IF NOT EXISTS order_status_changes (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "order_id" BIGINT NOT NULL REFERENCES orders(id),
+    "from_status" VARCHAR(20) NULL,
+    "to_status" VARCHAR(20) NOT NULL,
+    "actor_id" BIGINT NULL REFERENCES users(id),
+    "actor_role" VARCHAR(20) NOT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_changes_order_id ON order_status_changes(order_id);
+
+INSERT INTO order_status_changes (order_id, from_status, to_status, actor_role, created_at)
+SELECT id, NULL, status, 'system', created_at FROM orders;
+
+COMMIT;
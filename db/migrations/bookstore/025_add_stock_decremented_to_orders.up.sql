@@ -0,0 +1,6 @@
+BEGIN;
+
+-- whether the stock of the order items is currently taken off the books, older orders never decremented it
+ALTER TABLE orders ADD COLUMN "stock_decremented" BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
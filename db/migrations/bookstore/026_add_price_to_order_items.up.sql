@@ -0,0 +1,8 @@
+BEGIN;
+
+-- unit price the item was ordered at, older items take the current price of their book, if it still exists
+ALTER TABLE order_items ADD COLUMN "price" BIGINT NULL;
+
+UPDATE order_items oi SET "price" = b.price FROM books b WHERE b.id = oi.book_id;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- responses of requests sent with an Idempotency-Key, a NULL status_code is a request still in flight
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    "user_id" BIGINT NOT NULL REFERENCES users(id),
+    "key" VARCHAR(255) NOT NULL,
+    "fingerprint" VARCHAR(64) NOT NULL,
+    "status_code" INTEGER NULL,
+    "response" BYTEA NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY ("user_id", "key")
+);
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+-- a cart belongs to a signed in user or, for guests, to a random token handed out with the cart
+CREATE TABLE IF NOT EXISTS carts (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "user_id" BIGINT NULL UNIQUE REFERENCES users(id),
+    "token" VARCHAR(64) NULL UNIQUE,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CHECK ("user_id" IS NOT NULL OR "token" IS NOT NULL)
+);
+
+-- price is the price of the book when it was put in the cart, reads compare it with the current one
+CREATE TABLE IF NOT EXISTS cart_items (
+    "cart_id" BIGINT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    "sku" VARCHAR(32) NOT NULL REFERENCES books(sku),
+    "amount" BIGINT NOT NULL CHECK ("amount" > 0),
+    "price" BIGINT NOT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY ("cart_id", "sku")
+);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_orders_pending_reserved_until;
+ALTER TABLE orders DROP COLUMN IF EXISTS "reserved_until";
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- until when an unpaid order holds its stock, orders placed before reservations expired hold it until cancelled
+ALTER TABLE orders ADD COLUMN "reserved_until" TIMESTAMP WITH TIME ZONE NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_pending_reserved_until ON orders ("reserved_until")
+    WHERE "status" = 'pending_payment';
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- every payment attempt of an order, keyed by the reference the payment provider gave it, kept for reconciliation
+CREATE TABLE IF NOT EXISTS payment_attempts (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "order_id" BIGINT NOT NULL REFERENCES orders(id),
+    "provider" VARCHAR(32) NOT NULL,
+    "reference" VARCHAR(128) NOT NULL,
+    "amount" BIGINT NOT NULL CHECK ("amount" >= 0),
+    "refunded_amount" BIGINT NOT NULL DEFAULT 0 CHECK ("refunded_amount" >= 0),
+    "status" VARCHAR(16) NOT NULL,
+    "failure_reason" TEXT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    UNIQUE ("provider", "reference")
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_attempts_order_id ON payment_attempts ("order_id", "status");
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+-- a return of copies of one order item, requested by the customer after delivery and handled by staff. refund_amount
+-- is what was paid back once the books were received
+CREATE TABLE IF NOT EXISTS returns (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "order_id" BIGINT NOT NULL REFERENCES orders(id),
+    "order_item_id" BIGINT NOT NULL REFERENCES order_items(id),
+    "amount" BIGINT NOT NULL CHECK ("amount" > 0),
+    "reason" VARCHAR(32) NOT NULL,
+    "note" TEXT NULL,
+    "status" VARCHAR(16) NOT NULL,
+    "staff_note" TEXT NULL,
+    "handled_by" BIGINT NULL REFERENCES users(id),
+    "refund_amount" BIGINT NOT NULL DEFAULT 0 CHECK ("refund_amount" >= 0),
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_returns_order_id ON returns ("order_id", "order_item_id");
+CREATE INDEX IF NOT EXISTS idx_returns_status ON returns ("status", "id");
+
+COMMIT;
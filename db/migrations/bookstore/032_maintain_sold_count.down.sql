@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_returns_sold_count ON returns;
+DROP FUNCTION IF EXISTS books_return_sold_count();
+
+DROP TRIGGER IF EXISTS trg_orders_sold_count ON orders;
+DROP FUNCTION IF EXISTS books_release_sold_count();
+
+COMMIT;
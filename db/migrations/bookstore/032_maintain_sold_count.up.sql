@@ -0,0 +1,54 @@
+BEGIN;
+
+-- sold_count counts the copies customers kept: cancelled and refunded orders and received returns give theirs back
+UPDATE books b SET sold_count = COALESCE((
+    SELECT SUM(oi.amount) FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    WHERE oi.book_id = b.id AND o.status NOT IN ('cancelled', 'refunded')
+), 0) - COALESCE((
+    SELECT SUM(r.amount) FROM returns r
+    JOIN order_items oi ON oi.id = r.order_item_id
+    JOIN orders o ON o.id = r.order_id
+    WHERE oi.book_id = b.id AND r.status = 'received' AND o.status NOT IN ('cancelled', 'refunded')
+), 0);
+
+-- copies of the order that were returned already gave their count back
+CREATE OR REPLACE FUNCTION books_release_sold_count() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE books b SET sold_count = GREATEST(b.sold_count - s.amount, 0)
+    FROM (
+        SELECT oi.book_id, SUM(oi.amount - COALESCE(r.amount, 0)) AS amount
+        FROM order_items oi
+        LEFT JOIN (
+            SELECT order_item_id, SUM(amount) AS amount FROM returns
+            WHERE order_id = NEW.id AND status = 'received'
+            GROUP BY order_item_id
+        ) r ON r.order_item_id = oi.id
+        WHERE oi.order_id = NEW.id
+        GROUP BY oi.book_id
+    ) s
+    WHERE s.book_id = b.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_orders_sold_count AFTER UPDATE OF status ON orders
+    FOR EACH ROW WHEN (NEW.status IN ('cancelled', 'refunded') AND OLD.status NOT IN ('cancelled', 'refunded'))
+    EXECUTE FUNCTION books_release_sold_count();
+
+-- copies of cancelled and refunded orders gave their count back with the order
+CREATE OR REPLACE FUNCTION books_return_sold_count() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE books b SET sold_count = GREATEST(b.sold_count - NEW.amount, 0)
+    FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    WHERE oi.id = NEW.order_item_id AND b.id = oi.book_id AND o.status NOT IN ('cancelled', 'refunded');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_returns_sold_count AFTER UPDATE OF status ON returns
+    FOR EACH ROW WHEN (NEW.status = 'received' AND OLD.status <> 'received')
+    EXECUTE FUNCTION books_return_sold_count();
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- refunds are recorded with the change that causes them and sent to the payment provider once that change committed,
+-- pending ones are sent again until the provider took them. The id is the idempotency key of the refund at the
+-- provider, so sending it twice pays back once
+CREATE TABLE IF NOT EXISTS payment_refunds (
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "payment_attempt_id" BIGINT NOT NULL REFERENCES payment_attempts(id),
+    "amount" BIGINT NOT NULL CHECK ("amount" > 0),
+    "status" VARCHAR(16) NOT NULL,
+    "attempts" INT NOT NULL DEFAULT 0,
+    "last_error" TEXT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_pending ON payment_refunds ("updated_at") WHERE "status" = 'pending';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_idempotency_keys_expires_at;
+
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS "lease";
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- a request in flight holds its key under a lease until expires_at, a retry takes an expired lease over and the lease
+-- keeps the request it was taken from from completing or releasing the key
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS "lease" VARCHAR(32) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys ("expires_at");
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trg_books_sync_category ON books;
+DROP FUNCTION IF EXISTS books_sync_category();
+DROP FUNCTION IF EXISTS category_slug(TEXT);
+
+COMMIT;
@@ -0,0 +1,53 @@
+BEGIN;
+
+CREATE OR REPLACE FUNCTION category_slug(name TEXT) RETURNS TEXT AS $$
+    SELECT trim(BOTH '-' FROM regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'));
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- the category text of a book files it under the top level category with the same slug, which is created when missing,
+-- and a changed text takes the book out of the category of the old one
+CREATE OR REPLACE FUNCTION books_sync_category() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF OLD.category IS NOT DISTINCT FROM NEW.category THEN
+            RETURN NEW;
+        END IF;
+
+        DELETE FROM book_categories bc USING categories c
+        WHERE bc.book_id = NEW.id AND bc.category_id = c.id
+            AND c.parent_id IS NULL AND c.slug = category_slug(OLD.category);
+    END IF;
+
+    IF category_slug(NEW.category) = '' THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO categories (name, slug) VALUES (NEW.category, category_slug(NEW.category))
+    ON CONFLICT ((COALESCE(parent_id, 0)), slug) DO NOTHING;
+
+    INSERT INTO book_categories (book_id, category_id)
+    SELECT NEW.id, c.id FROM categories c WHERE c.parent_id IS NULL AND c.slug = category_slug(NEW.category)
+    ON CONFLICT DO NOTHING;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_books_sync_category AFTER INSERT OR UPDATE OF category ON books
+    FOR EACH ROW EXECUTE FUNCTION books_sync_category();
+
+-- books written since the categories were created only have their text
+INSERT INTO categories (name, slug)
+SELECT DISTINCT ON (category_slug(category)) category, category_slug(category)
+FROM books
+WHERE category_slug(category) <> ''
+ORDER BY category_slug(category), category
+ON CONFLICT ((COALESCE(parent_id, 0)), slug) DO NOTHING;
+
+INSERT INTO book_categories (book_id, category_id)
+SELECT b.id, c.id
+FROM books b
+JOIN categories c ON c.parent_id IS NULL AND c.slug = category_slug(b.category)
+ON CONFLICT DO NOTHING;
+
+COMMIT;
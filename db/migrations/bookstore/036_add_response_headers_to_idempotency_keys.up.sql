@@ -0,0 +1,6 @@
+BEGIN;
+
+-- headers of the response kept for a key, replayed with it, as a JSON object of header names to their values
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS "response_headers" JSONB NULL;
+
+COMMIT;
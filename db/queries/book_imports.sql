@@ -0,0 +1,48 @@
+-- name: CopyBooksToStaging :copyfrom
+INSERT INTO "books_staging" ("batch_id", "line", "isbn", "name", "authors", "description", "category", "language", "format", "price", "stock", "published_at", "status", "bisac_codes", "thema_codes") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
+
+-- name: UpsertBooksFromStaging :one
+WITH updated AS (
+    UPDATE "books" b SET
+        "name" = s.name,
+        "authors" = COALESCE(s.authors, b.authors),
+        "description" = COALESCE(s.description, b.description),
+        "category" = COALESCE(s.category, b.category),
+        "language" = COALESCE(s.language, b.language),
+        "format" = COALESCE(s.format, b.format),
+        "price" = COALESCE(s.price, b.price),
+        "stock" = COALESCE(s.stock, b.stock),
+        "published_at" = COALESCE(s.published_at, b.published_at),
+        "status" = CASE WHEN b.status = 'hidden' THEN b.status ELSE COALESCE(s.status, b.status) END,
+        "version" = b.version + 1,
+        "updated_at" = NOW()
+    FROM "books_staging" s
+    WHERE s.batch_id = $1 AND b.isbn = s.isbn
+    RETURNING b.id
+), inserted AS (
+    INSERT INTO "books" ("isbn", "name", "authors", "description", "category", "language", "format", "price", "stock", "published_at", "status", "created_at")
+    SELECT s.isbn, s.name, COALESCE(s.authors, ''), COALESCE(s.description, ''), COALESCE(s.category, ''),
+        COALESCE(s.language, 'en'), COALESCE(s.format, 'paperback'), COALESCE(s.price, 0), COALESCE(s.stock, 0),
+        s.published_at, COALESCE(s.status, 'active'), NOW()
+    FROM "books_staging" s
+    WHERE s.batch_id = $1 AND NOT EXISTS (SELECT 1 FROM "books" b WHERE b.isbn = s.isbn)
+    RETURNING id
+)
+SELECT (SELECT COUNT(*) FROM inserted)::bigint AS inserted,
+    (SELECT COUNT(*) FROM updated)::bigint AS updated;
+
+-- name: LinkStagedBookCategories :exec
+INSERT INTO "book_categories" ("book_id", "category_id")
+SELECT DISTINCT b.id, c.id
+FROM "books_staging" s
+JOIN "books" b ON b.isbn = s.isbn
+JOIN "categories" c ON c.bisac_code = ANY(s.bisac_codes) OR c.thema_code = ANY(s.thema_codes)
+WHERE s.batch_id = $1
+ON CONFLICT DO NOTHING;
+
+-- name: DiscontinueBooksByISBN :execrows
+UPDATE "books" SET "status" = 'discontinued', "version" = "version" + 1, "updated_at" = NOW()
+WHERE "isbn" = ANY(@isbns::varchar[]) AND "status" <> 'discontinued';
+
+-- name: ClearBooksStaging :exec
+DELETE FROM "books_staging" WHERE "batch_id" = $1;
\ No newline at end of file
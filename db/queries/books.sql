@@ -1,5 +1,217 @@
 -- name: GetBooks :many
-SELECT * FROM "books" LIMIT $1 OFFSET $2;
+SELECT b.id, b.name, b.authors, b.description, b.category, b.language, b.format, b.price, b.stock, b.published_at,
+    b.sold_count, b.created_at, w.series_id, w.series_volume, sr.name AS series_name, b.work_id, b.sku, b.cover_key,
+    COALESCE(ts_rank(books_search_vector(b.name, b.authors, b.description), q.tsq), 0)::real AS rank,
+    COALESCE(ts_headline('english', concat_ws(' ', b.name, b.authors, b.description), q.tsq,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5'), '')::text AS highlight
+FROM "books" b
+JOIN "works" w ON w.id = b.work_id
+LEFT JOIN "series" sr ON sr.id = w.series_id
+LEFT JOIN LATERAL (SELECT websearch_to_tsquery('english', sqlc.narg('query')::text) AS tsq) q ON TRUE
+WHERE b.status = 'active'
+    AND (q.tsq IS NULL OR books_search_vector(b.name, b.authors, b.description) @@ q.tsq)
+    AND (sqlc.narg('author')::text IS NULL OR b.authors ILIKE '%' || sqlc.narg('author')::text || '%')
+    AND (sqlc.narg('category')::text IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+        JOIN "categories" c ON c.slug = category_slug(sqlc.narg('category')::text)
+        WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(c.id))))
+    AND (sqlc.narg('category_id')::bigint IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+        WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(sqlc.narg('category_id')::bigint))))
+    AND (sqlc.narg('language')::text IS NULL OR b.language = sqlc.narg('language')::text)
+    AND (sqlc.narg('format')::text IS NULL OR b.format = sqlc.narg('format')::text)
+    AND (sqlc.narg('min_price')::bigint IS NULL OR b.price >= sqlc.narg('min_price')::bigint)
+    AND (sqlc.narg('max_price')::bigint IS NULL OR b.price <= sqlc.narg('max_price')::bigint)
+    AND (NOT sqlc.arg('in_stock')::boolean OR b.stock > 0)
+    AND (sqlc.narg('published_year')::int IS NULL OR (b.published_at >= make_date(sqlc.narg('published_year')::int, 1, 1)
+        AND b.published_at < make_date(sqlc.narg('published_year')::int + 1, 1, 1)))
+    AND (sqlc.narg('after_id')::bigint IS NULL OR (CASE sqlc.arg('sort')::text
+        WHEN 'relevance' THEN ts_rank(books_search_vector(b.name, b.authors, b.description), q.tsq) < sqlc.narg('after_rank')::real
+            OR (ts_rank(books_search_vector(b.name, b.authors, b.description), q.tsq) = sqlc.narg('after_rank')::real AND b.id > sqlc.narg('after_id')::bigint)
+        WHEN 'title' THEN b.name > sqlc.narg('after_name')::text
+            OR (b.name = sqlc.narg('after_name')::text AND b.id > sqlc.narg('after_id')::bigint)
+        WHEN 'price_asc' THEN b.price > sqlc.narg('after_price')::bigint
+            OR (b.price = sqlc.narg('after_price')::bigint AND b.id > sqlc.narg('after_id')::bigint)
+        WHEN 'price_desc' THEN b.price < sqlc.narg('after_price')::bigint
+            OR (b.price = sqlc.narg('after_price')::bigint AND b.id > sqlc.narg('after_id')::bigint)
+        WHEN 'publication_date' THEN (CASE WHEN sqlc.narg('after_published_at')::date IS NULL
+            THEN b.published_at IS NULL AND b.id > sqlc.narg('after_id')::bigint
+            ELSE b.published_at < sqlc.narg('after_published_at')::date OR b.published_at IS NULL
+                OR (b.published_at = sqlc.narg('after_published_at')::date AND b.id > sqlc.narg('after_id')::bigint)
+            END)
+        WHEN 'popularity' THEN b.sold_count < sqlc.narg('after_sold_count')::bigint
+            OR (b.sold_count = sqlc.narg('after_sold_count')::bigint AND b.id > sqlc.narg('after_id')::bigint)
+        WHEN 'newest' THEN b.created_at < sqlc.narg('after_created_at')::timestamptz
+            OR (b.created_at = sqlc.narg('after_created_at')::timestamptz AND b.id > sqlc.narg('after_id')::bigint)
+        ELSE b.id > sqlc.narg('after_id')::bigint
+    END))
+ORDER BY
+    CASE WHEN sqlc.arg('sort')::text = 'relevance' THEN ts_rank(books_search_vector(b.name, b.authors, b.description), q.tsq) END DESC NULLS LAST,
+    CASE WHEN sqlc.arg('sort')::text = 'title' THEN b.name END ASC,
+    CASE WHEN sqlc.arg('sort')::text = 'price_asc' THEN b.price END ASC,
+    CASE WHEN sqlc.arg('sort')::text = 'price_desc' THEN b.price END DESC,
+    CASE WHEN sqlc.arg('sort')::text = 'publication_date' THEN b.published_at END DESC NULLS LAST,
+    CASE WHEN sqlc.arg('sort')::text = 'popularity' THEN b.sold_count END DESC,
+    CASE WHEN sqlc.arg('sort')::text = 'newest' THEN b.created_at END DESC,
+    b.id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: FindBook :one
-SELECT * FROM "books" WHERE "id" = $1;
\ No newline at end of file
+SELECT * FROM "books" WHERE "id" = $1;
+
+-- name: FindBookBySKU :one
+SELECT * FROM "books" WHERE "sku" = $1;
+
+-- name: SuggestBooks :many
+SELECT b.id, b.name, b.authors,
+    GREATEST(word_similarity(@prefix::text, b.name), word_similarity(@prefix::text, b.authors))::real AS score
+FROM "books" b
+WHERE b.status = 'active' AND (@prefix::text <% b.name OR @prefix::text <% b.authors)
+ORDER BY score DESC, b.id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: GetBookFacets :many
+WITH filtered AS (
+    SELECT b.id, b.language, b.format, b.price
+    FROM "books" b
+    LEFT JOIN LATERAL (SELECT websearch_to_tsquery('english', sqlc.narg('query')::text) AS tsq) q ON TRUE
+    WHERE b.status = 'active'
+        AND (q.tsq IS NULL OR books_search_vector(b.name, b.authors, b.description) @@ q.tsq)
+        AND (sqlc.narg('author')::text IS NULL OR b.authors ILIKE '%' || sqlc.narg('author')::text || '%')
+        AND (sqlc.narg('category')::text IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+            JOIN "categories" c ON c.slug = category_slug(sqlc.narg('category')::text)
+            WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(c.id))))
+        AND (sqlc.narg('category_id')::bigint IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+            WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(sqlc.narg('category_id')::bigint))))
+        AND (sqlc.narg('language')::text IS NULL OR b.language = sqlc.narg('language')::text)
+        AND (sqlc.narg('format')::text IS NULL OR b.format = sqlc.narg('format')::text)
+        AND (sqlc.narg('min_price')::bigint IS NULL OR b.price >= sqlc.narg('min_price')::bigint)
+        AND (sqlc.narg('max_price')::bigint IS NULL OR b.price <= sqlc.narg('max_price')::bigint)
+        AND (NOT sqlc.arg('in_stock')::boolean OR b.stock > 0)
+        AND (sqlc.narg('published_year')::int IS NULL OR (b.published_at >= make_date(sqlc.narg('published_year')::int, 1, 1)
+            AND b.published_at < make_date(sqlc.narg('published_year')::int + 1, 1, 1)))
+)
+SELECT 'category'::text AS facet, c.name::text AS value, c.id AS category_id, COUNT(DISTINCT f.id)::bigint AS total
+FROM "categories" c
+JOIN "book_categories" bc ON bc.category_id IN (SELECT category_subtree(c.id))
+JOIN filtered f ON f.id = bc.book_id
+WHERE c.parent_id IS NOT DISTINCT FROM sqlc.narg('category_id')::bigint
+GROUP BY c.id, c.name
+UNION ALL
+SELECT
+    (CASE
+        WHEN GROUPING(f.language) = 0 THEN 'language'
+        WHEN GROUPING(f.format) = 0 THEN 'format'
+        ELSE 'price'
+    END)::text AS facet,
+    (CASE
+        WHEN GROUPING(f.language) = 0 THEN f.language
+        WHEN GROUPING(f.format) = 0 THEN f.format
+        ELSE p.bucket::text
+    END)::text AS value,
+    0::bigint AS category_id,
+    COUNT(*)::bigint AS total
+FROM filtered f
+CROSS JOIN LATERAL (SELECT width_bucket(f.price, sqlc.arg('price_edges')::bigint[]) AS bucket) p
+GROUP BY GROUPING SETS ((f.language), (f.format), (p.bucket))
+ORDER BY facet ASC, total DESC, value ASC;
+
+-- name: CountBooks :one
+SELECT COUNT(*)::bigint AS total
+FROM "books" b
+LEFT JOIN LATERAL (SELECT websearch_to_tsquery('english', sqlc.narg('query')::text) AS tsq) q ON TRUE
+WHERE b.status = 'active'
+    AND (q.tsq IS NULL OR books_search_vector(b.name, b.authors, b.description) @@ q.tsq)
+    AND (sqlc.narg('author')::text IS NULL OR b.authors ILIKE '%' || sqlc.narg('author')::text || '%')
+    AND (sqlc.narg('category')::text IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+        JOIN "categories" c ON c.slug = category_slug(sqlc.narg('category')::text)
+        WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(c.id))))
+    AND (sqlc.narg('category_id')::bigint IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+        WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(sqlc.narg('category_id')::bigint))))
+    AND (sqlc.narg('language')::text IS NULL OR b.language = sqlc.narg('language')::text)
+    AND (sqlc.narg('format')::text IS NULL OR b.format = sqlc.narg('format')::text)
+    AND (sqlc.narg('min_price')::bigint IS NULL OR b.price >= sqlc.narg('min_price')::bigint)
+    AND (sqlc.narg('max_price')::bigint IS NULL OR b.price <= sqlc.narg('max_price')::bigint)
+    AND (NOT sqlc.arg('in_stock')::boolean OR b.stock > 0)
+    AND (sqlc.narg('published_year')::int IS NULL OR (b.published_at >= make_date(sqlc.narg('published_year')::int, 1, 1)
+        AND b.published_at < make_date(sqlc.narg('published_year')::int + 1, 1, 1)));
+
+-- name: EstimateBooksCount :one
+SELECT GREATEST(c.reltuples, 0)::bigint AS estimate FROM pg_class c WHERE c.oid = 'books'::regclass;
+
+-- name: CreateBook :one
+WITH new_work AS (
+    INSERT INTO "works" ("title", "authors", "description", "series_id", "series_volume")
+    SELECT sqlc.arg('name')::varchar, sqlc.arg('authors')::varchar, sqlc.arg('description')::text,
+        sqlc.narg('series_id')::bigint, sqlc.narg('series_volume')::int
+    WHERE sqlc.arg('work_id')::bigint = 0
+    RETURNING "id", "series_id", "series_volume"
+), edited_work AS (
+    UPDATE "works" SET
+        "series_id" = COALESCE(sqlc.narg('series_id')::bigint, "series_id"),
+        "series_volume" = COALESCE(sqlc.narg('series_volume')::int, "series_volume")
+    WHERE "id" = sqlc.arg('work_id')::bigint
+    RETURNING "id", "series_id", "series_volume"
+), book AS (
+    INSERT INTO "books" ("name", "authors", "description", "category", "language", "format", "price", "stock", "published_at", "status",
+        "work_id", "created_at")
+    VALUES (sqlc.arg('name'), sqlc.arg('authors'), sqlc.arg('description'), sqlc.arg('category'), sqlc.arg('language'),
+        sqlc.arg('format'), sqlc.arg('price'), sqlc.arg('stock'), sqlc.narg('published_at'), sqlc.arg('status'),
+        COALESCE((SELECT "id" FROM new_work), NULLIF(sqlc.arg('work_id')::bigint, 0)), NOW())
+    RETURNING *
+)
+SELECT book.*, work.series_id, work.series_volume FROM book
+LEFT JOIN (SELECT id, series_id, series_volume FROM new_work UNION ALL SELECT id, series_id, series_volume FROM edited_work) work ON work.id = book.work_id;
+
+-- name: UpdateBook :one
+WITH book AS (
+    UPDATE "books" SET
+        "name" = COALESCE(sqlc.narg('name')::varchar, "name"),
+        "authors" = COALESCE(sqlc.narg('authors')::varchar, "authors"),
+        "description" = COALESCE(sqlc.narg('description')::text, "description"),
+        "category" = COALESCE(sqlc.narg('category')::varchar, "category"),
+        "language" = COALESCE(sqlc.narg('language')::varchar, "language"),
+        "format" = COALESCE(sqlc.narg('format')::varchar, "format"),
+        "price" = COALESCE(sqlc.narg('price')::bigint, "price"),
+        "stock" = COALESCE(sqlc.narg('stock')::bigint, "stock"),
+        "published_at" = COALESCE(sqlc.narg('published_at')::date, "published_at"),
+        "status" = COALESCE(sqlc.narg('status')::varchar, "status"),
+        "work_id" = COALESCE(sqlc.narg('work_id')::bigint, "work_id"),
+        "version" = "version" + 1,
+        "updated_at" = NOW()
+    WHERE "id" = sqlc.arg('id') AND "version" = sqlc.arg('version')
+    RETURNING *
+), work AS (
+    UPDATE "works" w SET
+        "series_id" = COALESCE(sqlc.narg('series_id')::bigint, w.series_id),
+        "series_volume" = COALESCE(sqlc.narg('series_volume')::int, w.series_volume)
+    FROM book
+    WHERE w.id = book.work_id
+    RETURNING w.id, w.series_id, w.series_volume
+)
+SELECT book.*, work.series_id, work.series_volume FROM book
+LEFT JOIN work ON work.id = book.work_id;
+
+-- name: DiscontinueBook :execrows
+UPDATE "books" SET "status" = 'discontinued', "version" = "version" + 1, "updated_at" = NOW()
+WHERE "id" = sqlc.arg('id') AND (sqlc.narg('version')::bigint IS NULL OR "version" = sqlc.narg('version')::bigint);
+
+-- name: DecrementBookStock :execrows
+UPDATE "books" SET "stock" = "stock" - sqlc.arg('amount')::bigint, "version" = "version" + 1, "updated_at" = NOW()
+WHERE "sku" = sqlc.arg('sku') AND "stock" >= sqlc.arg('amount')::bigint;
+
+-- name: IncrementBookStock :execrows
+UPDATE "books" SET "stock" = "stock" + sqlc.arg('amount')::bigint, "version" = "version" + 1, "updated_at" = NOW()
+WHERE "sku" = sqlc.arg('sku');
+
+-- name: ExportBooks :many
+SELECT * FROM "books"
+WHERE "id" > @after_id AND (sqlc.narg(updated_since)::timestamptz IS NULL OR "updated_at" >= sqlc.narg(updated_since)::timestamptz)
+ORDER BY "id"
+LIMIT sqlc.arg('limit');
+
+-- name: SetBookCover :one
+UPDATE "books" SET
+    "cover_key" = sqlc.arg('cover_key'),
+    "version" = "version" + 1,
+    "updated_at" = NOW()
+WHERE "id" = sqlc.arg('id')
+RETURNING *;
\ No newline at end of file
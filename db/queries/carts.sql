@@ -0,0 +1,40 @@
+-- name: GetOrCreateUserCart :one
+INSERT INTO "carts" ("user_id", "created_at", "updated_at") VALUES ($1, NOW(), NOW())
+ON CONFLICT ("user_id") DO UPDATE SET "updated_at" = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: CreateGuestCart :one
+INSERT INTO "carts" ("token", "created_at", "updated_at") VALUES ($1, NOW(), NOW()) RETURNING *;
+
+-- name: FindGuestCart :one
+SELECT * FROM "carts" WHERE "token" = $1 AND "user_id" IS NULL FOR UPDATE;
+
+-- name: DeleteCart :exec
+DELETE FROM "carts" WHERE "id" = $1;
+
+-- name: GetCartItems :many
+SELECT ci.sku, ci.amount, ci.price AS added_price,
+    b.id AS book_id, b.name AS book_name, b.authors AS book_authors, b.status AS book_status, b.price, b.stock
+FROM "cart_items" ci
+JOIN "books" b ON b.sku = ci.sku
+WHERE ci.cart_id = $1
+ORDER BY ci.created_at, ci.sku;
+
+-- name: SetCartItem :exec
+INSERT INTO "cart_items" ("cart_id", "sku", "amount", "price", "created_at", "updated_at")
+VALUES (sqlc.arg('cart_id'), sqlc.arg('sku'), sqlc.arg('amount'), sqlc.arg('price'), NOW(), NOW())
+ON CONFLICT ("cart_id", "sku") DO UPDATE
+SET "amount" = EXCLUDED.amount, "price" = EXCLUDED.price, "updated_at" = NOW();
+
+-- name: MergeCartItems :exec
+INSERT INTO "cart_items" ("cart_id", "sku", "amount", "price", "created_at", "updated_at")
+SELECT sqlc.arg('cart_id')::bigint, i.sku, i.amount, i.price, NOW(), NOW()
+FROM UNNEST(sqlc.arg('skus')::varchar[], sqlc.arg('amounts')::bigint[], sqlc.arg('prices')::bigint[]) AS i(sku, amount, price)
+ON CONFLICT ("cart_id", "sku") DO UPDATE
+SET "amount" = EXCLUDED.amount, "updated_at" = NOW();
+
+-- name: DeleteCartItem :execrows
+DELETE FROM "cart_items" WHERE "cart_id" = $1 AND "sku" = $2;
+
+-- name: ClearCart :exec
+DELETE FROM "cart_items" WHERE "cart_id" = $1;
\ No newline at end of file
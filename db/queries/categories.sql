@@ -0,0 +1,24 @@
+-- name: GetCategories :many
+SELECT * FROM "categories" ORDER BY "name", "id";
+
+-- name: FindCategory :one
+SELECT * FROM "categories" WHERE "id" = $1;
+
+-- name: CreateCategory :one
+INSERT INTO "categories" ("parent_id", "name", "slug", "bisac_code", "thema_code", "created_at")
+VALUES ($1, $2, $3, $4, $5, NOW())
+RETURNING *;
+
+-- name: GetBookCategories :many
+SELECT c.* FROM "categories" c
+JOIN "book_categories" bc ON bc.category_id = c.id
+WHERE bc.book_id = $1
+ORDER BY c.name, c.id;
+
+-- name: DeleteBookCategories :exec
+DELETE FROM "book_categories" WHERE "book_id" = $1;
+
+-- name: AddBookCategories :execrows
+INSERT INTO "book_categories" ("book_id", "category_id")
+SELECT @book_id::bigint, c.id FROM "categories" c WHERE c.id = ANY(@category_ids::bigint[])
+ON CONFLICT DO NOTHING;
\ No newline at end of file
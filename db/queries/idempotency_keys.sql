@@ -0,0 +1,25 @@
+-- name: ReserveIdempotencyKey :execrows
+INSERT INTO "idempotency_keys" ("user_id", "key", "fingerprint", "lease", "created_at", "expires_at")
+VALUES (sqlc.arg('user_id'), sqlc.arg('key'), sqlc.arg('fingerprint'), sqlc.arg('lease'), NOW(), sqlc.arg('expires_at'))
+ON CONFLICT ("user_id", "key") DO UPDATE
+SET "fingerprint" = EXCLUDED.fingerprint, "lease" = EXCLUDED.lease, "status_code" = NULL, "response" = NULL,
+    "response_headers" = NULL, "created_at" = NOW(), "expires_at" = EXCLUDED.expires_at
+WHERE "idempotency_keys"."expires_at" <= NOW();
+
+-- name: FindIdempotencyKey :one
+SELECT * FROM "idempotency_keys" WHERE "user_id" = $1 AND "key" = $2 AND "expires_at" > NOW();
+
+-- name: SaveIdempotencyResponse :exec
+UPDATE "idempotency_keys"
+SET "status_code" = sqlc.arg('status_code'), "response" = sqlc.arg('response'),
+    "response_headers" = sqlc.arg('response_headers'), "expires_at" = sqlc.arg('expires_at')
+WHERE "user_id" = sqlc.arg('user_id') AND "key" = sqlc.arg('key') AND "lease" = sqlc.arg('lease');
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM "idempotency_keys" WHERE "user_id" = $1 AND "key" = $2 AND "lease" = $3;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM "idempotency_keys"
+WHERE ("user_id", "key") IN (
+    SELECT "user_id", "key" FROM "idempotency_keys" WHERE "expires_at" <= NOW() LIMIT $1
+) AND "expires_at" <= NOW();
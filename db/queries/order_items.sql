@@ -1,5 +1,18 @@
 -- name: CreateOrderItem :one
-INSERT INTO "order_items" ("order_id", "book_id", "amount", "created_at") VALUES ($1, $2, $3, NOW()) RETURNING id, order_id, book_id, amount, created_at;
+INSERT INTO "order_items" ("order_id", "book_id", "sku", "amount", "price", "created_at")
+SELECT sqlc.arg('order_id')::bigint, b.id, b.sku, sqlc.arg('amount')::bigint, b.price, NOW() FROM "books" b WHERE b.sku = sqlc.arg('sku')
+RETURNING id, order_id, book_id, amount, created_at, sku, price;
 
--- name: GetMyOrderItems :many
-SELECT * FROM "order_items" WHERE order_id = $1;
\ No newline at end of file
+-- name: FindOrderItem :one
+SELECT id, order_id, book_id, amount, created_at, sku, price FROM "order_items" WHERE "id" = $1 AND "order_id" = $2;
+
+-- name: GetOrderItems :many
+SELECT oi.id, oi.order_id, oi.book_id, oi.amount, oi.created_at, oi.sku, oi.price,
+    b.name AS book_name, b.authors AS book_authors, b.status AS book_status
+FROM "order_items" oi
+LEFT JOIN "books" b ON b.id = oi.book_id
+WHERE oi.order_id = ANY(sqlc.arg('order_ids')::bigint[])
+ORDER BY oi.order_id, oi.id;
+
+-- name: GetOrderTotal :one
+SELECT COALESCE(SUM(price * amount), 0)::bigint AS total FROM "order_items" WHERE order_id = $1;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- name: CreateOrderStatusChange :one
+INSERT INTO "order_status_changes" ("order_id", "from_status", "to_status", "actor_id", "actor_role", "created_at")
+VALUES (sqlc.arg('order_id'), sqlc.narg('from_status'), sqlc.arg('to_status'), sqlc.narg('actor_id'), sqlc.arg('actor_role'), NOW())
+RETURNING *;
+
+-- name: GetOrderStatusChanges :many
+SELECT * FROM "order_status_changes"
+WHERE "order_id" = ANY(sqlc.arg('order_ids')::bigint[])
+ORDER BY "order_id", "id";
\ No newline at end of file
@@ -1,8 +1,52 @@
 -- name: CreateOrder :one
-INSERT INTO "orders" ("user_id", "created_at") VALUES ($1, NOW()) RETURNING id, user_id, created_at;
+INSERT INTO "orders" ("user_id", "stock_decremented", "reserved_until", "created_at")
+VALUES (sqlc.arg('user_id'), TRUE, NOW() + sqlc.arg('reservation_ttl')::interval, NOW())
+RETURNING id, user_id, status, reserved_until, created_at;
 
 -- name: GetMyOrders :many
-SELECT o.id as order_id, o.user_id, u.email as email, o.created_at
+SELECT o.id as order_id, o.user_id, u.email as email, o.status, o.created_at
 FROM "orders" o
 JOIN "users" u ON o.user_id = u.id
-WHERE o.user_id = $1 ORDER BY o.id DESC LIMIT $2 OFFSET $3;
\ No newline at end of file
+WHERE o.user_id = sqlc.arg('user_id')
+    AND (sqlc.narg('before_id')::bigint IS NULL OR o.id < sqlc.narg('before_id')::bigint)
+ORDER BY o.id DESC LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountMyOrders :one
+SELECT COUNT(*)::bigint AS total FROM "orders" o WHERE o.user_id = $1;
+
+-- name: FindOrder :one
+SELECT o.id, o.user_id, u.email, o.status, o.reserved_until, o.created_at
+FROM "orders" o
+JOIN "users" u ON o.user_id = u.id
+WHERE o.id = $1;
+
+-- name: FindOrderForUpdate :one
+SELECT id, user_id, status, created_at FROM "orders" WHERE "id" = $1 FOR UPDATE;
+
+-- name: GetExpiredOrders :many
+SELECT id, user_id, status, created_at FROM "orders"
+WHERE "status" = 'pending_payment' AND "reserved_until" <= NOW()
+ORDER BY "reserved_until" LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateOrderStatus :one
+UPDATE "orders" SET "status" = sqlc.arg('to_status'), "reserved_until" = NULL, "updated_at" = NOW()
+WHERE "id" = sqlc.arg('id') AND "status" = sqlc.arg('from_status')
+RETURNING id, user_id, status, created_at;
+
+-- name: ReleaseOrderStock :execrows
+WITH released AS (
+    UPDATE "orders" SET "stock_decremented" = FALSE WHERE "id" = $1 AND "stock_decremented" RETURNING id
+)
+UPDATE "books" b SET "stock" = b.stock + oi.amount, "version" = b.version + 1, "updated_at" = NOW()
+FROM (
+    SELECT oi.sku, SUM(oi.amount - COALESCE(r.amount, 0))::bigint AS amount FROM "order_items" oi
+    LEFT JOIN (
+        SELECT order_item_id, SUM(amount) AS amount FROM "returns"
+        WHERE order_id = $1 AND status = 'received'
+        GROUP BY order_item_id
+    ) r ON r.order_item_id = oi.id
+    WHERE oi.order_id IN (SELECT id FROM released) AND oi.sku IS NOT NULL
+    GROUP BY oi.sku
+) oi
+WHERE b.sku = oi.sku AND oi.amount > 0;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- name: CreatePaymentAttempt :one
+INSERT INTO "payment_attempts" ("order_id", "provider", "reference", "amount", "status", "created_at", "updated_at")
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+RETURNING *;
+
+-- name: FindPaymentAttempt :one
+SELECT * FROM "payment_attempts" WHERE "provider" = $1 AND "reference" = $2;
+
+-- name: FindOrderPaymentAttempt :one
+SELECT * FROM "payment_attempts" WHERE "order_id" = $1 AND "status" = $2 ORDER BY "id" DESC LIMIT 1;
+
+-- name: UpdatePaymentAttempt :one
+UPDATE "payment_attempts"
+SET "status" = $2, "refunded_amount" = $3, "failure_reason" = $4, "updated_at" = NOW()
+WHERE "id" = $1
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- name: CreatePaymentRefund :one
+INSERT INTO "payment_refunds" ("payment_attempt_id", "amount", "status", "created_at", "updated_at")
+VALUES ($1, $2, $3, NOW(), NOW())
+RETURNING *;
+
+-- name: FindPendingPaymentRefundForUpdate :one
+SELECT r.id, r.payment_attempt_id, r.amount, r.status, r.attempts, r.last_error, r.created_at, r.updated_at,
+    pa.provider, pa.reference
+FROM "payment_refunds" r
+JOIN "payment_attempts" pa ON pa.id = r.payment_attempt_id
+WHERE r.id = $1 AND r.status = 'pending'
+FOR UPDATE OF r SKIP LOCKED;
+
+-- name: GetPendingPaymentRefunds :many
+SELECT id FROM "payment_refunds"
+WHERE "status" = 'pending' AND "updated_at" <= $1
+ORDER BY "updated_at" LIMIT $2;
+
+-- name: UpdatePaymentRefund :exec
+UPDATE "payment_refunds"
+SET "status" = $2, "attempts" = $3, "last_error" = $4, "updated_at" = NOW()
+WHERE "id" = $1;
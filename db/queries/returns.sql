@@ -0,0 +1,26 @@
+-- name: CreateReturn :one
+INSERT INTO "returns" ("order_id", "order_item_id", "amount", "reason", "note", "status", "created_at", "updated_at")
+VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+RETURNING *;
+
+-- name: GetReturnedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM "returns"
+WHERE "order_id" = $1 AND "order_item_id" = $2 AND "status" <> 'rejected';
+
+-- name: FindReturnForUpdate :one
+SELECT * FROM "returns" WHERE "id" = $1 FOR UPDATE;
+
+-- name: UpdateReturn :one
+UPDATE "returns"
+SET "status" = $2, "staff_note" = $3, "handled_by" = $4, "refund_amount" = $5, "updated_at" = NOW()
+WHERE "id" = $1
+RETURNING *;
+
+-- name: GetOrderReturns :many
+SELECT * FROM "returns" WHERE "order_id" = ANY(sqlc.arg('order_ids')::bigint[]) ORDER BY "order_id", "id";
+
+-- name: GetReturns :many
+SELECT * FROM "returns"
+WHERE (sqlc.narg('status')::text IS NULL OR "status" = sqlc.narg('status')::text)
+ORDER BY "id"
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- name: FindSeries :one
+SELECT * FROM "series" WHERE "id" = $1;
+
+-- name: CreateSeries :one
+INSERT INTO "series" ("name", "description", "created_at") VALUES ($1, $2, NOW())
+RETURNING *;
+
+-- name: GetSeriesVolumes :many
+SELECT b.*, w.series_id, w.series_volume FROM "books" b
+JOIN "works" w ON w.id = b.work_id
+WHERE w.series_id = ANY(@series_ids::bigint[]) AND b.status <> 'hidden'
+ORDER BY w.series_id, w.series_volume NULLS LAST, b.id;
+
+-- name: GetSeriesOfBooks :many
+SELECT DISTINCT s.* FROM "series" s
+JOIN "works" w ON w.series_id = s.id
+JOIN "books" b ON b.work_id = w.id
+WHERE b.id = ANY(@book_ids::bigint[])
+ORDER BY s.name, s.id;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- name: FindWork :one
+SELECT * FROM "works" WHERE "id" = $1;
+
+-- name: GetWorkEditions :many
+SELECT * FROM "books"
+WHERE "work_id" = $1 AND "status" <> 'hidden'
+ORDER BY "format", "id";
+
+-- name: GetWorkIDsOfBooks :many
+SELECT DISTINCT "work_id" FROM "books"
+WHERE "id" = ANY(@book_ids::bigint[])
+ORDER BY "work_id";
\ No newline at end of file
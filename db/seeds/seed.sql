@@ -4,7 +4,11 @@ INSERT INTO users (email, created_at)
     VALUES ('pulungragil@gmail.com', NOW()), ('someone1@mail.com', NOW()), ('someone2@mail.com', NOW())
     ON CONFLICT(email) DO NOTHING;
 
-INSERT INTO books (name, created_at)
-VALUES ('Chicken Soup of Debugging', NOW()), ('How Google Sheet rules the world', NOW()), ('Catalog of contemporary art', NOW());
+UPDATE users SET role = 'admin' WHERE email = 'pulungragil@gmail.com';
+
+INSERT INTO books (name, authors, description, category, language, format, price, stock, published_at, created_at)
+VALUES ('Chicken Soup of Debugging', 'Ada Stacktrace', 'Heartwarming stories about finding the bug at 3 AM.', 'Programming', 'en', 'paperback', 4500, 20, '2019-03-01', NOW()),
+    ('How Google Sheet rules the world', 'Cell Reference', 'How spreadsheets quietly run every business on earth.', 'Business', 'en', 'hardcover', 9900, 5, '2021-08-15', NOW()),
+    ('Catalog of contemporary art', 'Various Artists', 'A visual catalog of modern and contemporary art works.', 'Art', 'en', 'hardcover', 15000, 0, '2015-11-20', NOW());
 
 COMMIT;
\ No newline at end of file
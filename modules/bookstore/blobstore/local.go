@@ -0,0 +1,101 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/raymondwongso/gogox/errorx"
+)
+
+// ImmutableCacheControl is sent with every blob. Keys are derived from the content, a blob never changes once written.
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+// LocalStore keeps blobs as files under a directory, a key being the slash separated path below it.
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{
+		dir: dir,
+	}
+}
+
+// Put writes the blob to a temporary file first and renames it into place, readers never see a partial blob. The
+// content type is not kept, it is sniffed again when the blob is served.
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+	if err = tmp.Close(); err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	if err = os.Rename(tmp.Name(), name); err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return ctx.Err()
+}
+
+// ServeHTTP serves the blob whose key is the request path, mount it behind http.StripPrefix.
+func (s *LocalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, err := s.path(r.URL.Path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", ImmutableCacheControl)
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
+
+// path maps a key onto a file below the store directory, rejecting keys that would escape it.
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || strings.Contains(key, "..") || strings.HasPrefix(path.Base(cleaned), ".") {
+		return "", errorx.ErrInvalidParameter("blob key invalid")
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,82 @@
+package blobstore_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/blobstore"
+)
+
+type LocalStoreTestSuite struct {
+	suite.Suite
+}
+
+func TestLocalStore(t *testing.T) {
+	suite.Run(t, new(LocalStoreTestSuite))
+}
+
+func (s *LocalStoreTestSuite) TestPut() {
+	ctx := context.Background()
+
+	s.Run("writes the blob below the directory", func() {
+		dir := s.T().TempDir()
+		store := blobstore.NewLocalStore(dir)
+
+		s.Require().NoError(store.Put(ctx, "abc/small", strings.NewReader("thumbnail"), "image/jpeg"))
+
+		stored, err := os.ReadFile(filepath.Join(dir, "abc", "small"))
+		s.Require().NoError(err)
+		s.Assert().Equal("thumbnail", string(stored))
+
+		entries, err := os.ReadDir(filepath.Join(dir, "abc"))
+		s.Require().NoError(err)
+		s.Assert().Len(entries, 1)
+	})
+
+	s.Run("rejects keys escaping the directory", func() {
+		store := blobstore.NewLocalStore(s.T().TempDir())
+
+		s.Assert().Error(store.Put(ctx, "../outside", strings.NewReader("x"), "image/jpeg"))
+		s.Assert().Error(store.Put(ctx, "", strings.NewReader("x"), "image/jpeg"))
+	})
+}
+
+func (s *LocalStoreTestSuite) TestServeHTTP() {
+	store := blobstore.NewLocalStore(s.T().TempDir())
+	s.Require().NoError(store.Put(context.Background(), "abc/original", strings.NewReader("\x89PNG\r\n\x1a\n"), "image/png"))
+	h := http.StripPrefix("/v1/covers/", store)
+
+	s.Run("serves the blob as immutable", func() {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost/v1/covers/abc/original", nil))
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Equal("image/png", resp.Header.Get("Content-Type"))
+		s.Assert().Equal(blobstore.ImmutableCacheControl, resp.Header.Get("Cache-Control"))
+		s.Assert().Equal("\x89PNG\r\n\x1a\n", string(body))
+	})
+
+	s.Run("missing blob", func() {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost/v1/covers/abc/large", nil))
+
+		s.Assert().Equal(http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	s.Run("directories are not listed", func() {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost/v1/covers/abc", nil))
+
+		s.Assert().Equal(http.StatusNotFound, w.Result().StatusCode)
+	})
+}
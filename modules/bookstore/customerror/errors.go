@@ -2,6 +2,24 @@ package customerror
 
 import "github.com/raymondwongso/gogox/errorx"
 
+const (
+	CodePreconditionFailed   = "bookstore.precondition_failed"
+	CodePreconditionRequired = "bookstore.precondition_required"
+	CodeUnprocessableEntity  = "bookstore.unprocessable_entity"
+)
+
+func ErrPreconditionFailed(msg string) *errorx.Error {
+	return errorx.New(CodePreconditionFailed, msg)
+}
+
+func ErrPreconditionRequired(msg string) *errorx.Error {
+	return errorx.New(CodePreconditionRequired, msg)
+}
+
+func ErrUnprocessableEntity(msg string) *errorx.Error {
+	return errorx.New(CodeUnprocessableEntity, msg)
+}
+
 func IsErrNotFound(err error) bool {
 	goxErr, ok := errorx.Parse(err)
 	if !ok {
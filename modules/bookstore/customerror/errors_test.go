@@ -33,3 +33,17 @@ func (s *CustomErrorTestSuite) TestIsErrNotFound() {
 		s.Assert().True(result)
 	})
 }
+
+func (s *CustomErrorTestSuite) TestPreconditionErrors() {
+	s.Run("precondition failed", func() {
+		err := customerror.ErrPreconditionFailed("book has been modified")
+		s.Assert().Equal(customerror.CodePreconditionFailed, err.Code)
+		s.Assert().EqualError(err, "book has been modified")
+	})
+
+	s.Run("precondition required", func() {
+		err := customerror.ErrPreconditionRequired("If-Match header is required")
+		s.Assert().Equal(customerror.CodePreconditionRequired, err.Code)
+		s.Assert().EqualError(err, "If-Match header is required")
+	})
+}
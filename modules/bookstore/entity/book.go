@@ -1,11 +1,177 @@
 package entity
 
+import "time"
+
+const (
+	BookSortRelevance       = "relevance"
+	BookSortTitle           = "title"
+	BookSortPriceAsc        = "price_asc"
+	BookSortPriceDesc       = "price_desc"
+	BookSortPublicationDate = "publication_date"
+	BookSortPopularity      = "popularity"
+	BookSortNewest          = "newest"
+)
+
+const (
+	BookStatusActive       = "active"
+	BookStatusDiscontinued = "discontinued"
+	BookStatusHidden       = "hidden"
+)
+
 type Book struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID          int64       `json:"id"`
+	WorkID      int64       `json:"work_id,omitempty"`
+	SKU         string      `json:"sku,omitempty"`
+	ISBN        string      `json:"isbn,omitempty"`
+	Name        string      `json:"name"`
+	Authors     string      `json:"authors"`
+	Description string      `json:"description"`
+	Category    string      `json:"category"`
+	Language    string      `json:"language"`
+	Format      string      `json:"format"`
+	Price       int64       `json:"price"`
+	Stock       int64       `json:"stock"`
+	PublishedAt *time.Time  `json:"published_at,omitempty"`
+	Highlight   string      `json:"highlight,omitempty"`
+	Version     int64       `json:"version,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	Series      *BookSeries `json:"series,omitempty"`
+	Cover       *BookCover  `json:"cover,omitempty"`
+	SoldCount   int64       `json:"-"`
+	CreatedAt   time.Time   `json:"-"`
+	UpdatedAt   time.Time   `json:"-"`
+	Rank        float32     `json:"-"`
+}
+
+// BookSeries places the work of a book in its series. Name is only filled in listings, Volume is zero for unnumbered
+// entries.
+type BookSeries struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name,omitempty"`
+	Volume int32  `json:"volume,omitempty"`
 }
 
 type GetBooksParams struct {
-	Limit  int64 `validate:"gt=0"`
-	Offset int64 `validate:"gte=0"`
+	Query         string `validate:"max=200"`
+	Author        string `validate:"max=255"`
+	Category      string `validate:"max=100"`
+	CategoryID    int64  `validate:"gte=0"`
+	Language      string `validate:"omitempty,min=2,max=8"`
+	Format        string `validate:"omitempty,oneof=hardcover paperback ebook audiobook"`
+	MinPrice      *int64 `validate:"omitempty,gte=0"`
+	MaxPrice      *int64 `validate:"omitempty,gte=0"`
+	InStock       bool
+	PublishedYear int32  `validate:"omitempty,gte=1000,lte=9999"`
+	Sort          string `validate:"omitempty,oneof=relevance title price_asc price_desc publication_date popularity newest"`
+	Cursor        string `validate:"max=512"`
+	After         *BookCursor
+	Limit         int64 `validate:"gt=0"`
+	Offset        int64 `validate:"gte=0"`
+}
+
+// BookCursor is the sort key of the last book of the previous page. Only the field matching the requested sort is
+// compared, ties are broken by ID.
+type BookCursor struct {
+	ID          int64
+	Rank        float32
+	Name        string
+	Price       int64
+	PublishedAt *time.Time
+	SoldCount   int64
+	CreatedAt   time.Time
+}
+
+// BookPriceFacetEdges are the lower bounds, in the smallest currency unit, of the price buckets returned as facets.
+var BookPriceFacetEdges = []int64{0, 5000, 10000, 20000, 50000}
+
+type BookList struct {
+	Data       []Book      `json:"data"`
+	Page       *Page       `json:"page,omitempty"`
+	Links      *PageLinks  `json:"links,omitempty"`
+	Facets     *BookFacets `json:"facets,omitempty"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasMore    bool        `json:"-"`
+}
+
+type BookFacets struct {
+	Categories  []FacetCount      `json:"categories"`
+	Languages   []FacetCount      `json:"languages"`
+	Formats     []FacetCount      `json:"formats"`
+	PriceRanges []PriceFacetCount `json:"price_ranges"`
+}
+
+// FacetCount counts the books with a value. Category facets are the categories one level below the filtered category,
+// or the top level ones, and carry the id to filter on with their name as the value.
+type FacetCount struct {
+	ID    int64  `json:"id,omitempty"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type PriceFacetCount struct {
+	Min   int64  `json:"min"`
+	Max   *int64 `json:"max,omitempty"`
+	Count int64  `json:"count"`
+}
+
+type CreateBookParams struct {
+	Name         string     `json:"name" validate:"required,max=255"`
+	Authors      string     `json:"authors" validate:"max=255"`
+	Description  string     `json:"description"`
+	Category     string     `json:"category" validate:"max=100"`
+	Language     string     `json:"language" validate:"required,min=2,max=8"`
+	Format       string     `json:"format" validate:"required,oneof=hardcover paperback ebook audiobook"`
+	Price        int64      `json:"price" validate:"gte=0"`
+	Stock        int64      `json:"stock" validate:"gte=0"`
+	PublishedAt  *time.Time `json:"published_at"`
+	Status       string     `json:"status" validate:"omitempty,oneof=active discontinued hidden"`
+	SeriesID     *int64     `json:"series_id" validate:"omitnil,gt=0"`
+	SeriesVolume *int32     `json:"series_volume" validate:"omitnil,gt=0"`
+	WorkID       *int64     `json:"work_id" validate:"omitnil,gt=0"`
+}
+
+// UpdateBookParams only changes the fields that are set. Version is the one the editor last read, the update is
+// rejected when the book has changed since.
+type UpdateBookParams struct {
+	ID           int64      `json:"-" validate:"required,gt=0"`
+	Version      int64      `json:"-" validate:"required,gt=0"`
+	Name         *string    `json:"name" validate:"omitnil,min=1,max=255"`
+	Authors      *string    `json:"authors" validate:"omitnil,max=255"`
+	Description  *string    `json:"description"`
+	Category     *string    `json:"category" validate:"omitnil,max=100"`
+	Language     *string    `json:"language" validate:"omitnil,min=2,max=8"`
+	Format       *string    `json:"format" validate:"omitnil,oneof=hardcover paperback ebook audiobook"`
+	Price        *int64     `json:"price" validate:"omitnil,gte=0"`
+	Stock        *int64     `json:"stock" validate:"omitnil,gte=0"`
+	PublishedAt  *time.Time `json:"published_at"`
+	Status       *string    `json:"status" validate:"omitnil,oneof=active discontinued hidden"`
+	SeriesID     *int64     `json:"series_id" validate:"omitnil,gt=0"`
+	SeriesVolume *int32     `json:"series_volume" validate:"omitnil,gt=0"`
+	WorkID       *int64     `json:"work_id" validate:"omitnil,gt=0"`
+}
+
+// DeleteBookParams discontinues a book, a zero Version skips the concurrency check. Books are never removed so that
+// past orders keep resolving them.
+type DeleteBookParams struct {
+	ID      int64 `validate:"required,gt=0"`
+	Version int64 `validate:"gte=0"`
+}
+
+// BookSummary is the part of a book shown next to the order items referencing it, whatever its status.
+type BookSummary struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Authors string `json:"authors"`
+	Status  string `json:"status"`
+}
+
+type BookSuggestion struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Authors string `json:"authors"`
+}
+
+type SuggestBooksParams struct {
+	Prefix string `validate:"required,min=2,max=100"`
+	Limit  int64  `validate:"gt=0,lte=20"`
 }
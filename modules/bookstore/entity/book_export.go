@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
+
+// ExportBooksParams selects the books of a catalog export. AfterID and Limit page through the table by id and are set
+// by the exporter, not by callers.
+type ExportBooksParams struct {
+	Format       string `validate:"required,oneof=csv jsonl"`
+	UpdatedSince *time.Time
+	AfterID      int64
+	Limit        int64
+}
+
+// BookExport is one line of a catalog export. Unlike Book it carries every column, hidden and discontinued books
+// included, so downstream copies can mirror the catalog.
+type BookExport struct {
+	ID          int64      `json:"id"`
+	SKU         string     `json:"sku"`
+	WorkID      int64      `json:"work_id"`
+	ISBN        string     `json:"isbn"`
+	Name        string     `json:"name"`
+	Authors     string     `json:"authors"`
+	Description string     `json:"description"`
+	Category    string     `json:"category"`
+	Language    string     `json:"language"`
+	Format      string     `json:"format"`
+	Price       int64      `json:"price"`
+	Stock       int64      `json:"stock"`
+	PublishedAt *time.Time `json:"published_at"`
+	SoldCount   int64      `json:"sold_count"`
+	Status      string     `json:"status"`
+	Version     int64      `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// BookImportRow is one validated catalog row on its way to the staging table. Line is where it was found in the
+// source file so that errors can be reported back to the people maintaining it. Empty or nil optional fields leave the
+// stored value untouched when the book already exists.
+type BookImportRow struct {
+	Line        int32
+	ISBN        string `validate:"required,len=13"`
+	Name        string `validate:"required,max=255"`
+	Authors     string `validate:"max=255"`
+	Description string `validate:"max=10000"`
+	Category    string `validate:"max=100"`
+	Language    string `validate:"omitempty,min=2,max=8"`
+	Format      string `validate:"omitempty,oneof=hardcover paperback ebook audiobook"`
+	Price       *int64 `validate:"omitnil,gte=0"`
+	Stock       *int64 `validate:"omitnil,gte=0"`
+	PublishedAt *time.Time
+	Status      string `validate:"omitempty,oneof=active discontinued"`
+	// BISACCodes and ThemaCodes link the book to the categories carrying those subject codes.
+	BISACCodes []string `validate:"max=20"`
+	ThemaCodes []string `validate:"max=20"`
+	// Delete marks a feed notification that withdraws the book, only ISBN is read for those.
+	Delete bool
+}
+
+type BookImportError struct {
+	Line    int    `json:"line"`
+	ISBN    string `json:"isbn,omitempty"`
+	Message string `json:"message"`
+}
+
+type BookImportReport struct {
+	DryRun       bool              `json:"dry_run"`
+	Rows         int64             `json:"rows"`
+	Inserted     int64             `json:"inserted"`
+	Updated      int64             `json:"updated"`
+	Discontinued int64             `json:"discontinued"`
+	Failed       int64             `json:"failed"`
+	Errors       []BookImportError `json:"errors"`
+}
+
+type BookUpsertCount struct {
+	Inserted int64
+	Updated  int64
+}
@@ -0,0 +1,50 @@
+package entity
+
+// Issues of a cart item, found when the cart is read. They tell the customer what changed since the book was put in
+// the cart.
+const (
+	CartIssueDiscontinued      = "discontinued"
+	CartIssueOutOfStock        = "out_of_stock"
+	CartIssueInsufficientStock = "insufficient_stock"
+	CartIssuePriceChanged      = "price_changed"
+)
+
+// Cart belongs to a signed in user or, for guests, to Token. Total is the price of the items at current prices.
+type Cart struct {
+	ID     int64      `json:"-"`
+	UserID int64      `json:"-"`
+	Token  string     `json:"token,omitempty"`
+	Items  []CartItem `json:"items"`
+	Total  int64      `json:"total"`
+}
+
+// CartItem is an edition in the cart. Price is the current price of the book, AddedPrice the price it had when it was
+// put in the cart.
+type CartItem struct {
+	SKU        string       `json:"sku"`
+	Book       *BookSummary `json:"book,omitempty"`
+	Amount     int64        `json:"amount"`
+	Price      int64        `json:"price"`
+	AddedPrice int64        `json:"added_price"`
+	Stock      int64        `json:"-"`
+	Issues     []string     `json:"issues,omitempty"`
+}
+
+// CartOwner names the cart of a request, the cart of the user when signed in and otherwise the guest cart of Token. A
+// signed in user sending the token of a guest cart takes over its items.
+type CartOwner struct {
+	UserID int64
+	Token  string
+}
+
+// SetCartItemParams puts Amount copies of the edition in the cart, replacing the amount already there.
+type SetCartItemParams struct {
+	Owner  CartOwner `json:"-"`
+	SKU    string    `json:"-" validate:"required,max=32"`
+	Amount int64     `json:"amount" validate:"required,gt=0"`
+}
+
+type RemoveCartItemParams struct {
+	Owner CartOwner `validate:"-"`
+	SKU   string    `validate:"required,max=32"`
+}
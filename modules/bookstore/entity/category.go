@@ -0,0 +1,31 @@
+package entity
+
+// Category is a node of the subject tree, e.g. Fiction > Mystery > Cozy. BISACCode and ThemaCode map the publisher
+// subject codes of an import onto the category.
+type Category struct {
+	ID        int64       `json:"id"`
+	ParentID  *int64      `json:"parent_id,omitempty"`
+	Name      string      `json:"name"`
+	Slug      string      `json:"slug"`
+	BISACCode string      `json:"bisac_code,omitempty"`
+	ThemaCode string      `json:"thema_code,omitempty"`
+	Children  []*Category `json:"children,omitempty"`
+}
+
+type CategoryList struct {
+	Data []*Category `json:"data"`
+}
+
+type CreateCategoryParams struct {
+	ParentID  *int64 `json:"parent_id" validate:"omitnil,gt=0"`
+	Name      string `json:"name" validate:"required,max=100"`
+	Slug      string `json:"slug" validate:"max=100"`
+	BISACCode string `json:"bisac_code" validate:"omitempty,len=9,alphanum"`
+	ThemaCode string `json:"thema_code" validate:"max=20"`
+}
+
+// SetBookCategoriesParams replaces every category of a book, an empty CategoryIDs removes them all.
+type SetBookCategoriesParams struct {
+	BookID      int64   `json:"-" validate:"required,gt=0"`
+	CategoryIDs []int64 `json:"category_ids" validate:"max=20,dive,gt=0"`
+}
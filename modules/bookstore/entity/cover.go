@@ -0,0 +1,35 @@
+package entity
+
+// Renditions a cover is stored in, the thumbnails are scaled to fit their width.
+const (
+	CoverOriginal = "original"
+	CoverSmall    = "small"
+	CoverMedium   = "medium"
+	CoverLarge    = "large"
+)
+
+// CoverURLPrefix is the path the API serves stored covers under.
+const CoverURLPrefix = "/v1/covers/"
+
+// BookCover has the URLs of every rendition of a book cover. They contain the content hash of the upload, a new cover
+// always gets new URLs so clients can cache them indefinitely.
+type BookCover struct {
+	Original string `json:"original"`
+	Small    string `json:"small"`
+	Medium   string `json:"medium"`
+	Large    string `json:"large"`
+}
+
+// CoverKey is the blob key of one rendition of the cover with the given content hash.
+func CoverKey(hash, rendition string) string {
+	return hash + "/" + rendition
+}
+
+func NewBookCover(hash string) *BookCover {
+	return &BookCover{
+		Original: CoverURLPrefix + CoverKey(hash, CoverOriginal),
+		Small:    CoverURLPrefix + CoverKey(hash, CoverSmall),
+		Medium:   CoverURLPrefix + CoverKey(hash, CoverMedium),
+		Large:    CoverURLPrefix + CoverKey(hash, CoverLarge),
+	}
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// IdempotencyKey is a request a user sent with an Idempotency-Key header. StatusCode is 0 while the request is in
+// flight, then StatusCode, Header and Response hold what was answered to it. Lease identifies the request holding the
+// key, only that request completes or releases it.
+type IdempotencyKey struct {
+	UserID      int64
+	Key         string
+	Fingerprint string
+	Lease       string
+	StatusCode  int
+	Header      map[string][]string
+	Response    []byte
+	ExpiresAt   time.Time
+}
@@ -2,40 +2,108 @@ package entity
 
 import "time"
 
+const (
+	OrderStatusPendingPayment = "pending_payment"
+	OrderStatusPaid           = "paid"
+	OrderStatusFulfilling     = "fulfilling"
+	OrderStatusShipped        = "shipped"
+	OrderStatusDelivered      = "delivered"
+	OrderStatusCancelled      = "cancelled"
+	OrderStatusRefunded       = "refunded"
+)
+
+// OrderActorSystem is the actor role of status changes no user made, like the ones backfilled for older orders.
+const OrderActorSystem = "system"
+
+// Order is placed pending payment and holds the stock of its items until ReservedUntil. An order still unpaid by then
+// is cancelled and its stock released. Returns lists the returns requested for its items.
 type Order struct {
-	ID        int64       `json:"id"`
-	UserID    int64       `json:"user_id"`
-	Email     string      `json:"email,omitempty"`
-	Items     []OrderItem `json:"items"`
-	CreatedAt time.Time   `json:"created_at"`
+	ID            int64               `json:"id"`
+	UserID        int64               `json:"user_id"`
+	Email         string              `json:"email,omitempty"`
+	Status        string              `json:"status"`
+	Items         []OrderItem         `json:"items"`
+	History       []OrderStatusChange `json:"history,omitempty"`
+	Returns       []Return            `json:"returns,omitempty"`
+	ReservedUntil *time.Time          `json:"reserved_until,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
 }
 
-type OrderItem struct {
-	ID        int64     `json:"id"`
-	OrderID   int64     `json:"order_id"`
-	BookID    int64     `json:"book_id"`
-	Amount    int64     `json:"amount"`
+// OrderStatusChange is one step of the order history. From is empty for the status the order was created in, ActorID
+// is nil when the system made the change.
+type OrderStatusChange struct {
+	OrderID   int64     `json:"-"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	ActorID   *int64    `json:"actor_id,omitempty"`
+	ActorRole string    `json:"actor_role"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type OrderItem struct {
+	ID        int64        `json:"id"`
+	OrderID   int64        `json:"order_id"`
+	BookID    int64        `json:"book_id"`
+	SKU       string       `json:"sku,omitempty"`
+	Book      *BookSummary `json:"book,omitempty"`
+	Amount    int64        `json:"amount"`
+	Price     *int64       `json:"price,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
 type BookAmount struct {
 	BookID int64 `json:"book_id" validate:"required,gt=0"`
 	Amount int64 `json:"amount" validate:"required,gt=0"`
 }
 
+// CreateOrderParams places an order, it holds the stock of its items for ReservationTTL.
 type CreateOrderParams struct {
-	UserID int64                   `validate:"required,gt=0"`
-	Items  []CreateOrderItemParams `json:"items"`
+	UserID         int64                   `validate:"required,gt=0"`
+	Items          []CreateOrderItemParams `json:"items"`
+	ReservationTTL time.Duration           `json:"-"`
 }
 
+// CreateOrderItemParams orders an edition by its SKU. BookID is still accepted from older clients and resolved to the
+// SKU of that book.
 type CreateOrderItemParams struct {
 	OrderID int64
-	BookID  int64 `json:"book_id" validate:"required,gt=0"`
-	Amount  int64 `json:"amount" validate:"required,gt=0"`
+	SKU     string `json:"sku" validate:"required_without=BookID,max=32"`
+	BookID  int64  `json:"book_id" validate:"required_without=SKU,gte=0"`
+	Amount  int64  `json:"amount" validate:"required,gt=0"`
+}
+
+// TransitionOrderParams moves an order to Status on behalf of the actor, a zero ActorID is the system.
+type TransitionOrderParams struct {
+	OrderID   int64  `json:"-" validate:"required,gt=0"`
+	Status    string `json:"status" validate:"required,oneof=pending_payment paid fulfilling shipped delivered cancelled refunded"`
+	ActorID   int64  `json:"-" validate:"gte=0"`
+	ActorRole string `json:"-" validate:"required,oneof=customer admin system"`
+}
+
+// GetOrderParams finds an order of the customer who placed it.
+type GetOrderParams struct {
+	OrderID int64 `validate:"required,gt=0"`
+	UserID  int64 `validate:"required,gt=0"`
+}
+
+// CancelOrderParams cancels an order on behalf of the customer who placed it.
+type CancelOrderParams struct {
+	OrderID int64 `validate:"required,gt=0"`
+	UserID  int64 `validate:"required,gt=0"`
 }
 
 type GetMyOrdersParams struct {
-	UserID int64 `validate:"required,gt=0"`
-	Limit  int64 `validate:"gt=0"`
-	Offset int64 `validate:"gte=0"`
+	UserID   int64  `validate:"required,gt=0"`
+	Cursor   string `validate:"max=512"`
+	BeforeID int64
+	Limit    int64 `validate:"gt=0"`
+	Offset   int64 `validate:"gte=0"`
+}
+
+type OrderList struct {
+	Data       []Order    `json:"data"`
+	Page       *Page      `json:"page,omitempty"`
+	Links      *PageLinks `json:"links,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	HasMore    bool       `json:"-"`
 }
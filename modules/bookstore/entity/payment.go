@@ -0,0 +1,82 @@
+package entity
+
+import "time"
+
+// Statuses of a payment attempt. An attempt is pending until the provider tells it was authorized or failed, capturing
+// while the capture is sent to the provider, captured once the money was taken and refunded once all of it was paid
+// back.
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusCapturing = "capturing"
+	PaymentStatusCaptured  = "captured"
+	PaymentStatusFailed    = "failed"
+	PaymentStatusRefunded  = "refunded"
+)
+
+// Types of the payment events providers send to the webhook.
+const (
+	PaymentEventAuthorized = "payment.authorized"
+	PaymentEventFailed     = "payment.failed"
+)
+
+// Payment is an attempt to pay an order, known to the provider by Reference. ClientSecret is only set when the
+// attempt is made, the client hands it to the provider to authorize the payment.
+type Payment struct {
+	ID             int64     `json:"id"`
+	OrderID        int64     `json:"order_id"`
+	Provider       string    `json:"provider"`
+	Reference      string    `json:"reference"`
+	Amount         int64     `json:"amount"`
+	RefundedAmount int64     `json:"refunded_amount"`
+	Status         string    `json:"status"`
+	FailureReason  string    `json:"failure_reason,omitempty"`
+	ClientSecret   string    `json:"client_secret,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Statuses of a refund. A refund is pending from the change that causes it until the provider took it.
+const (
+	PaymentRefundStatusPending   = "pending"
+	PaymentRefundStatusSucceeded = "succeeded"
+)
+
+// PaymentRefund pays Amount of a captured payment back. It is recorded with the change that causes it and sent to the
+// provider after that change committed, Provider and Reference name the payment it pays back when it is sent.
+type PaymentRefund struct {
+	ID        int64     `json:"id"`
+	PaymentID int64     `json:"payment_id"`
+	Provider  string    `json:"provider"`
+	Reference string    `json:"reference"`
+	Amount    int64     `json:"amount"`
+	Status    string    `json:"status"`
+	Attempts  int32     `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PayOrderParams struct {
+	OrderID int64 `validate:"required,gt=0"`
+	UserID  int64 `validate:"required,gt=0"`
+}
+
+// PaymentIntentParams asks the provider to prepare a payment of Amount for the order.
+type PaymentIntentParams struct {
+	OrderID int64
+	Amount  int64
+}
+
+// PaymentIntent is a payment the provider prepared, the customer authorizes it with ClientSecret.
+type PaymentIntent struct {
+	Reference    string
+	ClientSecret string
+}
+
+// PaymentEvent is a verified webhook call of the provider about the payment Reference.
+type PaymentEvent struct {
+	Type      string `json:"type"`
+	Reference string `json:"reference"`
+	Amount    int64  `json:"amount"`
+	Reason    string `json:"reason,omitempty"`
+}
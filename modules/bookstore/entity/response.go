@@ -2,6 +2,29 @@ package entity
 
 type UserContextKey struct{}
 
+type UserRoleContextKey struct{}
+
 type ErrorHandleResponse struct {
 	Message string `json:"message"`
 }
+
+// Page describes the slice of a list returned in a paginated envelope.
+type Page struct {
+	Limit          int64 `json:"limit"`
+	Offset         int64 `json:"offset"`
+	Total          int64 `json:"total"`
+	TotalEstimated bool  `json:"total_estimated,omitempty"`
+	HasMore        bool  `json:"has_more"`
+}
+
+type PageLinks struct {
+	Next *string `json:"next"`
+	Prev *string `json:"prev"`
+}
+
+// TotalCount is the number of rows matched by a list request. An estimated count comes from planner statistics
+// and may drift from the exact one.
+type TotalCount struct {
+	Count     int64
+	Estimated bool
+}
@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// Statuses of a return. Staff approve or reject a requested return, an approved return is received once the books
+// are back, which restocks them and refunds the customer. Rejected and received returns are final.
+const (
+	ReturnStatusRequested = "requested"
+	ReturnStatusApproved  = "approved"
+	ReturnStatusRejected  = "rejected"
+	ReturnStatusReceived  = "received"
+)
+
+// Reasons customers give for a return.
+const (
+	ReturnReasonDamaged   = "damaged"
+	ReturnReasonWrongItem = "wrong_item"
+	ReturnReasonOther     = "other"
+)
+
+// Return sends Amount copies of an order item back. RefundAmount is what the customer was refunded once the books
+// were received.
+type Return struct {
+	ID           int64     `json:"id"`
+	OrderID      int64     `json:"order_id"`
+	OrderItemID  int64     `json:"order_item_id"`
+	Amount       int64     `json:"amount"`
+	Reason       string    `json:"reason"`
+	Note         string    `json:"note,omitempty"`
+	Status       string    `json:"status"`
+	StaffNote    string    `json:"staff_note,omitempty"`
+	HandledBy    *int64    `json:"handled_by,omitempty"`
+	RefundAmount int64     `json:"refund_amount"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreateReturnParams requests a return of copies of an item of a delivered order on behalf of the customer who placed
+// it.
+type CreateReturnParams struct {
+	OrderID     int64  `json:"-" validate:"required,gt=0"`
+	UserID      int64  `json:"-" validate:"required,gt=0"`
+	OrderItemID int64  `json:"order_item_id" validate:"required,gt=0"`
+	Amount      int64  `json:"amount" validate:"required,gt=0"`
+	Reason      string `json:"reason" validate:"required,oneof=damaged wrong_item other"`
+	Note        string `json:"note" validate:"max=1000"`
+}
+
+// TransitionReturnParams moves a return to Status on behalf of a staff member.
+type TransitionReturnParams struct {
+	ReturnID  int64  `json:"-" validate:"required,gt=0"`
+	Status    string `json:"status" validate:"required,oneof=approved rejected received"`
+	StaffNote string `json:"staff_note" validate:"max=1000"`
+	ActorID   int64  `json:"-" validate:"required,gt=0"`
+}
+
+// GetReturnsParams lists returns, only those in Status when it is set.
+type GetReturnsParams struct {
+	Status string `validate:"omitempty,oneof=requested approved rejected received"`
+	Limit  int64  `validate:"gt=0,lte=100"`
+	Offset int64  `validate:"gte=0"`
+}
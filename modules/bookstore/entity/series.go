@@ -0,0 +1,22 @@
+package entity
+
+// Series is a run of books read in order. Volumes are sorted by volume number, unnumbered entries last.
+type Series struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Volumes     []Book `json:"volumes,omitempty"`
+}
+
+type CreateSeriesParams struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=10000"`
+}
+
+// SeriesCompletion is a series a customer has bought from, with the volumes still available that they do not own.
+type SeriesCompletion struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	OwnedVolumes   int64  `json:"owned_volumes"`
+	MissingVolumes []Book `json:"missing_volumes"`
+}
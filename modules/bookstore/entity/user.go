@@ -1,8 +1,14 @@
 package entity
 
+const (
+	UserRoleCustomer = "customer"
+	UserRoleAdmin    = "admin"
+)
+
 type User struct {
 	ID    int64
 	Email string
+	Role  string
 }
 
 type CreateUserParam struct {
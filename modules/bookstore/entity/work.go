@@ -0,0 +1,12 @@
+package entity
+
+// Work is a title independent of how it is published. Each of its editions is a Book with its own format, ISBN, price
+// and stock, and is what gets ordered. A work is one volume of its series, whatever edition of it is bought.
+type Work struct {
+	ID          int64       `json:"id"`
+	Title       string      `json:"title"`
+	Authors     string      `json:"authors"`
+	Description string      `json:"description"`
+	Series      *BookSeries `json:"series,omitempty"`
+	Editions    []Book      `json:"editions"`
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// CartTokenHeader carries the token of a guest cart. It is returned with the cart once the guest put a book in it and
+// the guest sends it back with later cart requests. A signed in user sending it takes over the guest cart.
+const CartTokenHeader = "X-Cart-Token"
+
+type CartService interface {
+	GetCart(ctx context.Context, owner entity.CartOwner) (*entity.Cart, error)
+	SetCartItem(ctx context.Context, params entity.SetCartItemParams) (*entity.Cart, error)
+	RemoveCartItem(ctx context.Context, params entity.RemoveCartItemParams) (*entity.Cart, error)
+	ClearCart(ctx context.Context, owner entity.CartOwner) error
+	Checkout(ctx context.Context, owner entity.CartOwner) (*entity.Order, error)
+}
+
+type CartHandler struct {
+	cartService CartService
+}
+
+func NewCartHandler(cartService CartService) *CartHandler {
+	return &CartHandler{
+		cartService: cartService,
+	}
+}
+
+// GetCart returns the cart with the current price and stock of its books, items that changed since they were put in
+// the cart list their issues.
+func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	cart, err := h.cartService.GetCart(r.Context(), cartOwner(r))
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	writeCart(w, cart)
+}
+
+func (h *CartHandler) SetCartItem(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params entity.SetCartItemParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		handleError(errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid"), w)
+		return
+	}
+	params.Owner = cartOwner(r)
+	params.SKU = httprouter.ParamsFromContext(r.Context()).ByName("sku")
+
+	cart, err := h.cartService.SetCartItem(r.Context(), params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	writeCart(w, cart)
+}
+
+func (h *CartHandler) RemoveCartItem(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	cart, err := h.cartService.RemoveCartItem(r.Context(), entity.RemoveCartItemParams{
+		Owner: cartOwner(r),
+		SKU:   httprouter.ParamsFromContext(r.Context()).ByName("sku"),
+	})
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	writeCart(w, cart)
+}
+
+func (h *CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := h.cartService.ClearCart(r.Context(), cartOwner(r)); err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Checkout places an order for the books in the cart of the signed in user and empties the cart.
+func (h *CartHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+	owner := cartOwner(r)
+	if _, err := getUserIDFromContext(ctx); err != nil {
+		handleError(err, w)
+		return
+	}
+
+	order, err := h.cartService.Checkout(ctx, owner)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(order)
+}
+
+// cartOwner names the cart of the request by the signed in user, if any, and the guest cart token.
+func cartOwner(r *http.Request) entity.CartOwner {
+	userID, _ := r.Context().Value(entity.UserContextKey{}).(int64)
+
+	return entity.CartOwner{
+		UserID: userID,
+		Token:  strings.TrimSpace(r.Header.Get(CartTokenHeader)),
+	}
+}
+
+func writeCart(w http.ResponseWriter, cart *entity.Cart) {
+	if cart.Token != "" {
+		w.Header().Set(CartTokenHeader, cart.Token)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(cart)
+}
@@ -0,0 +1,220 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
+	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
+)
+
+type CartHandlerTestSuite struct {
+	suite.Suite
+
+	cartSvc *mock_handler.MockCartService
+}
+
+func (s *CartHandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.cartSvc = mock_handler.NewMockCartService(ctrl)
+}
+
+func TestCartHandler(t *testing.T) {
+	suite.Run(t, new(CartHandlerTestSuite))
+}
+
+func (s *CartHandlerTestSuite) TestGetCart() {
+	s.Run("service error", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/v1/cart/items", nil)
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().GetCart(ctx, entity.CartOwner{}).
+			Return(nil, errorx.ErrInternal("internal server error")).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.GetCart(w, r)
+
+		s.Assert().Equal(http.StatusInternalServerError, w.Result().StatusCode)
+	})
+
+	s.Run("guest cart", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/v1/cart/items", nil)
+		r.Header.Set(handler.CartTokenHeader, "guest-token")
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().GetCart(ctx, entity.CartOwner{Token: "guest-token"}).
+			Return(&entity.Cart{ID: 8, Token: "guest-token", Total: 19000, Items: []entity.CartItem{{
+				SKU:        "BK00000099",
+				Book:       &entity.BookSummary{ID: 99, Name: "Dune", Authors: "Frank Herbert", Status: entity.BookStatusActive},
+				Amount:     2,
+				Price:      9500,
+				AddedPrice: 9000,
+				Stock:      10,
+				Issues:     []string{entity.CartIssuePriceChanged},
+			}}}, nil).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.GetCart(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Equal("guest-token", resp.Header.Get(handler.CartTokenHeader))
+		s.Assert().JSONEq(`{"token":"guest-token","total":19000,"items":[{"sku":"BK00000099",
+			"book":{"id":99,"name":"Dune","authors":"Frank Herbert","status":"active"},
+			"amount":2,"price":9500,"added_price":9000,"issues":["price_changed"]}]}`, string(body))
+	})
+
+	s.Run("user cart", func() {
+		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/v1/cart/items", nil)
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().GetCart(ctx, entity.CartOwner{UserID: 123}).
+			Return(&entity.Cart{ID: 7, UserID: 123, Items: []entity.CartItem{}}, nil).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.GetCart(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Empty(resp.Header.Get(handler.CartTokenHeader))
+		s.Assert().JSONEq(`{"items":[],"total":0}`, string(body))
+	})
+}
+
+func (s *CartHandlerTestSuite) TestSetCartItem() {
+	params := httprouter.Params{{Key: "sku", Value: "BK00000099"}}
+
+	s.Run("invalid body", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		r := httptest.NewRequestWithContext(ctx, http.MethodPut, "http://localhost/v1/cart/items/BK00000099",
+			strings.NewReader(`{"amount":`))
+		w := httptest.NewRecorder()
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.SetCartItem(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("book discontinued", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		r := httptest.NewRequestWithContext(ctx, http.MethodPut, "http://localhost/v1/cart/items/BK00000099",
+			strings.NewReader(`{"amount":2}`))
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().SetCartItem(ctx, entity.SetCartItemParams{SKU: "BK00000099", Amount: 2}).
+			Return(nil, customerror.ErrUnprocessableEntity("book BK00000099 has been discontinued")).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.SetCartItem(w, r)
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	})
+
+	s.Run("first book of a guest", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		r := httptest.NewRequestWithContext(ctx, http.MethodPut, "http://localhost/v1/cart/items/BK00000099",
+			strings.NewReader(`{"amount":2}`))
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().SetCartItem(ctx, entity.SetCartItemParams{SKU: "BK00000099", Amount: 2}).
+			Return(&entity.Cart{ID: 8, Token: "new-token", Items: []entity.CartItem{{SKU: "BK00000099", Amount: 2}}}, nil).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.SetCartItem(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Equal("new-token", resp.Header.Get(handler.CartTokenHeader))
+	})
+}
+
+func (s *CartHandlerTestSuite) TestRemoveCartItem() {
+	s.Run("book not in cart", func() {
+		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(123))
+		ctx = context.WithValue(ctx, httprouter.ParamsKey, httprouter.Params{{Key: "sku", Value: "BK00000099"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodDelete, "http://localhost/v1/cart/items/BK00000099", nil)
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().RemoveCartItem(ctx, entity.RemoveCartItemParams{Owner: entity.CartOwner{UserID: 123}, SKU: "BK00000099"}).
+			Return(nil, errorx.ErrNotFound("cart item cannot be found")).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.RemoveCartItem(w, r)
+
+		s.Assert().Equal(http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func (s *CartHandlerTestSuite) TestClearCart() {
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodDelete, "http://localhost/v1/cart/items", nil)
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().ClearCart(ctx, entity.CartOwner{UserID: 123}).Return(nil).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.ClearCart(w, r)
+
+		s.Assert().Equal(http.StatusNoContent, w.Result().StatusCode)
+	})
+}
+
+func (s *CartHandlerTestSuite) TestCheckout() {
+	s.Run("unauthorized", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/cart/checkout", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.Checkout(w, r)
+
+		s.Assert().Equal(http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	s.Run("cart is empty", func() {
+		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/cart/checkout", nil)
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().Checkout(ctx, entity.CartOwner{UserID: 123}).
+			Return(nil, customerror.ErrUnprocessableEntity("cart is empty")).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.Checkout(w, r)
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/cart/checkout", nil)
+		r.Header.Set(handler.CartTokenHeader, "guest-token")
+		w := httptest.NewRecorder()
+
+		s.cartSvc.EXPECT().Checkout(ctx, entity.CartOwner{UserID: 123, Token: "guest-token"}).
+			Return(&entity.Order{ID: 1, UserID: 123, Status: entity.OrderStatusPendingPayment}, nil).Times(1)
+
+		h := handler.NewCartHandler(s.cartSvc)
+		h.Checkout(w, r)
+
+		s.Assert().Equal(http.StatusCreated, w.Result().StatusCode)
+	})
+}
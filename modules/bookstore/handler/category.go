@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+type CategoryService interface {
+	GetCategoryTree(ctx context.Context) (*entity.CategoryList, error)
+	GetCategory(ctx context.Context, id int64) (*entity.Category, error)
+	CreateCategory(ctx context.Context, params entity.CreateCategoryParams) (*entity.Category, error)
+	SetBookCategories(ctx context.Context, params entity.SetBookCategoriesParams) ([]*entity.Category, error)
+}
+
+type CategoryHandler struct {
+	categoryService CategoryService
+	bookService     BookService
+}
+
+func NewCategoryHandler(categoryService CategoryService, bookService BookService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		bookService:     bookService,
+	}
+}
+
+// GetCategories returns the whole category tree.
+func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	categories, err := h.categoryService.GetCategoryTree(r.Context())
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(categories)
+}
+
+// GetCategoryBooks lists the books of a category and of all its descendants, taking the same query parameters as
+// GET /v1/books.
+func (h *CategoryHandler) GetCategoryBooks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	if _, err = h.categoryService.GetCategory(r.Context(), id); err != nil {
+		handleError(err, w)
+		return
+	}
+
+	writeBookList(w, r, h.bookService, id)
+}
+
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params entity.CreateCategoryParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		handleError(errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid"), w)
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(r.Context(), params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(category)
+}
+
+// SetBookCategories replaces the categories of the book with the ones listed in the body.
+func (h *CategoryHandler) SetBookCategories(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	var params entity.SetBookCategoriesParams
+	if err = json.NewDecoder(r.Body).Decode(&params); err != nil {
+		handleError(errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid"), w)
+		return
+	}
+	params.BookID = id
+
+	categories, err := h.categoryService.SetBookCategories(r.Context(), params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(entity.CategoryList{Data: categories})
+}
@@ -0,0 +1,173 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
+	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
+)
+
+type CategoryHandlerTestSuite struct {
+	suite.Suite
+
+	categorySvc *mock_handler.MockCategoryService
+	bookSvc     *mock_handler.MockBookService
+}
+
+func (s *CategoryHandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.categorySvc = mock_handler.NewMockCategoryService(ctrl)
+	s.bookSvc = mock_handler.NewMockBookService(ctrl)
+}
+
+func TestCategoryHandler(t *testing.T) {
+	suite.Run(t, new(CategoryHandlerTestSuite))
+}
+
+func (s *CategoryHandlerTestSuite) TestGetCategories() {
+	s.Run("successful", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/categories", nil)
+		w := httptest.NewRecorder()
+
+		parentID := int64(1)
+		s.categorySvc.EXPECT().GetCategoryTree(ctx).Return(&entity.CategoryList{Data: []*entity.Category{
+			{ID: 1, Name: "Fiction", Slug: "fiction", Children: []*entity.Category{
+				{ID: 3, ParentID: &parentID, Name: "Mystery", Slug: "mystery"},
+			}},
+		}}, nil).Times(1)
+
+		h := handler.NewCategoryHandler(s.categorySvc, s.bookSvc)
+		h.GetCategories(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`{"data":[{"id":1,"name":"Fiction","slug":"fiction","children":[
+			{"id":3,"parent_id":1,"name":"Mystery","slug":"mystery"}]}]}`, string(body))
+	})
+}
+
+func (s *CategoryHandlerTestSuite) TestGetCategoryBooks() {
+	s.Run("invalid id", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "x"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/categories/x/books", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewCategoryHandler(s.categorySvc, s.bookSvc)
+		h.GetCategoryBooks(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("category not found", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "9"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/categories/9/books", nil)
+		w := httptest.NewRecorder()
+
+		s.categorySvc.EXPECT().GetCategory(ctx, int64(9)).
+			Return(nil, errorx.ErrNotFound("category cannot be found")).Times(1)
+
+		h := handler.NewCategoryHandler(s.categorySvc, s.bookSvc)
+		h.GetCategoryBooks(w, r)
+
+		s.Assert().Equal(http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	s.Run("lists the books of the subtree", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "3"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/categories/3/books?limit=5&format=ebook", nil)
+		w := httptest.NewRecorder()
+
+		s.categorySvc.EXPECT().GetCategory(ctx, int64(3)).
+			Return(&entity.Category{ID: 3, Name: "Mystery", Slug: "mystery"}, nil).Times(1)
+		s.bookSvc.EXPECT().GetBooks(ctx, entity.GetBooksParams{CategoryID: 3, Format: "ebook", Limit: 5}).
+			Return(&entity.BookList{Data: []entity.Book{{ID: 11, Name: "Cozy Corpse"}}}, nil).Times(1)
+
+		h := handler.NewCategoryHandler(s.categorySvc, s.bookSvc)
+		h.GetCategoryBooks(w, r)
+		resp := w.Result()
+
+		var books []entity.Book
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&books))
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Equal([]entity.Book{{ID: 11, Name: "Cozy Corpse"}}, books)
+	})
+}
+
+func (s *CategoryHandlerTestSuite) TestCreateCategory() {
+	s.Run("invalid body", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/categories", strings.NewReader("{"))
+		w := httptest.NewRecorder()
+
+		h := handler.NewCategoryHandler(s.categorySvc, s.bookSvc)
+		h.CreateCategory(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("duplicate category", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/categories",
+			strings.NewReader(`{"name":"Fiction"}`))
+		w := httptest.NewRecorder()
+
+		s.categorySvc.EXPECT().CreateCategory(ctx, entity.CreateCategoryParams{Name: "Fiction"}).
+			Return(nil, customerror.ErrUnprocessableEntity("category already exists")).Times(1)
+
+		h := handler.NewCategoryHandler(s.categorySvc, s.bookSvc)
+		h.CreateCategory(w, r)
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/categories",
+			strings.NewReader(`{"parent_id":1,"name":"Mystery","thema_code":"FF"}`))
+		w := httptest.NewRecorder()
+
+		parentID := int64(1)
+		s.categorySvc.EXPECT().CreateCategory(ctx, entity.CreateCategoryParams{ParentID: &parentID, Name: "Mystery", ThemaCode: "FF"}).
+			Return(&entity.Category{ID: 3, ParentID: &parentID, Name: "Mystery", Slug: "mystery", ThemaCode: "FF"}, nil).Times(1)
+
+		h := handler.NewCategoryHandler(s.categorySvc, s.bookSvc)
+		h.CreateCategory(w, r)
+
+		s.Assert().Equal(http.StatusCreated, w.Result().StatusCode)
+	})
+}
+
+func (s *CategoryHandlerTestSuite) TestSetBookCategories() {
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "7"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodPut, "http://localhost/admin/books/7/categories",
+			strings.NewReader(`{"category_ids":[3]}`))
+		w := httptest.NewRecorder()
+
+		s.categorySvc.EXPECT().SetBookCategories(ctx, entity.SetBookCategoriesParams{BookID: 7, CategoryIDs: []int64{3}}).
+			Return([]*entity.Category{{ID: 3, Name: "Mystery", Slug: "mystery"}}, nil).Times(1)
+
+		h := handler.NewCategoryHandler(s.categorySvc, s.bookSvc)
+		h.SetBookCategories(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`{"data":[{"id":3,"name":"Mystery","slug":"mystery"}]}`, string(body))
+	})
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// MaxCoverBytes bounds the size of an uploaded cover image.
+const MaxCoverBytes = 10 << 20
+
+type CoverService interface {
+	UploadCover(ctx context.Context, bookID int64, r io.Reader) (*entity.Book, error)
+}
+
+type CoverHandler struct {
+	coverService CoverService
+}
+
+func NewCoverHandler(coverService CoverService) *CoverHandler {
+	return &CoverHandler{
+		coverService: coverService,
+	}
+}
+
+// UploadCover replaces the cover of a book with the JPEG or PNG image sent as the raw request body or as the "file"
+// part of a multipart form, and answers with the book and its cover URLs.
+func (h *CoverHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxCoverBytes)
+	body, err := uploadedFile(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+	defer body.Close()
+
+	book, err := h.coverService.UploadCover(r.Context(), id, body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = errorx.ErrInvalidParameter("file is too large")
+		}
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(book)
+}
@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
+	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
+)
+
+type CoverHandlerTestSuite struct {
+	suite.Suite
+
+	coverSvc *mock_handler.MockCoverService
+}
+
+func (s *CoverHandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.coverSvc = mock_handler.NewMockCoverService(ctrl)
+}
+
+func TestCoverHandler(t *testing.T) {
+	suite.Run(t, new(CoverHandlerTestSuite))
+}
+
+func (s *CoverHandlerTestSuite) TestUploadCover() {
+	s.Run("invalid id", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "x"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodPut, "http://localhost/admin/books/x/cover", strings.NewReader("x"))
+		w := httptest.NewRecorder()
+
+		h := handler.NewCoverHandler(s.coverSvc)
+		h.UploadCover(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("rejected image", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "7"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodPut, "http://localhost/admin/books/7/cover", strings.NewReader("GIF89a"))
+		w := httptest.NewRecorder()
+
+		s.coverSvc.EXPECT().UploadCover(ctx, int64(7), gomock.Any()).
+			Return(nil, errorx.ErrInvalidParameter("cover must be a JPEG or PNG image")).Times(1)
+
+		h := handler.NewCoverHandler(s.coverSvc)
+		h.UploadCover(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "7"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodPut, "http://localhost/admin/books/7/cover", strings.NewReader("image"))
+		w := httptest.NewRecorder()
+
+		s.coverSvc.EXPECT().UploadCover(ctx, int64(7), gomock.Any()).
+			Return(&entity.Book{ID: 7, Name: "Dune", Cover: entity.NewBookCover("abc")}, nil).Times(1)
+
+		h := handler.NewCoverHandler(s.coverSvc)
+		h.UploadCover(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`{"id":7,"name":"Dune","authors":"","description":"","category":"","language":"","format":"",
+			"price":0,"stock":0,"cover":{"original":"/v1/covers/abc/original","small":"/v1/covers/abc/small",
+			"medium":"/v1/covers/abc/medium","large":"/v1/covers/abc/large"}}`, string(body))
+	})
+}
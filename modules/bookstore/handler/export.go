@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+var exportContentTypes = map[string]string{
+	entity.ExportFormatCSV:   "text/csv; charset=utf-8",
+	entity.ExportFormatJSONL: "application/x-ndjson",
+}
+
+type ExportService interface {
+	ExportBooks(ctx context.Context, params entity.ExportBooksParams, w io.Writer) (int64, error)
+}
+
+type ExportHandler struct {
+	exportService ExportService
+}
+
+func NewExportHandler(exportService ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportBooks streams the catalog as `?format=csv` (the default) or `?format=jsonl`. `?updated_since=` takes an RFC 3339
+// time or a date and limits the export to books changed since then.
+func (h *ExportHandler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	params, err := parseExportParams(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		handleError(err, w)
+		return
+	}
+
+	out := &exportResponseWriter{ResponseWriter: w}
+	w.Header().Set("Content-Type", exportContentTypes[params.Format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="books-%s.%s"`,
+		time.Now().UTC().Format("20060102"),
+		params.Format,
+	))
+
+	_, err = h.exportService.ExportBooks(r.Context(), params, out)
+	if err == nil {
+		return
+	}
+
+	if out.written {
+		// the status line is gone already, cutting the stream short is the only signal left
+		fmt.Println(errorx.ParseAndWrap(err, "export interrupted").LogError())
+		return
+	}
+
+	w.Header().Del("Content-Disposition")
+	w.Header().Set("Content-Type", "application/json")
+	handleError(err, w)
+}
+
+func parseExportParams(r *http.Request) (entity.ExportBooksParams, error) {
+	query := r.URL.Query()
+	params := entity.ExportBooksParams{Format: strings.ToLower(strings.TrimSpace(query.Get("format")))}
+	if params.Format == "" {
+		params.Format = entity.ExportFormatCSV
+	}
+	if _, exist := exportContentTypes[params.Format]; !exist {
+		return params, errorx.ErrInvalidParameter("format invalid")
+	}
+
+	if raw := strings.TrimSpace(query.Get("updated_since")); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			since, err = time.Parse(time.DateOnly, raw)
+		}
+		if err != nil {
+			return params, errorx.ErrInvalidParameter("updated_since invalid")
+		}
+		params.UpdatedSince = &since
+	}
+
+	return params, nil
+}
+
+// exportResponseWriter remembers whether any of the body went out, after that an error can no longer be reported.
+type exportResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	e.written = true
+	return e.ResponseWriter.Write(p)
+}
@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
+	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
+)
+
+type ExportHandlerTestSuite struct {
+	suite.Suite
+
+	exportSvc *mock_handler.MockExportService
+}
+
+func (s *ExportHandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.exportSvc = mock_handler.NewMockExportService(ctrl)
+}
+
+func TestExportHandler(t *testing.T) {
+	suite.Run(t, new(ExportHandlerTestSuite))
+}
+
+func (s *ExportHandlerTestSuite) TestExportBooks() {
+	s.Run("invalid format", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/admin/books/export?format=xml", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewExportHandler(s.exportSvc)
+		h.ExportBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+		s.Assert().Equal("application/json", resp.Header.Get("Content-Type"))
+	})
+
+	s.Run("invalid updated since", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/admin/books/export?updated_since=yesterday", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewExportHandler(s.exportSvc)
+		h.ExportBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("error before anything was written", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/admin/books/export", nil)
+		w := httptest.NewRecorder()
+
+		s.exportSvc.EXPECT().ExportBooks(ctx, entity.ExportBooksParams{Format: "csv"}, gomock.Any()).
+			Return(int64(0), errors.New("db down")).Times(1)
+
+		h := handler.NewExportHandler(s.exportSvc)
+		h.ExportBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusInternalServerError, resp.StatusCode)
+		s.Assert().Equal("application/json", resp.Header.Get("Content-Type"))
+		s.Assert().Empty(resp.Header.Get("Content-Disposition"))
+	})
+
+	s.Run("successful jsonl with updated since", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/admin/books/export?format=jsonl&updated_since=2024-03-01", nil)
+		w := httptest.NewRecorder()
+
+		since := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+		s.exportSvc.EXPECT().ExportBooks(ctx, entity.ExportBooksParams{Format: "jsonl", UpdatedSince: &since}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ entity.ExportBooksParams, out io.Writer) (int64, error) {
+				_, err := io.WriteString(out, "{\"id\":1}\n")
+				return 1, err
+			}).Times(1)
+
+		h := handler.NewExportHandler(s.exportSvc)
+		h.ExportBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Equal("application/x-ndjson", resp.Header.Get("Content-Type"))
+		s.Assert().Regexp(`^attachment; filename="books-\d{8}\.jsonl"$`, resp.Header.Get("Content-Disposition"))
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		s.Assert().Equal("{\"id\":1}\n", string(rawRespBody))
+	})
+}
@@ -8,20 +8,27 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/julienschmidt/httprouter"
 	"github.com/raymondwongso/gogox/errorx"
 
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
 )
 
 const (
-	DefaultLimit  = 10
-	DefaultOffset = 0
+	DefaultLimit           = 10
+	DefaultOffset          = 0
+	DefaultSuggestionLimit = 5
 )
 
 var HTTPErrorCodeMapping = map[string]int{
-	errorx.CodeInvalidParameter: http.StatusBadRequest,
-	errorx.CodeUnauthorized:     http.StatusUnauthorized,
-	errorx.CodeNotFound:         http.StatusNotFound,
+	errorx.CodeInvalidParameter:          http.StatusBadRequest,
+	errorx.CodeUnauthorized:              http.StatusUnauthorized,
+	errorx.CodeForbidden:                 http.StatusForbidden,
+	errorx.CodeNotFound:                  http.StatusNotFound,
+	customerror.CodePreconditionFailed:   http.StatusPreconditionFailed,
+	customerror.CodePreconditionRequired: http.StatusPreconditionRequired,
+	customerror.CodeUnprocessableEntity:  http.StatusUnprocessableEntity,
 }
 
 type UserService interface {
@@ -29,12 +36,23 @@ type UserService interface {
 }
 
 type BookService interface {
-	GetBooks(ctx context.Context, params entity.GetBooksParams) ([]entity.Book, error)
+	GetBooks(ctx context.Context, params entity.GetBooksParams) (*entity.BookList, error)
+	CountBooks(ctx context.Context, params entity.GetBooksParams, estimate bool) (*entity.TotalCount, error)
+	GetBookFacets(ctx context.Context, params entity.GetBooksParams) (*entity.BookFacets, error)
+	SuggestBooks(ctx context.Context, params entity.SuggestBooksParams) ([]entity.BookSuggestion, error)
+	CreateBook(ctx context.Context, params entity.CreateBookParams) (*entity.Book, error)
+	UpdateBook(ctx context.Context, params entity.UpdateBookParams) (*entity.Book, error)
+	DeleteBook(ctx context.Context, params entity.DeleteBookParams) error
+	GetWork(ctx context.Context, id int64) (*entity.Work, error)
 }
 
 type OrderService interface {
-	GetOrders(ctx context.Context, params entity.GetMyOrdersParams) ([]entity.Order, error)
+	GetOrders(ctx context.Context, params entity.GetMyOrdersParams) (*entity.OrderList, error)
+	CountOrders(ctx context.Context, userID int64) (*entity.TotalCount, error)
 	CreateOrder(ctx context.Context, params entity.CreateOrderParams) (*entity.Order, error)
+	TransitionOrder(ctx context.Context, params entity.TransitionOrderParams) (*entity.Order, error)
+	GetOrder(ctx context.Context, params entity.GetOrderParams) (*entity.Order, error)
+	CancelOrder(ctx context.Context, params entity.CancelOrderParams) (*entity.Order, error)
 }
 
 type RestHandler struct {
@@ -77,7 +95,12 @@ func (h *RestHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 func (h *RestHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+	writeBookList(w, r, h.bookService, 0)
+}
 
+// writeBookList answers a book listing with the paging, filters and facets asked for in the query string. A non zero
+// categoryID narrows the listing to that category and everything below it.
+func writeBookList(w http.ResponseWriter, r *http.Request, bookService BookService, categoryID int64) {
 	limit, offset, err := parseLimitOffset(r)
 	if err != nil {
 		handleError(err, w)
@@ -85,21 +108,215 @@ func (h *RestHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	params := entity.GetBooksParams{
-		Limit:  int64(limit),
-		Offset: int64(offset),
+		Query:      strings.TrimSpace(r.URL.Query().Get("q")),
+		CategoryID: categoryID,
+		Cursor:     strings.TrimSpace(r.URL.Query().Get("cursor")),
+		Limit:      int64(limit),
+		Offset:     int64(offset),
+	}
+
+	if err = parseBookFilters(r, &params); err != nil {
+		handleError(err, w)
+		return
+	}
+
+	withFacets := false
+	if raw := strings.TrimSpace(r.URL.Query().Get("facets")); raw != "" {
+		withFacets, err = strconv.ParseBool(raw)
+		if err != nil {
+			handleError(errorx.ErrInvalidParameter("facets invalid"), w)
+			return
+		}
+	}
+
+	estimateTotal, err := parseTotalMode(r)
+	if err != nil {
+		handleError(err, w)
+		return
 	}
 
 	ctx := r.Context()
-	books, err := h.bookService.GetBooks(ctx, params)
+	books, err := bookService.GetBooks(ctx, params)
 	if err != nil {
 		handleError(err, w)
 		return
 	}
 
+	w.Header().Add("Vary", "Accept")
+	enveloped := wantsPageEnvelope(r)
+
+	// the bare array is kept for clients that paginate by offset and ask for nothing else
+	if !enveloped && !withFacets && !r.URL.Query().Has("cursor") {
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(books.Data)
+		return
+	}
+
+	if enveloped {
+		total, err := bookService.CountBooks(ctx, params, estimateTotal)
+		if err != nil {
+			handleError(err, w)
+			return
+		}
+
+		books.Page = newPage(params.Limit, params.Offset, books.HasMore, total)
+		books.Links = newPageLinks(r, books.Page, books.NextCursor)
+	}
+
+	if withFacets {
+		books.Facets, err = bookService.GetBookFacets(ctx, params)
+		if err != nil {
+			handleError(err, w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(books)
 }
 
+func (h *RestHandler) SuggestBooks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit := DefaultSuggestionLimit
+	if limitRaw := r.URL.Query().Get("limit"); strings.TrimSpace(limitRaw) != "" {
+		var err error
+		limit, err = strconv.Atoi(limitRaw)
+		if err != nil {
+			handleError(errorx.ErrInvalidParameter("limit invalid"), w)
+			return
+		}
+	}
+
+	params := entity.SuggestBooksParams{
+		Prefix: r.URL.Query().Get("prefix"),
+		Limit:  int64(limit),
+	}
+
+	ctx := r.Context()
+	suggestions, err := h.bookService.SuggestBooks(ctx, params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=60")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(suggestions)
+}
+
+func (h *RestHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params entity.CreateBookParams
+	err := json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		err = errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid")
+		handleError(err, w)
+		return
+	}
+
+	book, err := h.bookService.CreateBook(r.Context(), params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", versionETag(book.Version))
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(book)
+}
+
+// UpdateBook patches a book. The If-Match header must carry the ETag of the version being edited.
+func (h *RestHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	if r.Header.Get("If-Match") == "" {
+		handleError(customerror.ErrPreconditionRequired("If-Match header is required"), w)
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	var params entity.UpdateBookParams
+	err = json.NewDecoder(r.Body).Decode(&params)
+	if err != nil {
+		err = errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid")
+		handleError(err, w)
+		return
+	}
+	params.ID = id
+	params.Version = version
+
+	book, err := h.bookService.UpdateBook(r.Context(), params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.Header().Set("ETag", versionETag(book.Version))
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(book)
+}
+
+// DeleteBook discontinues a book. If-Match is optional here, when given the book is only discontinued at that version.
+func (h *RestHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	var version int64
+	if r.Header.Get("If-Match") != "" {
+		version, err = parseIfMatch(r)
+		if err != nil {
+			handleError(err, w)
+			return
+		}
+	}
+
+	err = h.bookService.DeleteBook(r.Context(), entity.DeleteBookParams{ID: id, Version: version})
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWork returns a title with all of its editions, so a client can offer the formats side by side.
+func (h *RestHandler) GetWork(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	work, err := h.bookService.GetWork(r.Context(), id)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(work)
+}
+
 func (h *RestHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -128,6 +345,101 @@ func (h *RestHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(order)
 }
 
+// UpdateOrderStatus moves an order along its lifecycle, recording the signed in user as the actor.
+func (h *RestHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	var params entity.TransitionOrderParams
+	if err = json.NewDecoder(r.Body).Decode(&params); err != nil {
+		handleError(errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid"), w)
+		return
+	}
+
+	ctx := r.Context()
+	params.OrderID = id
+	params.ActorID, err = getUserIDFromContext(ctx)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+	params.ActorRole, _ = ctx.Value(entity.UserRoleContextKey{}).(string)
+
+	order, err := h.orderService.TransitionOrder(ctx, params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(order)
+}
+
+// GetOrder returns an order of the signed in user.
+func (h *RestHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	ctx := r.Context()
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	order, err := h.orderService.GetOrder(ctx, entity.GetOrderParams{
+		OrderID: id,
+		UserID:  userID,
+	})
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(order)
+}
+
+// CancelOrder cancels an order of the signed in user.
+func (h *RestHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	ctx := r.Context()
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	order, err := h.orderService.CancelOrder(ctx, entity.CancelOrderParams{
+		OrderID: id,
+		UserID:  userID,
+	})
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(order)
+}
+
 func (h *RestHandler) GetMyOrders(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -138,6 +450,7 @@ func (h *RestHandler) GetMyOrders(w http.ResponseWriter, r *http.Request) {
 	}
 
 	params := entity.GetMyOrdersParams{
+		Cursor: strings.TrimSpace(r.URL.Query().Get("cursor")),
 		Limit:  int64(limit),
 		Offset: int64(offset),
 	}
@@ -149,14 +462,32 @@ func (h *RestHandler) GetMyOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	books, err := h.orderService.GetOrders(ctx, params)
+	orders, err := h.orderService.GetOrders(ctx, params)
 	if err != nil {
 		handleError(err, w)
 		return
 	}
 
+	w.Header().Add("Vary", "Accept")
+	enveloped := wantsPageEnvelope(r)
+
+	if enveloped {
+		total, err := h.orderService.CountOrders(ctx, params.UserID)
+		if err != nil {
+			handleError(err, w)
+			return
+		}
+
+		orders.Page = newPage(params.Limit, params.Offset, orders.HasMore, total)
+		orders.Links = newPageLinks(r, orders.Page, orders.NextCursor)
+	}
+
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(books)
+	if !enveloped && !r.URL.Query().Has("cursor") {
+		_ = json.NewEncoder(w).Encode(orders.Data)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(orders)
 }
 
 func handleError(err error, w http.ResponseWriter) {
@@ -198,6 +529,83 @@ func parseLimitOffset(r *http.Request) (limit, offset int, err error) {
 	return limit, offset, nil
 }
 
+func parseBookFilters(r *http.Request, params *entity.GetBooksParams) error {
+	query := r.URL.Query()
+	params.Author = strings.TrimSpace(query.Get("author"))
+	params.Category = strings.TrimSpace(query.Get("category"))
+	params.Language = strings.ToLower(strings.TrimSpace(query.Get("language")))
+	params.Format = strings.ToLower(strings.TrimSpace(query.Get("format")))
+	params.Sort = strings.ToLower(strings.TrimSpace(query.Get("sort")))
+
+	priceRanges := []struct {
+		name string
+		dest **int64
+	}{
+		{name: "min_price", dest: &params.MinPrice},
+		{name: "max_price", dest: &params.MaxPrice},
+	}
+	for _, p := range priceRanges {
+		raw := strings.TrimSpace(query.Get(p.name))
+		if raw == "" {
+			continue
+		}
+
+		price, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return errorx.ErrInvalidParameter(p.name + " invalid")
+		}
+		*p.dest = &price
+	}
+
+	if raw := strings.TrimSpace(query.Get("in_stock")); raw != "" {
+		inStock, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errorx.ErrInvalidParameter("in_stock invalid")
+		}
+		params.InStock = inStock
+	}
+
+	if raw := strings.TrimSpace(query.Get("published_year")); raw != "" {
+		year, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return errorx.ErrInvalidParameter("published_year invalid")
+		}
+		params.PublishedYear = int32(year)
+	}
+
+	return nil
+}
+
+// parseID reads the id route parameter.
+func parseID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(httprouter.ParamsFromContext(r.Context()).ByName("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errorx.ErrInvalidParameter("id invalid")
+	}
+
+	return id, nil
+}
+
+func versionETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// parseIfMatch reads the book version from an If-Match header holding a single strong ETag such as "3".
+func parseIfMatch(r *http.Request) (int64, error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	unquoted, err := strconv.Unquote(raw)
+	if err != nil || !strings.HasPrefix(raw, `"`) {
+		return 0, errorx.ErrInvalidParameter("If-Match invalid")
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errorx.ErrInvalidParameter("If-Match invalid")
+	}
+
+	return version, nil
+}
+
 func getUserIDFromContext(ctx context.Context) (int64, error) {
 	userID, ok := ctx.Value(entity.UserContextKey{}).(int64)
 	if !ok || userID == 0 {
@@ -12,8 +12,11 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/raymondwongso/gogox/errorx"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
 	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
@@ -212,7 +215,282 @@ func (s *HandlerTestSuite) TestGetBooks() {
 		}
 
 		s.bookSvc.EXPECT().GetBooks(ctx, params).
-			Return(expectedBooks, nil).Times(1)
+			Return(&entity.BookList{Data: expectedBooks}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(expectedBooks)
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+	s.Run("invalid price filter", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/books?max_price=cheap", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(entity.ErrorHandleResponse{Message: "max_price invalid"})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("filters and sort are passed to service", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/books?author=Ada&category=Programming"+
+			"&language=EN&format=ebook&min_price=1000&max_price=5000&in_stock=true&published_year=2021&sort=price_desc", nil)
+		w := httptest.NewRecorder()
+
+		minPrice, maxPrice := int64(1000), int64(5000)
+		params := entity.GetBooksParams{
+			Author:        "Ada",
+			Category:      "Programming",
+			Language:      "en",
+			Format:        "ebook",
+			MinPrice:      &minPrice,
+			MaxPrice:      &maxPrice,
+			InStock:       true,
+			PublishedYear: 2021,
+			Sort:          entity.BookSortPriceDesc,
+			Limit:         10,
+			Offset:        0,
+		}
+
+		s.bookSvc.EXPECT().GetBooks(ctx, params).
+			Return(&entity.BookList{Data: []entity.Book{}}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("facets are returned with the paginated books", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/books?category=Art&facets=true&limit=1&offset=1", nil)
+		w := httptest.NewRecorder()
+
+		params := entity.GetBooksParams{
+			Category: "Art",
+			Limit:    1,
+			Offset:   1,
+		}
+
+		books := []entity.Book{
+			{
+				ID:       3,
+				Name:     "Catalog of contemporary art",
+				Category: "Art",
+			},
+		}
+		facets := &entity.BookFacets{
+			Categories:  []entity.FacetCount{{Value: "Art", Count: 2}},
+			Languages:   []entity.FacetCount{{Value: "en", Count: 2}},
+			Formats:     []entity.FacetCount{{Value: "hardcover", Count: 2}},
+			PriceRanges: []entity.PriceFacetCount{{Min: 50000, Count: 2}},
+		}
+
+		s.bookSvc.EXPECT().GetBooks(ctx, params).
+			Return(&entity.BookList{Data: books}, nil).Times(1)
+		s.bookSvc.EXPECT().GetBookFacets(ctx, params).
+			Return(facets, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(entity.BookList{Data: books, Facets: facets})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("cursor request returns envelope with next cursor", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/books?sort=title&cursor=&limit=1", nil)
+		w := httptest.NewRecorder()
+
+		params := entity.GetBooksParams{
+			Sort:  entity.BookSortTitle,
+			Limit: 1,
+		}
+		list := &entity.BookList{
+			Data:       []entity.Book{{ID: 3, Name: "A"}},
+			NextCursor: "eyJpIjozfQ",
+		}
+
+		s.bookSvc.EXPECT().GetBooks(ctx, params).
+			Return(list, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(list)
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+		s.Assert().Contains(string(rawRespBody), `"next_cursor":"eyJpIjozfQ"`)
+	})
+
+	s.Run("invalid total mode", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/v2/books?total=roughly", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		handler.WithPageEnvelope(h.GetBooks)(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(entity.ErrorHandleResponse{Message: "total invalid"})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("v2 route returns page envelope with links", func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/v2/books?category=Art&limit=2&offset=2&total=estimated", nil)
+		w := httptest.NewRecorder()
+
+		params := entity.GetBooksParams{
+			Category: "Art",
+			Limit:    2,
+			Offset:   2,
+		}
+		books := []entity.Book{
+			{ID: 3, Name: "Catalog of contemporary art", Category: "Art"},
+			{ID: 4, Name: "Sculpture", Category: "Art"},
+		}
+
+		s.bookSvc.EXPECT().GetBooks(gomock.Any(), params).
+			Return(&entity.BookList{Data: books, NextCursor: "eyJpIjo0fQ", HasMore: true}, nil).Times(1)
+		s.bookSvc.EXPECT().CountBooks(gomock.Any(), params, true).
+			Return(&entity.TotalCount{Count: 9}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		handler.WithPageEnvelope(h.GetBooks)(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+
+		next := "/v2/books?category=Art&limit=2&offset=4&total=estimated"
+		prev := "/v2/books?category=Art&limit=2&offset=0&total=estimated"
+		expected, err := json.Marshal(entity.BookList{
+			Data: books,
+			Page: &entity.Page{
+				Limit:   2,
+				Offset:  2,
+				Total:   9,
+				HasMore: true,
+			},
+			Links: &entity.PageLinks{
+				Next: &next,
+				Prev: &prev,
+			},
+			NextCursor: "eyJpIjo0fQ",
+		})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("accept profile returns page envelope on last page", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/books", nil)
+		r.Header.Set("Accept", `application/json; profile="paginated"`)
+		w := httptest.NewRecorder()
+
+		params := entity.GetBooksParams{
+			Limit:  10,
+			Offset: 0,
+		}
+		books := []entity.Book{{ID: 1, Name: "A"}}
+
+		s.bookSvc.EXPECT().GetBooks(ctx, params).
+			Return(&entity.BookList{Data: books}, nil).Times(1)
+		s.bookSvc.EXPECT().CountBooks(ctx, params, false).
+			Return(&entity.TotalCount{Count: 1}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Equal("Accept", resp.Header.Get("Vary"))
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(entity.BookList{
+			Data: books,
+			Page: &entity.Page{
+				Limit: 10,
+				Total: 1,
+			},
+			Links: &entity.PageLinks{},
+		})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("search query is trimmed and passed to service", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/books?q=%20debuging%20soup%20", nil)
+		w := httptest.NewRecorder()
+
+		params := entity.GetBooksParams{
+			Query:  "debuging soup",
+			Limit:  10,
+			Offset: 0,
+		}
+
+		expectedBooks := []entity.Book{
+			{
+				ID:        1,
+				Name:      "Chicken Soup of Debugging",
+				Highlight: "Chicken <mark>Soup</mark> of Debugging",
+			},
+		}
+
+		s.bookSvc.EXPECT().GetBooks(ctx, params).
+			Return(&entity.BookList{Data: expectedBooks}, nil).Times(1)
 
 		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
 		h.GetBooks(w, r)
@@ -274,6 +552,39 @@ func (s *HandlerTestSuite) TestCreateOrder() {
 		s.JSONEq(string(expected), string(rawRespBody))
 	})
 
+	s.Run("discontinued book", func() {
+		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(123))
+		requestBody := `{"items":[{"book_id":99,"amount":1}]}`
+		params := entity.CreateOrderParams{
+			UserID: 123,
+			Items: []entity.CreateOrderItemParams{
+				{
+					BookID: 99,
+					Amount: 1,
+				},
+			},
+		}
+
+		s.orderSvc.EXPECT().CreateOrder(ctx, params).
+			Return(nil, customerror.ErrUnprocessableEntity("book 99 has been discontinued")).Times(1)
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/orders", strings.NewReader(requestBody))
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.CreateOrder(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(entity.ErrorHandleResponse{Message: "book 99 has been discontinued"})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
 	s.Run("service error", func() {
 		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(123))
 		requestBody := `{"items":[{"book_id":99,"amount":10}]}`
@@ -486,7 +797,7 @@ func (s *HandlerTestSuite) TestGetMyOrders() {
 		}
 
 		s.orderSvc.EXPECT().GetOrders(ctx, params).
-			Return(expectedBooks, nil).Times(1)
+			Return(&entity.OrderList{Data: expectedBooks}, nil).Times(1)
 
 		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
 		h.GetMyOrders(w, r)
@@ -501,4 +812,468 @@ func (s *HandlerTestSuite) TestGetMyOrders() {
 
 		s.JSONEq(string(expected), string(rawRespBody))
 	})
+
+	s.Run("v2 route returns page envelope with cursor link", func() {
+		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(99))
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/v2/orders?cursor=&limit=1", nil)
+		w := httptest.NewRecorder()
+
+		params := entity.GetMyOrdersParams{
+			Limit:  1,
+			UserID: 99,
+		}
+		orders := []entity.Order{{ID: 5, UserID: 99, Items: []entity.OrderItem{}, CreatedAt: now}}
+
+		s.orderSvc.EXPECT().GetOrders(gomock.Any(), params).
+			Return(&entity.OrderList{Data: orders, NextCursor: "eyJpIjo1fQ", HasMore: true}, nil).Times(1)
+		s.orderSvc.EXPECT().CountOrders(gomock.Any(), int64(99)).
+			Return(&entity.TotalCount{Count: 3}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		handler.WithPageEnvelope(h.GetMyOrders)(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+
+		next := "/v2/orders?cursor=eyJpIjo1fQ&limit=1"
+		expected, err := json.Marshal(entity.OrderList{
+			Data: orders,
+			Page: &entity.Page{
+				Limit:   1,
+				Total:   3,
+				HasMore: true,
+			},
+			Links: &entity.PageLinks{
+				Next: &next,
+			},
+			NextCursor: "eyJpIjo1fQ",
+		})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+}
+
+func (s *HandlerTestSuite) TestSuggestBooks() {
+	s.Run("invalid limit", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/books/suggest?prefix=deb&limit=many", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.SuggestBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(entity.ErrorHandleResponse{Message: "limit invalid"})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("successful with default limit", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/books/suggest?prefix=debuging", nil)
+		w := httptest.NewRecorder()
+
+		params := entity.SuggestBooksParams{
+			Prefix: "debuging",
+			Limit:  handler.DefaultSuggestionLimit,
+		}
+
+		expectedSuggestions := []entity.BookSuggestion{
+			{
+				ID:      1,
+				Name:    "Chicken Soup of Debugging",
+				Authors: "Ada Stacktrace",
+			},
+		}
+
+		s.bookSvc.EXPECT().SuggestBooks(ctx, params).
+			Return(expectedSuggestions, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.SuggestBooks(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(expectedSuggestions)
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+}
+
+func (s *HandlerTestSuite) TestCreateBook() {
+	s.Run("error while decoding json request body", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/books", strings.NewReader(`{"name":`))
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.CreateBook(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.Background()
+
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/books",
+			strings.NewReader(`{"name":"Refactoring","language":"en","format":"hardcover","price":4200,"stock":5}`))
+		w := httptest.NewRecorder()
+
+		book := &entity.Book{ID: 7, Name: "Refactoring", Language: "en", Format: "hardcover", Price: 4200, Stock: 5, Version: 1}
+		s.bookSvc.EXPECT().CreateBook(ctx, entity.CreateBookParams{
+			Name:     "Refactoring",
+			Language: "en",
+			Format:   "hardcover",
+			Price:    4200,
+			Stock:    5,
+		}).Return(book, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.CreateBook(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusCreated, resp.StatusCode)
+		s.Assert().Equal(`"1"`, resp.Header.Get("ETag"))
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(book)
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+}
+
+func (s *HandlerTestSuite) TestUpdateBook() {
+	newRequest := func(ifMatch, body string) *http.Request {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "7"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodPatch, "http://localhost/admin/books/7", strings.NewReader(body))
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+		return r
+	}
+
+	s.Run("missing if-match", func() {
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.UpdateBook(w, newRequest("", `{"stock":3}`))
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusPreconditionRequired, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(entity.ErrorHandleResponse{Message: "If-Match header is required"})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("invalid if-match", func() {
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.UpdateBook(w, newRequest(`W/"2"`, `{"stock":3}`))
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("invalid id", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "abc"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodPatch, "http://localhost/admin/books/abc", strings.NewReader(`{}`))
+		r.Header.Set("If-Match", `"2"`)
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.UpdateBook(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("stale version", func() {
+		r := newRequest(`"2"`, `{"stock":3}`)
+		w := httptest.NewRecorder()
+
+		stock := int64(3)
+		s.bookSvc.EXPECT().UpdateBook(r.Context(), entity.UpdateBookParams{ID: 7, Version: 2, Stock: &stock}).
+			Return(nil, customerror.ErrPreconditionFailed("book has been modified")).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.UpdateBook(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusPreconditionFailed, resp.StatusCode)
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(entity.ErrorHandleResponse{Message: "book has been modified"})
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("successful", func() {
+		r := newRequest(`"2"`, `{"stock":3}`)
+		w := httptest.NewRecorder()
+
+		stock := int64(3)
+		book := &entity.Book{ID: 7, Stock: 3, Version: 3}
+		s.bookSvc.EXPECT().UpdateBook(r.Context(), entity.UpdateBookParams{ID: 7, Version: 2, Stock: &stock}).
+			Return(book, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.UpdateBook(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Equal(`"3"`, resp.Header.Get("ETag"))
+
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		expected, err := json.Marshal(book)
+		s.Require().NoError(err)
+
+		s.JSONEq(string(expected), string(rawRespBody))
+	})
+}
+
+func (s *HandlerTestSuite) TestDeleteBook() {
+	s.Run("book not found", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "7"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodDelete, "http://localhost/admin/books/7", nil)
+		w := httptest.NewRecorder()
+
+		s.bookSvc.EXPECT().DeleteBook(ctx, entity.DeleteBookParams{ID: 7}).
+			Return(errorx.ErrNotFound("book cannot be found")).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.DeleteBook(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusNotFound, resp.StatusCode)
+	})
+
+	s.Run("successful with if-match", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "7"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodDelete, "http://localhost/admin/books/7", nil)
+		r.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+
+		s.bookSvc.EXPECT().DeleteBook(ctx, entity.DeleteBookParams{ID: 7, Version: 3}).
+			Return(nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.DeleteBook(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusNoContent, resp.StatusCode)
+	})
+}
+
+func (s *HandlerTestSuite) TestGetWork() {
+	s.Run("work not found", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "9"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/works/9", nil)
+		w := httptest.NewRecorder()
+
+		s.bookSvc.EXPECT().GetWork(ctx, int64(9)).
+			Return(nil, errorx.ErrNotFound("work cannot be found")).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetWork(w, r)
+
+		s.Assert().Equal(http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "3"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/works/3", nil)
+		w := httptest.NewRecorder()
+
+		s.bookSvc.EXPECT().GetWork(ctx, int64(3)).
+			Return(&entity.Work{ID: 3, Title: "Dune", Authors: "Frank Herbert", Editions: []entity.Book{
+				{ID: 4, WorkID: 3, SKU: "BK00000004", Name: "Dune", Format: "ebook", Price: 999},
+			}}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetWork(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`{"id":3,"title":"Dune","authors":"Frank Herbert","description":"","editions":[
+			{"id":4,"work_id":3,"sku":"BK00000004","name":"Dune","authors":"","description":"","category":"",
+			"language":"","format":"ebook","price":999,"stock":0}]}`, string(body))
+	})
+}
+
+func (s *HandlerTestSuite) TestUpdateOrderStatus() {
+	withAdmin := func(id string) context.Context {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: id}})
+		ctx = context.WithValue(ctx, entity.UserContextKey{}, int64(1))
+		return context.WithValue(ctx, entity.UserRoleContextKey{}, entity.UserRoleAdmin)
+	}
+
+	s.Run("invalid body", func() {
+		r := httptest.NewRequestWithContext(withAdmin("3"), http.MethodPost, "http://localhost/admin/orders/3/status", strings.NewReader("{"))
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.UpdateOrderStatus(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("transition not allowed", func() {
+		ctx := withAdmin("3")
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/orders/3/status",
+			strings.NewReader(`{"status":"shipped"}`))
+		w := httptest.NewRecorder()
+
+		s.orderSvc.EXPECT().TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: entity.OrderStatusShipped, ActorID: 1, ActorRole: entity.UserRoleAdmin,
+		}).Return(nil, customerror.ErrUnprocessableEntity("order cannot move from cancelled to shipped")).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.UpdateOrderStatus(w, r)
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := withAdmin("3")
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/orders/3/status",
+			strings.NewReader(`{"status":"shipped"}`))
+		w := httptest.NewRecorder()
+
+		s.orderSvc.EXPECT().TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: entity.OrderStatusShipped, ActorID: 1, ActorRole: entity.UserRoleAdmin,
+		}).Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusShipped}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.UpdateOrderStatus(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`{"id":3,"user_id":5,"status":"shipped","items":null,"created_at":"0001-01-01T00:00:00Z"}`, string(body))
+	})
+}
+
+func (s *HandlerTestSuite) TestGetOrder() {
+	withCustomer := func(id string) context.Context {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: id}})
+		return context.WithValue(ctx, entity.UserContextKey{}, int64(5))
+	}
+
+	s.Run("invalid id", func() {
+		r := httptest.NewRequestWithContext(withCustomer("abc"), http.MethodGet, "http://localhost/orders/abc", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetOrder(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("order of another customer", func() {
+		ctx := withCustomer("3")
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/orders/3", nil)
+		w := httptest.NewRecorder()
+
+		s.orderSvc.EXPECT().GetOrder(ctx, entity.GetOrderParams{OrderID: 3, UserID: 5}).
+			Return(nil, errorx.ErrNotFound("order cannot be found")).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetOrder(w, r)
+
+		s.Assert().Equal(http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := withCustomer("3")
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/orders/3", nil)
+		w := httptest.NewRecorder()
+		price := int64(12000)
+
+		s.orderSvc.EXPECT().GetOrder(ctx, entity.GetOrderParams{OrderID: 3, UserID: 5}).
+			Return(&entity.Order{
+				ID:     3,
+				UserID: 5,
+				Status: entity.OrderStatusPaid,
+				Items: []entity.OrderItem{
+					{ID: 29, OrderID: 3, BookID: 99, Book: &entity.BookSummary{ID: 99, Name: "Dune"}, Amount: 2, Price: &price},
+				},
+			}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.GetOrder(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`{"id":3,"user_id":5,"status":"paid","items":[{"id":29,"order_id":3,"book_id":99,
+			"book":{"id":99,"name":"Dune","authors":"","status":""},"amount":2,"price":12000,
+			"created_at":"0001-01-01T00:00:00Z"}],"created_at":"0001-01-01T00:00:00Z"}`, string(body))
+	})
+}
+
+func (s *HandlerTestSuite) TestCancelOrder() {
+	withCustomer := func(id string) context.Context {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: id}})
+		return context.WithValue(ctx, entity.UserContextKey{}, int64(5))
+	}
+
+	s.Run("order can no longer be cancelled", func() {
+		ctx := withCustomer("3")
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/orders/3/cancel", nil)
+		w := httptest.NewRecorder()
+
+		s.orderSvc.EXPECT().CancelOrder(ctx, entity.CancelOrderParams{OrderID: 3, UserID: 5}).
+			Return(nil, customerror.ErrUnprocessableEntity("order is shipped and can no longer be cancelled")).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.CancelOrder(w, r)
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := withCustomer("3")
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/orders/3/cancel", nil)
+		w := httptest.NewRecorder()
+
+		s.orderSvc.EXPECT().CancelOrder(ctx, entity.CancelOrderParams{OrderID: 3, UserID: 5}).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusCancelled}, nil).Times(1)
+
+		h := handler.NewHandler(s.userSvc, s.bookSvc, s.orderSvc)
+		h.CancelOrder(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`{"id":3,"user_id":5,"status":"cancelled","items":null,"created_at":"0001-01-01T00:00:00Z"}`, string(body))
+	})
 }
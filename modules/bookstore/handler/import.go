@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// MaxImportBytes bounds the size of an uploaded catalog file.
+const MaxImportBytes = 32 << 20
+
+type ImportService interface {
+	ImportBooksCSV(ctx context.Context, r io.Reader, dryRun bool) (*entity.BookImportReport, error)
+}
+
+type ImportHandler struct {
+	importService ImportService
+}
+
+func NewImportHandler(importService ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportBooksCSV accepts the CSV either as the raw request body or as the "file" part of a multipart form, and answers
+// with the import report. `?dry_run=true` validates and counts without committing.
+func (h *ImportHandler) ImportBooksCSV(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			handleError(errorx.ErrInvalidParameter("dry_run invalid"), w)
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxImportBytes)
+	body, err := uploadedFile(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+	defer body.Close()
+
+	report, err := h.importService.ImportBooksCSV(r.Context(), body, dryRun)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = errorx.ErrInvalidParameter("file is too large")
+		}
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// uploadedFile returns the raw request body, or the "file" part when the body is a multipart form.
+func uploadedFile(r *http.Request) (io.ReadCloser, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errorx.ErrInvalidParameter("file is required")
+		}
+		if err != nil {
+			return nil, errorx.ErrInvalidParameter("Input is invalid")
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
+	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
+)
+
+type ImportHandlerTestSuite struct {
+	suite.Suite
+
+	importSvc *mock_handler.MockImportService
+}
+
+func (s *ImportHandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.importSvc = mock_handler.NewMockImportService(ctrl)
+}
+
+func TestImportHandler(t *testing.T) {
+	suite.Run(t, new(ImportHandlerTestSuite))
+}
+
+func (s *ImportHandlerTestSuite) TestImportBooksCSV() {
+	csvFile := "title,isbn,authors,price,stock\nDune,9780306406157,Frank Herbert,15000,4\n"
+	report := &entity.BookImportReport{
+		DryRun:   true,
+		Rows:     1,
+		Inserted: 1,
+		Errors:   []entity.BookImportError{},
+	}
+
+	s.Run("invalid dry run", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/books/import?dry_run=maybe", strings.NewReader(csvFile))
+		w := httptest.NewRecorder()
+
+		h := handler.NewImportHandler(s.importSvc)
+		h.ImportBooksCSV(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+
+	s.Run("successful with raw body", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/books/import?dry_run=true", strings.NewReader(csvFile))
+		r.Header.Set("Content-Type", "text/csv")
+		w := httptest.NewRecorder()
+
+		s.importSvc.EXPECT().ImportBooksCSV(ctx, gomock.Any(), true).
+			DoAndReturn(func(_ context.Context, body io.Reader, _ bool) (*entity.BookImportReport, error) {
+				raw, err := io.ReadAll(body)
+				s.Require().NoError(err)
+				s.Assert().Equal(csvFile, string(raw))
+				return report, nil
+			}).Times(1)
+
+		h := handler.NewImportHandler(s.importSvc)
+		h.ImportBooksCSV(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+
+		expected, err := json.Marshal(report)
+		s.Require().NoError(err)
+		rawRespBody, err := io.ReadAll(resp.Body)
+		s.Require().NoError(err)
+		s.Assert().JSONEq(string(expected), string(rawRespBody))
+	})
+
+	s.Run("successful with multipart file", func() {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		s.Require().NoError(form.WriteField("note", "weekly feed"))
+		part, err := form.CreateFormFile("file", "books.csv")
+		s.Require().NoError(err)
+		_, err = part.Write([]byte(csvFile))
+		s.Require().NoError(err)
+		s.Require().NoError(form.Close())
+
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/books/import", &body)
+		r.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		s.importSvc.EXPECT().ImportBooksCSV(ctx, gomock.Any(), false).
+			DoAndReturn(func(_ context.Context, body io.Reader, _ bool) (*entity.BookImportReport, error) {
+				raw, err := io.ReadAll(body)
+				s.Require().NoError(err)
+				s.Assert().Equal(csvFile, string(raw))
+				return report, nil
+			}).Times(1)
+
+		h := handler.NewImportHandler(s.importSvc)
+		h.ImportBooksCSV(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("multipart without file", func() {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		s.Require().NoError(form.WriteField("note", "weekly feed"))
+		s.Require().NoError(form.Close())
+
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/books/import", &body)
+		r.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		h := handler.NewImportHandler(s.importSvc)
+		h.ImportBooksCSV(w, r)
+		resp := w.Result()
+
+		s.Assert().Equal(http.StatusBadRequest, resp.StatusCode)
+	})
+}
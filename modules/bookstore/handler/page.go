@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// PaginatedProfile is the Accept profile that asks list endpoints for the paginated envelope, e.g.
+// `Accept: application/json; profile="paginated"`.
+const PaginatedProfile = "paginated"
+
+const (
+	TotalExact     = "exact"
+	TotalEstimated = "estimated"
+)
+
+type pageEnvelopeKey struct{}
+
+// WithPageEnvelope makes the list handler behind it always answer with the paginated envelope, used by the v2 routes.
+func WithPageEnvelope(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), pageEnvelopeKey{}, true)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func wantsPageEnvelope(r *http.Request) bool {
+	if enveloped, _ := r.Context().Value(pageEnvelopeKey{}).(bool); enveloped {
+		return true
+	}
+
+	for _, accept := range strings.Split(strings.Join(r.Header.Values("Accept"), ","), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+
+		for _, profile := range strings.Fields(params["profile"]) {
+			if profile == PaginatedProfile {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func parseTotalMode(r *http.Request) (estimate bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("total"))) {
+	case "", TotalExact:
+		return false, nil
+	case TotalEstimated:
+		return true, nil
+	default:
+		return false, errorx.ErrInvalidParameter("total invalid")
+	}
+}
+
+func newPage(limit, offset int64, hasMore bool, total *entity.TotalCount) *entity.Page {
+	return &entity.Page{
+		Limit:          limit,
+		Offset:         offset,
+		Total:          total.Count,
+		TotalEstimated: total.Estimated,
+		HasMore:        hasMore,
+	}
+}
+
+// newPageLinks builds the next and previous page links from the request URL. A request paginated by cursor only
+// links forward since keyset cursors cannot be walked back.
+func newPageLinks(r *http.Request, page *entity.Page, nextCursor string) *entity.PageLinks {
+	links := &entity.PageLinks{}
+	byCursor := r.URL.Query().Has("cursor")
+
+	if page.HasMore {
+		query := r.URL.Query()
+		if byCursor {
+			query.Set("cursor", nextCursor)
+		} else {
+			query.Set("offset", strconv.FormatInt(page.Offset+page.Limit, 10))
+		}
+		query.Set("limit", strconv.FormatInt(page.Limit, 10))
+		next := r.URL.Path + "?" + query.Encode()
+		links.Next = &next
+	}
+
+	if !byCursor && page.Offset > 0 {
+		query := r.URL.Query()
+		query.Set("offset", strconv.FormatInt(max(page.Offset-page.Limit, 0), 10))
+		query.Set("limit", strconv.FormatInt(page.Limit, 10))
+		prev := r.URL.Path + "?" + query.Encode()
+		links.Prev = &prev
+	}
+
+	return links
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// PaymentSignatureHeader carries the signature of a webhook call of the payment provider.
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+// MaxWebhookBytes bounds the size of a webhook call of the payment provider.
+const MaxWebhookBytes = 64 << 10
+
+type PaymentService interface {
+	PayOrder(ctx context.Context, params entity.PayOrderParams) (*entity.Payment, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+}
+
+type PaymentHandler struct {
+	paymentService PaymentService
+}
+
+func NewPaymentHandler(paymentService PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// PayOrder starts paying an order of the signed in user and answers with the payment attempt, the client finishes the
+// payment with the provider using its client secret.
+func (h *PaymentHandler) PayOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	ctx := r.Context()
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	payment, err := h.paymentService.PayOrder(ctx, entity.PayOrderParams{
+		OrderID: id,
+		UserID:  userID,
+	})
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(payment)
+}
+
+// Webhook receives the payment events of the provider. Calls without a valid signature are refused.
+func (h *PaymentHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxWebhookBytes))
+	if err != nil {
+		handleError(errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid"), w)
+		return
+	}
+
+	err = h.paymentService.HandleWebhook(r.Context(), payload, r.Header.Get(PaymentSignatureHeader))
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
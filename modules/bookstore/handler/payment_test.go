@@ -0,0 +1,124 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
+	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
+)
+
+type PaymentHandlerTestSuite struct {
+	suite.Suite
+
+	paymentSvc *mock_handler.MockPaymentService
+}
+
+func (s *PaymentHandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.paymentSvc = mock_handler.NewMockPaymentService(ctrl)
+}
+
+func TestPaymentHandler(t *testing.T) {
+	suite.Run(t, new(PaymentHandlerTestSuite))
+}
+
+func (s *PaymentHandlerTestSuite) TestPayOrder() {
+	params := httprouter.Params{{Key: "id", Value: "3"}}
+
+	s.Run("unauthorized", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/orders/3/pay", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewPaymentHandler(s.paymentSvc)
+		h.PayOrder(w, r)
+
+		s.Assert().Equal(http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	s.Run("order cannot be paid", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		ctx = context.WithValue(ctx, entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/orders/3/pay", nil)
+		w := httptest.NewRecorder()
+
+		s.paymentSvc.EXPECT().PayOrder(ctx, entity.PayOrderParams{OrderID: 3, UserID: 123}).
+			Return(nil, customerror.ErrUnprocessableEntity("order is cancelled and cannot be paid")).Times(1)
+
+		h := handler.NewPaymentHandler(s.paymentSvc)
+		h.PayOrder(w, r)
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		ctx = context.WithValue(ctx, entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/orders/3/pay", nil)
+		w := httptest.NewRecorder()
+
+		s.paymentSvc.EXPECT().PayOrder(ctx, entity.PayOrderParams{OrderID: 3, UserID: 123}).
+			Return(&entity.Payment{
+				ID:           11,
+				OrderID:      3,
+				Provider:     "mock",
+				Reference:    "pi_3",
+				Amount:       20000,
+				Status:       entity.PaymentStatusPending,
+				ClientSecret: "pi_3_secret",
+			}, nil).Times(1)
+
+		h := handler.NewPaymentHandler(s.paymentSvc)
+		h.PayOrder(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusCreated, resp.StatusCode)
+		s.Assert().Contains(string(body), `"client_secret":"pi_3_secret"`)
+	})
+}
+
+func (s *PaymentHandlerTestSuite) TestWebhook() {
+	payload := `{"type":"payment.authorized","reference":"pi_3","amount":20000}`
+
+	s.Run("invalid signature", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/payments/webhook", strings.NewReader(payload))
+		r.Header.Set(handler.PaymentSignatureHeader, "forged")
+		w := httptest.NewRecorder()
+
+		s.paymentSvc.EXPECT().HandleWebhook(ctx, []byte(payload), "forged").
+			Return(errorx.ErrUnauthorized("webhook signature invalid")).Times(1)
+
+		h := handler.NewPaymentHandler(s.paymentSvc)
+		h.Webhook(w, r)
+
+		s.Assert().Equal(http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/payments/webhook", strings.NewReader(payload))
+		r.Header.Set(handler.PaymentSignatureHeader, "signature")
+		w := httptest.NewRecorder()
+
+		s.paymentSvc.EXPECT().HandleWebhook(ctx, []byte(payload), "signature").Return(nil).Times(1)
+
+		h := handler.NewPaymentHandler(s.paymentSvc)
+		h.Webhook(w, r)
+
+		s.Assert().Equal(http.StatusNoContent, w.Result().StatusCode)
+	})
+}
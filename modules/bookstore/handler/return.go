@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+type ReturnService interface {
+	RequestReturn(ctx context.Context, params entity.CreateReturnParams) (*entity.Return, error)
+	GetReturns(ctx context.Context, params entity.GetReturnsParams) ([]entity.Return, error)
+	TransitionReturn(ctx context.Context, params entity.TransitionReturnParams) (*entity.Return, error)
+}
+
+type ReturnHandler struct {
+	returnService ReturnService
+}
+
+func NewReturnHandler(returnService ReturnService) *ReturnHandler {
+	return &ReturnHandler{
+		returnService: returnService,
+	}
+}
+
+// RequestReturn requests a return of copies of an item of a delivered order of the signed in user.
+func (h *ReturnHandler) RequestReturn(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	var params entity.CreateReturnParams
+	if err = json.NewDecoder(r.Body).Decode(&params); err != nil {
+		handleError(errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid"), w)
+		return
+	}
+
+	ctx := r.Context()
+	params.OrderID = id
+	params.UserID, err = getUserIDFromContext(ctx)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	ret, err := h.returnService.RequestReturn(ctx, params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(ret)
+}
+
+// GetReturns lists returns oldest first for staff, only those in the status query parameter when it is given.
+func (h *ReturnHandler) GetReturns(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	limit, offset, err := parseLimitOffset(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	returns, err := h.returnService.GetReturns(r.Context(), entity.GetReturnsParams{
+		Status: strings.TrimSpace(r.URL.Query().Get("status")),
+		Limit:  int64(limit),
+		Offset: int64(offset),
+	})
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(returns)
+}
+
+// TransitionReturn approves, rejects or receives a return on behalf of the signed in staff member.
+func (h *ReturnHandler) TransitionReturn(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	var params entity.TransitionReturnParams
+	if err = json.NewDecoder(r.Body).Decode(&params); err != nil {
+		handleError(errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid"), w)
+		return
+	}
+
+	ctx := r.Context()
+	params.ReturnID = id
+	params.ActorID, err = getUserIDFromContext(ctx)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	ret, err := h.returnService.TransitionReturn(ctx, params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(ret)
+}
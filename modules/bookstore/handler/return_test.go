@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
+	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
+)
+
+type ReturnHandlerTestSuite struct {
+	suite.Suite
+
+	returnSvc *mock_handler.MockReturnService
+}
+
+func (s *ReturnHandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.returnSvc = mock_handler.NewMockReturnService(ctrl)
+}
+
+func TestReturnHandler(t *testing.T) {
+	suite.Run(t, new(ReturnHandlerTestSuite))
+}
+
+func (s *ReturnHandlerTestSuite) TestRequestReturn() {
+	params := httprouter.Params{{Key: "id", Value: "3"}}
+	payload := `{"order_item_id":8,"amount":2,"reason":"damaged","note":"cover torn"}`
+
+	s.Run("invalid body", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		ctx = context.WithValue(ctx, entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/orders/3/returns", strings.NewReader("{"))
+		w := httptest.NewRecorder()
+
+		h := handler.NewReturnHandler(s.returnSvc)
+		h.RequestReturn(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("unauthorized", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/orders/3/returns", strings.NewReader(payload))
+		w := httptest.NewRecorder()
+
+		h := handler.NewReturnHandler(s.returnSvc)
+		h.RequestReturn(w, r)
+
+		s.Assert().Equal(http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	s.Run("too many copies", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		ctx = context.WithValue(ctx, entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/orders/3/returns", strings.NewReader(payload))
+		w := httptest.NewRecorder()
+
+		s.returnSvc.EXPECT().RequestReturn(ctx, entity.CreateReturnParams{
+			OrderID:     3,
+			UserID:      123,
+			OrderItemID: 8,
+			Amount:      2,
+			Reason:      entity.ReturnReasonDamaged,
+			Note:        "cover torn",
+		}).Return(nil, customerror.ErrUnprocessableEntity("only 1 copies of the item can still be returned")).Times(1)
+
+		h := handler.NewReturnHandler(s.returnSvc)
+		h.RequestReturn(w, r)
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		ctx = context.WithValue(ctx, entity.UserContextKey{}, int64(123))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/orders/3/returns", strings.NewReader(payload))
+		w := httptest.NewRecorder()
+
+		s.returnSvc.EXPECT().RequestReturn(ctx, entity.CreateReturnParams{
+			OrderID:     3,
+			UserID:      123,
+			OrderItemID: 8,
+			Amount:      2,
+			Reason:      entity.ReturnReasonDamaged,
+			Note:        "cover torn",
+		}).Return(&entity.Return{ID: 11, OrderID: 3, OrderItemID: 8, Amount: 2, Status: entity.ReturnStatusRequested}, nil).Times(1)
+
+		h := handler.NewReturnHandler(s.returnSvc)
+		h.RequestReturn(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusCreated, resp.StatusCode)
+		s.Assert().Contains(string(body), `"status":"requested"`)
+	})
+}
+
+func (s *ReturnHandlerTestSuite) TestGetReturns() {
+	s.Run("invalid limit", func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/v1/admin/returns?limit=abc", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewReturnHandler(s.returnSvc)
+		h.GetReturns(w, r)
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/v1/admin/returns?status=requested", nil)
+		w := httptest.NewRecorder()
+
+		s.returnSvc.EXPECT().GetReturns(gomock.Any(), entity.GetReturnsParams{
+			Status: entity.ReturnStatusRequested,
+			Limit:  handler.DefaultLimit,
+			Offset: handler.DefaultOffset,
+		}).Return([]entity.Return{{ID: 11, Status: entity.ReturnStatusRequested}}, nil).Times(1)
+
+		h := handler.NewReturnHandler(s.returnSvc)
+		h.GetReturns(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Contains(string(body), `"id":11`)
+	})
+}
+
+func (s *ReturnHandlerTestSuite) TestTransitionReturn() {
+	params := httprouter.Params{{Key: "id", Value: "11"}}
+	payload := `{"status":"approved","staff_note":"send it back"}`
+
+	s.Run("return not found", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		ctx = context.WithValue(ctx, entity.UserContextKey{}, int64(2))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/admin/returns/11/status", strings.NewReader(payload))
+		w := httptest.NewRecorder()
+
+		s.returnSvc.EXPECT().TransitionReturn(ctx, entity.TransitionReturnParams{
+			ReturnID:  11,
+			Status:    entity.ReturnStatusApproved,
+			StaffNote: "send it back",
+			ActorID:   2,
+		}).Return(nil, errorx.ErrNotFound("return cannot be found")).Times(1)
+
+		h := handler.NewReturnHandler(s.returnSvc)
+		h.TransitionReturn(w, r)
+
+		s.Assert().Equal(http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, params)
+		ctx = context.WithValue(ctx, entity.UserContextKey{}, int64(2))
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/admin/returns/11/status", strings.NewReader(payload))
+		w := httptest.NewRecorder()
+
+		actorID := int64(2)
+		s.returnSvc.EXPECT().TransitionReturn(ctx, entity.TransitionReturnParams{
+			ReturnID:  11,
+			Status:    entity.ReturnStatusApproved,
+			StaffNote: "send it back",
+			ActorID:   2,
+		}).Return(&entity.Return{ID: 11, Status: entity.ReturnStatusApproved, StaffNote: "send it back", HandledBy: &actorID}, nil).Times(1)
+
+		h := handler.NewReturnHandler(s.returnSvc)
+		h.TransitionReturn(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().Contains(string(body), `"status":"approved"`)
+	})
+}
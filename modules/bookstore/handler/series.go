@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+type SeriesService interface {
+	GetSeries(ctx context.Context, id int64) (*entity.Series, error)
+	CreateSeries(ctx context.Context, params entity.CreateSeriesParams) (*entity.Series, error)
+	GetSeriesToComplete(ctx context.Context, userID int64) ([]entity.SeriesCompletion, error)
+}
+
+type SeriesHandler struct {
+	seriesService SeriesService
+}
+
+func NewSeriesHandler(seriesService SeriesService) *SeriesHandler {
+	return &SeriesHandler{
+		seriesService: seriesService,
+	}
+}
+
+// GetSeries returns a series with its volumes in reading order.
+func (h *SeriesHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := parseID(r)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	series, err := h.seriesService.GetSeries(r.Context(), id)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(series)
+}
+
+func (h *SeriesHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var params entity.CreateSeriesParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		handleError(errorx.Wrap(err, errorx.CodeInvalidParameter, "Input is invalid"), w)
+		return
+	}
+
+	series, err := h.seriesService.CreateSeries(r.Context(), params)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(series)
+}
+
+// GetSeriesToComplete lists, for the series the user has bought from, the volumes they are still missing.
+func (h *SeriesHandler) GetSeriesToComplete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx := r.Context()
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	completions, err := h.seriesService.GetSeriesToComplete(ctx, userID)
+	if err != nil {
+		handleError(err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(completions)
+}
@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/handler"
+	mock_handler "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/handler"
+)
+
+type SeriesHandlerTestSuite struct {
+	suite.Suite
+
+	seriesSvc *mock_handler.MockSeriesService
+}
+
+func (s *SeriesHandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.seriesSvc = mock_handler.NewMockSeriesService(ctrl)
+}
+
+func TestSeriesHandler(t *testing.T) {
+	suite.Run(t, new(SeriesHandlerTestSuite))
+}
+
+func (s *SeriesHandlerTestSuite) TestGetSeries() {
+	s.Run("series not found", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "9"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/series/9", nil)
+		w := httptest.NewRecorder()
+
+		s.seriesSvc.EXPECT().GetSeries(ctx, int64(9)).
+			Return(nil, errorx.ErrNotFound("series cannot be found")).Times(1)
+
+		h := handler.NewSeriesHandler(s.seriesSvc)
+		h.GetSeries(w, r)
+
+		s.Assert().Equal(http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "2"}})
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/series/2", nil)
+		w := httptest.NewRecorder()
+
+		s.seriesSvc.EXPECT().GetSeries(ctx, int64(2)).
+			Return(&entity.Series{ID: 2, Name: "Foundation", Volumes: []entity.Book{
+				{ID: 4, Name: "Foundation", Series: &entity.BookSeries{ID: 2, Volume: 1}},
+			}}, nil).Times(1)
+
+		h := handler.NewSeriesHandler(s.seriesSvc)
+		h.GetSeries(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`{"id":2,"name":"Foundation","description":"","volumes":[{"id":4,"name":"Foundation",
+			"authors":"","description":"","category":"","language":"","format":"","price":0,"stock":0,
+			"series":{"id":2,"volume":1}}]}`, string(body))
+	})
+}
+
+func (s *SeriesHandlerTestSuite) TestCreateSeries() {
+	s.Run("successful", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/admin/series",
+			strings.NewReader(`{"name":"Discworld"}`))
+		w := httptest.NewRecorder()
+
+		s.seriesSvc.EXPECT().CreateSeries(ctx, entity.CreateSeriesParams{Name: "Discworld"}).
+			Return(&entity.Series{ID: 3, Name: "Discworld"}, nil).Times(1)
+
+		h := handler.NewSeriesHandler(s.seriesSvc)
+		h.CreateSeries(w, r)
+
+		s.Assert().Equal(http.StatusCreated, w.Result().StatusCode)
+	})
+}
+
+func (s *SeriesHandlerTestSuite) TestGetSeriesToComplete() {
+	s.Run("unauthorized", func() {
+		ctx := context.Background()
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/users/me/incomplete-series", nil)
+		w := httptest.NewRecorder()
+
+		h := handler.NewSeriesHandler(s.seriesSvc)
+		h.GetSeriesToComplete(w, r)
+
+		s.Assert().Equal(http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	s.Run("successful", func() {
+		ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(1))
+		r := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/users/me/incomplete-series", nil)
+		w := httptest.NewRecorder()
+
+		s.seriesSvc.EXPECT().GetSeriesToComplete(ctx, int64(1)).
+			Return([]entity.SeriesCompletion{{ID: 2, Name: "Foundation", OwnedVolumes: 1, MissingVolumes: []entity.Book{}}}, nil).Times(1)
+
+		h := handler.NewSeriesHandler(s.seriesSvc)
+		h.GetSeriesToComplete(w, r)
+		resp := w.Result()
+
+		body, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusOK, resp.StatusCode)
+		s.Assert().JSONEq(`[{"id":2,"name":"Foundation","owned_volumes":1,"missing_volumes":[]}]`, string(body))
+	})
+}
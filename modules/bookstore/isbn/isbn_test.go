@@ -0,0 +1,55 @@
+package isbn_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/isbn"
+)
+
+type ISBNTestSuite struct {
+	suite.Suite
+}
+
+func TestISBN(t *testing.T) {
+	suite.Run(t, new(ISBNTestSuite))
+}
+
+func (s *ISBNTestSuite) TestNormalize() {
+	s.Run("isbn-13 with hyphens", func() {
+		result, err := isbn.Normalize("978-0-13-468599-1")
+		s.Require().NoError(err)
+		s.Assert().Equal("9780134685991", result)
+	})
+
+	s.Run("isbn-10 is converted to isbn-13", func() {
+		result, err := isbn.Normalize("0-201-63361-2")
+		s.Require().NoError(err)
+		s.Assert().Equal("9780201633610", result)
+	})
+
+	s.Run("isbn-10 with x check digit", func() {
+		result, err := isbn.Normalize("080442957x")
+		s.Require().NoError(err)
+		s.Assert().Equal("9780804429573", result)
+	})
+
+	s.Run("wrong check digit", func() {
+		result, err := isbn.Normalize("9780134685992")
+		s.Assert().Empty(result)
+		s.Assert().ErrorIs(err, isbn.ErrInvalidISBN)
+	})
+
+	s.Run("not digits", func() {
+		result, err := isbn.Normalize("97801346859ab")
+		s.Assert().Empty(result)
+		s.Assert().ErrorIs(err, isbn.ErrInvalidISBN)
+	})
+
+	s.Run("wrong length", func() {
+		result, err := isbn.Normalize("12345")
+		s.Assert().Empty(result)
+		s.Assert().ErrorIs(err, isbn.ErrInvalidISBN)
+	})
+}
@@ -52,8 +52,37 @@ func (m *Auth) CheckTokenMiddleware(next http.HandlerFunc) http.HandlerFunc {
 
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, entity.UserContextKey{}, user.ID)
+		ctx = context.WithValue(ctx, entity.UserRoleContextKey{}, user.Role)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
 	}
 }
+
+// OptionalTokenMiddleware authenticates the request like CheckTokenMiddleware when it has an Authorization header and
+// lets requests without one through as guests. A token that is sent but invalid is still rejected.
+func (m *Auth) OptionalTokenMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	authenticated := m.CheckTokenMiddleware(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimSpace(r.Header.Get("Authorization")) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authenticated.ServeHTTP(w, r)
+	}
+}
+
+// RequireRoleMiddleware authenticates the request like CheckTokenMiddleware and only lets users with the given role through.
+func (m *Auth) RequireRoleMiddleware(role string, next http.HandlerFunc) http.HandlerFunc {
+	return m.CheckTokenMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		userRole, _ := r.Context().Value(entity.UserRoleContextKey{}).(string)
+		if userRole != role {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(entity.ErrorHandleResponse{Message: "Forbidden"})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -151,3 +151,129 @@ func (s *MiddlewareTestSuite) TestCheckToken() {
 		assert.JSONEq(s.T(), string(expected), string(rawRespBody))
 	})
 }
+
+func (s *MiddlewareTestSuite) TestRequireRole() {
+	middleware := middleware.NewAuthMiddleware(s.userRepo)
+
+	s.Run("user without role", func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test-middleware", nil)
+		r.Header.Set("Authorization", "Bearer sometoken")
+		w := httptest.NewRecorder()
+
+		s.userRepo.EXPECT().FindUserByToken(context.Background(), "sometoken").
+			Return(&entity.User{ID: 123, Role: entity.UserRoleCustomer}, nil).Times(1)
+
+		router := httprouter.New()
+
+		handlerFunc := func(_ http.ResponseWriter, _ *http.Request) {
+			s.Fail("handler must not be called")
+		}
+
+		router.HandlerFunc(http.MethodGet, "/test-middleware", middleware.RequireRoleMiddleware(entity.UserRoleAdmin, handlerFunc))
+		router.ServeHTTP(w, r)
+		resp := w.Result()
+
+		assert.Equal(s.T(), http.StatusForbidden, resp.StatusCode)
+		rawRespBody, err := io.ReadAll(resp.Body)
+		require.NoError(s.T(), err)
+		expected, err := json.Marshal(map[string]string{"message": "Forbidden"})
+		require.NoError(s.T(), err)
+
+		assert.JSONEq(s.T(), string(expected), string(rawRespBody))
+	})
+
+	s.Run("user with role", func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test-middleware", nil)
+		r.Header.Set("Authorization", "Bearer sometoken")
+		w := httptest.NewRecorder()
+
+		s.userRepo.EXPECT().FindUserByToken(context.Background(), "sometoken").
+			Return(&entity.User{ID: 123, Role: entity.UserRoleAdmin}, nil).Times(1)
+
+		router := httprouter.New()
+
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			role, ok := r.Context().Value(entity.UserRoleContextKey{}).(string)
+			require.True(s.T(), ok)
+			assert.Equal(s.T(), entity.UserRoleAdmin, role)
+
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+		router.HandlerFunc(http.MethodGet, "/test-middleware", middleware.RequireRoleMiddleware(entity.UserRoleAdmin, handlerFunc))
+		router.ServeHTTP(w, r)
+		resp := w.Result()
+
+		assert.Equal(s.T(), http.StatusNoContent, resp.StatusCode)
+	})
+}
+
+func (s *MiddlewareTestSuite) TestOptionalToken() {
+	middleware := middleware.NewAuthMiddleware(s.userRepo)
+
+	s.Run("guest", func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test-middleware", nil)
+		w := httptest.NewRecorder()
+
+		router := httprouter.New()
+
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			_, ok := r.Context().Value(entity.UserContextKey{}).(int64)
+			assert.False(s.T(), ok)
+
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+		router.HandlerFunc(http.MethodGet, "/test-middleware", middleware.OptionalTokenMiddleware(handlerFunc))
+		router.ServeHTTP(w, r)
+		resp := w.Result()
+
+		assert.Equal(s.T(), http.StatusNoContent, resp.StatusCode)
+	})
+
+	s.Run("invalid token", func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test-middleware", nil)
+		r.Header.Set("Authorization", "Bearer sometoken")
+		w := httptest.NewRecorder()
+
+		s.userRepo.EXPECT().FindUserByToken(context.Background(), "sometoken").
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		router := httprouter.New()
+
+		handlerFunc := func(_ http.ResponseWriter, _ *http.Request) {
+			s.Fail("handler must not be called")
+		}
+
+		router.HandlerFunc(http.MethodGet, "/test-middleware", middleware.OptionalTokenMiddleware(handlerFunc))
+		router.ServeHTTP(w, r)
+		resp := w.Result()
+
+		assert.Equal(s.T(), http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	s.Run("signed in user", func() {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/test-middleware", nil)
+		r.Header.Set("Authorization", "Bearer sometoken")
+		w := httptest.NewRecorder()
+
+		s.userRepo.EXPECT().FindUserByToken(context.Background(), "sometoken").
+			Return(&entity.User{ID: 123, Role: entity.UserRoleCustomer}, nil).Times(1)
+
+		router := httprouter.New()
+
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value(entity.UserContextKey{}).(int64)
+			require.True(s.T(), ok)
+			assert.Equal(s.T(), int64(123), userID)
+
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+		router.HandlerFunc(http.MethodGet, "/test-middleware", middleware.OptionalTokenMiddleware(handlerFunc))
+		router.ServeHTTP(w, r)
+		resp := w.Result()
+
+		assert.Equal(s.T(), http.StatusNoContent, resp.StatusCode)
+	})
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request with the same key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	MaxIdempotencyKeyLength  = 255
+	// MaxIdempotentBodyBytes limits the body kept in memory to fingerprint a request.
+	MaxIdempotentBodyBytes = 1 << 20
+)
+
+// errIdempotencyKeyReleased is returned when the key was released every time the request holding it was looked up.
+var errIdempotencyKeyReleased = errors.New("idempotency key was released")
+
+type IdempotencyRepo interface {
+	ReserveIdempotencyKey(ctx context.Context, key entity.IdempotencyKey) (bool, error)
+	FindIdempotencyKey(ctx context.Context, userID int64, key string) (*entity.IdempotencyKey, error)
+	SaveIdempotencyResponse(ctx context.Context, key entity.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, key entity.IdempotencyKey) error
+}
+
+type Idempotency struct {
+	repo  IdempotencyRepo
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// NewIdempotencyMiddleware remembers responses for ttl, a key can be used for a new request once it expired. A request
+// holds its key for lease while it is handled, a retry takes the key over when the request died without releasing it,
+// so lease must be longer than a request takes.
+func NewIdempotencyMiddleware(repo IdempotencyRepo, ttl, lease time.Duration) *Idempotency {
+	return &Idempotency{
+		repo:  repo,
+		ttl:   ttl,
+		lease: lease,
+	}
+}
+
+// IdempotencyMiddleware answers a request retried with the same Idempotency-Key with the response of the first one
+// instead of handling it again. The key is scoped to the user, so it must run behind CheckTokenMiddleware. Reusing a
+// key for a different request is answered with 422 and a retry sent while the first request is still being handled
+// with 409 and a Retry-After. Replays carry the headers of the first response. Requests without the header are handled
+// as usual.
+func (m *Idempotency) IdempotencyMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(IdempotencyKeyHeader))
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if len(key) > MaxIdempotencyKeyLength {
+			writeMessage(w, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		userID, ok := r.Context().Value(entity.UserContextKey{}).(int64)
+		if !ok {
+			writeMessage(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxIdempotentBodyBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeMessage(w, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+
+			writeMessage(w, http.StatusBadRequest, "Input is invalid")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		lease, err := newLease()
+		if err != nil {
+			writeMessage(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+
+		ctx := r.Context()
+		reserved := entity.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Fingerprint: fingerprint(r, body),
+			Lease:       lease,
+			ExpiresAt:   time.Now().Add(m.lease),
+		}
+
+		stored, err := m.reserve(ctx, reserved)
+		if err != nil {
+			if errors.Is(err, errIdempotencyKeyReleased) {
+				writeRetry(w, "a request with this Idempotency-Key was just released, retry it")
+				return
+			}
+
+			writeMessage(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		if stored != nil {
+			replay(w, reserved, stored)
+			return
+		}
+
+		// the response is kept even when the client went away, its retry is answered from it
+		ctx = context.WithoutCancel(ctx)
+		defer func() {
+			// a panicking handler releases the key before the panic goes on, so its retry does not wait for the lease
+			if p := recover(); p != nil {
+				_ = m.repo.DeleteIdempotencyKey(ctx, reserved)
+				panic(p)
+			}
+		}()
+
+		rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			// a failure on our side may pass on retry, the key is released for it
+			_ = m.repo.DeleteIdempotencyKey(ctx, reserved)
+			return
+		}
+
+		reserved.StatusCode = rec.status
+		reserved.Header = rec.header
+		if reserved.Header == nil {
+			reserved.Header = rec.Header().Clone()
+		}
+		reserved.Response = rec.body.Bytes()
+		reserved.ExpiresAt = time.Now().Add(m.ttl)
+		_ = m.repo.SaveIdempotencyResponse(ctx, reserved)
+	}
+}
+
+// reserve claims the key for the request, or returns the request holding it. A key released by a failed request
+// between the two lookups is free again, it is claimed on a second try.
+func (m *Idempotency) reserve(ctx context.Context, key entity.IdempotencyKey) (*entity.IdempotencyKey, error) {
+	for try := 0; try < 2; try++ {
+		ok, err := m.repo.ReserveIdempotencyKey(ctx, key)
+		if err != nil || ok {
+			return nil, err
+		}
+
+		stored, err := m.repo.FindIdempotencyKey(ctx, key.UserID, key.Key)
+		if !customerror.IsErrNotFound(err) {
+			return stored, err
+		}
+	}
+
+	return nil, errIdempotencyKeyReleased
+}
+
+func replay(w http.ResponseWriter, reserved entity.IdempotencyKey, stored *entity.IdempotencyKey) {
+	if stored.Fingerprint != reserved.Fingerprint {
+		writeMessage(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+		return
+	}
+
+	if stored.StatusCode == 0 {
+		writeRetry(w, "a request with this Idempotency-Key is still being handled")
+		return
+	}
+
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(stored.StatusCode)
+	_, _ = w.Write(stored.Response)
+}
+
+// newLease returns a random token identifying the request holding a key.
+func newLease() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// fingerprint identifies the request a key was used for, by its method, path and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	_, _ = h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeRetry answers a request that can be sent again with the same key in a moment.
+func writeRetry(w http.ResponseWriter, message string) {
+	w.Header().Set("Retry-After", "1")
+	writeMessage(w, http.StatusConflict, message)
+}
+
+func writeMessage(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(entity.ErrorHandleResponse{Message: message})
+}
+
+// recordingWriter passes the response through and keeps a copy of it, with the headers as they were sent.
+type recordingWriter struct {
+	http.ResponseWriter
+
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.header = w.Header().Clone()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.header == nil {
+		w.header = w.Header().Clone()
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
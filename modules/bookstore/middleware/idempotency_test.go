@@ -0,0 +1,310 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/middleware"
+	mock_middleware "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/middleware"
+)
+
+type IdempotencyTestSuite struct {
+	suite.Suite
+
+	repo *mock_middleware.MockIdempotencyRepo
+}
+
+func (s *IdempotencyTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_middleware.NewMockIdempotencyRepo(ctrl)
+}
+
+func TestIdempotency(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
+
+func (s *IdempotencyTestSuite) TestIdempotencyMiddleware() {
+	m := middleware.NewIdempotencyMiddleware(s.repo, time.Hour, time.Minute)
+	ctx := context.WithValue(context.Background(), entity.UserContextKey{}, int64(123))
+
+	newRequest := func(key, body string) *http.Request {
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/v1/orders", strings.NewReader(body))
+		if key != "" {
+			r.Header.Set(middleware.IdempotencyKeyHeader, key)
+		}
+		return r
+	}
+
+	calls := 0
+	createOrder := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		s.Assert().Equal(`{"items":[{"sku":"BK00000099","amount":1}]}`, string(body))
+
+		w.Header().Set("Location", "/v1/orders/1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}
+	body := `{"items":[{"sku":"BK00000099","amount":1}]}`
+
+	// reserve captures the key reserved by the first request, later requests are compared against it
+	var first entity.IdempotencyKey
+	reserve := func(_ context.Context, key entity.IdempotencyKey) (bool, error) {
+		first = key
+		return true, nil
+	}
+
+	s.Run("without key", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("", body))
+
+		s.Assert().Equal(http.StatusCreated, w.Result().StatusCode)
+		s.Assert().Equal(1, calls)
+	})
+
+	s.Run("key too long", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest(strings.Repeat("k", 256), body))
+
+		s.Assert().Equal(http.StatusBadRequest, w.Result().StatusCode)
+		s.Assert().Zero(calls)
+	})
+
+	s.Run("first request keeps its response", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).DoAndReturn(reserve).Times(1)
+		s.repo.EXPECT().SaveIdempotencyResponse(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.IdempotencyKey) error {
+				s.Assert().Equal(first.Lease, key.Lease)
+				s.Assert().Equal(http.StatusCreated, key.StatusCode)
+				s.Assert().Equal([]string{"/v1/orders/1"}, key.Header["Location"])
+				s.Assert().Equal(`{"id":1}`, string(key.Response))
+				s.Assert().WithinDuration(time.Now().Add(time.Hour), key.ExpiresAt, time.Second)
+				return nil
+			}).Times(1)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-1", body))
+
+		s.Assert().Equal(http.StatusCreated, w.Result().StatusCode)
+		s.Assert().Equal(1, calls)
+		s.Assert().Equal(int64(123), first.UserID)
+		s.Assert().Equal("retry-1", first.Key)
+		s.Assert().Len(first.Lease, 32)
+		s.Assert().WithinDuration(time.Now().Add(time.Minute), first.ExpiresAt, time.Second)
+	})
+
+	s.Run("retry takes an abandoned key over under its own lease", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		var retried entity.IdempotencyKey
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.IdempotencyKey) (bool, error) {
+				retried = key
+				return true, nil
+			}).Times(1)
+		s.repo.EXPECT().SaveIdempotencyResponse(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.IdempotencyKey) error {
+				s.Assert().Equal(retried.Lease, key.Lease)
+				return nil
+			}).Times(1)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-1", body))
+
+		s.Assert().Equal(http.StatusCreated, w.Result().StatusCode)
+		s.Assert().Equal(1, calls)
+		s.Assert().Equal(first.Fingerprint, retried.Fingerprint)
+		s.Assert().NotEqual(first.Lease, retried.Lease)
+	})
+
+	s.Run("replay returns the first response", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.IdempotencyKey) (bool, error) {
+				s.Assert().Equal(first.Fingerprint, key.Fingerprint)
+				return false, nil
+			}).Times(1)
+		s.repo.EXPECT().FindIdempotencyKey(ctx, int64(123), "retry-1").
+			Return(&entity.IdempotencyKey{
+				UserID:      123,
+				Key:         "retry-1",
+				Fingerprint: first.Fingerprint,
+				StatusCode:  http.StatusCreated,
+				Header:      map[string][]string{"Location": {"/v1/orders/1"}, "X-Cart-Token": {"cart-token"}},
+				Response:    []byte(`{"id":1}`),
+			}, nil).Times(1)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-1", body))
+		resp := w.Result()
+
+		respBody, _ := io.ReadAll(resp.Body)
+		s.Assert().Equal(http.StatusCreated, resp.StatusCode)
+		s.Assert().Equal("true", resp.Header.Get(middleware.IdempotentReplayedHeader))
+		s.Assert().Equal("/v1/orders/1", resp.Header.Get("Location"))
+		s.Assert().Equal("cart-token", resp.Header.Get("X-Cart-Token"))
+		s.Assert().Equal(`{"id":1}`, string(respBody))
+		s.Assert().Zero(calls)
+	})
+
+	s.Run("same key with a different body", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).Return(false, nil).Times(1)
+		s.repo.EXPECT().FindIdempotencyKey(ctx, int64(123), "retry-1").
+			Return(&entity.IdempotencyKey{UserID: 123, Key: "retry-1", Fingerprint: first.Fingerprint, StatusCode: http.StatusCreated}, nil).Times(1)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-1", `{"items":[{"sku":"BK00000099","amount":2}]}`))
+
+		s.Assert().Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+		s.Assert().Zero(calls)
+	})
+
+	s.Run("first request still in flight", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).Return(false, nil).Times(1)
+		s.repo.EXPECT().FindIdempotencyKey(ctx, int64(123), "retry-1").
+			Return(&entity.IdempotencyKey{UserID: 123, Key: "retry-1", Fingerprint: first.Fingerprint}, nil).Times(1)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-1", body))
+
+		s.Assert().Equal(http.StatusConflict, w.Result().StatusCode)
+		s.Assert().Equal("1", w.Result().Header.Get("Retry-After"))
+		s.Assert().Zero(calls)
+	})
+
+	s.Run("key released by the first request meanwhile is taken", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		notFound := errorx.ErrNotFound("idempotency key cannot be found")
+		taken := s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).Return(false, nil).Times(1)
+		released := s.repo.EXPECT().FindIdempotencyKey(ctx, int64(123), "retry-1").Return(nil, notFound).After(taken).Times(1)
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).Return(true, nil).After(released).Times(1)
+		s.repo.EXPECT().SaveIdempotencyResponse(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-1", body))
+
+		s.Assert().Equal(http.StatusCreated, w.Result().StatusCode)
+		s.Assert().Equal(1, calls)
+	})
+
+	s.Run("key released on every lookup asks for a retry", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		notFound := errorx.ErrNotFound("idempotency key cannot be found")
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).Return(false, nil).Times(2)
+		s.repo.EXPECT().FindIdempotencyKey(ctx, int64(123), "retry-1").Return(nil, notFound).Times(2)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-1", body))
+
+		s.Assert().Equal(http.StatusConflict, w.Result().StatusCode)
+		s.Assert().Equal("1", w.Result().Header.Get("Retry-After"))
+		s.Assert().Zero(calls)
+	})
+
+	s.Run("find key got repo error", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).Return(false, nil).Times(1)
+		s.repo.EXPECT().FindIdempotencyKey(ctx, int64(123), "retry-1").Return(nil, errors.New("repo error")).Times(1)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-1", body))
+
+		s.Assert().Equal(http.StatusInternalServerError, w.Result().StatusCode)
+		s.Assert().Zero(calls)
+	})
+
+	s.Run("server error releases the key", func() {
+		w := httptest.NewRecorder()
+		failing := func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		var reserved entity.IdempotencyKey
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.IdempotencyKey) (bool, error) {
+				reserved = key
+				return true, nil
+			}).Times(1)
+		s.repo.EXPECT().DeleteIdempotencyKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.IdempotencyKey) error {
+				s.Assert().Equal(reserved, key)
+				return nil
+			}).Times(1)
+
+		m.IdempotencyMiddleware(failing)(w, newRequest("retry-2", body))
+
+		s.Assert().Equal(http.StatusInternalServerError, w.Result().StatusCode)
+		s.Assert().Equal("retry-2", reserved.Key)
+	})
+
+	s.Run("panicking handler releases the key", func() {
+		w := httptest.NewRecorder()
+		panicking := func(http.ResponseWriter, *http.Request) {
+			panic("handler failed")
+		}
+
+		var reserved entity.IdempotencyKey
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.IdempotencyKey) (bool, error) {
+				reserved = key
+				return true, nil
+			}).Times(1)
+		s.repo.EXPECT().DeleteIdempotencyKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.IdempotencyKey) error {
+				s.Assert().Equal(reserved, key)
+				return nil
+			}).Times(1)
+
+		s.Assert().PanicsWithValue("handler failed", func() {
+			m.IdempotencyMiddleware(panicking)(w, newRequest("retry-4", body))
+		})
+		s.Assert().Equal("retry-4", reserved.Key)
+	})
+
+	s.Run("body too large", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-5", strings.Repeat("a", middleware.MaxIdempotentBodyBytes+1)))
+
+		s.Assert().Equal(http.StatusRequestEntityTooLarge, w.Result().StatusCode)
+		s.Assert().Zero(calls)
+	})
+
+	s.Run("reserve key got repo error", func() {
+		calls = 0
+		w := httptest.NewRecorder()
+
+		s.repo.EXPECT().ReserveIdempotencyKey(ctx, gomock.Any()).Return(false, errors.New("repo error")).Times(1)
+
+		m.IdempotencyMiddleware(createOrder)(w, newRequest("retry-3", body))
+
+		s.Assert().Equal(http.StatusInternalServerError, w.Result().StatusCode)
+		s.Assert().Zero(calls)
+	})
+}
@@ -0,0 +1,399 @@
+// Package onix stream-reads ONIX for Books 3.0 messages written with reference tag names.
+package onix
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	NotificationDelete = "05"
+
+	ProductIDTypeISBN10 = "02"
+	ProductIDTypeGTIN13 = "03"
+	ProductIDTypeISBN13 = "15"
+
+	ContributorRoleAuthor = "A01"
+	TitleTypeDistinctive  = "01"
+	TitleLevelProduct     = "01"
+	LanguageRoleText      = "01"
+	TextTypeShort         = "02"
+	TextTypeDescription   = "03"
+	DateRolePublication   = "01"
+
+	SubjectSchemeBISAC = "10"
+	SubjectSchemeThema = "93"
+)
+
+var (
+	ErrNotONIX            = errors.New("onix message root element not found")
+	ErrShortTags          = errors.New("onix short tags are not supported, use reference tags")
+	ErrUnsupportedRelease = errors.New("onix release not supported, only 3.x is")
+)
+
+// Product is the subset of an ONIX Product record that the catalog reads. Everything is kept as text so that one bad
+// value cannot stop the decoder in the middle of a record.
+type Product struct {
+	// Line is where the Product element starts in the message.
+	Line             int              `xml:"-"`
+	RecordReference  string           `xml:"RecordReference"`
+	NotificationType string           `xml:"NotificationType"`
+	Identifiers      []Identifier     `xml:"ProductIdentifier"`
+	ProductForm      string           `xml:"DescriptiveDetail>ProductForm"`
+	Titles           []TitleDetail    `xml:"DescriptiveDetail>TitleDetail"`
+	Contributors     []Contributor    `xml:"DescriptiveDetail>Contributor"`
+	Languages        []Language       `xml:"DescriptiveDetail>Language"`
+	Subjects         []Subject        `xml:"DescriptiveDetail>Subject"`
+	TextContents     []TextContent    `xml:"CollateralDetail>TextContent"`
+	PublishingStatus string           `xml:"PublishingDetail>PublishingStatus"`
+	PublishingDates  []PublishingDate `xml:"PublishingDetail>PublishingDate"`
+	Supplies         []SupplyDetail   `xml:"ProductSupply>SupplyDetail"`
+}
+
+type Identifier struct {
+	Type  string `xml:"ProductIDType"`
+	Value string `xml:"IDValue"`
+}
+
+type TitleDetail struct {
+	Type     string         `xml:"TitleType"`
+	Elements []TitleElement `xml:"TitleElement"`
+}
+
+type TitleElement struct {
+	Level         string `xml:"TitleElementLevel"`
+	Text          string `xml:"TitleText"`
+	Prefix        string `xml:"TitlePrefix"`
+	WithoutPrefix string `xml:"TitleWithoutPrefix"`
+}
+
+type Contributor struct {
+	SequenceNumber string   `xml:"SequenceNumber"`
+	Roles          []string `xml:"ContributorRole"`
+	PersonName     string   `xml:"PersonName"`
+	NamesBeforeKey string   `xml:"NamesBeforeKey"`
+	KeyNames       string   `xml:"KeyNames"`
+	CorporateName  string   `xml:"CorporateName"`
+}
+
+type Language struct {
+	Role string `xml:"LanguageRole"`
+	Code string `xml:"LanguageCode"`
+}
+
+type Subject struct {
+	MainSubject *struct{} `xml:"MainSubject"`
+	Scheme      string    `xml:"SubjectSchemeIdentifier"`
+	Code        string    `xml:"SubjectCode"`
+	HeadingText string    `xml:"SubjectHeadingText"`
+}
+
+type TextContent struct {
+	Type  string `xml:"TextType"`
+	Texts []Text `xml:"Text"`
+}
+
+type Text struct {
+	Format string `xml:"textformat,attr"`
+	Value  string `xml:",innerxml"`
+}
+
+type PublishingDate struct {
+	Role string `xml:"PublishingDateRole"`
+	Date Date   `xml:"Date"`
+}
+
+type Date struct {
+	Format string `xml:"dateformat,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type SupplyDetail struct {
+	Availability string  `xml:"ProductAvailability"`
+	Stocks       []Stock `xml:"Stock"`
+	Prices       []Price `xml:"Price"`
+}
+
+type Stock struct {
+	OnHand string `xml:"OnHand"`
+}
+
+type Price struct {
+	Type     string `xml:"PriceType"`
+	Amount   string `xml:"PriceAmount"`
+	Currency string `xml:"CurrencyCode"`
+}
+
+// Reader yields the Product records of a message one at a time, so a feed never has to fit in memory.
+type Reader struct {
+	decoder *xml.Decoder
+	started bool
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{decoder: xml.NewDecoder(r)}
+}
+
+// Next returns the next Product, or io.EOF once the message is exhausted. Any other error means the message cannot be
+// read any further.
+func (r *Reader) Next() (*Product, error) {
+	for {
+		token, err := r.decoder.Token()
+		if errors.Is(err, io.EOF) {
+			if !r.started {
+				return nil, ErrNotONIX
+			}
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if !r.started {
+			if err = checkRoot(start); err != nil {
+				return nil, err
+			}
+			r.started = true
+			continue
+		}
+
+		if start.Name.Local != "Product" {
+			continue
+		}
+
+		line, _ := r.decoder.InputPos()
+		var product Product
+		if err = r.decoder.DecodeElement(&product, &start); err != nil {
+			return nil, err
+		}
+		product.Line = line
+
+		return &product, nil
+	}
+}
+
+func checkRoot(start xml.StartElement) error {
+	switch start.Name.Local {
+	case "ONIXMessage":
+	case "ONIXmessage":
+		return ErrShortTags
+	default:
+		return ErrNotONIX
+	}
+
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "release" && !strings.HasPrefix(attr.Value, "3.") {
+			return fmt.Errorf("%w: %s", ErrUnsupportedRelease, attr.Value)
+		}
+	}
+
+	return nil
+}
+
+// Deleted reports whether the record is a delete notification.
+func (p *Product) Deleted() bool {
+	return strings.TrimSpace(p.NotificationType) == NotificationDelete
+}
+
+// ISBN returns the raw ISBN of the product, preferring ISBN-13 over a 978/979 GTIN and then ISBN-10.
+func (p *Product) ISBN() string {
+	byType := make(map[string]string, len(p.Identifiers))
+	for _, id := range p.Identifiers {
+		byType[strings.TrimSpace(id.Type)] = strings.TrimSpace(id.Value)
+	}
+
+	if value := byType[ProductIDTypeISBN13]; value != "" {
+		return value
+	}
+	if value := byType[ProductIDTypeGTIN13]; strings.HasPrefix(value, "978") || strings.HasPrefix(value, "979") {
+		return value
+	}
+
+	return byType[ProductIDTypeISBN10]
+}
+
+// Title returns the distinctive title of the product.
+func (p *Product) Title() string {
+	for _, title := range p.Titles {
+		if strings.TrimSpace(title.Type) != TitleTypeDistinctive {
+			continue
+		}
+		for _, element := range title.Elements {
+			if strings.TrimSpace(element.Level) != TitleLevelProduct {
+				continue
+			}
+			if text := collapse(element.Text); text != "" {
+				return text
+			}
+			return collapse(element.Prefix + " " + element.WithoutPrefix)
+		}
+	}
+
+	return ""
+}
+
+// Authors returns the names of the A01 contributors in their sequence order.
+func (p *Product) Authors() []string {
+	contributors := make([]Contributor, 0, len(p.Contributors))
+	for _, c := range p.Contributors {
+		for _, role := range c.Roles {
+			if strings.TrimSpace(role) == ContributorRoleAuthor {
+				contributors = append(contributors, c)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		a, _ := strconv.Atoi(strings.TrimSpace(contributors[i].SequenceNumber))
+		b, _ := strconv.Atoi(strings.TrimSpace(contributors[j].SequenceNumber))
+		return a < b
+	})
+
+	authors := make([]string, 0, len(contributors))
+	for _, c := range contributors {
+		name := collapse(c.PersonName)
+		if name == "" {
+			name = collapse(c.NamesBeforeKey + " " + c.KeyNames)
+		}
+		if name == "" {
+			name = collapse(c.CorporateName)
+		}
+		if name != "" {
+			authors = append(authors, name)
+		}
+	}
+
+	return authors
+}
+
+// Language returns the ISO 639-2/B code of the language of the text.
+func (p *Product) Language() string {
+	for _, language := range p.Languages {
+		if strings.TrimSpace(language.Role) == LanguageRoleText {
+			return strings.ToLower(strings.TrimSpace(language.Code))
+		}
+	}
+
+	return ""
+}
+
+// MainSubject returns the subject flagged as main, or the first one when none is.
+func (p *Product) MainSubject() *Subject {
+	if len(p.Subjects) == 0 {
+		return nil
+	}
+
+	for i := range p.Subjects {
+		if p.Subjects[i].MainSubject != nil {
+			return &p.Subjects[i]
+		}
+	}
+
+	return &p.Subjects[0]
+}
+
+// SubjectCodes returns the distinct codes the product is classified under in scheme, the main subject first.
+func (p *Product) SubjectCodes(scheme string) []string {
+	var codes []string
+	seen := make(map[string]bool)
+	for _, subject := range p.Subjects {
+		code := strings.ToUpper(strings.TrimSpace(subject.Code))
+		if strings.TrimSpace(subject.Scheme) != scheme || code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		if subject.MainSubject != nil {
+			codes = append([]string{code}, codes...)
+			continue
+		}
+		codes = append(codes, code)
+	}
+
+	return codes
+}
+
+// Description returns the plain text of the main description, falling back to the short description.
+func (p *Product) Description() string {
+	for _, textType := range []string{TextTypeDescription, TextTypeShort} {
+		for _, content := range p.TextContents {
+			if strings.TrimSpace(content.Type) != textType || len(content.Texts) == 0 {
+				continue
+			}
+			return plainText(content.Texts[0].Value)
+		}
+	}
+
+	return ""
+}
+
+// PublicationDate returns the raw publication date and its ONIX dateformat code.
+func (p *Product) PublicationDate() (value, format string) {
+	for _, date := range p.PublishingDates {
+		if strings.TrimSpace(date.Role) == DateRolePublication {
+			return strings.TrimSpace(date.Date.Value), strings.TrimSpace(date.Date.Format)
+		}
+	}
+
+	return "", ""
+}
+
+// Supply returns the supply detail quoting a price in currency, or the first one when currency is empty.
+func (p *Product) Supply(currency string) *SupplyDetail {
+	for i := range p.Supplies {
+		if currency == "" || p.Supplies[i].Price(currency) != nil {
+			return &p.Supplies[i]
+		}
+	}
+
+	return nil
+}
+
+// Price returns the price quoted in currency, or the first price when currency is empty.
+func (s *SupplyDetail) Price(currency string) *Price {
+	for i := range s.Prices {
+		if currency == "" || strings.EqualFold(strings.TrimSpace(s.Prices[i].Currency), currency) {
+			return &s.Prices[i]
+		}
+	}
+
+	return nil
+}
+
+// plainText strips the markup a text may carry, whether raw XHTML, CDATA or escaped HTML, and collapses its whitespace.
+func plainText(raw string) string {
+	raw = strings.ReplaceAll(raw, "<![CDATA[", "")
+	raw = strings.ReplaceAll(raw, "]]>", "")
+	raw = html.UnescapeString(raw)
+
+	var b strings.Builder
+	inTag := false
+	for _, r := range raw {
+		switch {
+		case r == '<':
+			inTag = true
+			b.WriteRune(' ')
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+
+	return collapse(b.String())
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
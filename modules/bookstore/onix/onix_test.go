@@ -0,0 +1,164 @@
+package onix_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/onix"
+)
+
+const message = `<?xml version="1.0" encoding="UTF-8"?>
+<ONIXMessage release="3.0" xmlns="http://ns.editeur.org/onix/3.0/reference">
+  <Header>
+    <Sender><SenderName>Acme Publishing</SenderName></Sender>
+  </Header>
+  <Product>
+    <RecordReference>acme-0001</RecordReference>
+    <NotificationType>03</NotificationType>
+    <ProductIdentifier><ProductIDType>01</ProductIDType><IDValue>ACME-0001</IDValue></ProductIdentifier>
+    <ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>9780306406157</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <ProductForm>BB</ProductForm>
+      <TitleDetail>
+        <TitleType>01</TitleType>
+        <TitleElement>
+          <TitleElementLevel>01</TitleElementLevel>
+          <TitlePrefix>The</TitlePrefix>
+          <TitleWithoutPrefix>Long Voyage</TitleWithoutPrefix>
+        </TitleElement>
+      </TitleDetail>
+      <Contributor>
+        <SequenceNumber>2</SequenceNumber>
+        <ContributorRole>A01</ContributorRole>
+        <NamesBeforeKey>Jane</NamesBeforeKey>
+        <KeyNames>Doe</KeyNames>
+      </Contributor>
+      <Contributor>
+        <SequenceNumber>3</SequenceNumber>
+        <ContributorRole>B01</ContributorRole>
+        <PersonName>Edna Editor</PersonName>
+      </Contributor>
+      <Contributor>
+        <SequenceNumber>1</SequenceNumber>
+        <ContributorRole>A01</ContributorRole>
+        <PersonName>John Smith</PersonName>
+      </Contributor>
+      <Language><LanguageRole>01</LanguageRole><LanguageCode>eng</LanguageCode></Language>
+      <Subject>
+        <SubjectSchemeIdentifier>93</SubjectSchemeIdentifier>
+        <SubjectCode>FBA</SubjectCode>
+      </Subject>
+      <Subject>
+        <MainSubject/>
+        <SubjectSchemeIdentifier>10</SubjectSchemeIdentifier>
+        <SubjectCode>FIC047000</SubjectCode>
+        <SubjectHeadingText>Fiction / Sea Stories</SubjectHeadingText>
+      </Subject>
+    </DescriptiveDetail>
+    <CollateralDetail>
+      <TextContent>
+        <TextType>03</TextType>
+        <Text textformat="05"><p>A <b>long</b> voyage &amp; a short stay.</p></Text>
+      </TextContent>
+    </CollateralDetail>
+    <PublishingDetail>
+      <PublishingStatus>04</PublishingStatus>
+      <PublishingDate>
+        <PublishingDateRole>01</PublishingDateRole>
+        <Date dateformat="00">20240315</Date>
+      </PublishingDate>
+    </PublishingDetail>
+    <ProductSupply>
+      <SupplyDetail>
+        <ProductAvailability>21</ProductAvailability>
+        <Stock><OnHand>12</OnHand></Stock>
+        <Price><PriceType>02</PriceType><PriceAmount>24.99</PriceAmount><CurrencyCode>USD</CurrencyCode></Price>
+        <Price><PriceType>02</PriceType><PriceAmount>19.50</PriceAmount><CurrencyCode>GBP</CurrencyCode></Price>
+      </SupplyDetail>
+    </ProductSupply>
+  </Product>
+  <Product>
+    <RecordReference>acme-0002</RecordReference>
+    <NotificationType>05</NotificationType>
+    <ProductIdentifier><ProductIDType>03</ProductIDType><IDValue>9780131103627</IDValue></ProductIdentifier>
+  </Product>
+</ONIXMessage>`
+
+type ONIXTestSuite struct {
+	suite.Suite
+}
+
+func TestONIX(t *testing.T) {
+	suite.Run(t, new(ONIXTestSuite))
+}
+
+func (s *ONIXTestSuite) TestReader() {
+	s.Run("reads products in order", func() {
+		reader := onix.NewReader(strings.NewReader(message))
+
+		product, err := reader.Next()
+		s.Require().NoError(err)
+		s.Assert().Equal(6, product.Line)
+		s.Assert().False(product.Deleted())
+		s.Assert().Equal("9780306406157", product.ISBN())
+		s.Assert().Equal("The Long Voyage", product.Title())
+		s.Assert().Equal([]string{"John Smith", "Jane Doe"}, product.Authors())
+		s.Assert().Equal("eng", product.Language())
+		s.Assert().Equal("Fiction / Sea Stories", product.MainSubject().HeadingText)
+		s.Assert().Equal([]string{"FIC047000"}, product.SubjectCodes(onix.SubjectSchemeBISAC))
+		s.Assert().Equal([]string{"FBA"}, product.SubjectCodes(onix.SubjectSchemeThema))
+		s.Assert().Equal("A long voyage & a short stay.", product.Description())
+
+		date, format := product.PublicationDate()
+		s.Assert().Equal("20240315", date)
+		s.Assert().Equal("00", format)
+
+		supply := product.Supply("gbp")
+		s.Require().NotNil(supply)
+		s.Assert().Equal("19.50", supply.Price("gbp").Amount)
+		s.Assert().Equal("24.99", product.Supply("").Price("").Amount)
+		s.Assert().Nil(product.Supply("EUR"))
+
+		product, err = reader.Next()
+		s.Require().NoError(err)
+		s.Assert().True(product.Deleted())
+		s.Assert().Equal("9780131103627", product.ISBN())
+
+		product, err = reader.Next()
+		s.Assert().Nil(product)
+		s.Assert().ErrorIs(err, io.EOF)
+	})
+
+	s.Run("short tags are rejected", func() {
+		reader := onix.NewReader(strings.NewReader(`<ONIXmessage release="3.0"><product/></ONIXmessage>`))
+		_, err := reader.Next()
+		s.Assert().ErrorIs(err, onix.ErrShortTags)
+	})
+
+	s.Run("onix 2.1 is rejected", func() {
+		reader := onix.NewReader(strings.NewReader(`<ONIXMessage release="2.1"><Product/></ONIXMessage>`))
+		_, err := reader.Next()
+		s.Assert().ErrorIs(err, onix.ErrUnsupportedRelease)
+	})
+
+	s.Run("other documents are rejected", func() {
+		reader := onix.NewReader(strings.NewReader(`<catalog><book/></catalog>`))
+		_, err := reader.Next()
+		s.Assert().ErrorIs(err, onix.ErrNotONIX)
+
+		reader = onix.NewReader(strings.NewReader(``))
+		_, err = reader.Next()
+		s.Assert().ErrorIs(err, onix.ErrNotONIX)
+	})
+
+	s.Run("malformed xml stops the reader", func() {
+		reader := onix.NewReader(strings.NewReader(`<ONIXMessage><Product><RecordReference>x</Product>`))
+		_, err := reader.Next()
+		s.Assert().Error(err)
+		s.Assert().False(errors.Is(err, io.EOF))
+	})
+}
@@ -0,0 +1,53 @@
+package pagination_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/pagination"
+)
+
+type CursorTestSuite struct {
+	suite.Suite
+}
+
+func TestCursor(t *testing.T) {
+	suite.Run(t, new(CursorTestSuite))
+}
+
+func (s *CursorTestSuite) TestEncodeDecode() {
+	s.Run("round trip", func() {
+		cursor := pagination.Cursor{Sort: "title", Key: "Chicken Soup of Debugging", ID: 42}
+
+		result, err := pagination.Decode(cursor.Encode())
+		s.Require().NoError(err)
+		s.Assert().Equal(&cursor, result)
+	})
+
+	s.Run("encoded cursor is url safe", func() {
+		cursor := pagination.Cursor{Sort: "newest", Key: "2024-01-02T03:04:05.123456Z", ID: 7}
+
+		s.Assert().NotContains(cursor.Encode(), "+")
+		s.Assert().NotContains(cursor.Encode(), "/")
+		s.Assert().NotContains(cursor.Encode(), "=")
+	})
+
+	s.Run("not base64", func() {
+		result, err := pagination.Decode("not a cursor!")
+		s.Assert().Nil(result)
+		s.Assert().ErrorIs(err, pagination.ErrInvalidCursor)
+	})
+
+	s.Run("not json", func() {
+		result, err := pagination.Decode("bm90IGpzb24")
+		s.Assert().Nil(result)
+		s.Assert().ErrorIs(err, pagination.ErrInvalidCursor)
+	})
+
+	s.Run("missing id", func() {
+		result, err := pagination.Decode(pagination.Cursor{Sort: "title", Key: "A"}.Encode())
+		s.Assert().Nil(result)
+		s.Assert().ErrorIs(err, pagination.ErrInvalidCursor)
+	})
+}
@@ -0,0 +1,128 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// MockName is the provider name of payments taken by Mock.
+const MockName = "mock"
+
+// Mock is an in-process payment provider for development and tests, no money moves. Intents are kept in memory and
+// are gone after a restart. Webhook calls are signed with the hex encoded HMAC-SHA256 of the payload under the
+// webhook secret, so anyone holding the secret can play the provider and authorize a payment.
+type Mock struct {
+	secret []byte
+
+	mu      sync.Mutex
+	intents map[string]*mockIntent
+}
+
+type mockIntent struct {
+	amount   int64
+	refunded int64
+	captured bool
+	refunds  map[string]bool
+}
+
+func NewMock(webhookSecret string) *Mock {
+	return &Mock{
+		secret:  []byte(webhookSecret),
+		intents: map[string]*mockIntent{},
+	}
+}
+
+func (m *Mock) Name() string {
+	return MockName
+}
+
+func (m *Mock) CreateIntent(ctx context.Context, params entity.PaymentIntentParams) (*entity.PaymentIntent, error) {
+	if params.Amount <= 0 {
+		return nil, errorx.ErrInvalidParameter("amount invalid")
+	}
+
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+	reference := "pi_mock_" + hex.EncodeToString(id)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.intents[reference] = &mockIntent{amount: params.Amount, refunds: map[string]bool{}}
+
+	return &entity.PaymentIntent{
+		Reference:    reference,
+		ClientSecret: reference + "_secret",
+	}, nil
+}
+
+func (m *Mock) Capture(ctx context.Context, reference string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	intent, ok := m.intents[reference]
+	if !ok {
+		return errorx.ErrNotFound("payment cannot be found")
+	}
+	intent.captured = true
+
+	return nil
+}
+
+// Refund pays back amount of a captured payment, at most what was not refunded yet. A key refunded before is not paid
+// back again.
+func (m *Mock) Refund(ctx context.Context, reference, key string, amount int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	intent, ok := m.intents[reference]
+	if !ok {
+		return errorx.ErrNotFound("payment cannot be found")
+	}
+	if !intent.captured {
+		return errorx.ErrInvalidParameter("payment is not captured")
+	}
+	if intent.refunds[key] {
+		return nil
+	}
+	if amount <= 0 || amount > intent.amount-intent.refunded {
+		return errorx.ErrInvalidParameter("amount invalid")
+	}
+	intent.refunded += amount
+	intent.refunds[key] = true
+
+	return nil
+}
+
+func (m *Mock) VerifyWebhook(payload []byte, signature string) (*entity.PaymentEvent, error) {
+	if !hmac.Equal([]byte(m.Sign(payload)), []byte(signature)) {
+		return nil, errorx.ErrUnauthorized("webhook signature invalid")
+	}
+
+	var event entity.PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInvalidParameter, "webhook payload invalid")
+	}
+	if event.Reference == "" {
+		return nil, errorx.ErrInvalidParameter("webhook payload invalid")
+	}
+
+	return &event, nil
+}
+
+// Sign returns the signature of a webhook payload.
+func (m *Mock) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,66 @@
+package payment_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/payment"
+)
+
+type MockProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestMockProvider(t *testing.T) {
+	suite.Run(t, new(MockProviderTestSuite))
+}
+
+func (s *MockProviderTestSuite) TestPaymentFlow() {
+	ctx := context.Background()
+	provider := payment.NewMock("secret")
+
+	intent, err := provider.CreateIntent(ctx, entity.PaymentIntentParams{OrderID: 3, Amount: 20000})
+	s.Require().NoError(err)
+	s.Assert().NotEmpty(intent.Reference)
+	s.Assert().NotEmpty(intent.ClientSecret)
+
+	s.Assert().Error(provider.Refund(ctx, intent.Reference, "refund-1", 20000), "refunding a payment not captured")
+	s.Assert().Error(provider.Capture(ctx, "pi_unknown"))
+
+	s.Require().NoError(provider.Capture(ctx, intent.Reference))
+	s.Require().NoError(provider.Capture(ctx, intent.Reference), "capturing again")
+
+	s.Require().NoError(provider.Refund(ctx, intent.Reference, "refund-1", 5000))
+	s.Assert().Error(provider.Refund(ctx, intent.Reference, "refund-2", 15001), "refunding more than is left")
+	s.Require().NoError(provider.Refund(ctx, intent.Reference, "refund-2", 15000))
+	s.Require().NoError(provider.Refund(ctx, intent.Reference, "refund-1", 5000), "sending a refund again")
+}
+
+func (s *MockProviderTestSuite) TestVerifyWebhook() {
+	provider := payment.NewMock("secret")
+	payload := []byte(`{"type":"payment.authorized","reference":"pi_mock_1","amount":20000}`)
+
+	s.Run("signed by the secret", func() {
+		event, err := provider.VerifyWebhook(payload, provider.Sign(payload))
+		s.Require().NoError(err)
+		s.Assert().Equal(&entity.PaymentEvent{Type: entity.PaymentEventAuthorized, Reference: "pi_mock_1", Amount: 20000}, event)
+	})
+
+	s.Run("signed by another secret", func() {
+		event, err := provider.VerifyWebhook(payload, payment.NewMock("other").Sign(payload))
+		s.Assert().Nil(event)
+		s.Assert().Error(err)
+	})
+
+	s.Run("payload changed after signing", func() {
+		signature := provider.Sign(payload)
+		tampered := []byte(`{"type":"payment.authorized","reference":"pi_mock_1","amount":1}`)
+
+		event, err := provider.VerifyWebhook(tampered, signature)
+		s.Assert().Nil(event)
+		s.Assert().Error(err)
+	})
+}
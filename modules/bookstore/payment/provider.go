@@ -0,0 +1,43 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// EnvDevelopment is the environment the mock provider may run in.
+const EnvDevelopment = "development"
+
+// ErrNoProvider is returned by New when no payment provider is configured.
+var ErrNoProvider = errors.New("no payment provider configured")
+
+// Provider is a payment provider New can build, the order and payment services take it as their PaymentProvider.
+type Provider interface {
+	Name() string
+	CreateIntent(ctx context.Context, params entity.PaymentIntentParams) (*entity.PaymentIntent, error)
+	Capture(ctx context.Context, reference string) error
+	Refund(ctx context.Context, reference, key string, amount int64) error
+	VerifyWebhook(payload []byte, signature string) (*entity.PaymentEvent, error)
+}
+
+// New returns the payment provider called name for the environment env. The mock moves no money and forgets its
+// payments on restart, so outside development New refuses it instead of letting orders be paid for free.
+func New(name, env, webhookSecret string) (Provider, error) {
+	switch name {
+	case "":
+		return nil, ErrNoProvider
+	case MockName:
+		if env != EnvDevelopment {
+			return nil, fmt.Errorf("payment provider %s only runs in %s, not in %s", MockName, EnvDevelopment, env)
+		}
+		if webhookSecret == "" {
+			return nil, fmt.Errorf("payment provider %s needs a webhook secret", MockName)
+		}
+		return NewMock(webhookSecret), nil
+	default:
+		return nil, fmt.Errorf("payment provider %q not supported", name)
+	}
+}
@@ -0,0 +1,49 @@
+package payment_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/payment"
+)
+
+type ProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestProvider(t *testing.T) {
+	suite.Run(t, new(ProviderTestSuite))
+}
+
+func (s *ProviderTestSuite) TestNew() {
+	s.Run("mock in development", func() {
+		provider, err := payment.New(payment.MockName, payment.EnvDevelopment, "secret")
+		s.Require().NoError(err)
+		s.Assert().Equal(payment.MockName, provider.Name())
+	})
+
+	s.Run("no provider configured", func() {
+		provider, err := payment.New("", "production", "")
+		s.Assert().Nil(provider)
+		s.Assert().ErrorIs(err, payment.ErrNoProvider)
+	})
+
+	s.Run("mock outside development", func() {
+		provider, err := payment.New(payment.MockName, "production", "secret")
+		s.Assert().Nil(provider)
+		s.Assert().EqualError(err, "payment provider mock only runs in development, not in production")
+	})
+
+	s.Run("mock without webhook secret", func() {
+		provider, err := payment.New(payment.MockName, payment.EnvDevelopment, "")
+		s.Assert().Nil(provider)
+		s.Assert().EqualError(err, "payment provider mock needs a webhook secret")
+	})
+
+	s.Run("unknown provider", func() {
+		provider, err := payment.New("acme", payment.EnvDevelopment, "secret")
+		s.Assert().Nil(provider)
+		s.Assert().EqualError(err, `payment provider "acme" not supported`)
+	})
+}
@@ -7,38 +7,697 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countBooks = `-- name: CountBooks :one
+SELECT COUNT(*)::bigint AS total
+FROM "books" b
+LEFT JOIN LATERAL (SELECT websearch_to_tsquery('english', $1::text) AS tsq) q ON TRUE
+WHERE b.status = 'active'
+    AND (q.tsq IS NULL OR books_search_vector(b.name, b.authors, b.description) @@ q.tsq)
+    AND ($2::text IS NULL OR b.authors ILIKE '%' || $2::text || '%')
+    AND ($3::text IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+        JOIN "categories" c ON c.slug = category_slug($3::text)
+        WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(c.id))))
+    AND ($4::bigint IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+        WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree($4::bigint))))
+    AND ($5::text IS NULL OR b.language = $5::text)
+    AND ($6::text IS NULL OR b.format = $6::text)
+    AND ($7::bigint IS NULL OR b.price >= $7::bigint)
+    AND ($8::bigint IS NULL OR b.price <= $8::bigint)
+    AND (NOT $9::boolean OR b.stock > 0)
+    AND ($10::int IS NULL OR (b.published_at >= make_date($10::int, 1, 1)
+        AND b.published_at < make_date($10::int + 1, 1, 1)))
+`
+
+type CountBooksParams struct {
+	Query         pgtype.Text `db:"query"`
+	Author        pgtype.Text `db:"author"`
+	Category      pgtype.Text `db:"category"`
+	CategoryID    pgtype.Int8 `db:"category_id"`
+	Language      pgtype.Text `db:"language"`
+	Format        pgtype.Text `db:"format"`
+	MinPrice      pgtype.Int8 `db:"min_price"`
+	MaxPrice      pgtype.Int8 `db:"max_price"`
+	InStock       bool        `db:"in_stock"`
+	PublishedYear pgtype.Int4 `db:"published_year"`
+}
+
+func (q *Queries) CountBooks(ctx context.Context, arg CountBooksParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countBooks,
+		arg.Query,
+		arg.Author,
+		arg.Category,
+		arg.CategoryID,
+		arg.Language,
+		arg.Format,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+		arg.PublishedYear,
+	)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const createBook = `-- name: CreateBook :one
+WITH new_work AS (
+    INSERT INTO "works" ("title", "authors", "description", "series_id", "series_volume")
+    SELECT $1::varchar, $2::varchar, $3::text,
+        $4::bigint, $5::int
+    WHERE $6::bigint = 0
+    RETURNING "id", "series_id", "series_volume"
+), edited_work AS (
+    UPDATE "works" SET
+        "series_id" = COALESCE($4::bigint, "series_id"),
+        "series_volume" = COALESCE($5::int, "series_volume")
+    WHERE "id" = $6::bigint
+    RETURNING "id", "series_id", "series_volume"
+), book AS (
+    INSERT INTO "books" ("name", "authors", "description", "category", "language", "format", "price", "stock", "published_at", "status",
+        "work_id", "created_at")
+    VALUES ($1, $2, $3, $7, $8,
+        $9, $10, $11, $12, $13,
+        COALESCE((SELECT "id" FROM new_work), NULLIF($6::bigint, 0)), NOW())
+    RETURNING id, name, created_at, authors, description, category, language, format, price, stock, published_at, sold_count, version, updated_at, status, isbn, work_id, sku, cover_key
 )
+SELECT book.id, book.name, book.created_at, book.authors, book.description, book.category, book.language, book.format, book.price, book.stock, book.published_at, book.sold_count, book.version, book.updated_at, book.status, book.isbn, book.work_id, book.sku, book.cover_key, work.series_id, work.series_volume FROM book
+LEFT JOIN (SELECT id, series_id, series_volume FROM new_work UNION ALL SELECT id, series_id, series_volume FROM edited_work) work ON work.id = book.work_id
+`
+
+type CreateBookParams struct {
+	Name         string      `db:"name"`
+	Authors      string      `db:"authors"`
+	Description  string      `db:"description"`
+	SeriesID     pgtype.Int8 `db:"series_id"`
+	SeriesVolume pgtype.Int4 `db:"series_volume"`
+	WorkID       int64       `db:"work_id"`
+	Category     string      `db:"category"`
+	Language     string      `db:"language"`
+	Format       string      `db:"format"`
+	Price        int64       `db:"price"`
+	Stock        int64       `db:"stock"`
+	PublishedAt  pgtype.Date `db:"published_at"`
+	Status       string      `db:"status"`
+}
+
+type CreateBookRow struct {
+	ID           int64              `db:"id"`
+	Name         string             `db:"name"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at"`
+	Authors      string             `db:"authors"`
+	Description  string             `db:"description"`
+	Category     string             `db:"category"`
+	Language     string             `db:"language"`
+	Format       string             `db:"format"`
+	Price        int64              `db:"price"`
+	Stock        int64              `db:"stock"`
+	PublishedAt  pgtype.Date        `db:"published_at"`
+	SoldCount    int64              `db:"sold_count"`
+	Version      int64              `db:"version"`
+	UpdatedAt    pgtype.Timestamptz `db:"updated_at"`
+	Status       string             `db:"status"`
+	Isbn         pgtype.Text        `db:"isbn"`
+	WorkID       int64              `db:"work_id"`
+	Sku          string             `db:"sku"`
+	CoverKey     pgtype.Text        `db:"cover_key"`
+	SeriesID     pgtype.Int8        `db:"series_id"`
+	SeriesVolume pgtype.Int4        `db:"series_volume"`
+}
+
+func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (*CreateBookRow, error) {
+	row := q.db.QueryRow(ctx, createBook,
+		arg.Name,
+		arg.Authors,
+		arg.Description,
+		arg.SeriesID,
+		arg.SeriesVolume,
+		arg.WorkID,
+		arg.Category,
+		arg.Language,
+		arg.Format,
+		arg.Price,
+		arg.Stock,
+		arg.PublishedAt,
+		arg.Status,
+	)
+	var i CreateBookRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.Authors,
+		&i.Description,
+		&i.Category,
+		&i.Language,
+		&i.Format,
+		&i.Price,
+		&i.Stock,
+		&i.PublishedAt,
+		&i.SoldCount,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Isbn,
+		&i.WorkID,
+		&i.Sku,
+		&i.CoverKey,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return &i, err
+}
+
+const decrementBookStock = `-- name: DecrementBookStock :execrows
+UPDATE "books" SET "stock" = "stock" - $1::bigint, "version" = "version" + 1, "updated_at" = NOW()
+WHERE "sku" = $2 AND "stock" >= $1::bigint
+`
+
+type DecrementBookStockParams struct {
+	Amount int64  `db:"amount"`
+	Sku    string `db:"sku"`
+}
+
+func (q *Queries) DecrementBookStock(ctx context.Context, arg DecrementBookStockParams) (int64, error) {
+	result, err := q.db.Exec(ctx, decrementBookStock, arg.Amount, arg.Sku)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const discontinueBook = `-- name: DiscontinueBook :execrows
+UPDATE "books" SET "status" = 'discontinued', "version" = "version" + 1, "updated_at" = NOW()
+WHERE "id" = $1 AND ($2::bigint IS NULL OR "version" = $2::bigint)
+`
+
+type DiscontinueBookParams struct {
+	ID      int64       `db:"id"`
+	Version pgtype.Int8 `db:"version"`
+}
+
+func (q *Queries) DiscontinueBook(ctx context.Context, arg DiscontinueBookParams) (int64, error) {
+	result, err := q.db.Exec(ctx, discontinueBook, arg.ID, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const estimateBooksCount = `-- name: EstimateBooksCount :one
+SELECT GREATEST(c.reltuples, 0)::bigint AS estimate FROM pg_class c WHERE c.oid = 'books'::regclass
+`
+
+func (q *Queries) EstimateBooksCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, estimateBooksCount)
+	var estimate int64
+	err := row.Scan(&estimate)
+	return estimate, err
+}
+
+const exportBooks = `-- name: ExportBooks :many
+SELECT id, name, created_at, authors, description, category, language, format, price, stock, published_at, sold_count, version, updated_at, status, isbn, work_id, sku, cover_key FROM "books"
+WHERE "id" > $1 AND ($2::timestamptz IS NULL OR "updated_at" >= $2::timestamptz)
+ORDER BY "id"
+LIMIT $3
+`
+
+type ExportBooksParams struct {
+	AfterID      int64              `db:"after_id"`
+	UpdatedSince pgtype.Timestamptz `db:"updated_since"`
+	Limit        int64              `db:"limit"`
+}
+
+func (q *Queries) ExportBooks(ctx context.Context, arg ExportBooksParams) ([]*Book, error) {
+	rows, err := q.db.Query(ctx, exportBooks, arg.AfterID, arg.UpdatedSince, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.Authors,
+			&i.Description,
+			&i.Category,
+			&i.Language,
+			&i.Format,
+			&i.Price,
+			&i.Stock,
+			&i.PublishedAt,
+			&i.SoldCount,
+			&i.Version,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Isbn,
+			&i.WorkID,
+			&i.Sku,
+			&i.CoverKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
 const findBook = `-- name: FindBook :one
-SELECT id, name, created_at FROM "books" WHERE "id" = $1
+SELECT id, name, created_at, authors, description, category, language, format, price, stock, published_at, sold_count, version, updated_at, status, isbn, work_id, sku, cover_key FROM "books" WHERE "id" = $1
 `
 
 func (q *Queries) FindBook(ctx context.Context, id int64) (*Book, error) {
 	row := q.db.QueryRow(ctx, findBook, id)
 	var i Book
-	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.Authors,
+		&i.Description,
+		&i.Category,
+		&i.Language,
+		&i.Format,
+		&i.Price,
+		&i.Stock,
+		&i.PublishedAt,
+		&i.SoldCount,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Isbn,
+		&i.WorkID,
+		&i.Sku,
+		&i.CoverKey,
+	)
 	return &i, err
 }
 
+const findBookBySKU = `-- name: FindBookBySKU :one
+SELECT id, name, created_at, authors, description, category, language, format, price, stock, published_at, sold_count, version, updated_at, status, isbn, work_id, sku, cover_key FROM "books" WHERE "sku" = $1
+`
+
+func (q *Queries) FindBookBySKU(ctx context.Context, sku string) (*Book, error) {
+	row := q.db.QueryRow(ctx, findBookBySKU, sku)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.Authors,
+		&i.Description,
+		&i.Category,
+		&i.Language,
+		&i.Format,
+		&i.Price,
+		&i.Stock,
+		&i.PublishedAt,
+		&i.SoldCount,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Isbn,
+		&i.WorkID,
+		&i.Sku,
+		&i.CoverKey,
+	)
+	return &i, err
+}
+
+const getBookFacets = `-- name: GetBookFacets :many
+WITH filtered AS (
+    SELECT b.id, b.language, b.format, b.price
+    FROM "books" b
+    LEFT JOIN LATERAL (SELECT websearch_to_tsquery('english', $1::text) AS tsq) q ON TRUE
+    WHERE b.status = 'active'
+        AND (q.tsq IS NULL OR books_search_vector(b.name, b.authors, b.description) @@ q.tsq)
+        AND ($2::text IS NULL OR b.authors ILIKE '%' || $2::text || '%')
+        AND ($3::text IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+            JOIN "categories" c ON c.slug = category_slug($3::text)
+            WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(c.id))))
+        AND ($4::bigint IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+            WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree($4::bigint))))
+        AND ($5::text IS NULL OR b.language = $5::text)
+        AND ($6::text IS NULL OR b.format = $6::text)
+        AND ($7::bigint IS NULL OR b.price >= $7::bigint)
+        AND ($8::bigint IS NULL OR b.price <= $8::bigint)
+        AND (NOT $9::boolean OR b.stock > 0)
+        AND ($10::int IS NULL OR (b.published_at >= make_date($10::int, 1, 1)
+            AND b.published_at < make_date($10::int + 1, 1, 1)))
+)
+SELECT 'category'::text AS facet, c.name::text AS value, c.id AS category_id, COUNT(DISTINCT f.id)::bigint AS total
+FROM "categories" c
+JOIN "book_categories" bc ON bc.category_id IN (SELECT category_subtree(c.id))
+JOIN filtered f ON f.id = bc.book_id
+WHERE c.parent_id IS NOT DISTINCT FROM $4::bigint
+GROUP BY c.id, c.name
+UNION ALL
+SELECT
+    (CASE
+        WHEN GROUPING(f.language) = 0 THEN 'language'
+        WHEN GROUPING(f.format) = 0 THEN 'format'
+        ELSE 'price'
+    END)::text AS facet,
+    (CASE
+        WHEN GROUPING(f.language) = 0 THEN f.language
+        WHEN GROUPING(f.format) = 0 THEN f.format
+        ELSE p.bucket::text
+    END)::text AS value,
+    0::bigint AS category_id,
+    COUNT(*)::bigint AS total
+FROM filtered f
+CROSS JOIN LATERAL (SELECT width_bucket(f.price, $11::bigint[]) AS bucket) p
+GROUP BY GROUPING SETS ((f.language), (f.format), (p.bucket))
+ORDER BY facet ASC, total DESC, value ASC
+`
+
+type GetBookFacetsParams struct {
+	Query         pgtype.Text `db:"query"`
+	Author        pgtype.Text `db:"author"`
+	Category      pgtype.Text `db:"category"`
+	CategoryID    pgtype.Int8 `db:"category_id"`
+	Language      pgtype.Text `db:"language"`
+	Format        pgtype.Text `db:"format"`
+	MinPrice      pgtype.Int8 `db:"min_price"`
+	MaxPrice      pgtype.Int8 `db:"max_price"`
+	InStock       bool        `db:"in_stock"`
+	PublishedYear pgtype.Int4 `db:"published_year"`
+	PriceEdges    []int64     `db:"price_edges"`
+}
+
+type GetBookFacetsRow struct {
+	Facet      string `db:"facet"`
+	Value      string `db:"value"`
+	CategoryID int64  `db:"category_id"`
+	Total      int64  `db:"total"`
+}
+
+func (q *Queries) GetBookFacets(ctx context.Context, arg GetBookFacetsParams) ([]*GetBookFacetsRow, error) {
+	rows, err := q.db.Query(ctx, getBookFacets,
+		arg.Query,
+		arg.Author,
+		arg.Category,
+		arg.CategoryID,
+		arg.Language,
+		arg.Format,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+		arg.PublishedYear,
+		arg.PriceEdges,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetBookFacetsRow
+	for rows.Next() {
+		var i GetBookFacetsRow
+		if err := rows.Scan(
+			&i.Facet,
+			&i.Value,
+			&i.CategoryID,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getBooks = `-- name: GetBooks :many
-SELECT id, name, created_at FROM "books" LIMIT $1 OFFSET $2
+SELECT b.id, b.name, b.authors, b.description, b.category, b.language, b.format, b.price, b.stock, b.published_at,
+    b.sold_count, b.created_at, w.series_id, w.series_volume, sr.name AS series_name, b.work_id, b.sku, b.cover_key,
+    COALESCE(ts_rank(books_search_vector(b.name, b.authors, b.description), q.tsq), 0)::real AS rank,
+    COALESCE(ts_headline('english', concat_ws(' ', b.name, b.authors, b.description), q.tsq,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5'), '')::text AS highlight
+FROM "books" b
+JOIN "works" w ON w.id = b.work_id
+LEFT JOIN "series" sr ON sr.id = w.series_id
+LEFT JOIN LATERAL (SELECT websearch_to_tsquery('english', $1::text) AS tsq) q ON TRUE
+WHERE b.status = 'active'
+    AND (q.tsq IS NULL OR books_search_vector(b.name, b.authors, b.description) @@ q.tsq)
+    AND ($2::text IS NULL OR b.authors ILIKE '%' || $2::text || '%')
+    AND ($3::text IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+        JOIN "categories" c ON c.slug = category_slug($3::text)
+        WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree(c.id))))
+    AND ($4::bigint IS NULL OR EXISTS (SELECT 1 FROM "book_categories" bc
+        WHERE bc.book_id = b.id AND bc.category_id IN (SELECT category_subtree($4::bigint))))
+    AND ($5::text IS NULL OR b.language = $5::text)
+    AND ($6::text IS NULL OR b.format = $6::text)
+    AND ($7::bigint IS NULL OR b.price >= $7::bigint)
+    AND ($8::bigint IS NULL OR b.price <= $8::bigint)
+    AND (NOT $9::boolean OR b.stock > 0)
+    AND ($10::int IS NULL OR (b.published_at >= make_date($10::int, 1, 1)
+        AND b.published_at < make_date($10::int + 1, 1, 1)))
+    AND ($11::bigint IS NULL OR (CASE $12::text
+        WHEN 'relevance' THEN ts_rank(books_search_vector(b.name, b.authors, b.description), q.tsq) < $13::real
+            OR (ts_rank(books_search_vector(b.name, b.authors, b.description), q.tsq) = $13::real AND b.id > $11::bigint)
+        WHEN 'title' THEN b.name > $14::text
+            OR (b.name = $14::text AND b.id > $11::bigint)
+        WHEN 'price_asc' THEN b.price > $15::bigint
+            OR (b.price = $15::bigint AND b.id > $11::bigint)
+        WHEN 'price_desc' THEN b.price < $15::bigint
+            OR (b.price = $15::bigint AND b.id > $11::bigint)
+        WHEN 'publication_date' THEN (CASE WHEN $16::date IS NULL
+            THEN b.published_at IS NULL AND b.id > $11::bigint
+            ELSE b.published_at < $16::date OR b.published_at IS NULL
+                OR (b.published_at = $16::date AND b.id > $11::bigint)
+            END)
+        WHEN 'popularity' THEN b.sold_count < $17::bigint
+            OR (b.sold_count = $17::bigint AND b.id > $11::bigint)
+        WHEN 'newest' THEN b.created_at < $18::timestamptz
+            OR (b.created_at = $18::timestamptz AND b.id > $11::bigint)
+        ELSE b.id > $11::bigint
+    END))
+ORDER BY
+    CASE WHEN $12::text = 'relevance' THEN ts_rank(books_search_vector(b.name, b.authors, b.description), q.tsq) END DESC NULLS LAST,
+    CASE WHEN $12::text = 'title' THEN b.name END ASC,
+    CASE WHEN $12::text = 'price_asc' THEN b.price END ASC,
+    CASE WHEN $12::text = 'price_desc' THEN b.price END DESC,
+    CASE WHEN $12::text = 'publication_date' THEN b.published_at END DESC NULLS LAST,
+    CASE WHEN $12::text = 'popularity' THEN b.sold_count END DESC,
+    CASE WHEN $12::text = 'newest' THEN b.created_at END DESC,
+    b.id ASC
+LIMIT $19 OFFSET $20
 `
 
 type GetBooksParams struct {
-	Limit  int64 `db:"limit"`
-	Offset int64 `db:"offset"`
+	Query            pgtype.Text        `db:"query"`
+	Author           pgtype.Text        `db:"author"`
+	Category         pgtype.Text        `db:"category"`
+	CategoryID       pgtype.Int8        `db:"category_id"`
+	Language         pgtype.Text        `db:"language"`
+	Format           pgtype.Text        `db:"format"`
+	MinPrice         pgtype.Int8        `db:"min_price"`
+	MaxPrice         pgtype.Int8        `db:"max_price"`
+	InStock          bool               `db:"in_stock"`
+	PublishedYear    pgtype.Int4        `db:"published_year"`
+	AfterID          pgtype.Int8        `db:"after_id"`
+	Sort             string             `db:"sort"`
+	AfterRank        pgtype.Float4      `db:"after_rank"`
+	AfterName        pgtype.Text        `db:"after_name"`
+	AfterPrice       pgtype.Int8        `db:"after_price"`
+	AfterPublishedAt pgtype.Date        `db:"after_published_at"`
+	AfterSoldCount   pgtype.Int8        `db:"after_sold_count"`
+	AfterCreatedAt   pgtype.Timestamptz `db:"after_created_at"`
+	Limit            int64              `db:"limit"`
+	Offset           int64              `db:"offset"`
 }
 
-func (q *Queries) GetBooks(ctx context.Context, arg GetBooksParams) ([]*Book, error) {
-	rows, err := q.db.Query(ctx, getBooks, arg.Limit, arg.Offset)
+type GetBooksRow struct {
+	ID           int64              `db:"id"`
+	Name         string             `db:"name"`
+	Authors      string             `db:"authors"`
+	Description  string             `db:"description"`
+	Category     string             `db:"category"`
+	Language     string             `db:"language"`
+	Format       string             `db:"format"`
+	Price        int64              `db:"price"`
+	Stock        int64              `db:"stock"`
+	PublishedAt  pgtype.Date        `db:"published_at"`
+	SoldCount    int64              `db:"sold_count"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at"`
+	SeriesID     pgtype.Int8        `db:"series_id"`
+	SeriesVolume pgtype.Int4        `db:"series_volume"`
+	SeriesName   pgtype.Text        `db:"series_name"`
+	WorkID       int64              `db:"work_id"`
+	Sku          string             `db:"sku"`
+	CoverKey     pgtype.Text        `db:"cover_key"`
+	Rank         float32            `db:"rank"`
+	Highlight    string             `db:"highlight"`
+}
+
+func (q *Queries) GetBooks(ctx context.Context, arg GetBooksParams) ([]*GetBooksRow, error) {
+	rows, err := q.db.Query(ctx, getBooks,
+		arg.Query,
+		arg.Author,
+		arg.Category,
+		arg.CategoryID,
+		arg.Language,
+		arg.Format,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.InStock,
+		arg.PublishedYear,
+		arg.AfterID,
+		arg.Sort,
+		arg.AfterRank,
+		arg.AfterName,
+		arg.AfterPrice,
+		arg.AfterPublishedAt,
+		arg.AfterSoldCount,
+		arg.AfterCreatedAt,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*Book
+	var items []*GetBooksRow
 	for rows.Next() {
-		var i Book
-		if err := rows.Scan(&i.ID, &i.Name, &i.CreatedAt); err != nil {
+		var i GetBooksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Authors,
+			&i.Description,
+			&i.Category,
+			&i.Language,
+			&i.Format,
+			&i.Price,
+			&i.Stock,
+			&i.PublishedAt,
+			&i.SoldCount,
+			&i.CreatedAt,
+			&i.SeriesID,
+			&i.SeriesVolume,
+			&i.SeriesName,
+			&i.WorkID,
+			&i.Sku,
+			&i.CoverKey,
+			&i.Rank,
+			&i.Highlight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementBookStock = `-- name: IncrementBookStock :execrows
+UPDATE "books" SET "stock" = "stock" + $1::bigint, "version" = "version" + 1, "updated_at" = NOW()
+WHERE "sku" = $2
+`
+
+type IncrementBookStockParams struct {
+	Amount int64  `db:"amount"`
+	Sku    string `db:"sku"`
+}
+
+func (q *Queries) IncrementBookStock(ctx context.Context, arg IncrementBookStockParams) (int64, error) {
+	result, err := q.db.Exec(ctx, incrementBookStock, arg.Amount, arg.Sku)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setBookCover = `-- name: SetBookCover :one
+UPDATE "books" SET
+    "cover_key" = $1,
+    "version" = "version" + 1,
+    "updated_at" = NOW()
+WHERE "id" = $2
+RETURNING id, name, created_at, authors, description, category, language, format, price, stock, published_at, sold_count, version, updated_at, status, isbn, work_id, sku, cover_key
+`
+
+type SetBookCoverParams struct {
+	CoverKey pgtype.Text `db:"cover_key"`
+	ID       int64       `db:"id"`
+}
+
+func (q *Queries) SetBookCover(ctx context.Context, arg SetBookCoverParams) (*Book, error) {
+	row := q.db.QueryRow(ctx, setBookCover, arg.CoverKey, arg.ID)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.Authors,
+		&i.Description,
+		&i.Category,
+		&i.Language,
+		&i.Format,
+		&i.Price,
+		&i.Stock,
+		&i.PublishedAt,
+		&i.SoldCount,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Isbn,
+		&i.WorkID,
+		&i.Sku,
+		&i.CoverKey,
+	)
+	return &i, err
+}
+
+const suggestBooks = `-- name: SuggestBooks :many
+SELECT b.id, b.name, b.authors,
+    GREATEST(word_similarity($1::text, b.name), word_similarity($1::text, b.authors))::real AS score
+FROM "books" b
+WHERE b.status = 'active' AND ($1::text <% b.name OR $1::text <% b.authors)
+ORDER BY score DESC, b.id ASC
+LIMIT $2
+`
+
+type SuggestBooksParams struct {
+	Prefix string `db:"prefix"`
+	Limit  int64  `db:"limit"`
+}
+
+type SuggestBooksRow struct {
+	ID      int64   `db:"id"`
+	Name    string  `db:"name"`
+	Authors string  `db:"authors"`
+	Score   float32 `db:"score"`
+}
+
+func (q *Queries) SuggestBooks(ctx context.Context, arg SuggestBooksParams) ([]*SuggestBooksRow, error) {
+	rows, err := q.db.Query(ctx, suggestBooks, arg.Prefix, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*SuggestBooksRow
+	for rows.Next() {
+		var i SuggestBooksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Authors,
+			&i.Score,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, &i)
@@ -48,3 +707,120 @@ func (q *Queries) GetBooks(ctx context.Context, arg GetBooksParams) ([]*Book, er
 	}
 	return items, nil
 }
+
+const updateBook = `-- name: UpdateBook :one
+WITH book AS (
+    UPDATE "books" SET
+        "name" = COALESCE($1::varchar, "name"),
+        "authors" = COALESCE($2::varchar, "authors"),
+        "description" = COALESCE($3::text, "description"),
+        "category" = COALESCE($4::varchar, "category"),
+        "language" = COALESCE($5::varchar, "language"),
+        "format" = COALESCE($6::varchar, "format"),
+        "price" = COALESCE($7::bigint, "price"),
+        "stock" = COALESCE($8::bigint, "stock"),
+        "published_at" = COALESCE($9::date, "published_at"),
+        "status" = COALESCE($10::varchar, "status"),
+        "work_id" = COALESCE($11::bigint, "work_id"),
+        "version" = "version" + 1,
+        "updated_at" = NOW()
+    WHERE "id" = $12 AND "version" = $13
+    RETURNING id, name, created_at, authors, description, category, language, format, price, stock, published_at, sold_count, version, updated_at, status, isbn, work_id, sku, cover_key
+), work AS (
+    UPDATE "works" w SET
+        "series_id" = COALESCE($14::bigint, w.series_id),
+        "series_volume" = COALESCE($15::int, w.series_volume)
+    FROM book
+    WHERE w.id = book.work_id
+    RETURNING w.id, w.series_id, w.series_volume
+)
+SELECT book.id, book.name, book.created_at, book.authors, book.description, book.category, book.language, book.format, book.price, book.stock, book.published_at, book.sold_count, book.version, book.updated_at, book.status, book.isbn, book.work_id, book.sku, book.cover_key, work.series_id, work.series_volume FROM book
+LEFT JOIN work ON work.id = book.work_id
+`
+
+type UpdateBookParams struct {
+	Name         pgtype.Text `db:"name"`
+	Authors      pgtype.Text `db:"authors"`
+	Description  pgtype.Text `db:"description"`
+	Category     pgtype.Text `db:"category"`
+	Language     pgtype.Text `db:"language"`
+	Format       pgtype.Text `db:"format"`
+	Price        pgtype.Int8 `db:"price"`
+	Stock        pgtype.Int8 `db:"stock"`
+	PublishedAt  pgtype.Date `db:"published_at"`
+	Status       pgtype.Text `db:"status"`
+	WorkID       pgtype.Int8 `db:"work_id"`
+	ID           int64       `db:"id"`
+	Version      int64       `db:"version"`
+	SeriesID     pgtype.Int8 `db:"series_id"`
+	SeriesVolume pgtype.Int4 `db:"series_volume"`
+}
+
+type UpdateBookRow struct {
+	ID           int64              `db:"id"`
+	Name         string             `db:"name"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at"`
+	Authors      string             `db:"authors"`
+	Description  string             `db:"description"`
+	Category     string             `db:"category"`
+	Language     string             `db:"language"`
+	Format       string             `db:"format"`
+	Price        int64              `db:"price"`
+	Stock        int64              `db:"stock"`
+	PublishedAt  pgtype.Date        `db:"published_at"`
+	SoldCount    int64              `db:"sold_count"`
+	Version      int64              `db:"version"`
+	UpdatedAt    pgtype.Timestamptz `db:"updated_at"`
+	Status       string             `db:"status"`
+	Isbn         pgtype.Text        `db:"isbn"`
+	WorkID       int64              `db:"work_id"`
+	Sku          string             `db:"sku"`
+	CoverKey     pgtype.Text        `db:"cover_key"`
+	SeriesID     pgtype.Int8        `db:"series_id"`
+	SeriesVolume pgtype.Int4        `db:"series_volume"`
+}
+
+func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (*UpdateBookRow, error) {
+	row := q.db.QueryRow(ctx, updateBook,
+		arg.Name,
+		arg.Authors,
+		arg.Description,
+		arg.Category,
+		arg.Language,
+		arg.Format,
+		arg.Price,
+		arg.Stock,
+		arg.PublishedAt,
+		arg.Status,
+		arg.WorkID,
+		arg.ID,
+		arg.Version,
+		arg.SeriesID,
+		arg.SeriesVolume,
+	)
+	var i UpdateBookRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.Authors,
+		&i.Description,
+		&i.Category,
+		&i.Language,
+		&i.Format,
+		&i.Price,
+		&i.Stock,
+		&i.PublishedAt,
+		&i.SoldCount,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Isbn,
+		&i.WorkID,
+		&i.Sku,
+		&i.CoverKey,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return &i, err
+}
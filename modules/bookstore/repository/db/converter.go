@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -10,15 +11,83 @@ import (
 )
 
 type QuerierWithTx interface {
-	CreateOrder(ctx context.Context, userID int64) (*CreateOrderRow, error)
+	AddBookCategories(ctx context.Context, arg AddBookCategoriesParams) (int64, error)
+	ClearBooksStaging(ctx context.Context, batchID string) error
+	ClearCart(ctx context.Context, cartID int64) error
+	CopyBooksToStaging(ctx context.Context, arg []CopyBooksToStagingParams) (int64, error)
+	CountBooks(ctx context.Context, arg CountBooksParams) (int64, error)
+	CountMyOrders(ctx context.Context, userID int64) (int64, error)
+	CreateBook(ctx context.Context, arg CreateBookParams) (*CreateBookRow, error)
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (*Category, error)
+	CreateGuestCart(ctx context.Context, token pgtype.Text) (*Cart, error)
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (*CreateOrderRow, error)
 	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (*OrderItem, error)
+	CreateOrderStatusChange(ctx context.Context, arg CreateOrderStatusChangeParams) (*OrderStatusChange, error)
+	CreatePaymentAttempt(ctx context.Context, arg CreatePaymentAttemptParams) (*PaymentAttempt, error)
+	CreatePaymentRefund(ctx context.Context, arg CreatePaymentRefundParams) (*PaymentRefund, error)
+	CreateReturn(ctx context.Context, arg CreateReturnParams) (*Return, error)
+	CreateSeries(ctx context.Context, arg CreateSeriesParams) (*Series, error)
 	CreateUser(ctx context.Context, email string) (*User, error)
+	DecrementBookStock(ctx context.Context, arg DecrementBookStockParams) (int64, error)
+	DeleteBookCategories(ctx context.Context, bookID int64) error
+	DeleteCart(ctx context.Context, id int64) error
+	DeleteCartItem(ctx context.Context, arg DeleteCartItemParams) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, limit int64) (int64, error)
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	DiscontinueBook(ctx context.Context, arg DiscontinueBookParams) (int64, error)
+	DiscontinueBooksByISBN(ctx context.Context, isbns []string) (int64, error)
+	EstimateBooksCount(ctx context.Context) (int64, error)
+	ExportBooks(ctx context.Context, arg ExportBooksParams) ([]*Book, error)
 	FindBook(ctx context.Context, id int64) (*Book, error)
+	FindBookBySKU(ctx context.Context, sku string) (*Book, error)
+	FindCategory(ctx context.Context, id int64) (*Category, error)
+	FindGuestCart(ctx context.Context, token pgtype.Text) (*Cart, error)
+	FindIdempotencyKey(ctx context.Context, arg FindIdempotencyKeyParams) (*IdempotencyKey, error)
+	FindOrder(ctx context.Context, id int64) (*FindOrderRow, error)
+	FindOrderForUpdate(ctx context.Context, id int64) (*FindOrderForUpdateRow, error)
+	FindOrderItem(ctx context.Context, arg FindOrderItemParams) (*OrderItem, error)
+	FindOrderPaymentAttempt(ctx context.Context, arg FindOrderPaymentAttemptParams) (*PaymentAttempt, error)
+	FindPaymentAttempt(ctx context.Context, arg FindPaymentAttemptParams) (*PaymentAttempt, error)
+	FindPendingPaymentRefundForUpdate(ctx context.Context, id int64) (*FindPendingPaymentRefundForUpdateRow, error)
+	FindReturnForUpdate(ctx context.Context, id int64) (*Return, error)
+	FindSeries(ctx context.Context, id int64) (*Series, error)
 	FindUser(ctx context.Context, email string) (*User, error)
 	FindUserByToken(ctx context.Context, token pgtype.Text) (*User, error)
-	GetBooks(ctx context.Context, arg GetBooksParams) ([]*Book, error)
+	FindWork(ctx context.Context, id int64) (*Work, error)
+	GetBookCategories(ctx context.Context, bookID int64) ([]*Category, error)
+	GetBookFacets(ctx context.Context, arg GetBookFacetsParams) ([]*GetBookFacetsRow, error)
+	GetBooks(ctx context.Context, arg GetBooksParams) ([]*GetBooksRow, error)
+	GetCartItems(ctx context.Context, cartID int64) ([]*GetCartItemsRow, error)
+	GetCategories(ctx context.Context) ([]*Category, error)
+	GetExpiredOrders(ctx context.Context, limit int64) ([]*GetExpiredOrdersRow, error)
 	GetMyOrders(ctx context.Context, arg GetMyOrdersParams) ([]*GetMyOrdersRow, error)
-	GetMyOrderItems(ctx context.Context, orderID int64) ([]*OrderItem, error)
+	GetOrCreateUserCart(ctx context.Context, userID pgtype.Int8) (*Cart, error)
+	GetOrderItems(ctx context.Context, orderIds []int64) ([]*GetOrderItemsRow, error)
+	GetOrderReturns(ctx context.Context, orderIds []int64) ([]*Return, error)
+	GetOrderStatusChanges(ctx context.Context, orderIds []int64) ([]*OrderStatusChange, error)
+	GetOrderTotal(ctx context.Context, orderID int64) (int64, error)
+	GetPendingPaymentRefunds(ctx context.Context, arg GetPendingPaymentRefundsParams) ([]int64, error)
+	GetReturnedAmount(ctx context.Context, arg GetReturnedAmountParams) (int64, error)
+	GetReturns(ctx context.Context, arg GetReturnsParams) ([]*Return, error)
+	GetSeriesOfBooks(ctx context.Context, bookIds []int64) ([]*Series, error)
+	GetSeriesVolumes(ctx context.Context, seriesIds []int64) ([]*GetSeriesVolumesRow, error)
+	GetWorkEditions(ctx context.Context, workID int64) ([]*Book, error)
+	GetWorkIDsOfBooks(ctx context.Context, bookIds []int64) ([]int64, error)
+	IncrementBookStock(ctx context.Context, arg IncrementBookStockParams) (int64, error)
+	LinkStagedBookCategories(ctx context.Context, batchID string) error
+	MergeCartItems(ctx context.Context, arg MergeCartItemsParams) error
+	ReleaseOrderStock(ctx context.Context, id int64) (int64, error)
+	ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (int64, error)
+	SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error
+	SetBookCover(ctx context.Context, arg SetBookCoverParams) (*Book, error)
+	SetCartItem(ctx context.Context, arg SetCartItemParams) error
+	SuggestBooks(ctx context.Context, arg SuggestBooksParams) ([]*SuggestBooksRow, error)
+	UpdateBook(ctx context.Context, arg UpdateBookParams) (*UpdateBookRow, error)
+	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (*UpdateOrderStatusRow, error)
+	UpdatePaymentAttempt(ctx context.Context, arg UpdatePaymentAttemptParams) (*PaymentAttempt, error)
+	UpdatePaymentRefund(ctx context.Context, arg UpdatePaymentRefundParams) error
+	UpdateReturn(ctx context.Context, arg UpdateReturnParams) (*Return, error)
+	UpsertBooksFromStaging(ctx context.Context, batchID string) (*UpsertBooksFromStagingRow, error)
 	WrapTx(tx pgx.Tx) QuerierWithTx
 }
 
@@ -28,8 +97,165 @@ func (q *Queries) WrapTx(tx pgx.Tx) QuerierWithTx {
 
 func (b *Book) ToEntity() *entity.Book {
 	return &entity.Book{
-		ID:   b.ID,
-		Name: b.Name,
+		ID:          b.ID,
+		WorkID:      b.WorkID,
+		SKU:         b.Sku,
+		ISBN:        b.Isbn.String,
+		Name:        b.Name,
+		Authors:     b.Authors,
+		Description: b.Description,
+		Category:    b.Category,
+		Language:    b.Language,
+		Format:      b.Format,
+		Price:       b.Price,
+		Stock:       b.Stock,
+		PublishedAt: dateToTime(b.PublishedAt),
+		Version:     b.Version,
+		Status:      b.Status,
+		Cover:       bookCover(b.CoverKey),
+		SoldCount:   b.SoldCount,
+		CreatedAt:   b.CreatedAt.Time,
+		UpdatedAt:   b.UpdatedAt.Time,
+	}
+}
+
+func (b *CreateBookRow) ToEntity() *entity.Book {
+	return &entity.Book{
+		ID:          b.ID,
+		WorkID:      b.WorkID,
+		SKU:         b.Sku,
+		ISBN:        b.Isbn.String,
+		Name:        b.Name,
+		Authors:     b.Authors,
+		Description: b.Description,
+		Category:    b.Category,
+		Language:    b.Language,
+		Format:      b.Format,
+		Price:       b.Price,
+		Stock:       b.Stock,
+		PublishedAt: dateToTime(b.PublishedAt),
+		Version:     b.Version,
+		Status:      b.Status,
+		Series:      bookSeries(b.SeriesID, b.SeriesVolume, pgtype.Text{}),
+		Cover:       bookCover(b.CoverKey),
+		SoldCount:   b.SoldCount,
+		CreatedAt:   b.CreatedAt.Time,
+		UpdatedAt:   b.UpdatedAt.Time,
+	}
+}
+
+func (b *UpdateBookRow) ToEntity() *entity.Book {
+	return &entity.Book{
+		ID:          b.ID,
+		WorkID:      b.WorkID,
+		SKU:         b.Sku,
+		ISBN:        b.Isbn.String,
+		Name:        b.Name,
+		Authors:     b.Authors,
+		Description: b.Description,
+		Category:    b.Category,
+		Language:    b.Language,
+		Format:      b.Format,
+		Price:       b.Price,
+		Stock:       b.Stock,
+		PublishedAt: dateToTime(b.PublishedAt),
+		Version:     b.Version,
+		Status:      b.Status,
+		Series:      bookSeries(b.SeriesID, b.SeriesVolume, pgtype.Text{}),
+		Cover:       bookCover(b.CoverKey),
+		SoldCount:   b.SoldCount,
+		CreatedAt:   b.CreatedAt.Time,
+		UpdatedAt:   b.UpdatedAt.Time,
+	}
+}
+
+func (b *GetSeriesVolumesRow) ToEntity() *entity.Book {
+	return &entity.Book{
+		ID:          b.ID,
+		WorkID:      b.WorkID,
+		SKU:         b.Sku,
+		ISBN:        b.Isbn.String,
+		Name:        b.Name,
+		Authors:     b.Authors,
+		Description: b.Description,
+		Category:    b.Category,
+		Language:    b.Language,
+		Format:      b.Format,
+		Price:       b.Price,
+		Stock:       b.Stock,
+		PublishedAt: dateToTime(b.PublishedAt),
+		Version:     b.Version,
+		Status:      b.Status,
+		Series:      bookSeries(b.SeriesID, b.SeriesVolume, pgtype.Text{}),
+		Cover:       bookCover(b.CoverKey),
+		SoldCount:   b.SoldCount,
+		CreatedAt:   b.CreatedAt.Time,
+		UpdatedAt:   b.UpdatedAt.Time,
+	}
+}
+
+func (b *GetBooksRow) ToEntity() *entity.Book {
+	return &entity.Book{
+		ID:          b.ID,
+		WorkID:      b.WorkID,
+		SKU:         b.Sku,
+		Name:        b.Name,
+		Authors:     b.Authors,
+		Description: b.Description,
+		Category:    b.Category,
+		Language:    b.Language,
+		Format:      b.Format,
+		Price:       b.Price,
+		Stock:       b.Stock,
+		PublishedAt: dateToTime(b.PublishedAt),
+		Highlight:   b.Highlight,
+		Series:      bookSeries(b.SeriesID, b.SeriesVolume, b.SeriesName),
+		Cover:       bookCover(b.CoverKey),
+		SoldCount:   b.SoldCount,
+		CreatedAt:   b.CreatedAt.Time,
+		Rank:        b.Rank,
+	}
+}
+
+func (s *Series) ToEntity() *entity.Series {
+	return &entity.Series{
+		ID:          s.ID,
+		Name:        s.Name,
+		Description: s.Description,
+	}
+}
+
+func (w *Work) ToEntity() *entity.Work {
+	return &entity.Work{
+		ID:          w.ID,
+		Title:       w.Title,
+		Authors:     w.Authors,
+		Description: w.Description,
+		Series:      bookSeries(w.SeriesID, w.SeriesVolume, pgtype.Text{}),
+	}
+}
+
+func (c *Category) ToEntity() *entity.Category {
+	category := &entity.Category{
+		ID:        c.ID,
+		Name:      c.Name,
+		Slug:      c.Slug,
+		BISACCode: c.BisacCode.String,
+		ThemaCode: c.ThemaCode.String,
+	}
+	if c.ParentID.Valid {
+		parentID := c.ParentID.Int64
+		category.ParentID = &parentID
+	}
+
+	return category
+}
+
+func (b *SuggestBooksRow) ToEntity() *entity.BookSuggestion {
+	return &entity.BookSuggestion{
+		ID:      b.ID,
+		Name:    b.Name,
+		Authors: b.Authors,
 	}
 }
 
@@ -37,23 +263,242 @@ func (u *User) ToEntity() *entity.User {
 	return &entity.User{
 		ID:    u.ID,
 		Email: u.Email,
+		Role:  u.Role,
+	}
+}
+
+func (k *IdempotencyKey) ToEntity() *entity.IdempotencyKey {
+	return &entity.IdempotencyKey{
+		UserID:      k.UserID,
+		Key:         k.Key,
+		Fingerprint: k.Fingerprint,
+		StatusCode:  int(k.StatusCode.Int32),
+		Response:    k.Response,
+		Lease:       k.Lease,
+		ExpiresAt:   k.ExpiresAt.Time,
 	}
 }
 
 func (o *CreateOrderRow) ToEntity() *entity.Order {
+	return &entity.Order{
+		ID:            o.ID,
+		UserID:        o.UserID,
+		Status:        o.Status,
+		ReservedUntil: timestamptzToTime(o.ReservedUntil),
+		CreatedAt:     o.CreatedAt.Time,
+	}
+}
+
+func (o *FindOrderRow) ToEntity() *entity.Order {
+	return &entity.Order{
+		ID:            o.ID,
+		UserID:        o.UserID,
+		Email:         o.Email,
+		Status:        o.Status,
+		ReservedUntil: timestamptzToTime(o.ReservedUntil),
+		CreatedAt:     o.CreatedAt.Time,
+	}
+}
+
+func (o *GetExpiredOrdersRow) ToEntity() *entity.Order {
 	return &entity.Order{
 		ID:        o.ID,
 		UserID:    o.UserID,
+		Status:    o.Status,
 		CreatedAt: o.CreatedAt.Time,
 	}
 }
 
+func (o *FindOrderForUpdateRow) ToEntity() *entity.Order {
+	return &entity.Order{
+		ID:        o.ID,
+		UserID:    o.UserID,
+		Status:    o.Status,
+		CreatedAt: o.CreatedAt.Time,
+	}
+}
+
+func (o *UpdateOrderStatusRow) ToEntity() *entity.Order {
+	return &entity.Order{
+		ID:        o.ID,
+		UserID:    o.UserID,
+		Status:    o.Status,
+		CreatedAt: o.CreatedAt.Time,
+	}
+}
+
+func (c *OrderStatusChange) ToEntity() *entity.OrderStatusChange {
+	change := &entity.OrderStatusChange{
+		OrderID:   c.OrderID,
+		From:      c.FromStatus.String,
+		To:        c.ToStatus,
+		ActorRole: c.ActorRole,
+		CreatedAt: c.CreatedAt.Time,
+	}
+	if c.ActorID.Valid {
+		actorID := c.ActorID.Int64
+		change.ActorID = &actorID
+	}
+
+	return change
+}
+
 func (o *OrderItem) ToEntity() *entity.OrderItem {
 	return &entity.OrderItem{
 		ID:        o.ID,
 		OrderID:   o.OrderID,
 		BookID:    o.BookID,
+		SKU:       o.Sku.String,
+		Amount:    o.Amount,
+		Price:     int8Ptr(o.Price),
+		CreatedAt: o.CreatedAt.Time,
+	}
+}
+
+func (o *GetOrderItemsRow) ToEntity() *entity.OrderItem {
+	item := &entity.OrderItem{
+		ID:        o.ID,
+		OrderID:   o.OrderID,
+		BookID:    o.BookID,
+		SKU:       o.Sku.String,
 		Amount:    o.Amount,
+		Price:     int8Ptr(o.Price),
 		CreatedAt: o.CreatedAt.Time,
 	}
+
+	// historical items may point at a book removed before books were soft-deleted
+	if o.BookName.Valid {
+		item.Book = &entity.BookSummary{
+			ID:      o.BookID,
+			Name:    o.BookName.String,
+			Authors: o.BookAuthors.String,
+			Status:  o.BookStatus.String,
+		}
+	}
+
+	return item
+}
+
+func (c *Cart) ToEntity() *entity.Cart {
+	return &entity.Cart{
+		ID:     c.ID,
+		UserID: c.UserID.Int64,
+		Token:  c.Token.String,
+		Items:  []entity.CartItem{},
+	}
+}
+
+func (c *GetCartItemsRow) ToEntity() *entity.CartItem {
+	return &entity.CartItem{
+		SKU: c.Sku,
+		Book: &entity.BookSummary{
+			ID:      c.BookID,
+			Name:    c.BookName,
+			Authors: c.BookAuthors,
+			Status:  c.BookStatus,
+		},
+		Amount:     c.Amount,
+		Price:      c.Price,
+		AddedPrice: c.AddedPrice,
+		Stock:      c.Stock,
+	}
+}
+
+func (p *PaymentAttempt) ToEntity() *entity.Payment {
+	return &entity.Payment{
+		ID:             p.ID,
+		OrderID:        p.OrderID,
+		Provider:       p.Provider,
+		Reference:      p.Reference,
+		Amount:         p.Amount,
+		RefundedAmount: p.RefundedAmount,
+		Status:         p.Status,
+		FailureReason:  p.FailureReason.String,
+		CreatedAt:      p.CreatedAt.Time,
+		UpdatedAt:      p.UpdatedAt.Time,
+	}
+}
+
+func (r *PaymentRefund) ToEntity() *entity.PaymentRefund {
+	return &entity.PaymentRefund{
+		ID:        r.ID,
+		PaymentID: r.PaymentAttemptID,
+		Amount:    r.Amount,
+		Status:    r.Status,
+		Attempts:  r.Attempts,
+		LastError: r.LastError.String,
+		CreatedAt: r.CreatedAt.Time,
+		UpdatedAt: r.UpdatedAt.Time,
+	}
+}
+
+func (r *FindPendingPaymentRefundForUpdateRow) ToEntity() *entity.PaymentRefund {
+	return &entity.PaymentRefund{
+		ID:        r.ID,
+		PaymentID: r.PaymentAttemptID,
+		Provider:  r.Provider,
+		Reference: r.Reference,
+		Amount:    r.Amount,
+		Status:    r.Status,
+		Attempts:  r.Attempts,
+		LastError: r.LastError.String,
+		CreatedAt: r.CreatedAt.Time,
+		UpdatedAt: r.UpdatedAt.Time,
+	}
+}
+
+func (r *Return) ToEntity() *entity.Return {
+	return &entity.Return{
+		ID:           r.ID,
+		OrderID:      r.OrderID,
+		OrderItemID:  r.OrderItemID,
+		Amount:       r.Amount,
+		Reason:       r.Reason,
+		Note:         r.Note.String,
+		Status:       r.Status,
+		StaffNote:    r.StaffNote.String,
+		HandledBy:    int8Ptr(r.HandledBy),
+		RefundAmount: r.RefundAmount,
+		CreatedAt:    r.CreatedAt.Time,
+		UpdatedAt:    r.UpdatedAt.Time,
+	}
+}
+
+func dateToTime(d pgtype.Date) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Time
+}
+
+func timestamptzToTime(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func int8Ptr(n pgtype.Int8) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
+
+func bookSeries(id pgtype.Int8, volume pgtype.Int4, name pgtype.Text) *entity.BookSeries {
+	if !id.Valid {
+		return nil
+	}
+	return &entity.BookSeries{
+		ID:     id.Int64,
+		Name:   name.String,
+		Volume: volume.Int32,
+	}
+}
+
+func bookCover(key pgtype.Text) *entity.BookCover {
+	if !key.Valid {
+		return nil
+	}
+	return entity.NewBookCover(key.String)
 }
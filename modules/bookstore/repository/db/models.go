@@ -8,19 +8,101 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type BookCategory struct {
+	BookID     int64 `db:"book_id"`
+	CategoryID int64 `db:"category_id"`
+}
+
 type Book struct {
+	ID          int64              `db:"id"`
+	Name        string             `db:"name"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at"`
+	Authors     string             `db:"authors"`
+	Description string             `db:"description"`
+	Category    string             `db:"category"`
+	Language    string             `db:"language"`
+	Format      string             `db:"format"`
+	Price       int64              `db:"price"`
+	Stock       int64              `db:"stock"`
+	PublishedAt pgtype.Date        `db:"published_at"`
+	SoldCount   int64              `db:"sold_count"`
+	Version     int64              `db:"version"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at"`
+	Status      string             `db:"status"`
+	Isbn        pgtype.Text        `db:"isbn"`
+	WorkID      int64              `db:"work_id"`
+	Sku         string             `db:"sku"`
+	CoverKey    pgtype.Text        `db:"cover_key"`
+}
+
+type BooksStaging struct {
+	BatchID     string      `db:"batch_id"`
+	Line        int32       `db:"line"`
+	Isbn        string      `db:"isbn"`
+	Name        string      `db:"name"`
+	Authors     pgtype.Text `db:"authors"`
+	Price       pgtype.Int8 `db:"price"`
+	Stock       pgtype.Int8 `db:"stock"`
+	Description pgtype.Text `db:"description"`
+	Category    pgtype.Text `db:"category"`
+	Language    pgtype.Text `db:"language"`
+	Format      pgtype.Text `db:"format"`
+	PublishedAt pgtype.Date `db:"published_at"`
+	Status      pgtype.Text `db:"status"`
+	BisacCodes  []string    `db:"bisac_codes"`
+	ThemaCodes  []string    `db:"thema_codes"`
+}
+
+type Cart struct {
 	ID        int64              `db:"id"`
-	Name      string             `db:"name"`
+	UserID    pgtype.Int8        `db:"user_id"`
+	Token     pgtype.Text        `db:"token"`
 	CreatedAt pgtype.Timestamptz `db:"created_at"`
+	UpdatedAt pgtype.Timestamptz `db:"updated_at"`
 }
 
-type Order struct {
+type CartItem struct {
+	CartID    int64              `db:"cart_id"`
+	Sku       string             `db:"sku"`
+	Amount    int64              `db:"amount"`
+	Price     int64              `db:"price"`
+	CreatedAt pgtype.Timestamptz `db:"created_at"`
+	UpdatedAt pgtype.Timestamptz `db:"updated_at"`
+}
+
+type Category struct {
 	ID        int64              `db:"id"`
-	UserID    int64              `db:"user_id"`
-	BookID    pgtype.Int8        `db:"book_id"`
-	Amount    pgtype.Int8        `db:"amount"`
+	ParentID  pgtype.Int8        `db:"parent_id"`
+	Name      string             `db:"name"`
+	Slug      string             `db:"slug"`
+	BisacCode pgtype.Text        `db:"bisac_code"`
+	ThemaCode pgtype.Text        `db:"thema_code"`
 	CreatedAt pgtype.Timestamptz `db:"created_at"`
-	Details   []byte             `db:"details"`
+}
+
+type IdempotencyKey struct {
+	UserID          int64              `db:"user_id"`
+	Key             string             `db:"key"`
+	Fingerprint     string             `db:"fingerprint"`
+	StatusCode      pgtype.Int4        `db:"status_code"`
+	Response        []byte             `db:"response"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at"`
+	ExpiresAt       pgtype.Timestamptz `db:"expires_at"`
+	Lease           string             `db:"lease"`
+	ResponseHeaders []byte             `db:"response_headers"`
+}
+
+type Order struct {
+	ID               int64              `db:"id"`
+	UserID           int64              `db:"user_id"`
+	BookID           pgtype.Int8        `db:"book_id"`
+	Amount           pgtype.Int8        `db:"amount"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at"`
+	Details          []byte             `db:"details"`
+	Status           string             `db:"status"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at"`
+	StockDecremented bool               `db:"stock_decremented"`
+	ReservedUntil    pgtype.Timestamptz `db:"reserved_until"`
 }
 
 type OrderItem struct {
@@ -29,6 +111,64 @@ type OrderItem struct {
 	BookID    int64              `db:"book_id"`
 	Amount    int64              `db:"amount"`
 	CreatedAt pgtype.Timestamptz `db:"created_at"`
+	Sku       pgtype.Text        `db:"sku"`
+	Price     pgtype.Int8        `db:"price"`
+}
+
+type OrderStatusChange struct {
+	ID         int64              `db:"id"`
+	OrderID    int64              `db:"order_id"`
+	FromStatus pgtype.Text        `db:"from_status"`
+	ToStatus   string             `db:"to_status"`
+	ActorID    pgtype.Int8        `db:"actor_id"`
+	ActorRole  string             `db:"actor_role"`
+	CreatedAt  pgtype.Timestamptz `db:"created_at"`
+}
+
+type PaymentAttempt struct {
+	ID             int64              `db:"id"`
+	OrderID        int64              `db:"order_id"`
+	Provider       string             `db:"provider"`
+	Reference      string             `db:"reference"`
+	Amount         int64              `db:"amount"`
+	RefundedAmount int64              `db:"refunded_amount"`
+	Status         string             `db:"status"`
+	FailureReason  pgtype.Text        `db:"failure_reason"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `db:"updated_at"`
+}
+
+type PaymentRefund struct {
+	ID               int64              `db:"id"`
+	PaymentAttemptID int64              `db:"payment_attempt_id"`
+	Amount           int64              `db:"amount"`
+	Status           string             `db:"status"`
+	Attempts         int32              `db:"attempts"`
+	LastError        pgtype.Text        `db:"last_error"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at"`
+}
+
+type Return struct {
+	ID           int64              `db:"id"`
+	OrderID      int64              `db:"order_id"`
+	OrderItemID  int64              `db:"order_item_id"`
+	Amount       int64              `db:"amount"`
+	Reason       string             `db:"reason"`
+	Note         pgtype.Text        `db:"note"`
+	Status       string             `db:"status"`
+	StaffNote    pgtype.Text        `db:"staff_note"`
+	HandledBy    pgtype.Int8        `db:"handled_by"`
+	RefundAmount int64              `db:"refund_amount"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `db:"updated_at"`
+}
+
+type Series struct {
+	ID          int64              `db:"id"`
+	Name        string             `db:"name"`
+	Description string             `db:"description"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at"`
 }
 
 type User struct {
@@ -37,4 +177,15 @@ type User struct {
 	CreatedAt pgtype.Timestamptz `db:"created_at"`
 	Password  pgtype.Text        `db:"password"`
 	Token     pgtype.Text        `db:"token"`
+	Role      string             `db:"role"`
+}
+
+type Work struct {
+	ID           int64              `db:"id"`
+	Title        string             `db:"title"`
+	Authors      string             `db:"authors"`
+	Description  string             `db:"description"`
+	SeriesID     pgtype.Int8        `db:"series_id"`
+	SeriesVolume pgtype.Int4        `db:"series_volume"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at"`
 }
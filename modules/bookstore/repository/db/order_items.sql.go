@@ -7,20 +7,48 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createOrderItem = `-- name: CreateOrderItem :one
-INSERT INTO "order_items" ("order_id", "book_id", "amount", "created_at") VALUES ($1, $2, $3, NOW()) RETURNING id, order_id, book_id, amount, created_at
+INSERT INTO "order_items" ("order_id", "book_id", "sku", "amount", "price", "created_at")
+SELECT $1::bigint, b.id, b.sku, $2::bigint, b.price, NOW() FROM "books" b WHERE b.sku = $3
+RETURNING id, order_id, book_id, amount, created_at, sku, price
 `
 
 type CreateOrderItemParams struct {
-	OrderID int64 `db:"order_id"`
-	BookID  int64 `db:"book_id"`
-	Amount  int64 `db:"amount"`
+	OrderID int64  `db:"order_id"`
+	Amount  int64  `db:"amount"`
+	Sku     string `db:"sku"`
 }
 
 func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (*OrderItem, error) {
-	row := q.db.QueryRow(ctx, createOrderItem, arg.OrderID, arg.BookID, arg.Amount)
+	row := q.db.QueryRow(ctx, createOrderItem, arg.OrderID, arg.Amount, arg.Sku)
+	var i OrderItem
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.BookID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Sku,
+		&i.Price,
+	)
+	return &i, err
+}
+
+const findOrderItem = `-- name: FindOrderItem :one
+SELECT id, order_id, book_id, amount, created_at, sku, price FROM "order_items" WHERE "id" = $1 AND "order_id" = $2
+`
+
+type FindOrderItemParams struct {
+	ID      int64 `db:"id"`
+	OrderID int64 `db:"order_id"`
+}
+
+func (q *Queries) FindOrderItem(ctx context.Context, arg FindOrderItemParams) (*OrderItem, error) {
+	row := q.db.QueryRow(ctx, findOrderItem, arg.ID, arg.OrderID)
 	var i OrderItem
 	err := row.Scan(
 		&i.ID,
@@ -28,29 +56,54 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		&i.BookID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Sku,
+		&i.Price,
 	)
 	return &i, err
 }
 
-const getMyOrderItems = `-- name: GetMyOrderItems :many
-SELECT id, order_id, book_id, amount, created_at FROM "order_items" WHERE order_id = $1
+const getOrderItems = `-- name: GetOrderItems :many
+SELECT oi.id, oi.order_id, oi.book_id, oi.amount, oi.created_at, oi.sku, oi.price,
+    b.name AS book_name, b.authors AS book_authors, b.status AS book_status
+FROM "order_items" oi
+LEFT JOIN "books" b ON b.id = oi.book_id
+WHERE oi.order_id = ANY($1::bigint[])
+ORDER BY oi.order_id, oi.id
 `
 
-func (q *Queries) GetMyOrderItems(ctx context.Context, orderID int64) ([]*OrderItem, error) {
-	rows, err := q.db.Query(ctx, getMyOrderItems, orderID)
+type GetOrderItemsRow struct {
+	ID          int64              `db:"id"`
+	OrderID     int64              `db:"order_id"`
+	BookID      int64              `db:"book_id"`
+	Amount      int64              `db:"amount"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at"`
+	Sku         pgtype.Text        `db:"sku"`
+	Price       pgtype.Int8        `db:"price"`
+	BookName    pgtype.Text        `db:"book_name"`
+	BookAuthors pgtype.Text        `db:"book_authors"`
+	BookStatus  pgtype.Text        `db:"book_status"`
+}
+
+func (q *Queries) GetOrderItems(ctx context.Context, orderIds []int64) ([]*GetOrderItemsRow, error) {
+	rows, err := q.db.Query(ctx, getOrderItems, orderIds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*OrderItem
+	var items []*GetOrderItemsRow
 	for rows.Next() {
-		var i OrderItem
+		var i GetOrderItemsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.OrderID,
 			&i.BookID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Sku,
+			&i.Price,
+			&i.BookName,
+			&i.BookAuthors,
+			&i.BookStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -61,3 +114,14 @@ func (q *Queries) GetMyOrderItems(ctx context.Context, orderID int64) ([]*OrderI
 	}
 	return items, nil
 }
+
+const getOrderTotal = `-- name: GetOrderTotal :one
+SELECT COALESCE(SUM(price * amount), 0)::bigint AS total FROM "order_items" WHERE order_id = $1
+`
+
+func (q *Queries) GetOrderTotal(ctx context.Context, orderID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, getOrderTotal, orderID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
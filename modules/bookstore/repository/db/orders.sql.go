@@ -11,45 +11,172 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countMyOrders = `-- name: CountMyOrders :one
+SELECT COUNT(*)::bigint AS total FROM "orders" o WHERE o.user_id = $1
+`
+
+func (q *Queries) CountMyOrders(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countMyOrders, userID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createOrder = `-- name: CreateOrder :one
-INSERT INTO "orders" ("user_id", "created_at") VALUES ($1, NOW()) RETURNING id, user_id, created_at
+INSERT INTO "orders" ("user_id", "stock_decremented", "reserved_until", "created_at")
+VALUES ($1, TRUE, NOW() + $2::interval, NOW())
+RETURNING id, user_id, status, reserved_until, created_at
 `
 
+type CreateOrderParams struct {
+	UserID         int64           `db:"user_id"`
+	ReservationTtl pgtype.Interval `db:"reservation_ttl"`
+}
+
 type CreateOrderRow struct {
+	ID            int64              `db:"id"`
+	UserID        int64              `db:"user_id"`
+	Status        string             `db:"status"`
+	ReservedUntil pgtype.Timestamptz `db:"reserved_until"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at"`
+}
+
+func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (*CreateOrderRow, error) {
+	row := q.db.QueryRow(ctx, createOrder, arg.UserID, arg.ReservationTtl)
+	var i CreateOrderRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.ReservedUntil,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const findOrder = `-- name: FindOrder :one
+SELECT o.id, o.user_id, u.email, o.status, o.reserved_until, o.created_at
+FROM "orders" o
+JOIN "users" u ON o.user_id = u.id
+WHERE o.id = $1
+`
+
+type FindOrderRow struct {
+	ID            int64              `db:"id"`
+	UserID        int64              `db:"user_id"`
+	Email         string             `db:"email"`
+	Status        string             `db:"status"`
+	ReservedUntil pgtype.Timestamptz `db:"reserved_until"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at"`
+}
+
+func (q *Queries) FindOrder(ctx context.Context, id int64) (*FindOrderRow, error) {
+	row := q.db.QueryRow(ctx, findOrder, id)
+	var i FindOrderRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.Status,
+		&i.ReservedUntil,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const findOrderForUpdate = `-- name: FindOrderForUpdate :one
+SELECT id, user_id, status, created_at FROM "orders" WHERE "id" = $1 FOR UPDATE
+`
+
+type FindOrderForUpdateRow struct {
 	ID        int64              `db:"id"`
 	UserID    int64              `db:"user_id"`
+	Status    string             `db:"status"`
 	CreatedAt pgtype.Timestamptz `db:"created_at"`
 }
 
-func (q *Queries) CreateOrder(ctx context.Context, userID int64) (*CreateOrderRow, error) {
-	row := q.db.QueryRow(ctx, createOrder, userID)
-	var i CreateOrderRow
-	err := row.Scan(&i.ID, &i.UserID, &i.CreatedAt)
+func (q *Queries) FindOrderForUpdate(ctx context.Context, id int64) (*FindOrderForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, findOrderForUpdate, id)
+	var i FindOrderForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+	)
 	return &i, err
 }
 
+const getExpiredOrders = `-- name: GetExpiredOrders :many
+SELECT id, user_id, status, created_at FROM "orders"
+WHERE "status" = 'pending_payment' AND "reserved_until" <= NOW()
+ORDER BY "reserved_until" LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+type GetExpiredOrdersRow struct {
+	ID        int64              `db:"id"`
+	UserID    int64              `db:"user_id"`
+	Status    string             `db:"status"`
+	CreatedAt pgtype.Timestamptz `db:"created_at"`
+}
+
+func (q *Queries) GetExpiredOrders(ctx context.Context, limit int64) ([]*GetExpiredOrdersRow, error) {
+	rows, err := q.db.Query(ctx, getExpiredOrders, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetExpiredOrdersRow
+	for rows.Next() {
+		var i GetExpiredOrdersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getMyOrders = `-- name: GetMyOrders :many
-SELECT o.id as order_id, o.user_id, u.email as email, o.created_at
+SELECT o.id as order_id, o.user_id, u.email as email, o.status, o.created_at
 FROM "orders" o
 JOIN "users" u ON o.user_id = u.id
-WHERE o.user_id = $1 ORDER BY o.id DESC LIMIT $2 OFFSET $3
+WHERE o.user_id = $1
+    AND ($2::bigint IS NULL OR o.id < $2::bigint)
+ORDER BY o.id DESC LIMIT $3 OFFSET $4
 `
 
 type GetMyOrdersParams struct {
-	UserID int64 `db:"user_id"`
-	Limit  int64 `db:"limit"`
-	Offset int64 `db:"offset"`
+	UserID   int64       `db:"user_id"`
+	BeforeID pgtype.Int8 `db:"before_id"`
+	Limit    int64       `db:"limit"`
+	Offset   int64       `db:"offset"`
 }
 
 type GetMyOrdersRow struct {
 	OrderID   int64              `db:"order_id"`
 	UserID    int64              `db:"user_id"`
 	Email     string             `db:"email"`
+	Status    string             `db:"status"`
 	CreatedAt pgtype.Timestamptz `db:"created_at"`
 }
 
 func (q *Queries) GetMyOrders(ctx context.Context, arg GetMyOrdersParams) ([]*GetMyOrdersRow, error) {
-	rows, err := q.db.Query(ctx, getMyOrders, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getMyOrders,
+		arg.UserID,
+		arg.BeforeID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +188,7 @@ func (q *Queries) GetMyOrders(ctx context.Context, arg GetMyOrdersParams) ([]*Ge
 			&i.OrderID,
 			&i.UserID,
 			&i.Email,
+			&i.Status,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -72,3 +200,60 @@ func (q *Queries) GetMyOrders(ctx context.Context, arg GetMyOrdersParams) ([]*Ge
 	}
 	return items, nil
 }
+
+const releaseOrderStock = `-- name: ReleaseOrderStock :execrows
+WITH released AS (
+    UPDATE "orders" SET "stock_decremented" = FALSE WHERE "id" = $1 AND "stock_decremented" RETURNING id
+)
+UPDATE "books" b SET "stock" = b.stock + oi.amount, "version" = b.version + 1, "updated_at" = NOW()
+FROM (
+    SELECT oi.sku, SUM(oi.amount - COALESCE(r.amount, 0))::bigint AS amount FROM "order_items" oi
+    LEFT JOIN (
+        SELECT order_item_id, SUM(amount) AS amount FROM "returns"
+        WHERE order_id = $1 AND status = 'received'
+        GROUP BY order_item_id
+    ) r ON r.order_item_id = oi.id
+    WHERE oi.order_id IN (SELECT id FROM released) AND oi.sku IS NOT NULL
+    GROUP BY oi.sku
+) oi
+WHERE b.sku = oi.sku AND oi.amount > 0
+`
+
+func (q *Queries) ReleaseOrderStock(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, releaseOrderStock, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateOrderStatus = `-- name: UpdateOrderStatus :one
+UPDATE "orders" SET "status" = $1, "reserved_until" = NULL, "updated_at" = NOW()
+WHERE "id" = $2 AND "status" = $3
+RETURNING id, user_id, status, created_at
+`
+
+type UpdateOrderStatusParams struct {
+	ToStatus   string `db:"to_status"`
+	ID         int64  `db:"id"`
+	FromStatus string `db:"from_status"`
+}
+
+type UpdateOrderStatusRow struct {
+	ID        int64              `db:"id"`
+	UserID    int64              `db:"user_id"`
+	Status    string             `db:"status"`
+	CreatedAt pgtype.Timestamptz `db:"created_at"`
+}
+
+func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (*UpdateOrderStatusRow, error) {
+	row := q.db.QueryRow(ctx, updateOrderStatus, arg.ToStatus, arg.ID, arg.FromStatus)
+	var i UpdateOrderStatusRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
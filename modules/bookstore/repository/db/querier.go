@@ -11,15 +11,83 @@ import (
 )
 
 type Querier interface {
-	CreateOrder(ctx context.Context, userID int64) (*CreateOrderRow, error)
+	AddBookCategories(ctx context.Context, arg AddBookCategoriesParams) (int64, error)
+	ClearBooksStaging(ctx context.Context, batchID string) error
+	ClearCart(ctx context.Context, cartID int64) error
+	CopyBooksToStaging(ctx context.Context, arg []CopyBooksToStagingParams) (int64, error)
+	CountBooks(ctx context.Context, arg CountBooksParams) (int64, error)
+	CountMyOrders(ctx context.Context, userID int64) (int64, error)
+	CreateBook(ctx context.Context, arg CreateBookParams) (*CreateBookRow, error)
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (*Category, error)
+	CreateGuestCart(ctx context.Context, token pgtype.Text) (*Cart, error)
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (*CreateOrderRow, error)
 	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (*OrderItem, error)
+	CreateOrderStatusChange(ctx context.Context, arg CreateOrderStatusChangeParams) (*OrderStatusChange, error)
+	CreatePaymentAttempt(ctx context.Context, arg CreatePaymentAttemptParams) (*PaymentAttempt, error)
+	CreatePaymentRefund(ctx context.Context, arg CreatePaymentRefundParams) (*PaymentRefund, error)
+	CreateReturn(ctx context.Context, arg CreateReturnParams) (*Return, error)
+	CreateSeries(ctx context.Context, arg CreateSeriesParams) (*Series, error)
 	CreateUser(ctx context.Context, email string) (*User, error)
+	DecrementBookStock(ctx context.Context, arg DecrementBookStockParams) (int64, error)
+	DeleteBookCategories(ctx context.Context, bookID int64) error
+	DeleteCart(ctx context.Context, id int64) error
+	DeleteCartItem(ctx context.Context, arg DeleteCartItemParams) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, limit int64) (int64, error)
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	DiscontinueBook(ctx context.Context, arg DiscontinueBookParams) (int64, error)
+	DiscontinueBooksByISBN(ctx context.Context, isbns []string) (int64, error)
+	EstimateBooksCount(ctx context.Context) (int64, error)
+	ExportBooks(ctx context.Context, arg ExportBooksParams) ([]*Book, error)
 	FindBook(ctx context.Context, id int64) (*Book, error)
+	FindBookBySKU(ctx context.Context, sku string) (*Book, error)
+	FindCategory(ctx context.Context, id int64) (*Category, error)
+	FindGuestCart(ctx context.Context, token pgtype.Text) (*Cart, error)
+	FindIdempotencyKey(ctx context.Context, arg FindIdempotencyKeyParams) (*IdempotencyKey, error)
+	FindOrder(ctx context.Context, id int64) (*FindOrderRow, error)
+	FindOrderForUpdate(ctx context.Context, id int64) (*FindOrderForUpdateRow, error)
+	FindOrderItem(ctx context.Context, arg FindOrderItemParams) (*OrderItem, error)
+	FindOrderPaymentAttempt(ctx context.Context, arg FindOrderPaymentAttemptParams) (*PaymentAttempt, error)
+	FindPaymentAttempt(ctx context.Context, arg FindPaymentAttemptParams) (*PaymentAttempt, error)
+	FindPendingPaymentRefundForUpdate(ctx context.Context, id int64) (*FindPendingPaymentRefundForUpdateRow, error)
+	FindReturnForUpdate(ctx context.Context, id int64) (*Return, error)
+	FindSeries(ctx context.Context, id int64) (*Series, error)
 	FindUser(ctx context.Context, email string) (*User, error)
 	FindUserByToken(ctx context.Context, token pgtype.Text) (*User, error)
-	GetBooks(ctx context.Context, arg GetBooksParams) ([]*Book, error)
-	GetMyOrderItems(ctx context.Context, orderID int64) ([]*OrderItem, error)
+	FindWork(ctx context.Context, id int64) (*Work, error)
+	GetBookCategories(ctx context.Context, bookID int64) ([]*Category, error)
+	GetBookFacets(ctx context.Context, arg GetBookFacetsParams) ([]*GetBookFacetsRow, error)
+	GetBooks(ctx context.Context, arg GetBooksParams) ([]*GetBooksRow, error)
+	GetCartItems(ctx context.Context, cartID int64) ([]*GetCartItemsRow, error)
+	GetCategories(ctx context.Context) ([]*Category, error)
+	GetExpiredOrders(ctx context.Context, limit int64) ([]*GetExpiredOrdersRow, error)
 	GetMyOrders(ctx context.Context, arg GetMyOrdersParams) ([]*GetMyOrdersRow, error)
+	GetOrCreateUserCart(ctx context.Context, userID pgtype.Int8) (*Cart, error)
+	GetOrderItems(ctx context.Context, orderIds []int64) ([]*GetOrderItemsRow, error)
+	GetOrderReturns(ctx context.Context, orderIds []int64) ([]*Return, error)
+	GetOrderStatusChanges(ctx context.Context, orderIds []int64) ([]*OrderStatusChange, error)
+	GetOrderTotal(ctx context.Context, orderID int64) (int64, error)
+	GetPendingPaymentRefunds(ctx context.Context, arg GetPendingPaymentRefundsParams) ([]int64, error)
+	GetReturnedAmount(ctx context.Context, arg GetReturnedAmountParams) (int64, error)
+	GetReturns(ctx context.Context, arg GetReturnsParams) ([]*Return, error)
+	GetSeriesOfBooks(ctx context.Context, bookIds []int64) ([]*Series, error)
+	GetSeriesVolumes(ctx context.Context, seriesIds []int64) ([]*GetSeriesVolumesRow, error)
+	GetWorkEditions(ctx context.Context, workID int64) ([]*Book, error)
+	GetWorkIDsOfBooks(ctx context.Context, bookIds []int64) ([]int64, error)
+	IncrementBookStock(ctx context.Context, arg IncrementBookStockParams) (int64, error)
+	LinkStagedBookCategories(ctx context.Context, batchID string) error
+	MergeCartItems(ctx context.Context, arg MergeCartItemsParams) error
+	ReleaseOrderStock(ctx context.Context, id int64) (int64, error)
+	ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (int64, error)
+	SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error
+	SetBookCover(ctx context.Context, arg SetBookCoverParams) (*Book, error)
+	SetCartItem(ctx context.Context, arg SetCartItemParams) error
+	SuggestBooks(ctx context.Context, arg SuggestBooksParams) ([]*SuggestBooksRow, error)
+	UpdateBook(ctx context.Context, arg UpdateBookParams) (*UpdateBookRow, error)
+	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (*UpdateOrderStatusRow, error)
+	UpdatePaymentAttempt(ctx context.Context, arg UpdatePaymentAttemptParams) (*PaymentAttempt, error)
+	UpdatePaymentRefund(ctx context.Context, arg UpdatePaymentRefundParams) error
+	UpdateReturn(ctx context.Context, arg UpdateReturnParams) (*Return, error)
+	UpsertBooksFromStaging(ctx context.Context, batchID string) (*UpsertBooksFromStagingRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -12,7 +12,7 @@ import (
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO "users" ("email", "created_at") VALUES ($1, NOW()) ON CONFLICT(email) DO NOTHING RETURNING id, email, created_at, password, token
+INSERT INTO "users" ("email", "created_at") VALUES ($1, NOW()) ON CONFLICT(email) DO NOTHING RETURNING id, email, created_at, password, token, role
 `
 
 func (q *Queries) CreateUser(ctx context.Context, email string) (*User, error) {
@@ -24,12 +24,13 @@ func (q *Queries) CreateUser(ctx context.Context, email string) (*User, error) {
 		&i.CreatedAt,
 		&i.Password,
 		&i.Token,
+		&i.Role,
 	)
 	return &i, err
 }
 
 const findUser = `-- name: FindUser :one
-SELECT id, email, created_at, password, token FROM "users" WHERE "email" = $1
+SELECT id, email, created_at, password, token, role FROM "users" WHERE "email" = $1
 `
 
 func (q *Queries) FindUser(ctx context.Context, email string) (*User, error) {
@@ -41,12 +42,13 @@ func (q *Queries) FindUser(ctx context.Context, email string) (*User, error) {
 		&i.CreatedAt,
 		&i.Password,
 		&i.Token,
+		&i.Role,
 	)
 	return &i, err
 }
 
 const findUserByToken = `-- name: FindUserByToken :one
-SELECT id, email, created_at, password, token FROM "users" WHERE "token" = $1
+SELECT id, email, created_at, password, token, role FROM "users" WHERE "token" = $1
 `
 
 func (q *Queries) FindUserByToken(ctx context.Context, token pgtype.Text) (*User, error) {
@@ -58,6 +60,7 @@ func (q *Queries) FindUserByToken(ctx context.Context, token pgtype.Text) (*User
 		&i.CreatedAt,
 		&i.Password,
 		&i.Token,
+		&i.Role,
 	)
 	return &i, err
 }
@@ -0,0 +1,150 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository/db"
+)
+
+// countingQuerier serves pages of orders from memory and counts the queries it is asked,
+// each query waits roundTrip like one sent to the database would.
+type countingQuerier struct {
+	db.QuerierWithTx
+
+	orders    []*db.GetMyOrdersRow
+	items     map[int64][]*db.GetOrderItemsRow
+	queries   int
+	roundTrip time.Duration
+}
+
+// newCountingQuerier holds size orders with 1 to 5 items each, two status changes and no returns per order.
+func newCountingQuerier(size int64) *countingQuerier {
+	q := &countingQuerier{items: map[int64][]*db.GetOrderItemsRow{}}
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	for id := int64(1000); id > 1000-size; id-- {
+		q.orders = append(q.orders, &db.GetMyOrdersRow{
+			OrderID:   id,
+			UserID:    5,
+			Email:     "someone@test.com",
+			Status:    entity.OrderStatusPaid,
+			CreatedAt: now,
+		})
+		for n := int64(0); n <= id%5; n++ {
+			bookID := id*10 + n
+			q.items[id] = append(q.items[id], &db.GetOrderItemsRow{
+				ID:          bookID,
+				OrderID:     id,
+				BookID:      bookID,
+				Amount:      n + 1,
+				CreatedAt:   now,
+				Sku:         pgtype.Text{String: fmt.Sprintf("BK%08d", bookID), Valid: true},
+				Price:       pgtype.Int8{Int64: 9900, Valid: true},
+				BookName:    pgtype.Text{String: fmt.Sprintf("Book %d", bookID), Valid: true},
+				BookAuthors: pgtype.Text{String: "Some Author", Valid: true},
+				BookStatus:  pgtype.Text{String: entity.BookStatusActive, Valid: true},
+			})
+		}
+	}
+
+	return q
+}
+
+func (q *countingQuerier) query() {
+	q.queries++
+	time.Sleep(q.roundTrip)
+}
+
+func (q *countingQuerier) GetMyOrders(ctx context.Context, arg db.GetMyOrdersParams) ([]*db.GetMyOrdersRow, error) {
+	q.query()
+	return q.orders, nil
+}
+
+func (q *countingQuerier) GetOrderItems(ctx context.Context, orderIds []int64) ([]*db.GetOrderItemsRow, error) {
+	q.query()
+
+	var items []*db.GetOrderItemsRow
+	for _, id := range orderIds {
+		items = append(items, q.items[id]...)
+	}
+
+	return items, nil
+}
+
+func (q *countingQuerier) GetOrderStatusChanges(ctx context.Context, orderIds []int64) ([]*db.OrderStatusChange, error) {
+	q.query()
+
+	changes := make([]*db.OrderStatusChange, 0, 2*len(orderIds))
+	for _, id := range orderIds {
+		changes = append(changes,
+			&db.OrderStatusChange{OrderID: id, ToStatus: entity.OrderStatusPendingPayment, ActorRole: entity.UserRoleCustomer},
+			&db.OrderStatusChange{
+				OrderID:    id,
+				FromStatus: pgtype.Text{String: entity.OrderStatusPendingPayment, Valid: true},
+				ToStatus:   entity.OrderStatusPaid,
+				ActorRole:  entity.OrderActorSystem,
+			})
+	}
+
+	return changes, nil
+}
+
+func (q *countingQuerier) GetOrderReturns(ctx context.Context, orderIds []int64) ([]*db.Return, error) {
+	q.query()
+	return nil, nil
+}
+
+type QueryCountTestSuite struct {
+	suite.Suite
+}
+
+func TestQueryCount(t *testing.T) {
+	suite.Run(t, new(QueryCountTestSuite))
+}
+
+func (s *QueryCountTestSuite) TestGetMyOrdersQueriesDoNotGrowWithThePage() {
+	ctx := context.Background()
+
+	for _, size := range []int64{1, 10, 100} {
+		s.Run(fmt.Sprintf("page of %d orders", size), func() {
+			q := newCountingQuerier(size)
+			wrapper := repository.NewDbWrapperRepo(q)
+
+			orders, err := wrapper.GetMyOrders(ctx, entity.GetMyOrdersParams{UserID: 5, Limit: size})
+			s.Require().Nil(err)
+			s.Require().Len(orders, int(size))
+
+			// the page, then its items, status changes and returns
+			s.Assert().Equal(4, q.queries)
+			for _, order := range orders {
+				s.Assert().Len(order.Items, len(q.items[order.ID]))
+				s.Assert().Len(order.History, 2)
+			}
+		})
+	}
+}
+
+// BenchmarkGetMyOrders lists a page of 100 orders with 1 to 5 items each,
+// with a round trip of 250µs per query like a database on the same network.
+func BenchmarkGetMyOrders(b *testing.B) {
+	ctx := context.Background()
+	q := newCountingQuerier(100)
+	q.roundTrip = 250 * time.Microsecond
+	wrapper := repository.NewDbWrapperRepo(q)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := wrapper.GetMyOrders(ctx, entity.GetMyOrdersParams{UserID: 5, Limit: 100}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(q.queries)/float64(b.N), "queries/op")
+}
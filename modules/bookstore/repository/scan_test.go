@@ -0,0 +1,207 @@
+package repository_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository/db"
+)
+
+// scanRecorder is a db.DBTX that answers every query with one row and keeps the SQL and the scan destinations, so the
+// columns a query selects can be held against the fields the generated code scans them into.
+type scanRecorder struct {
+	sql  string
+	dest []any
+}
+
+func (r *scanRecorder) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (r *scanRecorder) Query(_ context.Context, sql string, _ ...interface{}) (pgx.Rows, error) {
+	r.sql = sql
+	return &recordedRows{recorder: r}, nil
+}
+
+func (r *scanRecorder) QueryRow(_ context.Context, sql string, _ ...interface{}) pgx.Row {
+	r.sql = sql
+	return &recordedRows{recorder: r}
+}
+
+func (r *scanRecorder) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, nil
+}
+
+type recordedRows struct {
+	recorder *scanRecorder
+	read     bool
+}
+
+func (r *recordedRows) Close()                                       {}
+func (r *recordedRows) Err() error                                   { return nil }
+func (r *recordedRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *recordedRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *recordedRows) Values() ([]any, error)                       { return nil, nil }
+func (r *recordedRows) RawValues() [][]byte                          { return nil }
+func (r *recordedRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *recordedRows) Next() bool {
+	next := !r.read
+	r.read = true
+	return next
+}
+
+func (r *recordedRows) Scan(dest ...any) error {
+	r.recorder.dest = dest
+	return nil
+}
+
+type ScanTestSuite struct {
+	suite.Suite
+}
+
+func TestScan(t *testing.T) {
+	suite.Run(t, new(ScanTestSuite))
+}
+
+func (s *ScanTestSuite) TestBookQueriesScanTheSelectedColumns() {
+	ctx := context.Background()
+	first := func(rows any, err error) (any, error) {
+		v := reflect.ValueOf(rows)
+		if err != nil || v.Len() == 0 {
+			return nil, err
+		}
+		return v.Index(0).Interface(), nil
+	}
+
+	cases := map[string]func(q *db.Queries) (any, error){
+		"CreateBook": func(q *db.Queries) (any, error) { return q.CreateBook(ctx, db.CreateBookParams{}) },
+		"ExportBooks": func(q *db.Queries) (any, error) {
+			return first(q.ExportBooks(ctx, db.ExportBooksParams{}))
+		},
+		"FindBook":      func(q *db.Queries) (any, error) { return q.FindBook(ctx, 1) },
+		"FindBookBySKU": func(q *db.Queries) (any, error) { return q.FindBookBySKU(ctx, "BK00000001") },
+		"GetBooks": func(q *db.Queries) (any, error) {
+			return first(q.GetBooks(ctx, db.GetBooksParams{}))
+		},
+		"GetSeriesVolumes": func(q *db.Queries) (any, error) {
+			return first(q.GetSeriesVolumes(ctx, []int64{1}))
+		},
+		"GetWorkEditions": func(q *db.Queries) (any, error) {
+			return first(q.GetWorkEditions(ctx, 1))
+		},
+		"SetBookCover": func(q *db.Queries) (any, error) { return q.SetBookCover(ctx, db.SetBookCoverParams{}) },
+		"SuggestBooks": func(q *db.Queries) (any, error) {
+			return first(q.SuggestBooks(ctx, db.SuggestBooksParams{}))
+		},
+		"UpdateBook": func(q *db.Queries) (any, error) { return q.UpdateBook(ctx, db.UpdateBookParams{}) },
+	}
+
+	for name, call := range cases {
+		s.Run(name, func() {
+			recorder := &scanRecorder{}
+			row, err := call(db.New(recorder))
+			s.Require().Nil(err)
+			s.Require().NotNil(row)
+
+			s.Assert().Equal(selectedColumns(recorder.sql), scannedColumns(row, recorder.dest))
+		})
+	}
+}
+
+func (s *ScanTestSuite) TestSelectedColumns() {
+	s.Assert().Equal([]string{"id", "series_name", "highlight"}, selectedColumns(`-- name: X :many
+SELECT b.id, sr.name AS series_name, COALESCE(ts_headline(b.name, 'StartSel=<mark>, StopSel=</mark>'), '')::text AS highlight
+FROM "books" b LEFT JOIN LATERAL (SELECT 1 FROM series) q ON TRUE`))
+	s.Assert().Equal([]string{"id", "name"}, selectedColumns(`-- name: X :one
+UPDATE "books" SET "name" = $1 WHERE "id" = $2
+RETURNING id, "name"`))
+}
+
+// selectedColumns lists the names of the columns a query returns, from its select list or its RETURNING clause.
+func selectedColumns(sql string) []string {
+	body := sql[strings.Index(sql, "\n")+1:]
+
+	var list string
+	if start := topLevelIndex(body, "RETURNING "); start >= 0 {
+		list = body[start+len("RETURNING "):]
+	} else {
+		start := topLevelIndex(body, "SELECT ") + len("SELECT ")
+		end := topLevelIndex(body[start:], "FROM ")
+		list = body[start : start+end]
+	}
+
+	var columns []string
+	for _, item := range splitTopLevel(list) {
+		item = strings.TrimSpace(item)
+		if as := strings.LastIndex(strings.ToUpper(item), " AS "); as >= 0 {
+			item = item[as+len(" AS "):]
+		} else if dot := strings.LastIndex(item, "."); dot >= 0 {
+			item = item[dot+1:]
+		}
+		columns = append(columns, strings.Trim(item, `"`))
+	}
+	return columns
+}
+
+// scannedColumns lists the db tags of the fields of row the scan destinations point at.
+func scannedColumns(row any, dest []any) []string {
+	v := reflect.ValueOf(row).Elem()
+
+	var columns []string
+	for _, d := range dest {
+		ptr := reflect.ValueOf(d).Pointer()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).Addr().Pointer() == ptr {
+				columns = append(columns, v.Type().Field(i).Tag.Get("db"))
+			}
+		}
+	}
+	return columns
+}
+
+// topLevelIndex finds keyword outside of parentheses and string literals, -1 when it is not there.
+func topLevelIndex(sql, keyword string) int {
+	depth, quoted := 0, false
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case sql[i] == '\'':
+			quoted = !quoted
+		case quoted:
+		case sql[i] == '(':
+			depth++
+		case sql[i] == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(sql[i:], keyword) && (i == 0 || strings.ContainsRune(" \n", rune(sql[i-1]))):
+			return i
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits a select list on the commas outside of parentheses and string literals.
+func splitTopLevel(list string) []string {
+	var items []string
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(list); i++ {
+		switch {
+		case list[i] == '\'':
+			quoted = !quoted
+		case quoted:
+		case list[i] == '(':
+			depth++
+		case list[i] == ')':
+			depth--
+		case depth == 0 && list[i] == ',':
+			items = append(items, list[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, list[start:])
+}
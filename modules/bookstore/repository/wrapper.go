@@ -3,16 +3,29 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/raymondwongso/gogox/errorx"
 
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/repository/db"
 )
 
+// postgres error codes the wrapper turns into client errors
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
+
 type DbWrapperRepo struct {
 	db db.QuerierWithTx
 }
@@ -58,10 +71,41 @@ func (w *DbWrapperRepo) FindUser(ctx context.Context, email string) (*entity.Use
 }
 
 func (w *DbWrapperRepo) GetBooks(ctx context.Context, arg entity.GetBooksParams) ([]entity.Book, error) {
-	result, err := w.db.GetBooks(ctx, db.GetBooksParams{
+	params := db.GetBooksParams{
+		Query:    optionalText(arg.Query),
+		Author:   optionalText(arg.Author),
+		Category: optionalText(arg.Category),
+		CategoryID: pgtype.Int8{
+			Int64: arg.CategoryID,
+			Valid: arg.CategoryID != 0,
+		},
+		Language: optionalText(arg.Language),
+		Format:   optionalText(arg.Format),
+		MinPrice: optionalInt8(arg.MinPrice),
+		MaxPrice: optionalInt8(arg.MaxPrice),
+		InStock:  arg.InStock,
+		PublishedYear: pgtype.Int4{
+			Int32: arg.PublishedYear,
+			Valid: arg.PublishedYear != 0,
+		},
+		Sort:   arg.Sort,
 		Limit:  arg.Limit,
 		Offset: arg.Offset,
-	})
+	}
+
+	if arg.After != nil {
+		params.AfterID = pgtype.Int8{Int64: arg.After.ID, Valid: true}
+		params.AfterRank = pgtype.Float4{Float32: arg.After.Rank, Valid: true}
+		params.AfterName = pgtype.Text{String: arg.After.Name, Valid: true}
+		params.AfterPrice = pgtype.Int8{Int64: arg.After.Price, Valid: true}
+		params.AfterSoldCount = pgtype.Int8{Int64: arg.After.SoldCount, Valid: true}
+		params.AfterCreatedAt = pgtype.Timestamptz{Time: arg.After.CreatedAt, Valid: true}
+		if arg.After.PublishedAt != nil {
+			params.AfterPublishedAt = pgtype.Date{Time: *arg.After.PublishedAt, Valid: true}
+		}
+	}
+
+	result, err := w.db.GetBooks(ctx, params)
 	if err != nil {
 		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
 	}
@@ -75,79 +119,417 @@ func (w *DbWrapperRepo) GetBooks(ctx context.Context, arg entity.GetBooksParams)
 	return resp, nil
 }
 
-func (w *DbWrapperRepo) CreateOrder(ctx context.Context, tx pgx.Tx, arg entity.CreateOrderParams) (*entity.Order, error) {
-	result, err := w.db.WrapTx(tx).CreateOrder(ctx, arg.UserID)
+func (w *DbWrapperRepo) CountBooks(ctx context.Context, arg entity.GetBooksParams) (int64, error) {
+	total, err := w.db.CountBooks(ctx, db.CountBooksParams{
+		Query:    optionalText(arg.Query),
+		Author:   optionalText(arg.Author),
+		Category: optionalText(arg.Category),
+		CategoryID: pgtype.Int8{
+			Int64: arg.CategoryID,
+			Valid: arg.CategoryID != 0,
+		},
+		Language: optionalText(arg.Language),
+		Format:   optionalText(arg.Format),
+		MinPrice: optionalInt8(arg.MinPrice),
+		MaxPrice: optionalInt8(arg.MaxPrice),
+		InStock:  arg.InStock,
+		PublishedYear: pgtype.Int4{
+			Int32: arg.PublishedYear,
+			Valid: arg.PublishedYear != 0,
+		},
+	})
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return total, nil
+}
+
+func (w *DbWrapperRepo) EstimateBooksCount(ctx context.Context) (int64, error) {
+	estimate, err := w.db.EstimateBooksCount(ctx)
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return estimate, nil
+}
+
+func (w *DbWrapperRepo) ExportBooks(ctx context.Context, arg entity.ExportBooksParams) ([]entity.Book, error) {
+	params := db.ExportBooksParams{
+		AfterID: arg.AfterID,
+		Limit:   arg.Limit,
+	}
+	if arg.UpdatedSince != nil {
+		params.UpdatedSince = pgtype.Timestamptz{Time: *arg.UpdatedSince, Valid: true}
+	}
 
+	result, err := w.db.ExportBooks(ctx, params)
 	if err != nil {
 		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
 	}
 
-	return result.ToEntity(), nil
+	resp := make([]entity.Book, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
 }
 
-func (w *DbWrapperRepo) GetMyOrders(ctx context.Context, arg entity.GetMyOrdersParams) ([]entity.Order, error) {
-	result, err := w.db.GetMyOrders(ctx, db.GetMyOrdersParams{
-		Limit:  arg.Limit,
-		Offset: arg.Offset,
-		UserID: arg.UserID,
+func (w *DbWrapperRepo) GetBookFacets(ctx context.Context, arg entity.GetBooksParams) (*entity.BookFacets, error) {
+	result, err := w.db.GetBookFacets(ctx, db.GetBookFacetsParams{
+		Query:      optionalText(arg.Query),
+		PriceEdges: entity.BookPriceFacetEdges,
+		Author:     optionalText(arg.Author),
+		Category:   optionalText(arg.Category),
+		CategoryID: pgtype.Int8{
+			Int64: arg.CategoryID,
+			Valid: arg.CategoryID != 0,
+		},
+		Language: optionalText(arg.Language),
+		Format:   optionalText(arg.Format),
+		MinPrice: optionalInt8(arg.MinPrice),
+		MaxPrice: optionalInt8(arg.MaxPrice),
+		InStock:  arg.InStock,
+		PublishedYear: pgtype.Int4{
+			Int32: arg.PublishedYear,
+			Valid: arg.PublishedYear != 0,
+		},
 	})
 	if err != nil {
 		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
 	}
 
-	resp := []entity.Order{}
-	orders := map[int64]entity.Order{}
-
+	facets := &entity.BookFacets{
+		Categories:  []entity.FacetCount{},
+		Languages:   []entity.FacetCount{},
+		Formats:     []entity.FacetCount{},
+		PriceRanges: []entity.PriceFacetCount{},
+	}
 	for _, r := range result {
-		var order entity.Order
-		var exist bool
-		if order, exist = orders[r.OrderID]; !exist {
-			order = entity.Order{
-				ID:        r.OrderID,
-				UserID:    r.UserID,
-				Email:     r.Email,
-				Items:     []entity.OrderItem{},
-				CreatedAt: r.CreatedAt.Time,
+		count := entity.FacetCount{Value: r.Value, Count: r.Total}
+		switch r.Facet {
+		case "category":
+			count.ID = r.CategoryID
+			facets.Categories = append(facets.Categories, count)
+		case "language":
+			facets.Languages = append(facets.Languages, count)
+		case "format":
+			facets.Formats = append(facets.Formats, count)
+		case "price":
+			priceRange, err := priceFacetFromBucket(r.Value, r.Total)
+			if err != nil {
+				return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
 			}
-			orders[r.OrderID] = order
+			facets.PriceRanges = append(facets.PriceRanges, *priceRange)
 		}
+	}
 
-		orderItems, err := w.db.GetMyOrderItems(ctx, r.OrderID)
-		if err != nil {
-			return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	sort.Slice(facets.PriceRanges, func(i, j int) bool {
+		return facets.PriceRanges[i].Min < facets.PriceRanges[j].Min
+	})
+
+	return facets, nil
+}
+
+// CreateBook adds a book as an edition of arg.WorkID, or of a new work when it has none. The series and volume are set
+// on that work, in the same statement.
+func (w *DbWrapperRepo) CreateBook(ctx context.Context, arg entity.CreateBookParams) (*entity.Book, error) {
+	// without a work the book starts a work of its own
+	var workID int64
+	if arg.WorkID != nil {
+		workID = *arg.WorkID
+	}
+
+	result, err := w.db.CreateBook(ctx, db.CreateBookParams{
+		Name:         arg.Name,
+		Authors:      arg.Authors,
+		Description:  arg.Description,
+		Category:     arg.Category,
+		Language:     arg.Language,
+		Format:       arg.Format,
+		Price:        arg.Price,
+		Stock:        arg.Stock,
+		PublishedAt:  optionalDate(arg.PublishedAt),
+		Status:       arg.Status,
+		SeriesID:     optionalInt8(arg.SeriesID),
+		SeriesVolume: optionalInt4(arg.SeriesVolume),
+		WorkID:       workID,
+	})
+	if err != nil {
+		return nil, bookWriteError(err)
+	}
+
+	return result.ToEntity(), nil
+}
+
+// UpdateBook applies the update only when the stored version still matches arg.Version, a series or volume is set on
+// the work of the book. When nothing was updated the book is looked up again to tell a missing book from a stale
+// version.
+func (w *DbWrapperRepo) UpdateBook(ctx context.Context, arg entity.UpdateBookParams) (*entity.Book, error) {
+	result, err := w.db.UpdateBook(ctx, db.UpdateBookParams{
+		Name:         optionalTextPtr(arg.Name),
+		Authors:      optionalTextPtr(arg.Authors),
+		Description:  optionalTextPtr(arg.Description),
+		Category:     optionalTextPtr(arg.Category),
+		Language:     optionalTextPtr(arg.Language),
+		Format:       optionalTextPtr(arg.Format),
+		Price:        optionalInt8(arg.Price),
+		Stock:        optionalInt8(arg.Stock),
+		PublishedAt:  optionalDate(arg.PublishedAt),
+		Status:       optionalTextPtr(arg.Status),
+		SeriesID:     optionalInt8(arg.SeriesID),
+		SeriesVolume: optionalInt4(arg.SeriesVolume),
+		WorkID:       optionalInt8(arg.WorkID),
+		ID:           arg.ID,
+		Version:      arg.Version,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, w.versionConflict(ctx, arg.ID)
+		}
+		return nil, bookWriteError(err)
+	}
+
+	return result.ToEntity(), nil
+}
+
+// DeleteBook soft-deletes a book by discontinuing it.
+func (w *DbWrapperRepo) DeleteBook(ctx context.Context, arg entity.DeleteBookParams) error {
+	discontinued, err := w.db.DiscontinueBook(ctx, db.DiscontinueBookParams{
+		ID: arg.ID,
+		Version: pgtype.Int8{
+			Int64: arg.Version,
+			Valid: arg.Version != 0,
+		},
+	})
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	if discontinued == 0 {
+		return w.versionConflict(ctx, arg.ID)
+	}
+
+	return nil
+}
+
+// bookWriteError tells the constraint violations a book write can run into from internal errors.
+func bookWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			switch pgErr.ConstraintName {
+			case "idx_books_isbn":
+				return customerror.ErrUnprocessableEntity("isbn already exists")
+			case "idx_books_sku":
+				return customerror.ErrUnprocessableEntity("sku already exists")
+			case "idx_works_series_id_series_volume":
+				return customerror.ErrUnprocessableEntity("series volume already exists")
+			}
+			return customerror.ErrUnprocessableEntity("book already exists")
+		case pgForeignKeyViolation:
+			switch pgErr.ConstraintName {
+			case "books_work_id_fkey":
+				return customerror.ErrUnprocessableEntity("work cannot be found")
+			case "works_series_id_fkey":
+				return customerror.ErrUnprocessableEntity("series cannot be found")
+			}
+		}
+	}
+
+	return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+}
+
+// SetBookCover points the book at the cover stored under coverKey.
+func (w *DbWrapperRepo) SetBookCover(ctx context.Context, id int64, coverKey string) (*entity.Book, error) {
+	result, err := w.db.SetBookCover(ctx, db.SetBookCoverParams{
+		CoverKey: optionalText(coverKey),
+		ID:       id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "book cannot be found")
 		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
 
-		for _, item := range orderItems {
-			order.Items = append(order.Items, *item.ToEntity())
+	return result.ToEntity(), nil
+}
+
+func (w *DbWrapperRepo) versionConflict(ctx context.Context, bookID int64) error {
+	_, err := w.db.FindBook(ctx, bookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.Wrap(err, errorx.CodeNotFound, "book cannot be found")
 		}
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return customerror.ErrPreconditionFailed("book has been modified")
+}
+
+func (w *DbWrapperRepo) CopyBooksToStaging(ctx context.Context, tx pgx.Tx, batchID string, rows []entity.BookImportRow) (int64, error) {
+	params := make([]db.CopyBooksToStagingParams, 0, len(rows))
+	for _, r := range rows {
+		params = append(params, db.CopyBooksToStagingParams{
+			BatchID:     batchID,
+			Line:        r.Line,
+			Isbn:        r.ISBN,
+			Name:        r.Name,
+			Authors:     optionalText(r.Authors),
+			Description: optionalText(r.Description),
+			Category:    optionalText(r.Category),
+			Language:    optionalText(r.Language),
+			Format:      optionalText(r.Format),
+			Price:       optionalInt8(r.Price),
+			Stock:       optionalInt8(r.Stock),
+			PublishedAt: optionalDate(r.PublishedAt),
+			Status:      optionalText(r.Status),
+			BisacCodes:  r.BISACCodes,
+			ThemaCodes:  r.ThemaCodes,
+		})
+	}
+
+	copied, err := w.db.WrapTx(tx).CopyBooksToStaging(ctx, params)
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return copied, nil
+}
+
+func (w *DbWrapperRepo) DiscontinueBooksByISBN(ctx context.Context, tx pgx.Tx, isbns []string) (int64, error) {
+	discontinued, err := w.db.WrapTx(tx).DiscontinueBooksByISBN(ctx, isbns)
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return discontinued, nil
+}
+
+func (w *DbWrapperRepo) UpsertBooksFromStaging(ctx context.Context, tx pgx.Tx, batchID string) (*entity.BookUpsertCount, error) {
+	result, err := w.db.WrapTx(tx).UpsertBooksFromStaging(ctx, batchID)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return &entity.BookUpsertCount{
+		Inserted: result.Inserted,
+		Updated:  result.Updated,
+	}, nil
+}
+
+// LinkStagedBookCategories adds the books of a batch to the categories matching their BISAC or Thema codes. Links
+// already there are kept.
+func (w *DbWrapperRepo) LinkStagedBookCategories(ctx context.Context, tx pgx.Tx, batchID string) error {
+	if err := w.db.WrapTx(tx).LinkStagedBookCategories(ctx, batchID); err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return nil
+}
+
+func (w *DbWrapperRepo) ClearBooksStaging(ctx context.Context, tx pgx.Tx, batchID string) error {
+	if err := w.db.WrapTx(tx).ClearBooksStaging(ctx, batchID); err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
 
-		orders[r.OrderID] = order
-		resp = append(resp, order)
+	return nil
+}
+
+func (w *DbWrapperRepo) GetCategories(ctx context.Context) ([]*entity.Category, error) {
+	result, err := w.db.GetCategories(ctx)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]*entity.Category, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, r.ToEntity())
 	}
 
 	return resp, nil
 }
 
-func (w *DbWrapperRepo) FindBook(ctx context.Context, tx pgx.Tx, id int64) (*entity.Book, error) {
-	result, err := w.db.WrapTx(tx).FindBook(ctx, id)
+func (w *DbWrapperRepo) FindCategory(ctx context.Context, id int64) (*entity.Category, error) {
+	result, err := w.db.FindCategory(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errorx.Wrap(err, errorx.CodeNotFound, "book cannot be found")
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "category cannot be found")
 		}
 		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
 	}
 
-	return result.ToEntity(), err
+	return result.ToEntity(), nil
 }
 
-func (w *DbWrapperRepo) FindUserByToken(ctx context.Context, token string) (*entity.User, error) {
-	result, err := w.db.FindUserByToken(ctx, pgtype.Text{
-		String: token,
-		Valid:  true,
+func (w *DbWrapperRepo) CreateCategory(ctx context.Context, arg entity.CreateCategoryParams) (*entity.Category, error) {
+	result, err := w.db.CreateCategory(ctx, db.CreateCategoryParams{
+		ParentID:  optionalInt8(arg.ParentID),
+		Name:      arg.Name,
+		Slug:      arg.Slug,
+		BisacCode: optionalText(arg.BISACCode),
+		ThemaCode: optionalText(arg.ThemaCode),
 	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgUniqueViolation:
+				return nil, customerror.ErrUnprocessableEntity("category already exists")
+			case pgForeignKeyViolation:
+				return nil, customerror.ErrUnprocessableEntity("parent category cannot be found")
+			}
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+func (w *DbWrapperRepo) GetBookCategories(ctx context.Context, bookID int64) ([]*entity.Category, error) {
+	result, err := w.db.GetBookCategories(ctx, bookID)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]*entity.Category, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+// SetBookCategories replaces the categories of a book and returns how many were linked, ids of categories that do not
+// exist are skipped.
+func (w *DbWrapperRepo) SetBookCategories(ctx context.Context, tx pgx.Tx, bookID int64, categoryIDs []int64) (int64, error) {
+	if err := w.db.WrapTx(tx).DeleteBookCategories(ctx, bookID); err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	if len(categoryIDs) == 0 {
+		return 0, nil
+	}
+
+	added, err := w.db.WrapTx(tx).AddBookCategories(ctx, db.AddBookCategoriesParams{
+		BookID:      bookID,
+		CategoryIds: categoryIDs,
+	})
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return added, nil
+}
+
+func (w *DbWrapperRepo) FindSeries(ctx context.Context, id int64) (*entity.Series, error) {
+	result, err := w.db.FindSeries(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, sql.ErrNoRows
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "series cannot be found")
 		}
 		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
 	}
@@ -155,15 +537,933 @@ func (w *DbWrapperRepo) FindUserByToken(ctx context.Context, token string) (*ent
 	return result.ToEntity(), nil
 }
 
-func (w *DbWrapperRepo) CreateOrderItem(ctx context.Context, tx pgx.Tx, params entity.CreateOrderItemParams) (*entity.OrderItem, error) {
-	result, err := w.db.WrapTx(tx).CreateOrderItem(ctx, db.CreateOrderItemParams{
-		OrderID: params.OrderID,
-		BookID:  params.BookID,
-		Amount:  params.Amount,
+func (w *DbWrapperRepo) CreateSeries(ctx context.Context, arg entity.CreateSeriesParams) (*entity.Series, error) {
+	result, err := w.db.CreateSeries(ctx, db.CreateSeriesParams{
+		Name:        arg.Name,
+		Description: arg.Description,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+func (w *DbWrapperRepo) FindWork(ctx context.Context, id int64) (*entity.Work, error) {
+	result, err := w.db.FindWork(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "work cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// GetWorkEditions returns the editions of a work that are not hidden, sorted by format.
+func (w *DbWrapperRepo) GetWorkEditions(ctx context.Context, workID int64) ([]entity.Book, error) {
+	result, err := w.db.GetWorkEditions(ctx, workID)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]entity.Book, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+// GetWorkIDsOfBooks returns the works the given books are editions of.
+func (w *DbWrapperRepo) GetWorkIDsOfBooks(ctx context.Context, bookIDs []int64) ([]int64, error) {
+	result, err := w.db.GetWorkIDsOfBooks(ctx, bookIDs)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result, nil
+}
+
+// GetSeriesVolumes returns the books of every series in seriesIDs that are not hidden, grouped by series and in volume
+// order. Every edition of a volume is listed.
+func (w *DbWrapperRepo) GetSeriesVolumes(ctx context.Context, seriesIDs []int64) ([]entity.Book, error) {
+	result, err := w.db.GetSeriesVolumes(ctx, seriesIDs)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]entity.Book, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+// GetSeriesOfBooks returns the series the works of the given books belong to.
+func (w *DbWrapperRepo) GetSeriesOfBooks(ctx context.Context, bookIDs []int64) ([]*entity.Series, error) {
+	result, err := w.db.GetSeriesOfBooks(ctx, bookIDs)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]*entity.Series, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+func (w *DbWrapperRepo) SuggestBooks(ctx context.Context, arg entity.SuggestBooksParams) ([]entity.BookSuggestion, error) {
+	result, err := w.db.SuggestBooks(ctx, db.SuggestBooksParams{
+		Prefix: arg.Prefix,
+		Limit:  arg.Limit,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := []entity.BookSuggestion{}
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+func (w *DbWrapperRepo) CreateOrder(ctx context.Context, tx pgx.Tx, arg entity.CreateOrderParams) (*entity.Order, error) {
+	result, err := w.db.WrapTx(tx).CreateOrder(ctx, db.CreateOrderParams{
+		UserID: arg.UserID,
+		ReservationTtl: pgtype.Interval{
+			Microseconds: arg.ReservationTTL.Microseconds(),
+			Valid:        true,
+		},
 	})
+
 	if err != nil {
 		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
 	}
 
 	return result.ToEntity(), nil
 }
+
+func (w *DbWrapperRepo) GetMyOrders(ctx context.Context, arg entity.GetMyOrdersParams) ([]entity.Order, error) {
+	result, err := w.db.GetMyOrders(ctx, db.GetMyOrdersParams{
+		Limit:  arg.Limit,
+		Offset: arg.Offset,
+		UserID: arg.UserID,
+		BeforeID: pgtype.Int8{
+			Int64: arg.BeforeID,
+			Valid: arg.BeforeID != 0,
+		},
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]entity.Order, 0, len(result))
+	orderIDs := make([]int64, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, entity.Order{
+			ID:        r.OrderID,
+			UserID:    r.UserID,
+			Email:     r.Email,
+			Status:    r.Status,
+			Items:     []entity.OrderItem{},
+			CreatedAt: r.CreatedAt.Time,
+		})
+		orderIDs = append(orderIDs, r.OrderID)
+	}
+
+	if len(orderIDs) == 0 {
+		return resp, nil
+	}
+
+	// items of the whole page come in one query, grouped by order here
+	orderItems, err := w.db.GetOrderItems(ctx, orderIDs)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	items := make(map[int64][]entity.OrderItem, len(orderIDs))
+	for _, item := range orderItems {
+		items[item.OrderID] = append(items[item.OrderID], *item.ToEntity())
+	}
+	for i := range resp {
+		if group, ok := items[resp[i].ID]; ok {
+			resp[i].Items = group
+		}
+	}
+
+	history, err := w.GetOrderStatusChanges(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byOrder := make(map[int64][]entity.OrderStatusChange, len(orderIDs))
+	for _, change := range history {
+		byOrder[change.OrderID] = append(byOrder[change.OrderID], change)
+	}
+	for i := range resp {
+		resp[i].History = byOrder[resp[i].ID]
+	}
+
+	returns, err := w.GetOrderReturns(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	returnsByOrder := make(map[int64][]entity.Return, len(orderIDs))
+	for _, ret := range returns {
+		returnsByOrder[ret.OrderID] = append(returnsByOrder[ret.OrderID], ret)
+	}
+	for i := range resp {
+		resp[i].Returns = returnsByOrder[resp[i].ID]
+	}
+
+	return resp, nil
+}
+
+// FindOrder returns the order with its items, history and returns.
+func (w *DbWrapperRepo) FindOrder(ctx context.Context, id int64) (*entity.Order, error) {
+	result, err := w.db.FindOrder(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "order cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	order := result.ToEntity()
+	orderItems, err := w.db.GetOrderItems(ctx, []int64{id})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	order.Items = make([]entity.OrderItem, 0, len(orderItems))
+	for _, item := range orderItems {
+		order.Items = append(order.Items, *item.ToEntity())
+	}
+
+	order.History, err = w.GetOrderStatusChanges(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+
+	order.Returns, err = w.GetOrderReturns(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetExpiredOrders locks up to limit unpaid orders whose reservation expired, oldest expiry first. Orders locked by
+// another transaction are skipped rather than waited for, so several sweepers can run at once without blocking.
+func (w *DbWrapperRepo) GetExpiredOrders(ctx context.Context, tx pgx.Tx, limit int64) ([]entity.Order, error) {
+	result, err := w.db.WrapTx(tx).GetExpiredOrders(ctx, limit)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]entity.Order, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+// FindOrderForUpdate locks the order until tx ends, so concurrent status changes are applied one after the other.
+func (w *DbWrapperRepo) FindOrderForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Order, error) {
+	result, err := w.db.WrapTx(tx).FindOrderForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "order cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// UpdateOrderStatus moves the order to the status to, provided it is still in the status from.
+func (w *DbWrapperRepo) UpdateOrderStatus(ctx context.Context, tx pgx.Tx, id int64, from, to string) (*entity.Order, error) {
+	result, err := w.db.WrapTx(tx).UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+		ToStatus:   to,
+		ID:         id,
+		FromStatus: from,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerror.ErrPreconditionFailed("order status has changed")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// ReleaseOrderStock puts the stock taken by the order items back on the shelf, less the copies received returns put back
+// already. It only does so once per order, calling it again, or for an order that never took stock, releases nothing.
+func (w *DbWrapperRepo) ReleaseOrderStock(ctx context.Context, tx pgx.Tx, orderID int64) (int64, error) {
+	released, err := w.db.WrapTx(tx).ReleaseOrderStock(ctx, orderID)
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return released, nil
+}
+
+func (w *DbWrapperRepo) CreateOrderStatusChange(ctx context.Context, tx pgx.Tx, change entity.OrderStatusChange) (*entity.OrderStatusChange, error) {
+	params := db.CreateOrderStatusChangeParams{
+		OrderID:    change.OrderID,
+		FromStatus: optionalText(change.From),
+		ToStatus:   change.To,
+		ActorID:    optionalInt8(change.ActorID),
+		ActorRole:  change.ActorRole,
+	}
+
+	result, err := w.db.WrapTx(tx).CreateOrderStatusChange(ctx, params)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// GetOrderStatusChanges returns the history of the given orders, grouped by order and oldest first.
+func (w *DbWrapperRepo) GetOrderStatusChanges(ctx context.Context, orderIDs []int64) ([]entity.OrderStatusChange, error) {
+	result, err := w.db.GetOrderStatusChanges(ctx, orderIDs)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]entity.OrderStatusChange, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+func (w *DbWrapperRepo) CountMyOrders(ctx context.Context, userID int64) (int64, error) {
+	total, err := w.db.CountMyOrders(ctx, userID)
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return total, nil
+}
+
+func (w *DbWrapperRepo) FindBook(ctx context.Context, tx pgx.Tx, id int64) (*entity.Book, error) {
+	result, err := w.db.WrapTx(tx).FindBook(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "book cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), err
+}
+
+func (w *DbWrapperRepo) FindBookBySKU(ctx context.Context, tx pgx.Tx, sku string) (*entity.Book, error) {
+	result, err := w.db.WrapTx(tx).FindBookBySKU(ctx, sku)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "book cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// DecrementBookStock takes amount off the stock of the edition, it changes nothing and returns 0 when fewer are left.
+func (w *DbWrapperRepo) DecrementBookStock(ctx context.Context, tx pgx.Tx, sku string, amount int64) (int64, error) {
+	decremented, err := w.db.WrapTx(tx).DecrementBookStock(ctx, db.DecrementBookStockParams{
+		Amount: amount,
+		Sku:    sku,
+	})
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return decremented, nil
+}
+
+// IncrementBookStock puts amount copies of the book back on the shelf.
+func (w *DbWrapperRepo) IncrementBookStock(ctx context.Context, tx pgx.Tx, sku string, amount int64) (int64, error) {
+	incremented, err := w.db.WrapTx(tx).IncrementBookStock(ctx, db.IncrementBookStockParams{
+		Amount: amount,
+		Sku:    sku,
+	})
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return incremented, nil
+}
+
+func (w *DbWrapperRepo) FindUserByToken(ctx context.Context, token string) (*entity.User, error) {
+	result, err := w.db.FindUserByToken(ctx, pgtype.Text{
+		String: token,
+		Valid:  true,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+func (w *DbWrapperRepo) CreateOrderItem(ctx context.Context, tx pgx.Tx, params entity.CreateOrderItemParams) (*entity.OrderItem, error) {
+	result, err := w.db.WrapTx(tx).CreateOrderItem(ctx, db.CreateOrderItemParams{
+		OrderID: params.OrderID,
+		Amount:  params.Amount,
+		Sku:     params.SKU,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "book cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// ReserveIdempotencyKey claims the key for a new request under its lease. It returns false when the key is already
+// taken by a request that has not expired, an expired key or lease is claimed again.
+func (w *DbWrapperRepo) ReserveIdempotencyKey(ctx context.Context, key entity.IdempotencyKey) (bool, error) {
+	reserved, err := w.db.ReserveIdempotencyKey(ctx, db.ReserveIdempotencyKeyParams{
+		UserID:      key.UserID,
+		Key:         key.Key,
+		Fingerprint: key.Fingerprint,
+		Lease:       key.Lease,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  key.ExpiresAt,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return false, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return reserved > 0, nil
+}
+
+func (w *DbWrapperRepo) FindIdempotencyKey(ctx context.Context, userID int64, key string) (*entity.IdempotencyKey, error) {
+	result, err := w.db.FindIdempotencyKey(ctx, db.FindIdempotencyKeyParams{
+		UserID: userID,
+		Key:    key,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "idempotency key cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := result.ToEntity()
+	if len(result.ResponseHeaders) > 0 {
+		if err = json.Unmarshal(result.ResponseHeaders, &resp.Header); err != nil {
+			return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+		}
+	}
+
+	return resp, nil
+}
+
+// SaveIdempotencyResponse keeps the status code, headers and response of the request until the key expires, completing
+// the key. Nothing is saved once another request took the lease over.
+func (w *DbWrapperRepo) SaveIdempotencyResponse(ctx context.Context, key entity.IdempotencyKey) error {
+	header, err := json.Marshal(key.Header)
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	err = w.db.SaveIdempotencyResponse(ctx, db.SaveIdempotencyResponseParams{
+		StatusCode: pgtype.Int4{
+			Int32: int32(key.StatusCode),
+			Valid: true,
+		},
+		Response:        key.Response,
+		ResponseHeaders: header,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  key.ExpiresAt,
+			Valid: true,
+		},
+		UserID: key.UserID,
+		Key:    key.Key,
+		Lease:  key.Lease,
+	})
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey releases the key, so the request can be sent again with it. A key another request took the lease
+// of over is kept.
+func (w *DbWrapperRepo) DeleteIdempotencyKey(ctx context.Context, key entity.IdempotencyKey) error {
+	err := w.db.DeleteIdempotencyKey(ctx, db.DeleteIdempotencyKeyParams{
+		UserID: key.UserID,
+		Key:    key.Key,
+		Lease:  key.Lease,
+	})
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes up to limit expired keys and returns how many it removed.
+func (w *DbWrapperRepo) DeleteExpiredIdempotencyKeys(ctx context.Context, limit int64) (int64, error) {
+	deleted, err := w.db.DeleteExpiredIdempotencyKeys(ctx, limit)
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return deleted, nil
+}
+
+// GetUserCart returns the cart of the user, creating an empty one on the first call. The cart stays locked until tx
+// ends, so requests changing the same cart are applied one after the other.
+func (w *DbWrapperRepo) GetUserCart(ctx context.Context, tx pgx.Tx, userID int64) (*entity.Cart, error) {
+	result, err := w.db.WrapTx(tx).GetOrCreateUserCart(ctx, pgtype.Int8{
+		Int64: userID,
+		Valid: true,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// FindGuestCart returns the cart of a guest by its token and locks it until tx ends.
+func (w *DbWrapperRepo) FindGuestCart(ctx context.Context, tx pgx.Tx, token string) (*entity.Cart, error) {
+	result, err := w.db.WrapTx(tx).FindGuestCart(ctx, pgtype.Text{
+		String: token,
+		Valid:  true,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "cart cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+func (w *DbWrapperRepo) CreateGuestCart(ctx context.Context, tx pgx.Tx, token string) (*entity.Cart, error) {
+	result, err := w.db.WrapTx(tx).CreateGuestCart(ctx, pgtype.Text{
+		String: token,
+		Valid:  true,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// MergeCart puts the items into cart to, replacing the amounts of books already there but keeping the price they were
+// added at, and deletes cart from.
+func (w *DbWrapperRepo) MergeCart(ctx context.Context, tx pgx.Tx, from, to int64, items []entity.CartItem) error {
+	q := w.db.WrapTx(tx)
+
+	if len(items) > 0 {
+		params := db.MergeCartItemsParams{
+			CartID:  to,
+			Skus:    make([]string, 0, len(items)),
+			Amounts: make([]int64, 0, len(items)),
+			Prices:  make([]int64, 0, len(items)),
+		}
+		for _, item := range items {
+			params.Skus = append(params.Skus, item.SKU)
+			params.Amounts = append(params.Amounts, item.Amount)
+			params.Prices = append(params.Prices, item.Price)
+		}
+
+		if err := q.MergeCartItems(ctx, params); err != nil {
+			return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+		}
+	}
+
+	if err := q.DeleteCart(ctx, from); err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return nil
+}
+
+// GetCartItems returns the items of the cart, oldest first, with the current price and stock of their books.
+func (w *DbWrapperRepo) GetCartItems(ctx context.Context, tx pgx.Tx, cartID int64) ([]entity.CartItem, error) {
+	result, err := w.db.WrapTx(tx).GetCartItems(ctx, cartID)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]entity.CartItem, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+// SetCartItem puts the item in the cart, replacing the amount and price of the same book already there.
+func (w *DbWrapperRepo) SetCartItem(ctx context.Context, tx pgx.Tx, cartID int64, item entity.CartItem) error {
+	err := w.db.WrapTx(tx).SetCartItem(ctx, db.SetCartItemParams{
+		CartID: cartID,
+		Sku:    item.SKU,
+		Amount: item.Amount,
+		Price:  item.Price,
+	})
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return nil
+}
+
+func (w *DbWrapperRepo) DeleteCartItem(ctx context.Context, tx pgx.Tx, cartID int64, sku string) (int64, error) {
+	deleted, err := w.db.WrapTx(tx).DeleteCartItem(ctx, db.DeleteCartItemParams{
+		CartID: cartID,
+		Sku:    sku,
+	})
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return deleted, nil
+}
+
+func (w *DbWrapperRepo) ClearCart(ctx context.Context, tx pgx.Tx, cartID int64) error {
+	if err := w.db.WrapTx(tx).ClearCart(ctx, cartID); err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return nil
+}
+
+// GetOrderTotal returns what the order costs, the price of its items at the time it was placed.
+func (w *DbWrapperRepo) GetOrderTotal(ctx context.Context, tx pgx.Tx, orderID int64) (int64, error) {
+	total, err := w.db.WrapTx(tx).GetOrderTotal(ctx, orderID)
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return total, nil
+}
+
+func (w *DbWrapperRepo) CreatePaymentAttempt(ctx context.Context, tx pgx.Tx, payment entity.Payment) (*entity.Payment, error) {
+	result, err := w.db.WrapTx(tx).CreatePaymentAttempt(ctx, db.CreatePaymentAttemptParams{
+		OrderID:   payment.OrderID,
+		Provider:  payment.Provider,
+		Reference: payment.Reference,
+		Amount:    payment.Amount,
+		Status:    payment.Status,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// FindPaymentAttempt returns the attempt the provider knows by reference.
+func (w *DbWrapperRepo) FindPaymentAttempt(ctx context.Context, tx pgx.Tx, provider, reference string) (*entity.Payment, error) {
+	result, err := w.db.WrapTx(tx).FindPaymentAttempt(ctx, db.FindPaymentAttemptParams{
+		Provider:  provider,
+		Reference: reference,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "payment cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// FindOrderPayment returns the latest payment attempt of the order in the given status.
+func (w *DbWrapperRepo) FindOrderPayment(ctx context.Context, tx pgx.Tx, orderID int64, status string) (*entity.Payment, error) {
+	result, err := w.db.WrapTx(tx).FindOrderPaymentAttempt(ctx, db.FindOrderPaymentAttemptParams{
+		OrderID: orderID,
+		Status:  status,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "payment cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// UpdatePaymentAttempt saves the status, refunded amount and failure reason of the attempt.
+func (w *DbWrapperRepo) UpdatePaymentAttempt(ctx context.Context, tx pgx.Tx, payment entity.Payment) (*entity.Payment, error) {
+	result, err := w.db.WrapTx(tx).UpdatePaymentAttempt(ctx, db.UpdatePaymentAttemptParams{
+		ID:             payment.ID,
+		Status:         payment.Status,
+		RefundedAmount: payment.RefundedAmount,
+		FailureReason:  optionalText(payment.FailureReason),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "payment cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// CreatePaymentRefund records a pending refund of the payment.
+func (w *DbWrapperRepo) CreatePaymentRefund(ctx context.Context, tx pgx.Tx, refund entity.PaymentRefund) (*entity.PaymentRefund, error) {
+	result, err := w.db.WrapTx(tx).CreatePaymentRefund(ctx, db.CreatePaymentRefundParams{
+		PaymentAttemptID: refund.PaymentID,
+		Amount:           refund.Amount,
+		Status:           refund.Status,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// FindPendingPaymentRefundForUpdate locks a pending refund along with the reference of its payment. A refund that was
+// sent already, or that another transaction holds, cannot be found.
+func (w *DbWrapperRepo) FindPendingPaymentRefundForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.PaymentRefund, error) {
+	result, err := w.db.WrapTx(tx).FindPendingPaymentRefundForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "refund cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// GetPendingPaymentRefunds returns the ids of up to limit pending refunds last touched before the given time, the ones
+// waiting longest first.
+func (w *DbWrapperRepo) GetPendingPaymentRefunds(ctx context.Context, before time.Time, limit int64) ([]int64, error) {
+	result, err := w.db.GetPendingPaymentRefunds(ctx, db.GetPendingPaymentRefundsParams{
+		UpdatedAt: pgtype.Timestamptz{Time: before, Valid: true},
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result, nil
+}
+
+// UpdatePaymentRefund saves the status, attempts and last error of the refund.
+func (w *DbWrapperRepo) UpdatePaymentRefund(ctx context.Context, tx pgx.Tx, refund entity.PaymentRefund) error {
+	err := w.db.WrapTx(tx).UpdatePaymentRefund(ctx, db.UpdatePaymentRefundParams{
+		ID:        refund.ID,
+		Status:    refund.Status,
+		Attempts:  refund.Attempts,
+		LastError: optionalText(refund.LastError),
+	})
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return nil
+}
+
+// FindOrderItem returns an item of the order, items of other orders are not found.
+func (w *DbWrapperRepo) FindOrderItem(ctx context.Context, tx pgx.Tx, orderID, id int64) (*entity.OrderItem, error) {
+	result, err := w.db.WrapTx(tx).FindOrderItem(ctx, db.FindOrderItemParams{
+		ID:      id,
+		OrderID: orderID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "order item cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// GetReturnedAmount returns how many copies of the order item were asked back by returns not rejected.
+func (w *DbWrapperRepo) GetReturnedAmount(ctx context.Context, tx pgx.Tx, orderID, orderItemID int64) (int64, error) {
+	total, err := w.db.WrapTx(tx).GetReturnedAmount(ctx, db.GetReturnedAmountParams{
+		OrderID:     orderID,
+		OrderItemID: orderItemID,
+	})
+	if err != nil {
+		return 0, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return total, nil
+}
+
+func (w *DbWrapperRepo) CreateReturn(ctx context.Context, tx pgx.Tx, ret entity.Return) (*entity.Return, error) {
+	result, err := w.db.WrapTx(tx).CreateReturn(ctx, db.CreateReturnParams{
+		OrderID:     ret.OrderID,
+		OrderItemID: ret.OrderItemID,
+		Amount:      ret.Amount,
+		Reason:      ret.Reason,
+		Note:        optionalText(ret.Note),
+		Status:      ret.Status,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// FindReturnForUpdate locks the return until tx ends, so concurrent status changes are applied one after the other.
+func (w *DbWrapperRepo) FindReturnForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Return, error) {
+	result, err := w.db.WrapTx(tx).FindReturnForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "return cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// UpdateReturn saves the status, staff note, handling staff member and refund of the return.
+func (w *DbWrapperRepo) UpdateReturn(ctx context.Context, tx pgx.Tx, ret entity.Return) (*entity.Return, error) {
+	result, err := w.db.WrapTx(tx).UpdateReturn(ctx, db.UpdateReturnParams{
+		ID:           ret.ID,
+		Status:       ret.Status,
+		StaffNote:    optionalText(ret.StaffNote),
+		HandledBy:    optionalInt8(ret.HandledBy),
+		RefundAmount: ret.RefundAmount,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.Wrap(err, errorx.CodeNotFound, "return cannot be found")
+		}
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	return result.ToEntity(), nil
+}
+
+// GetOrderReturns returns the returns of the orders, grouped by order and oldest first.
+func (w *DbWrapperRepo) GetOrderReturns(ctx context.Context, orderIDs []int64) ([]entity.Return, error) {
+	result, err := w.db.GetOrderReturns(ctx, orderIDs)
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]entity.Return, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+// GetReturns lists returns oldest first, only those in arg.Status when it is set.
+func (w *DbWrapperRepo) GetReturns(ctx context.Context, arg entity.GetReturnsParams) ([]entity.Return, error) {
+	result, err := w.db.GetReturns(ctx, db.GetReturnsParams{
+		Status: optionalText(arg.Status),
+		Limit:  arg.Limit,
+		Offset: arg.Offset,
+	})
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	resp := make([]entity.Return, 0, len(result))
+	for _, r := range result {
+		resp = append(resp, *r.ToEntity())
+	}
+
+	return resp, nil
+}
+
+func optionalText(s string) pgtype.Text {
+	return pgtype.Text{
+		String: s,
+		Valid:  s != "",
+	}
+}
+
+func optionalTextPtr(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{
+		String: *s,
+		Valid:  true,
+	}
+}
+
+func optionalDate(t *time.Time) pgtype.Date {
+	if t == nil {
+		return pgtype.Date{}
+	}
+	return pgtype.Date{
+		Time:  *t,
+		Valid: true,
+	}
+}
+
+func optionalInt8(i *int64) pgtype.Int8 {
+	if i == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{
+		Int64: *i,
+		Valid: true,
+	}
+}
+
+func optionalInt4(i *int32) pgtype.Int4 {
+	if i == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{
+		Int32: *i,
+		Valid: true,
+	}
+}
+
+// priceFacetFromBucket converts a width_bucket index over entity.BookPriceFacetEdges into a price range.
+func priceFacetFromBucket(bucket string, total int64) (*entity.PriceFacetCount, error) {
+	idx, err := strconv.Atoi(bucket)
+	if err != nil || idx < 1 || idx > len(entity.BookPriceFacetEdges) {
+		return nil, fmt.Errorf("unexpected price bucket %q", bucket)
+	}
+
+	priceRange := &entity.PriceFacetCount{
+		Min:   entity.BookPriceFacetEdges[idx-1],
+		Count: total,
+	}
+	if idx < len(entity.BookPriceFacetEdges) {
+		max := entity.BookPriceFacetEdges[idx] - 1
+		priceRange.Max = &max
+	}
+
+	return priceRange, nil
+}
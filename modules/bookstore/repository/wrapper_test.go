@@ -8,10 +8,12 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/raymondwongso/gogox/errorx"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/repository/db"
@@ -159,34 +161,29 @@ func (s *WrapperTestSuite) TestFindUser() {
 
 func (s *WrapperTestSuite) TestGetBooks() {
 	ctx := context.Background()
-	now := time.Now()
 	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
 	expectedBooks := []entity.Book{
 		{
-			ID:   123,
-			Name: "Book A",
+			ID:      123,
+			Name:    "Book A",
+			Authors: "Someone",
 		},
 		{
-			ID:   124,
-			Name: "Book B",
+			ID:          124,
+			Name:        "Book B",
+			Description: "About debugging",
 		},
 	}
-	rowsFromDB := []*db.Book{
+	rowsFromDB := []*db.GetBooksRow{
 		{
-			ID:   123,
-			Name: "Book A",
-			CreatedAt: pgtype.Timestamptz{
-				Time:  now,
-				Valid: true,
-			},
+			ID:      123,
+			Name:    "Book A",
+			Authors: "Someone",
 		},
 		{
-			ID:   124,
-			Name: "Book B",
-			CreatedAt: pgtype.Timestamptz{
-				Time:  now,
-				Valid: true,
-			},
+			ID:          124,
+			Name:        "Book B",
+			Description: "About debugging",
 		},
 	}
 
@@ -221,6 +218,336 @@ func (s *WrapperTestSuite) TestGetBooks() {
 		s.Assert().Equal(expectedBooks, result)
 		s.Assert().Nil(err)
 	})
+
+	s.Run("search books passes query, filters and returns highlight", func() {
+		minPrice := int64(1000)
+		querierParams := db.GetBooksParams{
+			Query: pgtype.Text{
+				String: "debugging",
+				Valid:  true,
+			},
+			Author: pgtype.Text{
+				String: "ada",
+				Valid:  true,
+			},
+			CategoryID: pgtype.Int8{
+				Int64: 3,
+				Valid: true,
+			},
+			Format: pgtype.Text{
+				String: "ebook",
+				Valid:  true,
+			},
+			MinPrice: pgtype.Int8{
+				Int64: 1000,
+				Valid: true,
+			},
+			InStock: true,
+			PublishedYear: pgtype.Int4{
+				Int32: 2020,
+				Valid: true,
+			},
+			Sort:   entity.BookSortRelevance,
+			Limit:  100,
+			Offset: 2,
+		}
+		wrapperParams := entity.GetBooksParams{
+			Query:         "debugging",
+			Author:        "ada",
+			CategoryID:    3,
+			Format:        "ebook",
+			MinPrice:      &minPrice,
+			InStock:       true,
+			PublishedYear: 2020,
+			Sort:          entity.BookSortRelevance,
+			Limit:         100,
+			Offset:        2,
+		}
+
+		s.querierRepo.EXPECT().GetBooks(ctx, querierParams).
+			Return([]*db.GetBooksRow{
+				{
+					ID:          124,
+					Name:        "Book B",
+					Description: "About debugging",
+					Highlight:   "Book B About <mark>debugging</mark>",
+				},
+			}, nil).Times(1)
+
+		result, err := wrapper.GetBooks(ctx, wrapperParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.Book{
+			{
+				ID:          124,
+				Name:        "Book B",
+				Description: "About debugging",
+				Highlight:   "Book B About <mark>debugging</mark>",
+			},
+		}, result)
+	})
+
+	s.Run("get books after cursor passes keyset params", func() {
+		published := time.Date(2021, 8, 15, 0, 0, 0, 0, time.UTC)
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		querierParams := db.GetBooksParams{
+			AfterID: pgtype.Int8{
+				Int64: 124,
+				Valid: true,
+			},
+			Sort: entity.BookSortPublicationDate,
+			AfterRank: pgtype.Float4{
+				Valid: true,
+			},
+			AfterName: pgtype.Text{
+				String: "Book B",
+				Valid:  true,
+			},
+			AfterPrice: pgtype.Int8{
+				Valid: true,
+			},
+			AfterPublishedAt: pgtype.Date{
+				Time:  published,
+				Valid: true,
+			},
+			AfterSoldCount: pgtype.Int8{
+				Valid: true,
+			},
+			AfterCreatedAt: pgtype.Timestamptz{
+				Time:  created,
+				Valid: true,
+			},
+			Limit: 100,
+		}
+		wrapperParams := entity.GetBooksParams{
+			Sort: entity.BookSortPublicationDate,
+			After: &entity.BookCursor{
+				ID:          124,
+				Name:        "Book B",
+				PublishedAt: &published,
+				CreatedAt:   created,
+			},
+			Limit: 100,
+		}
+
+		s.querierRepo.EXPECT().GetBooks(ctx, querierParams).
+			Return([]*db.GetBooksRow{}, nil).Times(1)
+
+		result, err := wrapper.GetBooks(ctx, wrapperParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.Book{}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestCountBooks() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	querierParams := db.CountBooksParams{
+		Category: pgtype.Text{
+			String: "Art",
+			Valid:  true,
+		},
+		InStock: true,
+	}
+	wrapperParams := entity.GetBooksParams{
+		Category: "Art",
+		InStock:  true,
+		Limit:    10,
+		Offset:   20,
+	}
+
+	s.Run("count books got querier error", func() {
+		s.querierRepo.EXPECT().CountBooks(ctx, querierParams).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		result, err := wrapper.CountBooks(ctx, wrapperParams)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+		s.Assert().Contains(goxErr.LogError(), "[common.internal] internal server error: querier error")
+	})
+
+	s.Run("count books successful", func() {
+		s.querierRepo.EXPECT().CountBooks(ctx, querierParams).
+			Return(int64(12), nil).Times(1)
+
+		result, err := wrapper.CountBooks(ctx, wrapperParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal(int64(12), result)
+	})
+
+	s.Run("estimate books count got querier error", func() {
+		s.querierRepo.EXPECT().EstimateBooksCount(ctx).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		result, err := wrapper.EstimateBooksCount(ctx)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("estimate books count successful", func() {
+		s.querierRepo.EXPECT().EstimateBooksCount(ctx).
+			Return(int64(250000), nil).Times(1)
+
+		result, err := wrapper.EstimateBooksCount(ctx)
+		s.Assert().Nil(err)
+		s.Assert().Equal(int64(250000), result)
+	})
+}
+
+func (s *WrapperTestSuite) TestCountMyOrders() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("count my orders got querier error", func() {
+		s.querierRepo.EXPECT().CountMyOrders(ctx, int64(9919)).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		result, err := wrapper.CountMyOrders(ctx, 9919)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("count my orders successful", func() {
+		s.querierRepo.EXPECT().CountMyOrders(ctx, int64(9919)).
+			Return(int64(4), nil).Times(1)
+
+		result, err := wrapper.CountMyOrders(ctx, 9919)
+		s.Assert().Nil(err)
+		s.Assert().Equal(int64(4), result)
+	})
+}
+
+func (s *WrapperTestSuite) TestGetBookFacets() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	querierParams := db.GetBookFacetsParams{
+		Query: pgtype.Text{
+			String: "art",
+			Valid:  true,
+		},
+		PriceEdges: entity.BookPriceFacetEdges,
+	}
+	wrapperParams := entity.GetBooksParams{
+		Query:  "art",
+		Limit:  10,
+		Offset: 20,
+	}
+
+	s.Run("get book facets got querier error", func() {
+		s.querierRepo.EXPECT().GetBookFacets(ctx, querierParams).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetBookFacets(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("get book facets got unexpected price bucket", func() {
+		s.querierRepo.EXPECT().GetBookFacets(ctx, querierParams).
+			Return([]*db.GetBookFacetsRow{{Facet: "price", Value: "99", Total: 1}}, nil).Times(1)
+
+		result, err := wrapper.GetBookFacets(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Contains(goxErr.LogError(), `unexpected price bucket "99"`)
+	})
+
+	s.Run("get book facets successful", func() {
+		s.querierRepo.EXPECT().GetBookFacets(ctx, querierParams).
+			Return([]*db.GetBookFacetsRow{
+				{Facet: "category", Value: "Art", CategoryID: 2, Total: 3},
+				{Facet: "category", Value: "Design", CategoryID: 5, Total: 1},
+				{Facet: "format", Value: "hardcover", Total: 4},
+				{Facet: "language", Value: "en", Total: 4},
+				{Facet: "price", Value: "5", Total: 1},
+				{Facet: "price", Value: "2", Total: 3},
+			}, nil).Times(1)
+
+		result, err := wrapper.GetBookFacets(ctx, wrapperParams)
+		s.Require().Nil(err)
+
+		max := int64(9999)
+		s.Assert().Equal(&entity.BookFacets{
+			Categories: []entity.FacetCount{
+				{ID: 2, Value: "Art", Count: 3},
+				{ID: 5, Value: "Design", Count: 1},
+			},
+			Languages: []entity.FacetCount{
+				{Value: "en", Count: 4},
+			},
+			Formats: []entity.FacetCount{
+				{Value: "hardcover", Count: 4},
+			},
+			PriceRanges: []entity.PriceFacetCount{
+				{Min: 5000, Max: &max, Count: 3},
+				{Min: 50000, Count: 1},
+			},
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestSuggestBooks() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	querierParams := db.SuggestBooksParams{
+		Prefix: "debuging",
+		Limit:  5,
+	}
+	wrapperParams := entity.SuggestBooksParams{
+		Prefix: "debuging",
+		Limit:  5,
+	}
+
+	s.Run("suggest books got querier error", func() {
+		s.querierRepo.EXPECT().SuggestBooks(ctx, querierParams).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.SuggestBooks(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("suggest books successful", func() {
+		s.querierRepo.EXPECT().SuggestBooks(ctx, querierParams).
+			Return([]*db.SuggestBooksRow{
+				{
+					ID:      1,
+					Name:    "Chicken Soup of Debugging",
+					Authors: "Ada Stacktrace",
+					Score:   0.8,
+				},
+			}, nil).Times(1)
+
+		result, err := wrapper.SuggestBooks(ctx, wrapperParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.BookSuggestion{
+			{
+				ID:      1,
+				Name:    "Chicken Soup of Debugging",
+				Authors: "Ada Stacktrace",
+			},
+		}, result)
+	})
 }
 
 func (s *WrapperTestSuite) TestCreateOrder() {
@@ -237,17 +564,28 @@ func (s *WrapperTestSuite) TestCreateOrder() {
 				Amount: 10,
 			},
 		},
+		ReservationTTL: 30 * time.Minute,
+	}
+	queryParams := db.CreateOrderParams{
+		UserID:         userID,
+		ReservationTtl: pgtype.Interval{Microseconds: (30 * time.Minute).Microseconds(), Valid: true},
 	}
+	reservedUntil := now.Add(30 * time.Minute)
 
 	expectedOrder := &entity.Order{
-		ID:        90,
-		UserID:    9919,
-		CreatedAt: now,
+		ID:            90,
+		UserID:        9919,
+		ReservedUntil: &reservedUntil,
+		CreatedAt:     now,
 	}
 
 	rowFromDB := &db.CreateOrderRow{
 		ID:     90,
 		UserID: 9919,
+		ReservedUntil: pgtype.Timestamptz{
+			Time:  reservedUntil,
+			Valid: true,
+		},
 		CreatedAt: pgtype.Timestamptz{
 			Time:  now,
 			Valid: true,
@@ -257,7 +595,7 @@ func (s *WrapperTestSuite) TestCreateOrder() {
 	s.Run("create order got querier error", func() {
 		s.querierRepo.EXPECT().WrapTx(nil).
 			Return(s.querierRepo).Times(1)
-		s.querierRepo.EXPECT().CreateOrder(ctx, userID).
+		s.querierRepo.EXPECT().CreateOrder(ctx, queryParams).
 			Return(nil, errors.New("querier error")).Times(1)
 
 		result, err := wrapper.CreateOrder(ctx, nil, wrapperParams)
@@ -272,7 +610,7 @@ func (s *WrapperTestSuite) TestCreateOrder() {
 	s.Run("create order successful", func() {
 		s.querierRepo.EXPECT().WrapTx(nil).
 			Return(s.querierRepo).Times(1)
-		s.querierRepo.EXPECT().CreateOrder(ctx, userID).
+		s.querierRepo.EXPECT().CreateOrder(ctx, queryParams).
 			Return(rowFromDB, nil).Times(1)
 
 		result, err := wrapper.CreateOrder(ctx, nil, wrapperParams)
@@ -290,21 +628,36 @@ func (s *WrapperTestSuite) TestGetMyOrders() {
 			ID:     123,
 			UserID: 9919,
 			Email:  "someone@test.com",
+			Status: entity.OrderStatusPaid,
 			Items: []entity.OrderItem{
 				{
-					ID:        984,
-					OrderID:   123,
-					BookID:    920,
+					ID:      984,
+					OrderID: 123,
+					BookID:  920,
+					Book: &entity.BookSummary{
+						ID:      920,
+						Name:    "Chicken Soup of Debugging",
+						Authors: "Ada Stacktrace",
+						Status:  "discontinued",
+					},
 					Amount:    10,
 					CreatedAt: now,
 				},
 			},
+			History: []entity.OrderStatusChange{
+				{OrderID: 123, To: entity.OrderStatusPendingPayment, ActorRole: entity.OrderActorSystem, CreatedAt: now},
+				{OrderID: 123, From: entity.OrderStatusPendingPayment, To: entity.OrderStatusPaid, ActorRole: entity.OrderActorSystem, CreatedAt: now},
+			},
+			Returns: []entity.Return{
+				{ID: 7, OrderID: 123, OrderItemID: 984, Amount: 1, Reason: entity.ReturnReasonDamaged, Status: entity.ReturnStatusRequested, CreatedAt: now},
+			},
 			CreatedAt: now,
 		},
 		{
 			ID:     124,
 			UserID: 9919,
 			Email:  "someone@test.com",
+			Status: entity.OrderStatusPendingPayment,
 			Items: []entity.OrderItem{
 				{
 					ID:        985,
@@ -323,6 +676,7 @@ func (s *WrapperTestSuite) TestGetMyOrders() {
 			OrderID: 123,
 			UserID:  9919,
 			Email:   "someone@test.com",
+			Status:  entity.OrderStatusPaid,
 			CreatedAt: pgtype.Timestamptz{
 				Time:  now,
 				Valid: true,
@@ -332,6 +686,7 @@ func (s *WrapperTestSuite) TestGetMyOrders() {
 			OrderID: 124,
 			UserID:  9919,
 			Email:   "someone@test.com",
+			Status:  entity.OrderStatusPendingPayment,
 			CreatedAt: pgtype.Timestamptz{
 				Time:  now,
 				Valid: true,
@@ -339,7 +694,7 @@ func (s *WrapperTestSuite) TestGetMyOrders() {
 		},
 	}
 
-	order123ItemRowFromDB := []*db.OrderItem{
+	order123ItemRowFromDB := []*db.GetOrderItemsRow{
 		{
 			ID:      984,
 			OrderID: 123,
@@ -349,10 +704,22 @@ func (s *WrapperTestSuite) TestGetMyOrders() {
 				Time:  now,
 				Valid: true,
 			},
+			BookName: pgtype.Text{
+				String: "Chicken Soup of Debugging",
+				Valid:  true,
+			},
+			BookAuthors: pgtype.Text{
+				String: "Ada Stacktrace",
+				Valid:  true,
+			},
+			BookStatus: pgtype.Text{
+				String: "discontinued",
+				Valid:  true,
+			},
 		},
 	}
 
-	order124ItemRowFromDB := []*db.OrderItem{
+	order124ItemRowFromDB := []*db.GetOrderItemsRow{
 		{
 			ID:      985,
 			OrderID: 124,
@@ -393,7 +760,7 @@ func (s *WrapperTestSuite) TestGetMyOrders() {
 	s.Run("get my order items got querier error", func() {
 		s.querierRepo.EXPECT().GetMyOrders(ctx, querierParams).
 			Return(rowsFromDB, nil).Times(1)
-		s.querierRepo.EXPECT().GetMyOrderItems(ctx, rowsFromDB[0].OrderID).
+		s.querierRepo.EXPECT().GetOrderItems(ctx, []int64{123, 124}).
 			Return(nil, errors.New("querier error")).Times(1)
 
 		result, err := wrapper.GetMyOrders(ctx, wrapperParams)
@@ -409,15 +776,59 @@ func (s *WrapperTestSuite) TestGetMyOrders() {
 	s.Run("get my orders successful", func() {
 		s.querierRepo.EXPECT().GetMyOrders(ctx, querierParams).
 			Return(rowsFromDB, nil).Times(1)
-		s.querierRepo.EXPECT().GetMyOrderItems(ctx, rowsFromDB[0].OrderID).
-			Return(order123ItemRowFromDB, nil).Times(1)
-		s.querierRepo.EXPECT().GetMyOrderItems(ctx, rowsFromDB[1].OrderID).
-			Return(order124ItemRowFromDB, nil).Times(1)
+		s.querierRepo.EXPECT().GetOrderItems(ctx, []int64{123, 124}).
+			Return(append(order123ItemRowFromDB, order124ItemRowFromDB...), nil).Times(1)
+		s.querierRepo.EXPECT().GetOrderStatusChanges(ctx, []int64{123, 124}).
+			Return([]*db.OrderStatusChange{
+				{
+					OrderID:   123,
+					ToStatus:  entity.OrderStatusPendingPayment,
+					ActorRole: entity.OrderActorSystem,
+					CreatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+				},
+				{
+					OrderID:    123,
+					FromStatus: pgtype.Text{String: entity.OrderStatusPendingPayment, Valid: true},
+					ToStatus:   entity.OrderStatusPaid,
+					ActorRole:  entity.OrderActorSystem,
+					CreatedAt:  pgtype.Timestamptz{Time: now, Valid: true},
+				},
+			}, nil).Times(1)
+		s.querierRepo.EXPECT().GetOrderReturns(ctx, []int64{123, 124}).
+			Return([]*db.Return{
+				{
+					ID:          7,
+					OrderID:     123,
+					OrderItemID: 984,
+					Amount:      1,
+					Reason:      entity.ReturnReasonDamaged,
+					Status:      entity.ReturnStatusRequested,
+					CreatedAt:   pgtype.Timestamptz{Time: now, Valid: true},
+				},
+			}, nil).Times(1)
 
 		result, err := wrapper.GetMyOrders(ctx, wrapperParams)
 		s.Assert().Equal(expectedOrders, result)
 		s.Assert().Nil(err)
 	})
+	s.Run("get my orders before cursor id", func() {
+		s.querierRepo.EXPECT().GetMyOrders(ctx, db.GetMyOrdersParams{
+			UserID: 9919,
+			BeforeID: pgtype.Int8{
+				Int64: 125,
+				Valid: true,
+			},
+			Limit: 100,
+		}).Return([]*db.GetMyOrdersRow{}, nil).Times(1)
+
+		result, err := wrapper.GetMyOrders(ctx, entity.GetMyOrdersParams{
+			UserID:   9919,
+			BeforeID: 125,
+			Limit:    100,
+		})
+		s.Assert().Equal([]entity.Order{}, result)
+		s.Assert().Nil(err)
+	})
 }
 
 func (s *WrapperTestSuite) TestCreateOrderItem() {
@@ -427,13 +838,13 @@ func (s *WrapperTestSuite) TestCreateOrderItem() {
 
 	querierParams := db.CreateOrderItemParams{
 		OrderID: 847,
-		BookID:  27,
 		Amount:  19,
+		Sku:     "BK00000027",
 	}
 
 	wrapperParams := entity.CreateOrderItemParams{
 		OrderID: 847,
-		BookID:  27,
+		SKU:     "BK00000027",
 		Amount:  19,
 	}
 
@@ -441,6 +852,7 @@ func (s *WrapperTestSuite) TestCreateOrderItem() {
 		ID:        90,
 		OrderID:   847,
 		BookID:    27,
+		SKU:       "BK00000027",
 		Amount:    19,
 		CreatedAt: now,
 	}
@@ -454,8 +866,20 @@ func (s *WrapperTestSuite) TestCreateOrderItem() {
 			Time:  now,
 			Valid: true,
 		},
+		Sku: pgtype.Text{String: "BK00000027", Valid: true},
 	}
 
+	s.Run("create order item with an unknown sku", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().CreateOrderItem(ctx, querierParams).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.CreateOrderItem(ctx, nil, wrapperParams)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
 	s.Run("create order item got querier error", func() {
 		s.querierRepo.EXPECT().WrapTx(nil).
 			Return(s.querierRepo).Times(1)
@@ -482,3 +906,1714 @@ func (s *WrapperTestSuite) TestCreateOrderItem() {
 		s.Assert().Nil(err)
 	})
 }
+
+func (s *WrapperTestSuite) TestCreateBook() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	published := time.Date(2018, 11, 20, 0, 0, 0, 0, time.UTC)
+
+	querierParams := db.CreateBookParams{
+		Name:     "Refactoring",
+		Language: "en",
+		Format:   "hardcover",
+		Price:    4200,
+		PublishedAt: pgtype.Date{
+			Time:  published,
+			Valid: true,
+		},
+	}
+	wrapperParams := entity.CreateBookParams{
+		Name:        "Refactoring",
+		Language:    "en",
+		Format:      "hardcover",
+		Price:       4200,
+		PublishedAt: &published,
+	}
+
+	s.Run("create book got querier error", func() {
+		s.querierRepo.EXPECT().CreateBook(ctx, querierParams).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.CreateBook(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+		s.Assert().Contains(goxErr.LogError(), "[common.internal] internal server error: querier error")
+	})
+
+	s.Run("create book with a taken series volume", func() {
+		seriesID, volume := int64(2), int32(1)
+		params := querierParams
+		params.SeriesID = pgtype.Int8{Int64: 2, Valid: true}
+		params.SeriesVolume = pgtype.Int4{Int32: 1, Valid: true}
+		s.querierRepo.EXPECT().CreateBook(ctx, params).
+			Return(nil, &pgconn.PgError{Code: "23505", ConstraintName: "idx_works_series_id_series_volume"}).Times(1)
+
+		withSeries := wrapperParams
+		withSeries.SeriesID = &seriesID
+		withSeries.SeriesVolume = &volume
+		result, err := wrapper.CreateBook(ctx, withSeries)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "series volume already exists")
+	})
+
+	s.Run("create book with a taken isbn", func() {
+		s.querierRepo.EXPECT().CreateBook(ctx, querierParams).
+			Return(nil, &pgconn.PgError{Code: "23505", ConstraintName: "idx_books_isbn"}).Times(1)
+
+		result, err := wrapper.CreateBook(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "isbn already exists")
+	})
+
+	s.Run("create book with a taken sku", func() {
+		s.querierRepo.EXPECT().CreateBook(ctx, querierParams).
+			Return(nil, &pgconn.PgError{Code: "23505", ConstraintName: "idx_books_sku"}).Times(1)
+
+		result, err := wrapper.CreateBook(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "sku already exists")
+	})
+
+	s.Run("create book for an unknown work", func() {
+		workID := int64(99)
+		params := querierParams
+		params.WorkID = 99
+		s.querierRepo.EXPECT().CreateBook(ctx, params).
+			Return(nil, &pgconn.PgError{Code: "23503", ConstraintName: "books_work_id_fkey"}).Times(1)
+
+		withWork := wrapperParams
+		withWork.WorkID = &workID
+		result, err := wrapper.CreateBook(ctx, withWork)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "work cannot be found")
+	})
+
+	s.Run("create book successful", func() {
+		s.querierRepo.EXPECT().CreateBook(ctx, querierParams).
+			Return(&db.CreateBookRow{
+				ID:       7,
+				Name:     "Refactoring",
+				Language: "en",
+				Format:   "hardcover",
+				Price:    4200,
+				PublishedAt: pgtype.Date{
+					Time:  published,
+					Valid: true,
+				},
+				Version: 1,
+				WorkID:  7,
+				Sku:     "BK00000007",
+			}, nil).Times(1)
+
+		result, err := wrapper.CreateBook(ctx, wrapperParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Book{
+			ID:          7,
+			WorkID:      7,
+			SKU:         "BK00000007",
+			Name:        "Refactoring",
+			Language:    "en",
+			Format:      "hardcover",
+			Price:       4200,
+			PublishedAt: &published,
+			Version:     1,
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestUpdateBook() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	category := ""
+	stock := int64(3)
+
+	querierParams := db.UpdateBookParams{
+		Category: pgtype.Text{
+			Valid: true,
+		},
+		Stock: pgtype.Int8{
+			Int64: 3,
+			Valid: true,
+		},
+		ID:      7,
+		Version: 2,
+	}
+	wrapperParams := entity.UpdateBookParams{
+		ID:       7,
+		Version:  2,
+		Category: &category,
+		Stock:    &stock,
+	}
+
+	s.Run("update book got querier error", func() {
+		s.querierRepo.EXPECT().UpdateBook(ctx, querierParams).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.UpdateBook(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("update book not found", func() {
+		s.querierRepo.EXPECT().UpdateBook(ctx, querierParams).
+			Return(nil, sql.ErrNoRows).Times(1)
+		s.querierRepo.EXPECT().FindBook(ctx, int64(7)).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.UpdateBook(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeNotFound, goxErr.Code)
+		s.Assert().EqualError(goxErr, "book cannot be found")
+	})
+
+	s.Run("update book stale version", func() {
+		s.querierRepo.EXPECT().UpdateBook(ctx, querierParams).
+			Return(nil, sql.ErrNoRows).Times(1)
+		s.querierRepo.EXPECT().FindBook(ctx, int64(7)).
+			Return(&db.Book{ID: 7, Version: 3}, nil).Times(1)
+
+		result, err := wrapper.UpdateBook(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodePreconditionFailed, goxErr.Code)
+		s.Assert().EqualError(goxErr, "book has been modified")
+	})
+
+	s.Run("update book successful", func() {
+		s.querierRepo.EXPECT().UpdateBook(ctx, querierParams).
+			Return(&db.UpdateBookRow{ID: 7, Stock: 3, Version: 3}, nil).Times(1)
+
+		result, err := wrapper.UpdateBook(ctx, wrapperParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Book{ID: 7, Stock: 3, Version: 3}, result)
+	})
+
+	s.Run("update book places its work in a series", func() {
+		seriesID, volume := int64(2), int32(4)
+		params := querierParams
+		params.SeriesID = pgtype.Int8{Int64: 2, Valid: true}
+		params.SeriesVolume = pgtype.Int4{Int32: 4, Valid: true}
+		s.querierRepo.EXPECT().UpdateBook(ctx, params).
+			Return(&db.UpdateBookRow{
+				ID:           7,
+				WorkID:       5,
+				Version:      3,
+				SeriesID:     pgtype.Int8{Int64: 2, Valid: true},
+				SeriesVolume: pgtype.Int4{Int32: 4, Valid: true},
+			}, nil).Times(1)
+
+		withSeries := wrapperParams
+		withSeries.SeriesID = &seriesID
+		withSeries.SeriesVolume = &volume
+		result, err := wrapper.UpdateBook(ctx, withSeries)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Book{ID: 7, WorkID: 5, Version: 3, Series: &entity.BookSeries{ID: 2, Volume: 4}}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestDeleteBook() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("delete book got querier error", func() {
+		s.querierRepo.EXPECT().DiscontinueBook(ctx, db.DiscontinueBookParams{ID: 7}).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		err := wrapper.DeleteBook(ctx, entity.DeleteBookParams{ID: 7})
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("delete book stale version", func() {
+		querierParams := db.DiscontinueBookParams{
+			ID: 7,
+			Version: pgtype.Int8{
+				Int64: 2,
+				Valid: true,
+			},
+		}
+		s.querierRepo.EXPECT().DiscontinueBook(ctx, querierParams).
+			Return(int64(0), nil).Times(1)
+		s.querierRepo.EXPECT().FindBook(ctx, int64(7)).
+			Return(&db.Book{ID: 7, Version: 3}, nil).Times(1)
+
+		err := wrapper.DeleteBook(ctx, entity.DeleteBookParams{ID: 7, Version: 2})
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodePreconditionFailed, goxErr.Code)
+	})
+
+	s.Run("delete book successful", func() {
+		s.querierRepo.EXPECT().DiscontinueBook(ctx, db.DiscontinueBookParams{ID: 7}).
+			Return(int64(1), nil).Times(1)
+
+		err := wrapper.DeleteBook(ctx, entity.DeleteBookParams{ID: 7})
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *WrapperTestSuite) TestCopyBooksToStaging() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	batchID := "batch-1"
+	price, stock := int64(15000), int64(4)
+
+	rows := []entity.BookImportRow{
+		{
+			Line:       2,
+			ISBN:       "9780306406157",
+			Name:       "Dune",
+			Authors:    "Frank Herbert",
+			Price:      &price,
+			Stock:      &stock,
+			ThemaCodes: []string{"FBA"},
+		},
+		{
+			Line:   3,
+			ISBN:   "9780131103627",
+			Name:   "The C Programming Language",
+			Format: "paperback",
+			Status: entity.BookStatusActive,
+		},
+	}
+
+	querierParams := []db.CopyBooksToStagingParams{
+		{
+			BatchID:    batchID,
+			Line:       2,
+			Isbn:       "9780306406157",
+			Name:       "Dune",
+			Authors:    pgtype.Text{String: "Frank Herbert", Valid: true},
+			Price:      pgtype.Int8{Int64: 15000, Valid: true},
+			Stock:      pgtype.Int8{Int64: 4, Valid: true},
+			ThemaCodes: []string{"FBA"},
+		},
+		{
+			BatchID: batchID,
+			Line:    3,
+			Isbn:    "9780131103627",
+			Name:    "The C Programming Language",
+			Format:  pgtype.Text{String: "paperback", Valid: true},
+			Status:  pgtype.Text{String: entity.BookStatusActive, Valid: true},
+		},
+	}
+
+	s.Run("copy books got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().CopyBooksToStaging(ctx, querierParams).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		result, err := wrapper.CopyBooksToStaging(ctx, nil, batchID, rows)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+		s.Assert().Contains(goxErr.LogError(), "querier error")
+	})
+
+	s.Run("copy books successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().CopyBooksToStaging(ctx, querierParams).
+			Return(int64(2), nil).Times(1)
+
+		result, err := wrapper.CopyBooksToStaging(ctx, nil, batchID, rows)
+		s.Assert().Equal(int64(2), result)
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *WrapperTestSuite) TestUpsertBooksFromStaging() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	batchID := "batch-1"
+
+	s.Run("upsert books got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().UpsertBooksFromStaging(ctx, batchID).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.UpsertBooksFromStaging(ctx, nil, batchID)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("upsert books successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().UpsertBooksFromStaging(ctx, batchID).
+			Return(&db.UpsertBooksFromStagingRow{Inserted: 3, Updated: 2}, nil).Times(1)
+
+		result, err := wrapper.UpsertBooksFromStaging(ctx, nil, batchID)
+		s.Assert().Equal(&entity.BookUpsertCount{Inserted: 3, Updated: 2}, result)
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *WrapperTestSuite) TestDiscontinueBooksByISBN() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	isbns := []string{"9780306406157", "9780131103627"}
+
+	s.Run("discontinue books got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().DiscontinueBooksByISBN(ctx, isbns).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		result, err := wrapper.DiscontinueBooksByISBN(ctx, nil, isbns)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("discontinue books successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().DiscontinueBooksByISBN(ctx, isbns).
+			Return(int64(1), nil).Times(1)
+
+		result, err := wrapper.DiscontinueBooksByISBN(ctx, nil, isbns)
+		s.Assert().Equal(int64(1), result)
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *WrapperTestSuite) TestExportBooks() {
+	ctx := context.Background()
+	now := time.Now()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	since := now.Add(-time.Hour)
+
+	querierParams := db.ExportBooksParams{
+		AfterID:      10,
+		UpdatedSince: pgtype.Timestamptz{Time: since, Valid: true},
+		Limit:        2,
+	}
+
+	s.Run("export books got querier error", func() {
+		s.querierRepo.EXPECT().ExportBooks(ctx, querierParams).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.ExportBooks(ctx, entity.ExportBooksParams{AfterID: 10, UpdatedSince: &since, Limit: 2})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("export books successful", func() {
+		s.querierRepo.EXPECT().ExportBooks(ctx, db.ExportBooksParams{AfterID: 10, Limit: 2}).
+			Return([]*db.Book{
+				{
+					ID:        11,
+					Name:      "Dune",
+					Isbn:      pgtype.Text{String: "9780306406157", Valid: true},
+					SoldCount: 5,
+					Version:   2,
+					Status:    entity.BookStatusHidden,
+					CreatedAt: pgtype.Timestamptz{Time: since, Valid: true},
+					UpdatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+				},
+			}, nil).Times(1)
+
+		result, err := wrapper.ExportBooks(ctx, entity.ExportBooksParams{AfterID: 10, Limit: 2})
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.Book{
+			{
+				ID:        11,
+				ISBN:      "9780306406157",
+				Name:      "Dune",
+				SoldCount: 5,
+				Version:   2,
+				Status:    entity.BookStatusHidden,
+				CreatedAt: since,
+				UpdatedAt: now,
+			},
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestLinkStagedBookCategories() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("link categories got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().LinkStagedBookCategories(ctx, "batch-1").
+			Return(errors.New("querier error")).Times(1)
+
+		err := wrapper.LinkStagedBookCategories(ctx, nil, "batch-1")
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("link categories successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().LinkStagedBookCategories(ctx, "batch-1").
+			Return(nil).Times(1)
+
+		err := wrapper.LinkStagedBookCategories(ctx, nil, "batch-1")
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *WrapperTestSuite) TestGetCategories() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("get categories got querier error", func() {
+		s.querierRepo.EXPECT().GetCategories(ctx).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetCategories(ctx)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("get categories successful", func() {
+		s.querierRepo.EXPECT().GetCategories(ctx).
+			Return([]*db.Category{
+				{ID: 1, Name: "Fiction", Slug: "fiction", BisacCode: pgtype.Text{String: "FIC000000", Valid: true}},
+				{ID: 3, ParentID: pgtype.Int8{Int64: 1, Valid: true}, Name: "Mystery", Slug: "mystery"},
+			}, nil).Times(1)
+
+		result, err := wrapper.GetCategories(ctx)
+		s.Assert().Nil(err)
+
+		parentID := int64(1)
+		s.Assert().Equal([]*entity.Category{
+			{ID: 1, Name: "Fiction", Slug: "fiction", BISACCode: "FIC000000"},
+			{ID: 3, ParentID: &parentID, Name: "Mystery", Slug: "mystery"},
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindCategory() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("category not found", func() {
+		s.querierRepo.EXPECT().FindCategory(ctx, int64(9)).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindCategory(ctx, 9)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("find category successful", func() {
+		s.querierRepo.EXPECT().FindCategory(ctx, int64(1)).
+			Return(&db.Category{ID: 1, Name: "Fiction", Slug: "fiction"}, nil).Times(1)
+
+		result, err := wrapper.FindCategory(ctx, 1)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Category{ID: 1, Name: "Fiction", Slug: "fiction"}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestCreateCategory() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	parentID := int64(1)
+
+	querierParams := db.CreateCategoryParams{
+		ParentID: pgtype.Int8{Int64: 1, Valid: true},
+		Name:     "Mystery",
+		Slug:     "mystery",
+	}
+	wrapperParams := entity.CreateCategoryParams{ParentID: &parentID, Name: "Mystery", Slug: "mystery"}
+
+	s.Run("create category with a taken slug", func() {
+		s.querierRepo.EXPECT().CreateCategory(ctx, querierParams).
+			Return(nil, &pgconn.PgError{Code: "23505"}).Times(1)
+
+		result, err := wrapper.CreateCategory(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "category already exists")
+	})
+
+	s.Run("create category under a missing parent", func() {
+		s.querierRepo.EXPECT().CreateCategory(ctx, querierParams).
+			Return(nil, &pgconn.PgError{Code: "23503"}).Times(1)
+
+		result, err := wrapper.CreateCategory(ctx, wrapperParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "parent category cannot be found")
+	})
+
+	s.Run("create category successful", func() {
+		s.querierRepo.EXPECT().CreateCategory(ctx, querierParams).
+			Return(&db.Category{ID: 3, ParentID: querierParams.ParentID, Name: "Mystery", Slug: "mystery"}, nil).Times(1)
+
+		result, err := wrapper.CreateCategory(ctx, wrapperParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Category{ID: 3, ParentID: &parentID, Name: "Mystery", Slug: "mystery"}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestSetBookCategories() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("set book categories got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().DeleteBookCategories(ctx, int64(7)).
+			Return(errors.New("querier error")).Times(1)
+
+		result, err := wrapper.SetBookCategories(ctx, nil, 7, []int64{1})
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("clear book categories", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().DeleteBookCategories(ctx, int64(7)).
+			Return(nil).Times(1)
+
+		result, err := wrapper.SetBookCategories(ctx, nil, 7, nil)
+		s.Assert().Zero(result)
+		s.Assert().Nil(err)
+	})
+
+	s.Run("set book categories successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(2)
+		s.querierRepo.EXPECT().DeleteBookCategories(ctx, int64(7)).
+			Return(nil).Times(1)
+		s.querierRepo.EXPECT().AddBookCategories(ctx, db.AddBookCategoriesParams{BookID: 7, CategoryIds: []int64{1, 3}}).
+			Return(int64(2), nil).Times(1)
+
+		result, err := wrapper.SetBookCategories(ctx, nil, 7, []int64{1, 3})
+		s.Assert().Equal(int64(2), result)
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindSeries() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("series not found", func() {
+		s.querierRepo.EXPECT().FindSeries(ctx, int64(9)).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindSeries(ctx, 9)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("find series successful", func() {
+		s.querierRepo.EXPECT().FindSeries(ctx, int64(2)).
+			Return(&db.Series{ID: 2, Name: "Foundation", Description: "Psychohistory"}, nil).Times(1)
+
+		result, err := wrapper.FindSeries(ctx, 2)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Series{ID: 2, Name: "Foundation", Description: "Psychohistory"}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestGetSeriesVolumes() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("get series volumes got querier error", func() {
+		s.querierRepo.EXPECT().GetSeriesVolumes(ctx, []int64{2}).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetSeriesVolumes(ctx, []int64{2})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("get series volumes successful", func() {
+		s.querierRepo.EXPECT().GetSeriesVolumes(ctx, []int64{2}).
+			Return([]*db.GetSeriesVolumesRow{
+				{
+					ID:           4,
+					Name:         "Foundation",
+					Status:       entity.BookStatusActive,
+					SeriesID:     pgtype.Int8{Int64: 2, Valid: true},
+					SeriesVolume: pgtype.Int4{Int32: 1, Valid: true},
+				},
+			}, nil).Times(1)
+
+		result, err := wrapper.GetSeriesVolumes(ctx, []int64{2})
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.Book{
+			{
+				ID:     4,
+				Name:   "Foundation",
+				Status: entity.BookStatusActive,
+				Series: &entity.BookSeries{ID: 2, Volume: 1},
+			},
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindBookBySKU() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("book not found", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindBookBySKU(ctx, "BK00000009").
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindBookBySKU(ctx, nil, "BK00000009")
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("find book by sku successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindBookBySKU(ctx, "BK00000004").
+			Return(&db.Book{ID: 4, Name: "Dune", Format: "ebook", WorkID: 3, Sku: "BK00000004"}, nil).Times(1)
+
+		result, err := wrapper.FindBookBySKU(ctx, nil, "BK00000004")
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Book{ID: 4, Name: "Dune", Format: "ebook", WorkID: 3, SKU: "BK00000004"}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindWork() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("work not found", func() {
+		s.querierRepo.EXPECT().FindWork(ctx, int64(9)).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindWork(ctx, 9)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("find work successful", func() {
+		s.querierRepo.EXPECT().FindWork(ctx, int64(3)).
+			Return(&db.Work{
+				ID:           3,
+				Title:        "Dune",
+				Authors:      "Frank Herbert",
+				SeriesID:     pgtype.Int8{Int64: 2, Valid: true},
+				SeriesVolume: pgtype.Int4{Int32: 1, Valid: true},
+			}, nil).Times(1)
+
+		result, err := wrapper.FindWork(ctx, 3)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Work{
+			ID:      3,
+			Title:   "Dune",
+			Authors: "Frank Herbert",
+			Series:  &entity.BookSeries{ID: 2, Volume: 1},
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestGetWorkEditions() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("get work editions got querier error", func() {
+		s.querierRepo.EXPECT().GetWorkEditions(ctx, int64(3)).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetWorkEditions(ctx, 3)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("get work editions successful", func() {
+		s.querierRepo.EXPECT().GetWorkEditions(ctx, int64(3)).
+			Return([]*db.Book{
+				{ID: 4, Name: "Dune", Format: "ebook", WorkID: 3, Sku: "BK00000004"},
+				{ID: 3, Name: "Dune", Format: "hardcover", WorkID: 3, Sku: "BK00000003"},
+			}, nil).Times(1)
+
+		result, err := wrapper.GetWorkEditions(ctx, 3)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.Book{
+			{ID: 4, Name: "Dune", Format: "ebook", WorkID: 3, SKU: "BK00000004"},
+			{ID: 3, Name: "Dune", Format: "hardcover", WorkID: 3, SKU: "BK00000003"},
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestGetWorkIDsOfBooks() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("get work ids of books got querier error", func() {
+		s.querierRepo.EXPECT().GetWorkIDsOfBooks(ctx, []int64{3, 4}).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetWorkIDsOfBooks(ctx, []int64{3, 4})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("get work ids of books successful", func() {
+		s.querierRepo.EXPECT().GetWorkIDsOfBooks(ctx, []int64{3, 4}).Return([]int64{3}, nil).Times(1)
+
+		result, err := wrapper.GetWorkIDsOfBooks(ctx, []int64{3, 4})
+		s.Assert().Nil(err)
+		s.Assert().Equal([]int64{3}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestSetBookCover() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	params := db.SetBookCoverParams{CoverKey: pgtype.Text{String: "abc", Valid: true}, ID: 7}
+
+	s.Run("book not found", func() {
+		s.querierRepo.EXPECT().SetBookCover(ctx, params).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.SetBookCover(ctx, 7, "abc")
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("set book cover successful", func() {
+		s.querierRepo.EXPECT().SetBookCover(ctx, params).
+			Return(&db.Book{ID: 7, Name: "Dune", Version: 2, CoverKey: pgtype.Text{String: "abc", Valid: true}}, nil).Times(1)
+
+		result, err := wrapper.SetBookCover(ctx, 7, "abc")
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Book{ID: 7, Name: "Dune", Version: 2, Cover: entity.NewBookCover("abc")}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindOrder() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	now := time.Now()
+
+	s.Run("order not found", func() {
+		s.querierRepo.EXPECT().FindOrder(ctx, int64(9)).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindOrder(ctx, 9)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("order items got querier error", func() {
+		s.querierRepo.EXPECT().FindOrder(ctx, int64(3)).
+			Return(&db.FindOrderRow{ID: 3, UserID: 5, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.querierRepo.EXPECT().GetOrderItems(ctx, []int64{3}).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.FindOrder(ctx, 3)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("find order successful", func() {
+		price := int64(12000)
+
+		s.querierRepo.EXPECT().FindOrder(ctx, int64(3)).
+			Return(&db.FindOrderRow{
+				ID:        3,
+				UserID:    5,
+				Email:     "someone@test.com",
+				Status:    entity.OrderStatusPaid,
+				CreatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+			}, nil).Times(1)
+		s.querierRepo.EXPECT().GetOrderItems(ctx, []int64{3}).
+			Return([]*db.GetOrderItemsRow{
+				{
+					ID:          29,
+					OrderID:     3,
+					BookID:      99,
+					Amount:      2,
+					Sku:         pgtype.Text{String: "BK00000099", Valid: true},
+					Price:       pgtype.Int8{Int64: price, Valid: true},
+					BookName:    pgtype.Text{String: "Dune", Valid: true},
+					BookAuthors: pgtype.Text{String: "Frank Herbert", Valid: true},
+					BookStatus:  pgtype.Text{String: entity.BookStatusActive, Valid: true},
+					CreatedAt:   pgtype.Timestamptz{Time: now, Valid: true},
+				},
+			}, nil).Times(1)
+		s.querierRepo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).
+			Return([]*db.OrderStatusChange{
+				{
+					OrderID:   3,
+					ToStatus:  entity.OrderStatusPaid,
+					ActorRole: entity.OrderActorSystem,
+					CreatedAt: pgtype.Timestamptz{Time: now, Valid: true},
+				},
+			}, nil).Times(1)
+		s.querierRepo.EXPECT().GetOrderReturns(ctx, []int64{3}).
+			Return([]*db.Return{}, nil).Times(1)
+
+		result, err := wrapper.FindOrder(ctx, 3)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Order{
+			ID:     3,
+			UserID: 5,
+			Email:  "someone@test.com",
+			Status: entity.OrderStatusPaid,
+			Items: []entity.OrderItem{
+				{
+					ID:        29,
+					OrderID:   3,
+					BookID:    99,
+					SKU:       "BK00000099",
+					Book:      &entity.BookSummary{ID: 99, Name: "Dune", Authors: "Frank Herbert", Status: entity.BookStatusActive},
+					Amount:    2,
+					Price:     &price,
+					CreatedAt: now,
+				},
+			},
+			History: []entity.OrderStatusChange{
+				{OrderID: 3, To: entity.OrderStatusPaid, ActorRole: entity.OrderActorSystem, CreatedAt: now},
+			},
+			Returns:   []entity.Return{},
+			CreatedAt: now,
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestGetExpiredOrders() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("get expired orders got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().GetExpiredOrders(ctx, int64(50)).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetExpiredOrders(ctx, nil, 50)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("get expired orders successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().GetExpiredOrders(ctx, int64(50)).
+			Return([]*db.GetExpiredOrdersRow{
+				{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment},
+				{ID: 4, UserID: 6, Status: entity.OrderStatusPendingPayment},
+			}, nil).Times(1)
+
+		result, err := wrapper.GetExpiredOrders(ctx, nil, 50)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.Order{
+			{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment},
+			{ID: 4, UserID: 6, Status: entity.OrderStatusPendingPayment},
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindOrderForUpdate() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("order not found", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindOrderForUpdate(ctx, int64(9)).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindOrderForUpdate(ctx, nil, 9)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("find order successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindOrderForUpdate(ctx, int64(3)).
+			Return(&db.FindOrderForUpdateRow{ID: 3, UserID: 5, Status: entity.OrderStatusPaid}, nil).Times(1)
+
+		result, err := wrapper.FindOrderForUpdate(ctx, nil, 3)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPaid}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestUpdateOrderStatus() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	params := db.UpdateOrderStatusParams{ToStatus: entity.OrderStatusShipped, ID: 3, FromStatus: entity.OrderStatusFulfilling}
+
+	s.Run("status changed concurrently", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().UpdateOrderStatus(ctx, params).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.UpdateOrderStatus(ctx, nil, 3, entity.OrderStatusFulfilling, entity.OrderStatusShipped)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodePreconditionFailed, goxErr.Code)
+	})
+
+	s.Run("update order status successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().UpdateOrderStatus(ctx, params).
+			Return(&db.UpdateOrderStatusRow{ID: 3, UserID: 5, Status: entity.OrderStatusShipped}, nil).Times(1)
+
+		result, err := wrapper.UpdateOrderStatus(ctx, nil, 3, entity.OrderStatusFulfilling, entity.OrderStatusShipped)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusShipped}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestCreateOrderStatusChange() {
+	ctx := context.Background()
+	now := time.Now()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	actorID := int64(1)
+
+	s.Run("create order status change successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().CreateOrderStatusChange(ctx, db.CreateOrderStatusChangeParams{
+			OrderID:    3,
+			FromStatus: pgtype.Text{String: entity.OrderStatusPaid, Valid: true},
+			ToStatus:   entity.OrderStatusFulfilling,
+			ActorID:    pgtype.Int8{Int64: 1, Valid: true},
+			ActorRole:  entity.UserRoleAdmin,
+		}).Return(&db.OrderStatusChange{
+			ID:         8,
+			OrderID:    3,
+			FromStatus: pgtype.Text{String: entity.OrderStatusPaid, Valid: true},
+			ToStatus:   entity.OrderStatusFulfilling,
+			ActorID:    pgtype.Int8{Int64: 1, Valid: true},
+			ActorRole:  entity.UserRoleAdmin,
+			CreatedAt:  pgtype.Timestamptz{Time: now, Valid: true},
+		}, nil).Times(1)
+
+		result, err := wrapper.CreateOrderStatusChange(ctx, nil, entity.OrderStatusChange{
+			OrderID:   3,
+			From:      entity.OrderStatusPaid,
+			To:        entity.OrderStatusFulfilling,
+			ActorID:   &actorID,
+			ActorRole: entity.UserRoleAdmin,
+		})
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.OrderStatusChange{
+			OrderID:   3,
+			From:      entity.OrderStatusPaid,
+			To:        entity.OrderStatusFulfilling,
+			ActorID:   &actorID,
+			ActorRole: entity.UserRoleAdmin,
+			CreatedAt: now,
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestDecrementBookStock() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	params := db.DecrementBookStockParams{Amount: 2, Sku: "BK00000099"}
+
+	s.Run("decrement stock got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().DecrementBookStock(ctx, params).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		result, err := wrapper.DecrementBookStock(ctx, nil, "BK00000099", 2)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("decrement stock successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().DecrementBookStock(ctx, params).
+			Return(int64(1), nil).Times(1)
+
+		result, err := wrapper.DecrementBookStock(ctx, nil, "BK00000099", 2)
+		s.Assert().Equal(int64(1), result)
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *WrapperTestSuite) TestReleaseOrderStock() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("release stock got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().ReleaseOrderStock(ctx, int64(3)).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		result, err := wrapper.ReleaseOrderStock(ctx, nil, 3)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("release stock successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().ReleaseOrderStock(ctx, int64(3)).
+			Return(int64(2), nil).Times(1)
+
+		result, err := wrapper.ReleaseOrderStock(ctx, nil, 3)
+		s.Assert().Equal(int64(2), result)
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *WrapperTestSuite) TestReserveIdempotencyKey() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	expiresAt := time.Now().Add(time.Hour)
+	key := entity.IdempotencyKey{UserID: 123, Key: "retry-1", Fingerprint: "abc", Lease: "lease-1", ExpiresAt: expiresAt}
+	params := db.ReserveIdempotencyKeyParams{
+		UserID:      123,
+		Key:         "retry-1",
+		Fingerprint: "abc",
+		Lease:       "lease-1",
+		ExpiresAt:   pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}
+
+	s.Run("reserve key got querier error", func() {
+		s.querierRepo.EXPECT().ReserveIdempotencyKey(ctx, params).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		reserved, err := wrapper.ReserveIdempotencyKey(ctx, key)
+		s.Assert().False(reserved)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("key already taken", func() {
+		s.querierRepo.EXPECT().ReserveIdempotencyKey(ctx, params).
+			Return(int64(0), nil).Times(1)
+
+		reserved, err := wrapper.ReserveIdempotencyKey(ctx, key)
+		s.Assert().Nil(err)
+		s.Assert().False(reserved)
+	})
+
+	s.Run("key reserved", func() {
+		s.querierRepo.EXPECT().ReserveIdempotencyKey(ctx, params).
+			Return(int64(1), nil).Times(1)
+
+		reserved, err := wrapper.ReserveIdempotencyKey(ctx, key)
+		s.Assert().Nil(err)
+		s.Assert().True(reserved)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindIdempotencyKey() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	params := db.FindIdempotencyKeyParams{UserID: 123, Key: "retry-1"}
+
+	s.Run("key not found", func() {
+		s.querierRepo.EXPECT().FindIdempotencyKey(ctx, params).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindIdempotencyKey(ctx, 123, "retry-1")
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("find key successful", func() {
+		expiresAt := time.Now().Add(time.Hour)
+		s.querierRepo.EXPECT().FindIdempotencyKey(ctx, params).
+			Return(&db.IdempotencyKey{
+				UserID:          123,
+				Key:             "retry-1",
+				Fingerprint:     "abc",
+				StatusCode:      pgtype.Int4{Int32: 201, Valid: true},
+				Response:        []byte(`{"id":1}`),
+				ResponseHeaders: []byte(`{"Location":["/v1/orders/1"]}`),
+				ExpiresAt:       pgtype.Timestamptz{Time: expiresAt, Valid: true},
+			}, nil).Times(1)
+
+		result, err := wrapper.FindIdempotencyKey(ctx, 123, "retry-1")
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.IdempotencyKey{
+			UserID:      123,
+			Key:         "retry-1",
+			Fingerprint: "abc",
+			StatusCode:  201,
+			Header:      map[string][]string{"Location": {"/v1/orders/1"}},
+			Response:    []byte(`{"id":1}`),
+			ExpiresAt:   expiresAt,
+		}, result)
+	})
+
+	s.Run("key saved before headers were kept", func() {
+		s.querierRepo.EXPECT().FindIdempotencyKey(ctx, params).
+			Return(&db.IdempotencyKey{UserID: 123, Key: "retry-1", StatusCode: pgtype.Int4{Int32: 201, Valid: true}}, nil).Times(1)
+
+		result, err := wrapper.FindIdempotencyKey(ctx, 123, "retry-1")
+		s.Assert().Nil(err)
+		s.Assert().Nil(result.Header)
+	})
+}
+
+func (s *WrapperTestSuite) TestSaveIdempotencyResponse() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	expiresAt := time.Now().Add(time.Hour)
+	key := entity.IdempotencyKey{
+		UserID:     123,
+		Key:        "retry-1",
+		Lease:      "lease-1",
+		StatusCode: 201,
+		Header:     map[string][]string{"Location": {"/v1/orders/1"}},
+		Response:   []byte(`{"id":1}`),
+		ExpiresAt:  expiresAt,
+	}
+	params := db.SaveIdempotencyResponseParams{
+		StatusCode:      pgtype.Int4{Int32: 201, Valid: true},
+		Response:        []byte(`{"id":1}`),
+		ResponseHeaders: []byte(`{"Location":["/v1/orders/1"]}`),
+		ExpiresAt:       pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		UserID:          123,
+		Key:             "retry-1",
+		Lease:           "lease-1",
+	}
+
+	s.Run("save response got querier error", func() {
+		s.querierRepo.EXPECT().SaveIdempotencyResponse(ctx, params).
+			Return(errors.New("querier error")).Times(1)
+
+		err := wrapper.SaveIdempotencyResponse(ctx, key)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("save response successful", func() {
+		s.querierRepo.EXPECT().SaveIdempotencyResponse(ctx, params).
+			Return(nil).Times(1)
+
+		s.Assert().Nil(wrapper.SaveIdempotencyResponse(ctx, key))
+	})
+}
+
+func (s *WrapperTestSuite) TestDeleteIdempotencyKey() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	key := entity.IdempotencyKey{UserID: 123, Key: "retry-1", Lease: "lease-1"}
+	params := db.DeleteIdempotencyKeyParams{UserID: 123, Key: "retry-1", Lease: "lease-1"}
+
+	s.Run("delete key got querier error", func() {
+		s.querierRepo.EXPECT().DeleteIdempotencyKey(ctx, params).
+			Return(errors.New("querier error")).Times(1)
+
+		err := wrapper.DeleteIdempotencyKey(ctx, key)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("delete key successful", func() {
+		s.querierRepo.EXPECT().DeleteIdempotencyKey(ctx, params).
+			Return(nil).Times(1)
+
+		s.Assert().Nil(wrapper.DeleteIdempotencyKey(ctx, key))
+	})
+}
+
+func (s *WrapperTestSuite) TestDeleteExpiredIdempotencyKeys() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("delete expired keys got querier error", func() {
+		s.querierRepo.EXPECT().DeleteExpiredIdempotencyKeys(ctx, int64(100)).
+			Return(int64(0), errors.New("querier error")).Times(1)
+
+		deleted, err := wrapper.DeleteExpiredIdempotencyKeys(ctx, 100)
+		s.Assert().Zero(deleted)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("delete expired keys successful", func() {
+		s.querierRepo.EXPECT().DeleteExpiredIdempotencyKeys(ctx, int64(100)).
+			Return(int64(42), nil).Times(1)
+
+		deleted, err := wrapper.DeleteExpiredIdempotencyKeys(ctx, 100)
+		s.Assert().Nil(err)
+		s.Assert().Equal(int64(42), deleted)
+	})
+}
+
+func (s *WrapperTestSuite) TestGetUserCart() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	userID := pgtype.Int8{Int64: 123, Valid: true}
+
+	s.Run("get cart got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().GetOrCreateUserCart(ctx, userID).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetUserCart(ctx, nil, 123)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("get cart successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().GetOrCreateUserCart(ctx, userID).
+			Return(&db.Cart{ID: 7, UserID: userID}, nil).Times(1)
+
+		result, err := wrapper.GetUserCart(ctx, nil, 123)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Cart{ID: 7, UserID: 123, Items: []entity.CartItem{}}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindGuestCart() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	token := pgtype.Text{String: "guest-token", Valid: true}
+
+	s.Run("cart not found", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindGuestCart(ctx, token).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindGuestCart(ctx, nil, "guest-token")
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("find cart successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindGuestCart(ctx, token).
+			Return(&db.Cart{ID: 8, Token: token}, nil).Times(1)
+
+		result, err := wrapper.FindGuestCart(ctx, nil, "guest-token")
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Cart{ID: 8, Token: "guest-token", Items: []entity.CartItem{}}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestMergeCart() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	items := []entity.CartItem{
+		{SKU: "BK00000099", Amount: 3, Price: 9000},
+		{SKU: "BK00000102", Amount: 1, Price: 2500},
+	}
+	params := db.MergeCartItemsParams{
+		CartID:  7,
+		Skus:    []string{"BK00000099", "BK00000102"},
+		Amounts: []int64{3, 1},
+		Prices:  []int64{9000, 2500},
+	}
+
+	s.Run("merge items got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().MergeCartItems(ctx, params).
+			Return(errors.New("querier error")).Times(1)
+
+		err := wrapper.MergeCart(ctx, nil, 8, 7, items)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("delete cart got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().MergeCartItems(ctx, params).
+			Return(nil).Times(1)
+		s.querierRepo.EXPECT().DeleteCart(ctx, int64(8)).
+			Return(errors.New("querier error")).Times(1)
+
+		err := wrapper.MergeCart(ctx, nil, 8, 7, items)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("merge cart successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().MergeCartItems(ctx, params).
+			Return(nil).Times(1)
+		s.querierRepo.EXPECT().DeleteCart(ctx, int64(8)).
+			Return(nil).Times(1)
+
+		s.Assert().Nil(wrapper.MergeCart(ctx, nil, 8, 7, items))
+	})
+
+	s.Run("nothing to merge only deletes the cart", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().DeleteCart(ctx, int64(8)).
+			Return(nil).Times(1)
+
+		s.Assert().Nil(wrapper.MergeCart(ctx, nil, 8, 7, nil))
+	})
+}
+
+func (s *WrapperTestSuite) TestGetCartItems() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("get items got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().GetCartItems(ctx, int64(7)).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetCartItems(ctx, nil, 7)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("get items successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().GetCartItems(ctx, int64(7)).
+			Return([]*db.GetCartItemsRow{{
+				Sku:         "BK00000099",
+				Amount:      2,
+				AddedPrice:  9000,
+				BookID:      99,
+				BookName:    "Dune",
+				BookAuthors: "Frank Herbert",
+				BookStatus:  entity.BookStatusActive,
+				Price:       9500,
+				Stock:       4,
+			}}, nil).Times(1)
+
+		result, err := wrapper.GetCartItems(ctx, nil, 7)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.CartItem{{
+			SKU:        "BK00000099",
+			Book:       &entity.BookSummary{ID: 99, Name: "Dune", Authors: "Frank Herbert", Status: entity.BookStatusActive},
+			Amount:     2,
+			Price:      9500,
+			AddedPrice: 9000,
+			Stock:      4,
+		}}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindPaymentAttempt() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	params := db.FindPaymentAttemptParams{Provider: "mock", Reference: "pi_3"}
+
+	s.Run("payment not found", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindPaymentAttempt(ctx, params).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindPaymentAttempt(ctx, nil, "mock", "pi_3")
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("find payment successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindPaymentAttempt(ctx, params).
+			Return(&db.PaymentAttempt{
+				ID:            11,
+				OrderID:       3,
+				Provider:      "mock",
+				Reference:     "pi_3",
+				Amount:        20000,
+				Status:        entity.PaymentStatusFailed,
+				FailureReason: pgtype.Text{String: "card declined", Valid: true},
+			}, nil).Times(1)
+
+		result, err := wrapper.FindPaymentAttempt(ctx, nil, "mock", "pi_3")
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Payment{
+			ID:            11,
+			OrderID:       3,
+			Provider:      "mock",
+			Reference:     "pi_3",
+			Amount:        20000,
+			Status:        entity.PaymentStatusFailed,
+			FailureReason: "card declined",
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestUpdatePaymentAttempt() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("update payment got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().UpdatePaymentAttempt(ctx, gomock.Any()).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.UpdatePaymentAttempt(ctx, nil, entity.Payment{ID: 11})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("captured payment has no failure reason", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().UpdatePaymentAttempt(ctx, db.UpdatePaymentAttemptParams{
+			ID:     11,
+			Status: entity.PaymentStatusCaptured,
+		}).Return(&db.PaymentAttempt{ID: 11, Status: entity.PaymentStatusCaptured}, nil).Times(1)
+
+		result, err := wrapper.UpdatePaymentAttempt(ctx, nil, entity.Payment{ID: 11, Status: entity.PaymentStatusCaptured})
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Payment{ID: 11, Status: entity.PaymentStatusCaptured}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestCreatePaymentRefund() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	refund := entity.PaymentRefund{PaymentID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}
+
+	s.Run("create refund got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().CreatePaymentRefund(ctx, gomock.Any()).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.CreatePaymentRefund(ctx, nil, refund)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("create refund successful", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().CreatePaymentRefund(ctx, db.CreatePaymentRefundParams{
+			PaymentAttemptID: 11,
+			Amount:           19000,
+			Status:           entity.PaymentRefundStatusPending,
+		}).Return(&db.PaymentRefund{ID: 21, PaymentAttemptID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}, nil).Times(1)
+
+		result, err := wrapper.CreatePaymentRefund(ctx, nil, refund)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.PaymentRefund{ID: 21, PaymentID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestFindPendingPaymentRefundForUpdate() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("refund sent already", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, int64(21)).
+			Return(nil, sql.ErrNoRows).Times(1)
+
+		result, err := wrapper.FindPendingPaymentRefundForUpdate(ctx, nil, 21)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("refund carries the reference of its payment", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, int64(21)).
+			Return(&db.FindPendingPaymentRefundForUpdateRow{
+				ID:               21,
+				PaymentAttemptID: 11,
+				Amount:           19000,
+				Status:           entity.PaymentRefundStatusPending,
+				Attempts:         1,
+				LastError:        pgtype.Text{String: "provider down", Valid: true},
+				Provider:         "mock",
+				Reference:        "pi_3",
+			}, nil).Times(1)
+
+		result, err := wrapper.FindPendingPaymentRefundForUpdate(ctx, nil, 21)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.PaymentRefund{
+			ID:        21,
+			PaymentID: 11,
+			Provider:  "mock",
+			Reference: "pi_3",
+			Amount:    19000,
+			Status:    entity.PaymentRefundStatusPending,
+			Attempts:  1,
+			LastError: "provider down",
+		}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestGetPendingPaymentRefunds() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+	before := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	s.Run("get refunds got querier error", func() {
+		s.querierRepo.EXPECT().GetPendingPaymentRefunds(ctx, gomock.Any()).
+			Return(nil, errors.New("querier error")).Times(1)
+
+		result, err := wrapper.GetPendingPaymentRefunds(ctx, before, 10)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("get refunds successful", func() {
+		s.querierRepo.EXPECT().GetPendingPaymentRefunds(ctx, db.GetPendingPaymentRefundsParams{
+			UpdatedAt: pgtype.Timestamptz{Time: before, Valid: true},
+			Limit:     10,
+		}).Return([]int64{21, 22}, nil).Times(1)
+
+		result, err := wrapper.GetPendingPaymentRefunds(ctx, before, 10)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]int64{21, 22}, result)
+	})
+}
+
+func (s *WrapperTestSuite) TestUpdatePaymentRefund() {
+	ctx := context.Background()
+	wrapper := repository.NewDbWrapperRepo(s.querierRepo)
+
+	s.Run("update refund got querier error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().UpdatePaymentRefund(ctx, gomock.Any()).
+			Return(errors.New("querier error")).Times(1)
+
+		err := wrapper.UpdatePaymentRefund(ctx, nil, entity.PaymentRefund{ID: 21})
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "internal server error")
+	})
+
+	s.Run("succeeded refund has no error", func() {
+		s.querierRepo.EXPECT().WrapTx(nil).
+			Return(s.querierRepo).Times(1)
+		s.querierRepo.EXPECT().UpdatePaymentRefund(ctx, db.UpdatePaymentRefundParams{
+			ID:       21,
+			Status:   entity.PaymentRefundStatusSucceeded,
+			Attempts: 2,
+		}).Return(nil).Times(1)
+
+		err := wrapper.UpdatePaymentRefund(ctx, nil, entity.PaymentRefund{ID: 21, Status: entity.PaymentRefundStatusSucceeded, Attempts: 2})
+		s.Assert().Nil(err)
+	})
+}
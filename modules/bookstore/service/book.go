@@ -2,28 +2,269 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/raymondwongso/gogox/errorx"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/pagination"
 )
 
+// EstimatedCountThreshold is the table size under which an estimated count falls back to an exact one.
+const EstimatedCountThreshold = 10000
+
 type BookService struct {
-	repo      BookRepository
-	validator *validator.Validate
+	repo        BookRepository
+	validator   *validator.Validate
+	suggestions *suggestionCache
 }
 
 func NewBookService(repo BookRepository) *BookService {
 	return &BookService{
-		repo:      repo,
-		validator: validator.New(),
+		repo:        repo,
+		validator:   validator.New(),
+		suggestions: newSuggestionCache(DefaultSuggestionCacheSize, DefaultSuggestionCacheTTL),
+	}
+}
+
+func (s *BookService) GetBooks(ctx context.Context, params entity.GetBooksParams) (*entity.BookList, error) {
+	if err := s.validateGetBooksParams(params); err != nil {
+		return nil, err
+	}
+
+	if params.Query == "" && params.Sort == entity.BookSortRelevance {
+		params.Sort = ""
+	}
+	if params.Query != "" && params.Sort == "" {
+		params.Sort = entity.BookSortRelevance
+	}
+
+	if params.Cursor != "" {
+		if params.Offset != 0 {
+			return nil, errorx.ErrInvalidParameter("cursor cannot be combined with offset")
+		}
+
+		after, err := decodeBookCursor(params.Cursor, params.Sort)
+		if err != nil {
+			return nil, err
+		}
+		params.After = after
+	}
+
+	// one extra row tells whether there is a next page without a separate count
+	limit := params.Limit
+	params.Limit++
+
+	books, err := s.repo.GetBooks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &entity.BookList{Data: books}
+	if int64(len(books)) > limit {
+		list.Data = books[:limit]
+		list.NextCursor = encodeBookCursor(params.Sort, list.Data[limit-1])
+		list.HasMore = true
+	}
+
+	return list, nil
+}
+
+// CountBooks counts the books matched by the filters in params. With estimate set and no filter narrowing the catalog,
+// the planner's row estimate is returned instead once the table is large enough for an exact count to be costly.
+func (s *BookService) CountBooks(ctx context.Context, params entity.GetBooksParams, estimate bool) (*entity.TotalCount, error) {
+	if err := s.validateGetBooksParams(params); err != nil {
+		return nil, err
+	}
+
+	if estimate && !hasBookFilters(params) {
+		count, err := s.repo.EstimateBooksCount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if count >= EstimatedCountThreshold {
+			return &entity.TotalCount{Count: count, Estimated: true}, nil
+		}
+	}
+
+	count, err := s.repo.CountBooks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TotalCount{Count: count}, nil
+}
+
+// GetBookFacets counts the whole result set matched by the filters in params, regardless of the requested page.
+func (s *BookService) GetBookFacets(ctx context.Context, params entity.GetBooksParams) (*entity.BookFacets, error) {
+	if err := s.validateGetBooksParams(params); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetBookFacets(ctx, params)
+}
+
+func (s *BookService) validateGetBooksParams(params entity.GetBooksParams) error {
+	if err := s.validator.Struct(params); err != nil {
+		return errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	if params.MinPrice != nil && params.MaxPrice != nil && *params.MinPrice > *params.MaxPrice {
+		return errorx.ErrInvalidParameter("min_price cannot be greater than max_price")
+	}
+
+	return nil
+}
+
+func (s *BookService) SuggestBooks(ctx context.Context, params entity.SuggestBooksParams) ([]entity.BookSuggestion, error) {
+	params.Prefix = strings.ToLower(strings.Join(strings.Fields(params.Prefix), " "))
+	if err := s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	key := fmt.Sprintf("%d:%s", params.Limit, params.Prefix)
+	if cached, exist := s.suggestions.get(key); exist {
+		return cached, nil
 	}
+
+	result, err := s.repo.SuggestBooks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	s.suggestions.set(key, result)
+	return result, nil
+}
+
+func (s *BookService) CreateBook(ctx context.Context, params entity.CreateBookParams) (*entity.Book, error) {
+	params.Name = strings.TrimSpace(params.Name)
+	params.Language = strings.ToLower(strings.TrimSpace(params.Language))
+	params.Format = strings.ToLower(strings.TrimSpace(params.Format))
+	if params.Status == "" {
+		params.Status = entity.BookStatusActive
+	}
+	if err := s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	return s.repo.CreateBook(ctx, params)
+}
+
+func (s *BookService) UpdateBook(ctx context.Context, params entity.UpdateBookParams) (*entity.Book, error) {
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		params.Name = &name
+	}
+	if params.Language != nil {
+		language := strings.ToLower(strings.TrimSpace(*params.Language))
+		params.Language = &language
+	}
+	if params.Format != nil {
+		format := strings.ToLower(strings.TrimSpace(*params.Format))
+		params.Format = &format
+	}
+	if err := s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	if params.Name == nil && params.Authors == nil && params.Description == nil && params.Category == nil &&
+		params.Language == nil && params.Format == nil && params.Price == nil && params.Stock == nil &&
+		params.PublishedAt == nil && params.Status == nil && params.SeriesID == nil && params.SeriesVolume == nil &&
+		params.WorkID == nil {
+		return nil, errorx.ErrInvalidParameter("nothing to update")
+	}
+
+	return s.repo.UpdateBook(ctx, params)
 }
 
-func (s *BookService) GetBooks(ctx context.Context, params entity.GetBooksParams) ([]entity.Book, error) {
+func (s *BookService) DeleteBook(ctx context.Context, params entity.DeleteBookParams) error {
 	if err := s.validator.Struct(params); err != nil {
+		return errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	return s.repo.DeleteBook(ctx, params)
+}
+
+// GetWork returns a work with every edition of it that is not hidden.
+func (s *BookService) GetWork(ctx context.Context, id int64) (*entity.Work, error) {
+	if id <= 0 {
 		return nil, errorx.ErrInvalidParameter("Input is invalid")
 	}
 
-	return s.repo.GetBooks(ctx, params)
+	work, err := s.repo.FindWork(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	work.Editions, err = s.repo.GetWorkEditions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return work, nil
+}
+
+func hasBookFilters(params entity.GetBooksParams) bool {
+	return params.Query != "" || params.Author != "" || params.Category != "" || params.CategoryID != 0 ||
+		params.Language != "" || params.Format != "" || params.MinPrice != nil || params.MaxPrice != nil ||
+		params.InStock || params.PublishedYear != 0
+}
+
+func encodeBookCursor(sort string, last entity.Book) string {
+	cursor := pagination.Cursor{Sort: sort, ID: last.ID}
+	switch sort {
+	case entity.BookSortRelevance:
+		cursor.Key = strconv.FormatFloat(float64(last.Rank), 'g', -1, 32)
+	case entity.BookSortTitle:
+		cursor.Key = last.Name
+	case entity.BookSortPriceAsc, entity.BookSortPriceDesc:
+		cursor.Key = strconv.FormatInt(last.Price, 10)
+	case entity.BookSortPublicationDate:
+		if last.PublishedAt != nil {
+			cursor.Key = last.PublishedAt.Format(time.DateOnly)
+		}
+	case entity.BookSortPopularity:
+		cursor.Key = strconv.FormatInt(last.SoldCount, 10)
+	case entity.BookSortNewest:
+		cursor.Key = last.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return cursor.Encode()
+}
+
+func decodeBookCursor(encoded, sort string) (*entity.BookCursor, error) {
+	cursor, err := pagination.Decode(encoded)
+	if err != nil || cursor.Sort != sort {
+		return nil, errorx.ErrInvalidParameter("cursor invalid")
+	}
+
+	after := &entity.BookCursor{ID: cursor.ID}
+	switch sort {
+	case entity.BookSortRelevance:
+		var rank float64
+		rank, err = strconv.ParseFloat(cursor.Key, 32)
+		after.Rank = float32(rank)
+	case entity.BookSortTitle:
+		after.Name = cursor.Key
+	case entity.BookSortPriceAsc, entity.BookSortPriceDesc:
+		after.Price, err = strconv.ParseInt(cursor.Key, 10, 64)
+	case entity.BookSortPublicationDate:
+		if cursor.Key != "" {
+			var publishedAt time.Time
+			publishedAt, err = time.Parse(time.DateOnly, cursor.Key)
+			after.PublishedAt = &publishedAt
+		}
+	case entity.BookSortPopularity:
+		after.SoldCount, err = strconv.ParseInt(cursor.Key, 10, 64)
+	case entity.BookSortNewest:
+		after.CreatedAt, err = time.Parse(time.RFC3339Nano, cursor.Key)
+	}
+	if err != nil {
+		return nil, errorx.ErrInvalidParameter("cursor invalid")
+	}
+
+	return after, nil
 }
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// ExportBatchSize is how many books are read per query while an export walks the catalog.
+const ExportBatchSize = 1000
+
+// bookExportColumns is the CSV header, in the order csvBookExportWriter writes the values.
+var bookExportColumns = []string{
+	"id", "sku", "work_id", "isbn", "name", "authors", "description", "category", "language", "format", "price", "stock",
+	"published_at", "sold_count", "status", "version", "created_at", "updated_at",
+}
+
+type ExportService struct {
+	repo      ExportRepository
+	validator *validator.Validate
+}
+
+func NewExportService(repo ExportRepository) *ExportService {
+	return &ExportService{
+		repo:      repo,
+		validator: validator.New(),
+	}
+}
+
+// bookExportWriter encodes one export format. Write is called once per book, Flush after every batch.
+type bookExportWriter interface {
+	Write(book entity.BookExport) error
+	Flush() error
+}
+
+// ExportBooks writes the whole catalog to w, optionally only the books updated since a given time. Books are read in
+// id order one batch at a time, so memory stays flat however large the catalog is. It returns how many were written.
+func (s *ExportService) ExportBooks(ctx context.Context, params entity.ExportBooksParams, w io.Writer) (int64, error) {
+	if err := s.validator.Struct(params); err != nil {
+		return 0, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	var out bookExportWriter
+	switch params.Format {
+	case entity.ExportFormatCSV:
+		out = newCSVBookExportWriter(w)
+	case entity.ExportFormatJSONL:
+		out = newJSONLBookExportWriter(w)
+	}
+
+	params.AfterID = 0
+	params.Limit = ExportBatchSize
+
+	var written int64
+	for {
+		books, err := s.repo.ExportBooks(ctx, params)
+		if err != nil {
+			return written, err
+		}
+
+		for _, book := range books {
+			if err = out.Write(toBookExport(book)); err != nil {
+				return written, err
+			}
+			written++
+		}
+
+		if err = out.Flush(); err != nil {
+			return written, err
+		}
+
+		if int64(len(books)) < params.Limit {
+			return written, nil
+		}
+		params.AfterID = books[len(books)-1].ID
+	}
+}
+
+func toBookExport(book entity.Book) entity.BookExport {
+	return entity.BookExport{
+		ID:          book.ID,
+		SKU:         book.SKU,
+		WorkID:      book.WorkID,
+		ISBN:        book.ISBN,
+		Name:        book.Name,
+		Authors:     book.Authors,
+		Description: book.Description,
+		Category:    book.Category,
+		Language:    book.Language,
+		Format:      book.Format,
+		Price:       book.Price,
+		Stock:       book.Stock,
+		PublishedAt: book.PublishedAt,
+		SoldCount:   book.SoldCount,
+		Status:      book.Status,
+		Version:     book.Version,
+		CreatedAt:   book.CreatedAt,
+		UpdatedAt:   book.UpdatedAt,
+	}
+}
+
+type csvBookExportWriter struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVBookExportWriter(w io.Writer) *csvBookExportWriter {
+	return &csvBookExportWriter{writer: csv.NewWriter(w)}
+}
+
+func (c *csvBookExportWriter) Write(book entity.BookExport) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	publishedAt := ""
+	if book.PublishedAt != nil {
+		publishedAt = book.PublishedAt.Format(time.DateOnly)
+	}
+
+	return c.writer.Write([]string{
+		strconv.FormatInt(book.ID, 10),
+		book.SKU,
+		strconv.FormatInt(book.WorkID, 10),
+		book.ISBN,
+		book.Name,
+		book.Authors,
+		book.Description,
+		book.Category,
+		book.Language,
+		book.Format,
+		strconv.FormatInt(book.Price, 10),
+		strconv.FormatInt(book.Stock, 10),
+		publishedAt,
+		strconv.FormatInt(book.SoldCount, 10),
+		book.Status,
+		strconv.FormatInt(book.Version, 10),
+		book.CreatedAt.Format(time.RFC3339),
+		book.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// Flush also writes the header of an empty export, so consumers always get the columns.
+func (c *csvBookExportWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvBookExportWriter) writeHeader() error {
+	if c.wroteHeader {
+		return nil
+	}
+
+	c.wroteHeader = true
+	return c.writer.Write(bookExportColumns)
+}
+
+type jsonlBookExportWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLBookExportWriter(w io.Writer) *jsonlBookExportWriter {
+	buffer := bufio.NewWriter(w)
+	return &jsonlBookExportWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+func (j *jsonlBookExportWriter) Write(book entity.BookExport) error {
+	return j.encoder.Encode(book)
+}
+
+func (j *jsonlBookExportWriter) Flush() error {
+	return j.buffer.Flush()
+}
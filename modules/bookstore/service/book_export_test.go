@@ -0,0 +1,155 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
+)
+
+type ExportServiceTestSuite struct {
+	suite.Suite
+
+	repo *mock_service.MockExportRepository
+}
+
+func (s *ExportServiceTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_service.NewMockExportRepository(ctrl)
+}
+
+func TestExportService(t *testing.T) {
+	suite.Run(t, new(ExportServiceTestSuite))
+}
+
+func (s *ExportServiceTestSuite) TestExportBooks() {
+	ctx := context.Background()
+	svc := service.NewExportService(s.repo)
+	createdAt := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	publishedAt := time.Date(1965, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	book := entity.Book{
+		ID:          7,
+		WorkID:      5,
+		SKU:         "BK00000007",
+		ISBN:        "9780306406157",
+		Name:        "Dune",
+		Authors:     "Frank Herbert",
+		Description: "Spice, sand",
+		Category:    "science fiction",
+		Language:    "en",
+		Format:      "hardcover",
+		Price:       15000,
+		Stock:       4,
+		PublishedAt: &publishedAt,
+		SoldCount:   9,
+		Status:      entity.BookStatusActive,
+		Version:     3,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+
+	s.Run("export books with invalid format", func() {
+		var out bytes.Buffer
+		written, err := svc.ExportBooks(ctx, entity.ExportBooksParams{Format: "xml"}, &out)
+		s.Assert().Zero(written)
+		s.Assert().Equal(errorx.ErrInvalidParameter("Input is invalid"), err)
+		s.Assert().Empty(out.String())
+	})
+
+	s.Run("export books got repository error", func() {
+		s.repo.EXPECT().ExportBooks(ctx, entity.ExportBooksParams{Format: "csv", Limit: service.ExportBatchSize}).
+			Return(nil, errors.New("repository error")).Times(1)
+
+		var out bytes.Buffer
+		written, err := svc.ExportBooks(ctx, entity.ExportBooksParams{Format: "csv"}, &out)
+		s.Assert().Zero(written)
+		s.Assert().EqualError(err, "repository error")
+	})
+
+	s.Run("export books as csv", func() {
+		s.repo.EXPECT().ExportBooks(ctx, entity.ExportBooksParams{Format: "csv", Limit: service.ExportBatchSize}).
+			Return([]entity.Book{book}, nil).Times(1)
+
+		var out bytes.Buffer
+		written, err := svc.ExportBooks(ctx, entity.ExportBooksParams{Format: "csv"}, &out)
+		s.Assert().Nil(err)
+		s.Assert().Equal(int64(1), written)
+		s.Assert().Equal(strings.Join([]string{
+			"id,sku,work_id,isbn,name,authors,description,category,language,format,price,stock,published_at,sold_count,status,version,created_at,updated_at",
+			`7,BK00000007,5,9780306406157,Dune,Frank Herbert,"Spice, sand",science fiction,en,hardcover,15000,4,1965-08-01,9,active,3,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z`,
+			"",
+		}, "\n"), out.String())
+	})
+
+	s.Run("export books empty csv still has a header", func() {
+		s.repo.EXPECT().ExportBooks(ctx, entity.ExportBooksParams{Format: "csv", Limit: service.ExportBatchSize}).
+			Return([]entity.Book{}, nil).Times(1)
+
+		var out bytes.Buffer
+		written, err := svc.ExportBooks(ctx, entity.ExportBooksParams{Format: "csv"}, &out)
+		s.Assert().Nil(err)
+		s.Assert().Zero(written)
+		s.Assert().True(strings.HasPrefix(out.String(), "id,sku,work_id,isbn,name,"))
+	})
+
+	s.Run("export books as jsonl across batches", func() {
+		since := createdAt.Add(-time.Hour)
+		firstBatch := make([]entity.Book, service.ExportBatchSize)
+		for i := range firstBatch {
+			firstBatch[i] = entity.Book{ID: int64(i + 1)}
+		}
+
+		gomock.InOrder(
+			s.repo.EXPECT().ExportBooks(ctx, entity.ExportBooksParams{
+				Format:       "jsonl",
+				UpdatedSince: &since,
+				Limit:        service.ExportBatchSize,
+			}).Return(firstBatch, nil).Times(1),
+			s.repo.EXPECT().ExportBooks(ctx, entity.ExportBooksParams{
+				Format:       "jsonl",
+				UpdatedSince: &since,
+				AfterID:      service.ExportBatchSize,
+				Limit:        service.ExportBatchSize,
+			}).Return([]entity.Book{book}, nil).Times(1),
+		)
+
+		var out bytes.Buffer
+		written, err := svc.ExportBooks(ctx, entity.ExportBooksParams{Format: "jsonl", UpdatedSince: &since}, &out)
+		s.Assert().Nil(err)
+		s.Assert().Equal(int64(service.ExportBatchSize+1), written)
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		s.Require().Len(lines, service.ExportBatchSize+1)
+		s.Assert().JSONEq(`{
+			"id": 7,
+			"sku": "BK00000007",
+			"work_id": 5,
+			"isbn": "9780306406157",
+			"name": "Dune",
+			"authors": "Frank Herbert",
+			"description": "Spice, sand",
+			"category": "science fiction",
+			"language": "en",
+			"format": "hardcover",
+			"price": 15000,
+			"stock": 4,
+			"published_at": "1965-08-01T00:00:00Z",
+			"sold_count": 9,
+			"status": "active",
+			"version": 3,
+			"created_at": "2024-01-02T03:04:05Z",
+			"updated_at": "2024-01-02T03:04:05Z"
+		}`, lines[service.ExportBatchSize])
+	})
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/isbn"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+)
+
+const (
+	// ImportBatchSize is how many rows are buffered before they are copied into the staging table.
+	ImportBatchSize = 1000
+	// MaxImportErrors caps the row errors kept in a report, the failed count still covers every row.
+	MaxImportErrors = 1000
+)
+
+// bookImportColumns are the CSV header names an import file must contain, in any order.
+var bookImportColumns = []string{"title", "isbn", "authors", "price", "stock"}
+
+// bookImportFields maps BookImportRow fields back to the column names users see in their file.
+var bookImportFields = map[string]string{
+	"ISBN":        "isbn",
+	"Name":        "title",
+	"Authors":     "authors",
+	"Description": "description",
+	"Category":    "category",
+	"Language":    "language",
+	"Format":      "format",
+	"Price":       "price",
+	"Stock":       "stock",
+	"Status":      "status",
+}
+
+type ImportService struct {
+	repo      ImportRepository
+	validator *validator.Validate
+	txStarter repository.TxStarter
+}
+
+func NewImportService(repo ImportRepository, txStarter repository.TxStarter) *ImportService {
+	return &ImportService{
+		repo:      repo,
+		validator: validator.New(),
+		txStarter: txStarter,
+	}
+}
+
+// bookImportSource yields catalog rows one at a time until io.EOF. A *bookImportRowError only rejects the row it
+// belongs to, any other error aborts the whole import.
+type bookImportSource interface {
+	Next() (entity.BookImportRow, error)
+}
+
+type bookImportRowError struct {
+	line    int
+	isbn    string
+	message string
+}
+
+func (e *bookImportRowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.message)
+}
+
+// ImportBooksCSV upserts every valid row of a CSV file by ISBN. Invalid rows are listed in the report and skipped, the
+// rest are still imported. In dry-run mode the upsert runs to produce the counts but is rolled back.
+func (s *ImportService) ImportBooksCSV(ctx context.Context, r io.Reader, dryRun bool) (*entity.BookImportReport, error) {
+	src, err := newCSVBookSource(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importBooks(ctx, src, dryRun)
+}
+
+func (s *ImportService) importBooks(ctx context.Context, src bookImportSource, dryRun bool) (*entity.BookImportReport, error) {
+	batchID, err := newImportBatchID()
+	if err != nil {
+		return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	report := &entity.BookImportReport{DryRun: dryRun, Errors: []entity.BookImportError{}}
+	seen := make(map[string]int32)
+	batch := make([]entity.BookImportRow, 0, ImportBatchSize)
+	var deletes []string
+	var staged int64
+	var hasSubjects bool
+
+	for {
+		var row entity.BookImportRow
+		row, err = src.Next()
+		if errors.Is(err, io.EOF) {
+			err = nil
+			break
+		}
+
+		var rowErr *bookImportRowError
+		if errors.As(err, &rowErr) {
+			report.Rows++
+			addImportError(report, rowErr)
+			err = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		report.Rows++
+		if rowErr = s.validateImportRow(row, seen); rowErr != nil {
+			addImportError(report, rowErr)
+			continue
+		}
+		seen[row.ISBN] = row.Line
+
+		if row.Delete {
+			deletes = append(deletes, row.ISBN)
+			continue
+		}
+		hasSubjects = hasSubjects || len(row.BISACCodes) > 0 || len(row.ThemaCodes) > 0
+
+		batch = append(batch, row)
+		if len(batch) < ImportBatchSize {
+			continue
+		}
+
+		var copied int64
+		copied, err = s.repo.CopyBooksToStaging(ctx, tx, batchID, batch)
+		if err != nil {
+			return nil, err
+		}
+		staged += copied
+		batch = batch[:0]
+	}
+
+	if len(batch) > 0 {
+		var copied int64
+		copied, err = s.repo.CopyBooksToStaging(ctx, tx, batchID, batch)
+		if err != nil {
+			return nil, err
+		}
+		staged += copied
+	}
+
+	if staged > 0 {
+		var count *entity.BookUpsertCount
+		count, err = s.repo.UpsertBooksFromStaging(ctx, tx, batchID)
+		if err != nil {
+			return nil, err
+		}
+		report.Inserted = count.Inserted
+		report.Updated = count.Updated
+
+		if hasSubjects {
+			err = s.repo.LinkStagedBookCategories(ctx, tx, batchID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = s.repo.ClearBooksStaging(ctx, tx, batchID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(deletes) > 0 {
+		report.Discontinued, err = s.repo.DiscontinueBooksByISBN(ctx, tx, deletes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		err = tx.Rollback(ctx)
+	} else {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *ImportService) validateImportRow(row entity.BookImportRow, seen map[string]int32) *bookImportRowError {
+	var err error
+	if row.Delete {
+		// a withdrawal only carries the isbn
+		err = s.validator.StructPartial(row, "ISBN")
+	} else {
+		err = s.validator.Struct(row)
+	}
+	if err != nil {
+		message := "row is invalid"
+		var fieldErrs validator.ValidationErrors
+		if errors.As(err, &fieldErrs) {
+			message = fmt.Sprintf("%s is invalid", bookImportFields[fieldErrs[0].Field()])
+		}
+		return &bookImportRowError{line: int(row.Line), isbn: row.ISBN, message: message}
+	}
+
+	if line, exist := seen[row.ISBN]; exist {
+		return &bookImportRowError{
+			line:    int(row.Line),
+			isbn:    row.ISBN,
+			message: fmt.Sprintf("isbn duplicates line %d", line),
+		}
+	}
+
+	return nil
+}
+
+func addImportError(report *entity.BookImportReport, rowErr *bookImportRowError) {
+	report.Failed++
+	if len(report.Errors) < MaxImportErrors {
+		report.Errors = append(report.Errors, entity.BookImportError{
+			Line:    rowErr.line,
+			ISBN:    rowErr.isbn,
+			Message: rowErr.message,
+		})
+	}
+}
+
+func newImportBatchID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+type csvBookSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVBookSource(r io.Reader) (*csvBookSource, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errorx.ErrInvalidParameter("csv header cannot be read")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range bookImportColumns {
+		if _, exist := columns[name]; !exist {
+			return nil, errorx.ErrInvalidParameter(fmt.Sprintf("csv header is missing column %q", name))
+		}
+	}
+
+	return &csvBookSource{reader: reader, columns: columns}, nil
+}
+
+func (c *csvBookSource) Next() (entity.BookImportRow, error) {
+	record, err := c.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return entity.BookImportRow{}, &bookImportRowError{line: parseErr.Line, message: parseErr.Err.Error()}
+		}
+		return entity.BookImportRow{}, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	field := func(name string) string {
+		if i := c.columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	rawISBN := field("isbn")
+	normalized, err := isbn.Normalize(rawISBN)
+	if err != nil {
+		return entity.BookImportRow{}, &bookImportRowError{line: line, isbn: rawISBN, message: "isbn is invalid"}
+	}
+
+	price, err := strconv.ParseInt(field("price"), 10, 64)
+	if err != nil {
+		return entity.BookImportRow{}, &bookImportRowError{line: line, isbn: normalized, message: "price is invalid"}
+	}
+
+	stock, err := strconv.ParseInt(field("stock"), 10, 64)
+	if err != nil {
+		return entity.BookImportRow{}, &bookImportRowError{line: line, isbn: normalized, message: "stock is invalid"}
+	}
+
+	return entity.BookImportRow{
+		Line:    int32(line),
+		ISBN:    normalized,
+		Name:    field("title"),
+		Authors: field("authors"),
+		Price:   &price,
+		Stock:   &stock,
+	}, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/isbn"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/onix"
+)
+
+// onixFormats maps ONIX product form codes onto book formats, forms not listed keep the stored format.
+var onixFormats = map[string]string{
+	"BB": "hardcover",
+	"BC": "paperback",
+	"AJ": "audiobook",
+	"AN": "audiobook",
+	"EA": "ebook",
+	"EB": "ebook",
+	"ED": "ebook",
+}
+
+// onixDiscontinuedAvailability are the ProductAvailability codes meaning the product can no longer be supplied.
+var onixDiscontinuedAvailability = map[string]bool{
+	"40": true,
+	"41": true,
+	"43": true,
+	"46": true,
+	"51": true,
+}
+
+// onixDiscontinuedPublishing are the PublishingStatus codes used when a feed carries no availability.
+var onixDiscontinuedPublishing = map[string]bool{
+	"06": true,
+	"07": true,
+	"08": true,
+	"11": true,
+}
+
+// onixLanguages shortens the ISO 639-2/B codes used by ONIX to the two letter codes books are stored with.
+var onixLanguages = map[string]string{
+	"ara": "ar",
+	"chi": "zh",
+	"dut": "nl",
+	"eng": "en",
+	"fre": "fr",
+	"ger": "de",
+	"ind": "id",
+	"ita": "it",
+	"jpn": "ja",
+	"kor": "ko",
+	"may": "ms",
+	"por": "pt",
+	"rus": "ru",
+	"spa": "es",
+}
+
+// zeroDecimalCurrencies have no minor unit, their ONIX amounts are stored as they are.
+var zeroDecimalCurrencies = map[string]bool{
+	"CLP": true,
+	"ISK": true,
+	"JPY": true,
+	"KRW": true,
+	"VND": true,
+}
+
+// ImportBooksONIX applies the Product records of an ONIX 3.0 message. Records are upserted by ISBN, delete
+// notifications discontinue the book. Prices are read in currency, the first quoted price is used when it is empty.
+func (s *ImportService) ImportBooksONIX(ctx context.Context, r io.Reader, currency string, dryRun bool) (*entity.BookImportReport, error) {
+	src := &onixBookSource{
+		reader:   onix.NewReader(r),
+		currency: strings.ToUpper(strings.TrimSpace(currency)),
+	}
+
+	return s.importBooks(ctx, src, dryRun)
+}
+
+type onixBookSource struct {
+	reader   *onix.Reader
+	currency string
+}
+
+func (o *onixBookSource) Next() (entity.BookImportRow, error) {
+	product, err := o.reader.Next()
+	if errors.Is(err, io.EOF) {
+		return entity.BookImportRow{}, err
+	}
+	if err != nil {
+		return entity.BookImportRow{}, errorx.ErrInvalidParameter(fmt.Sprintf("onix message invalid: %s", err))
+	}
+
+	rawISBN := product.ISBN()
+	normalized, err := isbn.Normalize(rawISBN)
+	if err != nil {
+		if rawISBN == "" {
+			rawISBN = product.RecordReference
+		}
+		return entity.BookImportRow{}, &bookImportRowError{line: product.Line, isbn: rawISBN, message: "isbn is invalid"}
+	}
+
+	row := entity.BookImportRow{
+		Line: int32(product.Line),
+		ISBN: normalized,
+	}
+	if product.Deleted() {
+		row.Delete = true
+		return row, nil
+	}
+
+	row.Name = product.Title()
+	row.Authors = strings.Join(product.Authors(), ", ")
+	row.Description = product.Description()
+	row.Format = onixFormats[strings.TrimSpace(product.ProductForm)]
+
+	if language := product.Language(); language != "" {
+		row.Language = language
+		if short, exist := onixLanguages[language]; exist {
+			row.Language = short
+		}
+	}
+
+	if subject := product.MainSubject(); subject != nil {
+		row.Category = strings.TrimSpace(subject.HeadingText)
+	}
+	row.BISACCodes = product.SubjectCodes(onix.SubjectSchemeBISAC)
+	row.ThemaCodes = product.SubjectCodes(onix.SubjectSchemeThema)
+
+	if value, format := product.PublicationDate(); value != "" {
+		publishedAt, err := parseONIXDate(value, format)
+		if err != nil {
+			return entity.BookImportRow{}, &bookImportRowError{line: product.Line, isbn: normalized, message: "publication date is invalid"}
+		}
+		row.PublishedAt = &publishedAt
+	}
+
+	if onixDiscontinuedPublishing[strings.TrimSpace(product.PublishingStatus)] {
+		row.Status = entity.BookStatusDiscontinued
+	}
+
+	if supply := product.Supply(o.currency); supply != nil {
+		if availability := strings.TrimSpace(supply.Availability); availability != "" {
+			row.Status = entity.BookStatusActive
+			if onixDiscontinuedAvailability[availability] {
+				row.Status = entity.BookStatusDiscontinued
+			}
+		}
+
+		if price := supply.Price(o.currency); price != nil {
+			amount, err := parseONIXAmount(price.Amount, strings.ToUpper(strings.TrimSpace(price.Currency)))
+			if err != nil {
+				return entity.BookImportRow{}, &bookImportRowError{line: product.Line, isbn: normalized, message: "price is invalid"}
+			}
+			row.Price = &amount
+		}
+
+		if len(supply.Stocks) > 0 {
+			var stock int64
+			for _, s := range supply.Stocks {
+				onHand, err := strconv.ParseInt(strings.TrimSpace(s.OnHand), 10, 64)
+				if err != nil {
+					return entity.BookImportRow{}, &bookImportRowError{line: product.Line, isbn: normalized, message: "stock is invalid"}
+				}
+				stock += onHand
+			}
+			row.Stock = &stock
+		}
+	}
+
+	return row, nil
+}
+
+// parseONIXDate reads the day, month and year precisions of ONIX dateformat, YYYYMMDD being the default.
+func parseONIXDate(value, format string) (time.Time, error) {
+	switch format {
+	case "", "00":
+		return time.Parse("20060102", value)
+	case "01":
+		return time.Parse("200601", value)
+	case "05":
+		return time.Parse("2006", value)
+	default:
+		return time.Time{}, fmt.Errorf("dateformat %s not supported", format)
+	}
+}
+
+// parseONIXAmount converts a decimal PriceAmount into the smallest currency unit without going through floats.
+func parseONIXAmount(amount, currency string) (int64, error) {
+	decimals := 2
+	if zeroDecimalCurrencies[currency] {
+		decimals = 0
+	}
+
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if whole == "" || len(fraction) > decimals {
+		return 0, fmt.Errorf("amount %q has more than %d decimals", amount, decimals)
+	}
+
+	return strconv.ParseInt(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10, 64)
+}
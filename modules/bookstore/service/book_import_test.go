@@ -0,0 +1,258 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+	mock_repository "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/repository"
+	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
+)
+
+type ImportServiceTestSuite struct {
+	suite.Suite
+
+	repo   *mock_service.MockImportRepository
+	txFunc repository.TxStarter
+	tx     *mock_repository.MockTransactionable
+}
+
+func (s *ImportServiceTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_service.NewMockImportRepository(ctrl)
+	s.tx = mock_repository.NewMockTransactionable(ctrl)
+	s.txFunc = func(ctx context.Context) (pgx.Tx, error) {
+		return s.tx, nil
+	}
+}
+
+func TestImportService(t *testing.T) {
+	suite.Run(t, new(ImportServiceTestSuite))
+}
+
+func (s *ImportServiceTestSuite) TestImportBooksCSV() {
+	ctx := context.Background()
+	svc := service.NewImportService(s.repo, s.txFunc)
+
+	file := strings.Join([]string{
+		"Title,ISBN,Authors,Price,Stock",
+		"Dune,0-306-40615-2,Frank Herbert,15000,4",
+		"Emma,978-0-306-40615-8,Jane Austen,9000,1",
+		"Dune Again,9780306406157,Frank Herbert,15000,4",
+		",9781861972712,Nobody,100,1",
+		"Cheap,9780131103627,Someone,free,1",
+	}, "\n")
+
+	price, stock := int64(15000), int64(4)
+	stagedRows := []entity.BookImportRow{
+		{
+			Line:    2,
+			ISBN:    "9780306406157",
+			Name:    "Dune",
+			Authors: "Frank Herbert",
+			Price:   &price,
+			Stock:   &stock,
+		},
+	}
+
+	expectedErrors := []entity.BookImportError{
+		{Line: 3, ISBN: "978-0-306-40615-8", Message: "isbn is invalid"},
+		{Line: 4, ISBN: "9780306406157", Message: "isbn duplicates line 2"},
+		{Line: 5, ISBN: "9781861972712", Message: "title is invalid"},
+		{Line: 6, ISBN: "9780131103627", Message: "price is invalid"},
+	}
+
+	s.Run("import books with missing header column", func() {
+		result, err := svc.ImportBooksCSV(ctx, strings.NewReader("title,isbn,price\n"), false)
+		s.Assert().Nil(result)
+		s.Assert().Equal(errorx.ErrInvalidParameter(`csv header is missing column "authors"`), err)
+	})
+
+	s.Run("import books got copy error", func() {
+		s.repo.EXPECT().CopyBooksToStaging(ctx, s.tx, gomock.Any(), stagedRows).
+			Return(int64(0), errors.New("copy error")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.ImportBooksCSV(ctx, strings.NewReader(file), false)
+		s.Assert().Nil(result)
+		s.Assert().EqualError(err, "copy error")
+	})
+
+	s.Run("import books successful with row errors", func() {
+		s.repo.EXPECT().CopyBooksToStaging(ctx, s.tx, gomock.Any(), stagedRows).
+			Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().UpsertBooksFromStaging(ctx, s.tx, gomock.Any()).
+			Return(&entity.BookUpsertCount{Inserted: 1}, nil).Times(1)
+		s.repo.EXPECT().ClearBooksStaging(ctx, s.tx, gomock.Any()).
+			Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.ImportBooksCSV(ctx, strings.NewReader(file), false)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.BookImportReport{
+			Rows:     5,
+			Inserted: 1,
+			Failed:   4,
+			Errors:   expectedErrors,
+		}, result)
+	})
+
+	s.Run("import books dry run rolls back", func() {
+		s.repo.EXPECT().CopyBooksToStaging(ctx, s.tx, gomock.Any(), stagedRows).
+			Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().UpsertBooksFromStaging(ctx, s.tx, gomock.Any()).
+			Return(&entity.BookUpsertCount{Updated: 1}, nil).Times(1)
+		s.repo.EXPECT().ClearBooksStaging(ctx, s.tx, gomock.Any()).
+			Return(nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.ImportBooksCSV(ctx, strings.NewReader(file), true)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.BookImportReport{
+			DryRun:  true,
+			Rows:    5,
+			Updated: 1,
+			Failed:  4,
+			Errors:  expectedErrors,
+		}, result)
+	})
+
+	s.Run("import books without valid rows skips upsert", func() {
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.ImportBooksCSV(ctx, strings.NewReader("title,isbn,authors,price,stock\nx,123,y,1,1"), false)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.BookImportReport{
+			Rows:   1,
+			Failed: 1,
+			Errors: []entity.BookImportError{{Line: 2, ISBN: "123", Message: "isbn is invalid"}},
+		}, result)
+	})
+}
+
+func (s *ImportServiceTestSuite) TestImportBooksONIX() {
+	ctx := context.Background()
+	svc := service.NewImportService(s.repo, s.txFunc)
+
+	message := `<?xml version="1.0" encoding="UTF-8"?>
+<ONIXMessage release="3.0">
+  <Product>
+    <NotificationType>03</NotificationType>
+    <ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>978-0-306-40615-7</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <ProductForm>BB</ProductForm>
+      <TitleDetail>
+        <TitleType>01</TitleType>
+        <TitleElement><TitleElementLevel>01</TitleElementLevel><TitleText>Dune</TitleText></TitleElement>
+      </TitleDetail>
+      <Contributor><ContributorRole>A01</ContributorRole><PersonName>Frank Herbert</PersonName></Contributor>
+      <Language><LanguageRole>01</LanguageRole><LanguageCode>eng</LanguageCode></Language>
+      <Subject><MainSubject/><SubjectSchemeIdentifier>10</SubjectSchemeIdentifier><SubjectCode>fic028000</SubjectCode><SubjectHeadingText>Science Fiction</SubjectHeadingText></Subject>
+    </DescriptiveDetail>
+    <CollateralDetail>
+      <TextContent><TextType>03</TextType><Text>Spice &amp; sand.</Text></TextContent>
+    </CollateralDetail>
+    <PublishingDetail>
+      <PublishingDate><PublishingDateRole>01</PublishingDateRole><Date dateformat="01">196508</Date></PublishingDate>
+    </PublishingDetail>
+    <ProductSupply>
+      <SupplyDetail>
+        <ProductAvailability>21</ProductAvailability>
+        <Stock><OnHand>3</OnHand></Stock>
+        <Stock><OnHand>4</OnHand></Stock>
+        <Price><PriceAmount>24.99</PriceAmount><CurrencyCode>USD</CurrencyCode></Price>
+        <Price><PriceAmount>19.5</PriceAmount><CurrencyCode>GBP</CurrencyCode></Price>
+      </SupplyDetail>
+    </ProductSupply>
+  </Product>
+  <Product>
+    <NotificationType>03</NotificationType>
+    <RecordReference>broken-isbn</RecordReference>
+  </Product>
+  <Product>
+    <NotificationType>03</NotificationType>
+    <ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>9781861972712</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <TitleDetail>
+        <TitleType>01</TitleType>
+        <TitleElement><TitleElementLevel>01</TitleElementLevel><TitleText>Dated</TitleText></TitleElement>
+      </TitleDetail>
+    </DescriptiveDetail>
+    <PublishingDetail>
+      <PublishingDate><PublishingDateRole>01</PublishingDateRole><Date>2024-13-01</Date></PublishingDate>
+    </PublishingDetail>
+  </Product>
+  <Product>
+    <NotificationType>05</NotificationType>
+    <ProductIdentifier><ProductIDType>02</ProductIDType><IDValue>0131103628</IDValue></ProductIdentifier>
+  </Product>
+</ONIXMessage>`
+
+	price, stock := int64(1950), int64(7)
+	publishedAt := time.Date(1965, time.August, 1, 0, 0, 0, 0, time.UTC)
+	stagedRows := []entity.BookImportRow{
+		{
+			Line:        3,
+			ISBN:        "9780306406157",
+			Name:        "Dune",
+			Authors:     "Frank Herbert",
+			Description: "Spice & sand.",
+			Category:    "Science Fiction",
+			Language:    "en",
+			Format:      "hardcover",
+			Price:       &price,
+			Stock:       &stock,
+			PublishedAt: &publishedAt,
+			Status:      entity.BookStatusActive,
+			BISACCodes:  []string{"FIC028000"},
+		},
+	}
+
+	s.Run("import onix with an unreadable message", func() {
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.ImportBooksONIX(ctx, strings.NewReader(`<ONIXmessage/>`), "", false)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("import onix successful", func() {
+		s.repo.EXPECT().CopyBooksToStaging(ctx, s.tx, gomock.Any(), stagedRows).
+			Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().UpsertBooksFromStaging(ctx, s.tx, gomock.Any()).
+			Return(&entity.BookUpsertCount{Updated: 1}, nil).Times(1)
+		s.repo.EXPECT().LinkStagedBookCategories(ctx, s.tx, gomock.Any()).
+			Return(nil).Times(1)
+		s.repo.EXPECT().ClearBooksStaging(ctx, s.tx, gomock.Any()).
+			Return(nil).Times(1)
+		s.repo.EXPECT().DiscontinueBooksByISBN(ctx, s.tx, []string{"9780131103627"}).
+			Return(int64(1), nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.ImportBooksONIX(ctx, strings.NewReader(message), "gbp", false)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.BookImportReport{
+			Rows:         4,
+			Updated:      1,
+			Discontinued: 1,
+			Failed:       2,
+			Errors: []entity.BookImportError{
+				{Line: 32, ISBN: "broken-isbn", Message: "isbn is invalid"},
+				{Line: 36, ISBN: "9781861972712", Message: "publication date is invalid"},
+			},
+		}, result)
+	})
+}
@@ -3,12 +3,16 @@ package service_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/raymondwongso/gogox/errorx"
 	"github.com/stretchr/testify/suite"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/pagination"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
 	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
 )
@@ -36,6 +40,10 @@ func (s *BookServiceTestSuite) TestGetBooks() {
 		Limit:  10,
 		Offset: 0,
 	}
+	repoParams := entity.GetBooksParams{
+		Limit:  11,
+		Offset: 0,
+	}
 
 	s.Run("get books validation error", func() {
 		svcParams := entity.GetBooksParams{
@@ -50,8 +58,67 @@ func (s *BookServiceTestSuite) TestGetBooks() {
 		s.Assert().EqualError(goxErr, "Input is invalid")
 	})
 
+	s.Run("get books search query too long", func() {
+		svcParams := entity.GetBooksParams{
+			Query: strings.Repeat("debugging ", 30),
+			Limit: 10,
+		}
+
+		result, err := svc.GetBooks(ctx, svcParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("get books unknown sort field", func() {
+		svcParams := entity.GetBooksParams{
+			Sort:  "isbn",
+			Limit: 10,
+		}
+
+		result, err := svc.GetBooks(ctx, svcParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("get books min price greater than max price", func() {
+		minPrice, maxPrice := int64(5000), int64(1000)
+		svcParams := entity.GetBooksParams{
+			MinPrice: &minPrice,
+			MaxPrice: &maxPrice,
+			Limit:    10,
+		}
+
+		result, err := svc.GetBooks(ctx, svcParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "min_price cannot be greater than max_price")
+	})
+
+	s.Run("get books search defaults to relevance sort", func() {
+		s.repo.EXPECT().GetBooks(ctx, entity.GetBooksParams{
+			Query: "debugging",
+			Sort:  entity.BookSortRelevance,
+			Limit: 11,
+		}).Return([]entity.Book{}, nil).Times(1)
+
+		result, err := svc.GetBooks(ctx, entity.GetBooksParams{
+			Query: "debugging",
+			Limit: 10,
+		})
+		s.Assert().Nil(err)
+		s.Assert().NotNil(result)
+	})
+
 	s.Run("get books repo error", func() {
-		s.repo.EXPECT().GetBooks(ctx, svcParams).
+		s.repo.EXPECT().GetBooks(ctx, repoParams).
 			Return(nil, errors.New("repo error")).Times(1)
 
 		result, err := svc.GetBooks(ctx, svcParams)
@@ -60,11 +127,362 @@ func (s *BookServiceTestSuite) TestGetBooks() {
 	})
 
 	s.Run("get books success", func() {
-		s.repo.EXPECT().GetBooks(ctx, svcParams).
+		s.repo.EXPECT().GetBooks(ctx, repoParams).
 			Return([]entity.Book{}, nil).Times(1)
 
 		result, err := svc.GetBooks(ctx, svcParams)
 		s.Assert().Nil(err)
-		s.Assert().NotNil(result)
+		s.Assert().Equal(&entity.BookList{Data: []entity.Book{}}, result)
+	})
+
+	s.Run("get books cursor cannot be combined with offset", func() {
+		result, err := svc.GetBooks(ctx, entity.GetBooksParams{
+			Cursor: pagination.Cursor{ID: 3}.Encode(),
+			Limit:  10,
+			Offset: 20,
+		})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "cursor cannot be combined with offset")
+	})
+
+	s.Run("get books cursor from another sort order is rejected", func() {
+		result, err := svc.GetBooks(ctx, entity.GetBooksParams{
+			Sort:   entity.BookSortPriceAsc,
+			Cursor: pagination.Cursor{Sort: entity.BookSortTitle, Key: "A", ID: 3}.Encode(),
+			Limit:  10,
+		})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "cursor invalid")
+	})
+
+	s.Run("get books next cursor resumes after the last row", func() {
+		published := time.Date(2021, 8, 15, 0, 0, 0, 0, time.UTC)
+		page := []entity.Book{
+			{ID: 2, Name: "B", PublishedAt: &published},
+			{ID: 5, Name: "A", PublishedAt: &published},
+			{ID: 9, Name: "C"},
+		}
+
+		s.repo.EXPECT().GetBooks(ctx, entity.GetBooksParams{
+			Sort:  entity.BookSortPublicationDate,
+			Limit: 3,
+		}).Return(page, nil).Times(1)
+
+		result, err := svc.GetBooks(ctx, entity.GetBooksParams{
+			Sort:  entity.BookSortPublicationDate,
+			Limit: 2,
+		})
+		s.Require().Nil(err)
+		s.Assert().Equal(page[:2], result.Data)
+		s.Assert().True(result.HasMore)
+		s.Require().NotEmpty(result.NextCursor)
+
+		s.repo.EXPECT().GetBooks(ctx, entity.GetBooksParams{
+			Sort:   entity.BookSortPublicationDate,
+			Cursor: result.NextCursor,
+			After:  &entity.BookCursor{ID: 5, PublishedAt: &published},
+			Limit:  3,
+		}).Return(page[2:], nil).Times(1)
+
+		result, err = svc.GetBooks(ctx, entity.GetBooksParams{
+			Sort:   entity.BookSortPublicationDate,
+			Cursor: result.NextCursor,
+			Limit:  2,
+		})
+		s.Require().Nil(err)
+		s.Assert().Equal(page[2:], result.Data)
+		s.Assert().False(result.HasMore)
+		s.Assert().Empty(result.NextCursor)
+	})
+}
+
+func (s *BookServiceTestSuite) TestGetBookFacets() {
+	ctx := context.Background()
+	svc := service.NewBookService(s.repo)
+
+	svcParams := entity.GetBooksParams{
+		Category: "Art",
+		Limit:    10,
+		Offset:   0,
+	}
+
+	s.Run("get book facets validation error", func() {
+		result, err := svc.GetBookFacets(ctx, entity.GetBooksParams{Format: "scroll", Limit: 10})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("get book facets success", func() {
+		facets := &entity.BookFacets{
+			Categories: []entity.FacetCount{{Value: "Art", Count: 2}},
+		}
+		s.repo.EXPECT().GetBookFacets(ctx, svcParams).
+			Return(facets, nil).Times(1)
+
+		result, err := svc.GetBookFacets(ctx, svcParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal(facets, result)
+	})
+}
+
+func (s *BookServiceTestSuite) TestCountBooks() {
+	ctx := context.Background()
+	svc := service.NewBookService(s.repo)
+
+	s.Run("count books validation error", func() {
+		result, err := svc.CountBooks(ctx, entity.GetBooksParams{Format: "scroll", Limit: 10}, false)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("count books exact", func() {
+		params := entity.GetBooksParams{Limit: 10}
+		s.repo.EXPECT().CountBooks(ctx, params).
+			Return(int64(42), nil).Times(1)
+
+		result, err := svc.CountBooks(ctx, params, false)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.TotalCount{Count: 42}, result)
+	})
+
+	s.Run("count books estimate ignored when filtered", func() {
+		params := entity.GetBooksParams{Category: "Art", Limit: 10}
+		s.repo.EXPECT().CountBooks(ctx, params).
+			Return(int64(3), nil).Times(1)
+
+		result, err := svc.CountBooks(ctx, params, true)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.TotalCount{Count: 3}, result)
+	})
+
+	s.Run("count books estimate on large catalog", func() {
+		s.repo.EXPECT().EstimateBooksCount(ctx).
+			Return(int64(250000), nil).Times(1)
+
+		result, err := svc.CountBooks(ctx, entity.GetBooksParams{Limit: 10}, true)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.TotalCount{Count: 250000, Estimated: true}, result)
+	})
+
+	s.Run("count books estimate on small catalog falls back to exact count", func() {
+		params := entity.GetBooksParams{Limit: 10}
+		s.repo.EXPECT().EstimateBooksCount(ctx).
+			Return(int64(120), nil).Times(1)
+		s.repo.EXPECT().CountBooks(ctx, params).
+			Return(int64(118), nil).Times(1)
+
+		result, err := svc.CountBooks(ctx, params, true)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.TotalCount{Count: 118}, result)
+	})
+
+	s.Run("count books repo error", func() {
+		params := entity.GetBooksParams{Limit: 10}
+		s.repo.EXPECT().CountBooks(ctx, params).
+			Return(int64(0), errors.New("repo error")).Times(1)
+
+		result, err := svc.CountBooks(ctx, params, false)
+		s.Assert().Nil(result)
+		s.Assert().Contains(err.Error(), "repo error")
+	})
+}
+
+func (s *BookServiceTestSuite) TestSuggestBooks() {
+	ctx := context.Background()
+	svc := service.NewBookService(s.repo)
+
+	svcParams := entity.SuggestBooksParams{
+		Prefix: "debuging",
+		Limit:  5,
+	}
+
+	suggestions := []entity.BookSuggestion{
+		{
+			ID:   1,
+			Name: "Chicken Soup of Debugging",
+		},
+	}
+
+	s.Run("suggest books validation error", func() {
+		result, err := svc.SuggestBooks(ctx, entity.SuggestBooksParams{Prefix: " d ", Limit: 5})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("suggest books repo error is not cached", func() {
+		s.repo.EXPECT().SuggestBooks(ctx, svcParams).
+			Return(nil, errors.New("repo error")).Times(1)
+
+		result, err := svc.SuggestBooks(ctx, svcParams)
+		s.Assert().Nil(result)
+		s.Assert().Contains(err.Error(), "repo error")
+	})
+
+	s.Run("suggest books is served from cache for the same normalized prefix", func() {
+		s.repo.EXPECT().SuggestBooks(ctx, svcParams).
+			Return(suggestions, nil).Times(1)
+
+		result, err := svc.SuggestBooks(ctx, svcParams)
+		s.Assert().Nil(err)
+		s.Assert().Equal(suggestions, result)
+
+		result, err = svc.SuggestBooks(ctx, entity.SuggestBooksParams{Prefix: "  DEBUGING ", Limit: 5})
+		s.Assert().Nil(err)
+		s.Assert().Equal(suggestions, result)
+	})
+}
+
+func (s *BookServiceTestSuite) TestCreateBook() {
+	ctx := context.Background()
+	svc := service.NewBookService(s.repo)
+
+	s.Run("create book validation error", func() {
+		result, err := svc.CreateBook(ctx, entity.CreateBookParams{Name: " ", Language: "en", Format: "paperback"})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("create book normalizes input", func() {
+		s.repo.EXPECT().CreateBook(ctx, entity.CreateBookParams{
+			Name:     "Refactoring",
+			Language: "en",
+			Format:   "hardcover",
+			Price:    4200,
+			Status:   entity.BookStatusActive,
+		}).Return(&entity.Book{ID: 7, Name: "Refactoring", Version: 1}, nil).Times(1)
+
+		result, err := svc.CreateBook(ctx, entity.CreateBookParams{
+			Name:     " Refactoring ",
+			Language: "EN",
+			Format:   "Hardcover",
+			Price:    4200,
+		})
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Book{ID: 7, Name: "Refactoring", Version: 1}, result)
+	})
+}
+
+func (s *BookServiceTestSuite) TestUpdateBook() {
+	ctx := context.Background()
+	svc := service.NewBookService(s.repo)
+	price := int64(-1)
+
+	s.Run("update book validation error", func() {
+		result, err := svc.UpdateBook(ctx, entity.UpdateBookParams{ID: 7, Version: 2, Price: &price})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("update book without changes", func() {
+		result, err := svc.UpdateBook(ctx, entity.UpdateBookParams{ID: 7, Version: 2})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "nothing to update")
+	})
+
+	s.Run("update book stale version", func() {
+		format := "ebook"
+		s.repo.EXPECT().UpdateBook(ctx, entity.UpdateBookParams{ID: 7, Version: 2, Format: &format}).
+			Return(nil, customerror.ErrPreconditionFailed("book has been modified")).Times(1)
+
+		upper := "EBOOK"
+		result, err := svc.UpdateBook(ctx, entity.UpdateBookParams{ID: 7, Version: 2, Format: &upper})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodePreconditionFailed, goxErr.Code)
+	})
+
+	s.Run("update book success", func() {
+		stock := int64(3)
+		params := entity.UpdateBookParams{ID: 7, Version: 2, Stock: &stock}
+		s.repo.EXPECT().UpdateBook(ctx, params).
+			Return(&entity.Book{ID: 7, Stock: 3, Version: 3}, nil).Times(1)
+
+		result, err := svc.UpdateBook(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Book{ID: 7, Stock: 3, Version: 3}, result)
+	})
+}
+
+func (s *BookServiceTestSuite) TestDeleteBook() {
+	ctx := context.Background()
+	svc := service.NewBookService(s.repo)
+
+	s.Run("delete book validation error", func() {
+		err := svc.DeleteBook(ctx, entity.DeleteBookParams{})
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("delete book success", func() {
+		s.repo.EXPECT().DeleteBook(ctx, entity.DeleteBookParams{ID: 7, Version: 3}).
+			Return(nil).Times(1)
+
+		err := svc.DeleteBook(ctx, entity.DeleteBookParams{ID: 7, Version: 3})
+		s.Assert().Nil(err)
+	})
+}
+
+func (s *BookServiceTestSuite) TestGetWork() {
+	ctx := context.Background()
+	svc := service.NewBookService(s.repo)
+
+	s.Run("invalid id", func() {
+		result, err := svc.GetWork(ctx, 0)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("work not found", func() {
+		s.repo.EXPECT().FindWork(ctx, int64(9)).
+			Return(nil, errorx.ErrNotFound("work cannot be found")).Times(1)
+
+		result, err := svc.GetWork(ctx, 9)
+		s.Assert().Nil(result)
+		s.Assert().Error(err)
+	})
+
+	s.Run("work with its editions", func() {
+		editions := []entity.Book{
+			{ID: 4, WorkID: 3, SKU: "BK00000004", Name: "Dune", Format: "ebook"},
+			{ID: 3, WorkID: 3, SKU: "BK00000003", Name: "Dune", Format: "hardcover"},
+		}
+		s.repo.EXPECT().FindWork(ctx, int64(3)).
+			Return(&entity.Work{ID: 3, Title: "Dune", Authors: "Frank Herbert"}, nil).Times(1)
+		s.repo.EXPECT().GetWorkEditions(ctx, int64(3)).Return(editions, nil).Times(1)
+
+		result, err := svc.GetWork(ctx, 3)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Work{ID: 3, Title: "Dune", Authors: "Frank Herbert", Editions: editions}, result)
 	})
 }
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+)
+
+// CartService keeps the carts of users and guests. A cart holds the books a customer intends to order, checking it
+// out places the order through the OrderService, so the cart is bound by the same OrderLimits.
+type CartService struct {
+	repo      CartRepository
+	orders    *OrderService
+	validator *validator.Validate
+	txStarter repository.TxStarter
+}
+
+func NewCartService(repo CartRepository, orders *OrderService, txStarter repository.TxStarter) *CartService {
+	return &CartService{
+		repo:      repo,
+		orders:    orders,
+		validator: validator.New(),
+		txStarter: txStarter,
+	}
+}
+
+// GetCart returns the cart of the owner with the current price and stock of its books. A guest without a cart gets an
+// empty one, it is only stored once a book is put in it.
+func (s *CartService) GetCart(ctx context.Context, owner entity.CartOwner) (*entity.Cart, error) {
+	var err error
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var cart *entity.Cart
+	cart, err = s.cart(ctx, tx, owner, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if cart != nil {
+		cart.Items, err = s.repo.GetCartItems(ctx, tx, cart.ID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		cart = &entity.Cart{Items: []entity.CartItem{}}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewCart(cart)
+	return cart, nil
+}
+
+// SetCartItem puts the book in the cart at its current price, replacing the amount already there.
+func (s *CartService) SetCartItem(ctx context.Context, params entity.SetCartItemParams) (*entity.Cart, error) {
+	var err error
+	if err = s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	limits := s.orders.limits
+	if params.Amount > limits.MaxLineQuantity {
+		return nil, customerror.ErrUnprocessableEntity(
+			fmt.Sprintf("a cart cannot have more than %d copies of book %s", limits.MaxLineQuantity, params.SKU))
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var book *entity.Book
+	book, err = s.repo.FindBookBySKU(ctx, tx, params.SKU)
+	if err != nil {
+		return nil, err
+	}
+
+	switch book.Status {
+	case entity.BookStatusHidden:
+		err = errorx.ErrNotFound("book cannot be found")
+		return nil, err
+	case entity.BookStatusDiscontinued:
+		err = customerror.ErrUnprocessableEntity(fmt.Sprintf("book %s has been discontinued", book.SKU))
+		return nil, err
+	}
+
+	var cart *entity.Cart
+	cart, err = s.cart(ctx, tx, params.Owner, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []entity.CartItem
+	items, err = s.repo.GetCartItems(ctx, tx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, total := 0, params.Amount
+	for _, item := range items {
+		if item.SKU != book.SKU {
+			lines++
+			total += item.Amount
+		}
+	}
+	if lines >= limits.MaxLines {
+		err = customerror.ErrUnprocessableEntity(
+			fmt.Sprintf("a cart cannot have more than %d different books", limits.MaxLines))
+		return nil, err
+	}
+	if total > limits.MaxOrderQuantity {
+		err = customerror.ErrUnprocessableEntity(
+			fmt.Sprintf("a cart cannot have more than %d books in total", limits.MaxOrderQuantity))
+		return nil, err
+	}
+
+	err = s.repo.SetCartItem(ctx, tx, cart.ID, entity.CartItem{
+		SKU:    book.SKU,
+		Amount: params.Amount,
+		Price:  book.Price,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cart.Items, err = s.repo.GetCartItems(ctx, tx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewCart(cart)
+	return cart, nil
+}
+
+// RemoveCartItem takes the book out of the cart.
+func (s *CartService) RemoveCartItem(ctx context.Context, params entity.RemoveCartItemParams) (*entity.Cart, error) {
+	var err error
+	if err = s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var cart *entity.Cart
+	cart, err = s.cart(ctx, tx, params.Owner, false)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		err = errorx.ErrNotFound("cart item cannot be found")
+		return nil, err
+	}
+
+	var deleted int64
+	deleted, err = s.repo.DeleteCartItem(ctx, tx, cart.ID, params.SKU)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		err = errorx.ErrNotFound("cart item cannot be found")
+		return nil, err
+	}
+
+	cart.Items, err = s.repo.GetCartItems(ctx, tx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewCart(cart)
+	return cart, nil
+}
+
+// ClearCart takes all books out of the cart.
+func (s *CartService) ClearCart(ctx context.Context, owner entity.CartOwner) error {
+	var err error
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var cart *entity.Cart
+	cart, err = s.cart(ctx, tx, owner, false)
+	if err != nil {
+		return err
+	}
+
+	if cart != nil {
+		err = s.repo.ClearCart(ctx, tx, cart.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Checkout places an order for the books in the cart of the user and empties the cart, both in one transaction. Prices
+// must not have changed since the books were put in the cart, the customer confirms a new price by setting the item
+// again.
+func (s *CartService) Checkout(ctx context.Context, owner entity.CartOwner) (*entity.Order, error) {
+	var err error
+	if owner.UserID <= 0 {
+		return nil, errorx.ErrUnauthorized("Unauthorized")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var cart *entity.Cart
+	cart, err = s.cart(ctx, tx, owner, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []entity.CartItem
+	items, err = s.repo.GetCartItems(ctx, tx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		err = customerror.ErrUnprocessableEntity("cart is empty")
+		return nil, err
+	}
+
+	params := entity.CreateOrderParams{
+		UserID: owner.UserID,
+		Items:  make([]entity.CreateOrderItemParams, 0, len(items)),
+	}
+	for _, item := range items {
+		if item.Price != item.AddedPrice {
+			err = customerror.ErrUnprocessableEntity(
+				fmt.Sprintf("price of book %s has changed from %d to %d", item.SKU, item.AddedPrice, item.Price))
+			return nil, err
+		}
+
+		params.Items = append(params.Items, entity.CreateOrderItemParams{
+			SKU:    item.SKU,
+			Amount: item.Amount,
+		})
+	}
+
+	var order *entity.Order
+	order, err = s.orders.createOrder(ctx, tx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.ClearCart(ctx, tx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// cart returns the cart of the owner. A signed in user always has a cart, the guest cart of the token the user sent is
+// merged into it. A guest cart is created with a new token only when create is set, otherwise a guest without a cart
+// gets nil.
+func (s *CartService) cart(ctx context.Context, tx pgx.Tx, owner entity.CartOwner, create bool) (*entity.Cart, error) {
+	var guest *entity.Cart
+	if owner.Token != "" {
+		var err error
+		guest, err = s.repo.FindGuestCart(ctx, tx, owner.Token)
+		if err != nil && !customerror.IsErrNotFound(err) {
+			return nil, err
+		}
+	}
+
+	if owner.UserID > 0 {
+		cart, err := s.repo.GetUserCart(ctx, tx, owner.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if guest != nil {
+			if err = s.mergeCart(ctx, tx, guest, cart); err != nil {
+				return nil, err
+			}
+		}
+
+		return cart, nil
+	}
+
+	if guest != nil || !create {
+		return guest, nil
+	}
+
+	// the token is all a guest needs to reach the cart, so it must not be guessable
+	token, err := newCartToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateGuestCart(ctx, tx, token)
+}
+
+// mergeCart moves the items of the guest cart into the cart of the user, adding up the amounts of books in both. The
+// merged cart stays within what an order allows: amounts are cut down to the limits, and books of the guest cart that
+// do not fit anymore are left out, the oldest ones are kept.
+func (s *CartService) mergeCart(ctx context.Context, tx pgx.Tx, guest, cart *entity.Cart) error {
+	guestItems, err := s.repo.GetCartItems(ctx, tx, guest.ID)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.repo.GetCartItems(ctx, tx, cart.ID)
+	if err != nil {
+		return err
+	}
+
+	limits := s.orders.limits
+	amounts := make(map[string]int64, len(items))
+	var total int64
+	for _, item := range items {
+		amounts[item.SKU] = item.Amount
+		total += item.Amount
+	}
+
+	var merged []entity.CartItem
+	for _, item := range guestItems {
+		amount, exist := amounts[item.SKU]
+		if !exist && len(amounts) >= limits.MaxLines {
+			continue
+		}
+
+		added := min(item.Amount, limits.MaxLineQuantity-amount, limits.MaxOrderQuantity-total)
+		if added <= 0 {
+			continue
+		}
+
+		amounts[item.SKU] = amount + added
+		total += added
+		merged = append(merged, entity.CartItem{SKU: item.SKU, Amount: amount + added, Price: item.AddedPrice})
+	}
+
+	return s.repo.MergeCart(ctx, tx, guest.ID, cart.ID, merged)
+}
+
+// reviewCart compares the items with the current state of their books and adds up the total at current prices.
+func reviewCart(cart *entity.Cart) {
+	cart.Total = 0
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		item.Issues = nil
+
+		switch {
+		case item.Book != nil && item.Book.Status == entity.BookStatusDiscontinued:
+			item.Issues = append(item.Issues, entity.CartIssueDiscontinued)
+		case item.Stock <= 0:
+			item.Issues = append(item.Issues, entity.CartIssueOutOfStock)
+		case item.Stock < item.Amount:
+			item.Issues = append(item.Issues, entity.CartIssueInsufficientStock)
+		}
+
+		if item.Price != item.AddedPrice {
+			item.Issues = append(item.Issues, entity.CartIssuePriceChanged)
+		}
+
+		cart.Total += item.Price * item.Amount
+	}
+}
+
+func newCartToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
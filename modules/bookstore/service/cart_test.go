@@ -0,0 +1,471 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+	mock_repository "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/repository"
+	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
+)
+
+type CartServiceTestSuite struct {
+	suite.Suite
+
+	repo      *mock_service.MockCartRepository
+	orderRepo *mock_service.MockOrderRepository
+	payments  *mock_service.MockPaymentProvider
+	txFunc    repository.TxStarter
+	tx        *mock_repository.MockTransactionable
+}
+
+func (s *CartServiceTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_service.NewMockCartRepository(ctrl)
+	s.orderRepo = mock_service.NewMockOrderRepository(ctrl)
+	s.payments = mock_service.NewMockPaymentProvider(ctrl)
+	s.tx = mock_repository.NewMockTransactionable(ctrl)
+	s.txFunc = func(ctx context.Context) (pgx.Tx, error) {
+		return s.tx, nil
+	}
+}
+
+func TestCartService(t *testing.T) {
+	suite.Run(t, new(CartServiceTestSuite))
+}
+
+func (s *CartServiceTestSuite) newService(limits service.OrderLimits) *service.CartService {
+	orders := service.NewOrderService(s.orderRepo, s.txFunc, s.payments, limits)
+	return service.NewCartService(s.repo, orders, s.txFunc)
+}
+
+func (s *CartServiceTestSuite) TestGetCart() {
+	ctx := context.Background()
+	svc := s.newService(service.DefaultOrderLimits)
+	dune := &entity.BookSummary{ID: 99, Name: "Dune", Status: entity.BookStatusActive}
+
+	s.Run("guest without cart", func() {
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.GetCart(ctx, entity.CartOwner{})
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Cart{Items: []entity.CartItem{}}, result)
+	})
+
+	s.Run("guest token of a removed cart", func() {
+		s.repo.EXPECT().FindGuestCart(ctx, s.tx, "gone").
+			Return(nil, errorx.ErrNotFound("cart cannot be found")).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.GetCart(ctx, entity.CartOwner{Token: "gone"})
+		s.Assert().Nil(err)
+		s.Assert().Empty(result.Items)
+	})
+
+	s.Run("items are revalidated", func() {
+		s.repo.EXPECT().FindGuestCart(ctx, s.tx, "guest-token").
+			Return(&entity.Cart{ID: 8, Token: "guest-token"}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(8)).
+			Return([]entity.CartItem{
+				{SKU: "BK00000099", Book: dune, Amount: 2, Price: 9500, AddedPrice: 9000, Stock: 10},
+				{SKU: "BK00000100", Book: dune, Amount: 3, Price: 1000, AddedPrice: 1000, Stock: 1},
+				{SKU: "BK00000101", Book: dune, Amount: 1, Price: 2000, AddedPrice: 2000, Stock: 0},
+				{SKU: "BK00000102", Book: &entity.BookSummary{ID: 102, Status: entity.BookStatusDiscontinued},
+					Amount: 1, Price: 500, AddedPrice: 500, Stock: 5},
+			}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.GetCart(ctx, entity.CartOwner{Token: "guest-token"})
+		s.Require().Nil(err)
+		s.Assert().Equal("guest-token", result.Token)
+		s.Assert().Equal(int64(2*9500+3*1000+2000+500), result.Total)
+		s.Assert().Equal([]string{entity.CartIssuePriceChanged}, result.Items[0].Issues)
+		s.Assert().Equal([]string{entity.CartIssueInsufficientStock}, result.Items[1].Issues)
+		s.Assert().Equal([]string{entity.CartIssueOutOfStock}, result.Items[2].Issues)
+		s.Assert().Equal([]string{entity.CartIssueDiscontinued}, result.Items[3].Issues)
+	})
+
+	s.Run("guest cart is merged on login", func() {
+		s.repo.EXPECT().FindGuestCart(ctx, s.tx, "guest-token").
+			Return(&entity.Cart{ID: 8, Token: "guest-token"}, nil).Times(1)
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(8)).
+			Return([]entity.CartItem{{SKU: "BK00000099", Book: dune, Amount: 1, Price: 9000, AddedPrice: 9000, Stock: 10}}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).Return([]entity.CartItem{}, nil).Times(1)
+		s.repo.EXPECT().MergeCart(ctx, s.tx, int64(8), int64(7), []entity.CartItem{{SKU: "BK00000099", Amount: 1, Price: 9000}}).
+			Return(nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{{SKU: "BK00000099", Book: dune, Amount: 1, Price: 9000, AddedPrice: 9000, Stock: 10}}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.GetCart(ctx, entity.CartOwner{UserID: 123, Token: "guest-token"})
+		s.Require().Nil(err)
+		s.Assert().Empty(result.Token)
+		s.Assert().Equal(int64(9000), result.Total)
+		s.Assert().Nil(result.Items[0].Issues)
+	})
+
+	s.Run("merge got repo error", func() {
+		s.repo.EXPECT().FindGuestCart(ctx, s.tx, "guest-token").
+			Return(&entity.Cart{ID: 8, Token: "guest-token"}, nil).Times(1)
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(8)).
+			Return([]entity.CartItem{{SKU: "BK00000099", Amount: 1, AddedPrice: 9000}}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).Return([]entity.CartItem{}, nil).Times(1)
+		s.repo.EXPECT().MergeCart(ctx, s.tx, int64(8), int64(7), []entity.CartItem{{SKU: "BK00000099", Amount: 1, Price: 9000}}).
+			Return(errors.New("repo error")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.GetCart(ctx, entity.CartOwner{UserID: 123, Token: "guest-token"})
+		s.Assert().Nil(result)
+		s.Assert().EqualError(err, "repo error")
+	})
+
+	s.Run("get guest items got repo error", func() {
+		s.repo.EXPECT().FindGuestCart(ctx, s.tx, "guest-token").
+			Return(&entity.Cart{ID: 8, Token: "guest-token"}, nil).Times(1)
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(8)).Return(nil, errors.New("repo error")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.GetCart(ctx, entity.CartOwner{UserID: 123, Token: "guest-token"})
+		s.Assert().Nil(result)
+		s.Assert().EqualError(err, "repo error")
+	})
+
+	s.Run("merge keeps the cart within the order limits", func() {
+		limited := s.newService(service.OrderLimits{MaxLineQuantity: 5, MaxOrderQuantity: 6, MaxLines: 2})
+
+		s.repo.EXPECT().FindGuestCart(ctx, s.tx, "guest-token").
+			Return(&entity.Cart{ID: 8, Token: "guest-token"}, nil).Times(1)
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(8)).
+			Return([]entity.CartItem{
+				{SKU: "BK00000099", Amount: 4, Price: 9000, AddedPrice: 8500},
+				{SKU: "BK00000102", Amount: 3, Price: 2500, AddedPrice: 2500},
+				{SKU: "BK00000103", Amount: 1, Price: 1000, AddedPrice: 1000},
+			}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{{SKU: "BK00000099", Amount: 3, Price: 9000, AddedPrice: 9000}}, nil).Times(1)
+		// dune is cut down to the line limit, the next book to what is left of the total and the last one has no line
+		s.repo.EXPECT().MergeCart(ctx, s.tx, int64(8), int64(7), []entity.CartItem{
+			{SKU: "BK00000099", Amount: 5, Price: 8500},
+			{SKU: "BK00000102", Amount: 1, Price: 2500},
+		}).Return(nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{
+				{SKU: "BK00000099", Book: dune, Amount: 5, Price: 9000, AddedPrice: 9000, Stock: 10},
+				{SKU: "BK00000102", Amount: 1, Price: 2500, AddedPrice: 2500, Stock: 10},
+			}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := limited.GetCart(ctx, entity.CartOwner{UserID: 123, Token: "guest-token"})
+		s.Require().Nil(err)
+		s.Assert().Len(result.Items, 2)
+		s.Assert().Equal(int64(5), result.Items[0].Amount)
+	})
+}
+
+func (s *CartServiceTestSuite) TestSetCartItem() {
+	ctx := context.Background()
+	svc := s.newService(service.OrderLimits{MaxLineQuantity: 5, MaxOrderQuantity: 6, MaxLines: 2})
+	book := &entity.Book{ID: 99, Name: "Dune", SKU: "BK00000099", Price: 9000, Stock: 10, Status: entity.BookStatusActive}
+	owner := entity.CartOwner{UserID: 123}
+
+	s.Run("validation error", func() {
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{Owner: owner, SKU: "BK00000099"})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("too many copies", func() {
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{Owner: owner, SKU: "BK00000099", Amount: 6})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "a cart cannot have more than 5 copies of book BK00000099")
+	})
+
+	s.Run("book not found", func() {
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, "BK00000404").
+			Return(nil, errorx.ErrNotFound("book cannot be found")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{Owner: owner, SKU: "BK00000404", Amount: 1})
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("book hidden", func() {
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, "BK00000103").
+			Return(&entity.Book{ID: 103, SKU: "BK00000103", Status: entity.BookStatusHidden}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{Owner: owner, SKU: "BK00000103", Amount: 1})
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("book discontinued", func() {
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, "BK00000102").
+			Return(&entity.Book{ID: 102, SKU: "BK00000102", Status: entity.BookStatusDiscontinued}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{Owner: owner, SKU: "BK00000102", Amount: 1})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "book BK00000102 has been discontinued")
+	})
+
+	s.Run("too many books in total", func() {
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, book.SKU).Return(book, nil).Times(1)
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{{SKU: "BK00000100", Amount: 4}, {SKU: book.SKU, Amount: 1}}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{Owner: owner, SKU: book.SKU, Amount: 3})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "a cart cannot have more than 6 books in total")
+	})
+
+	s.Run("too many different books", func() {
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, book.SKU).Return(book, nil).Times(1)
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{{SKU: "BK00000100", Amount: 1}, {SKU: "BK00000101", Amount: 1}}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{Owner: owner, SKU: book.SKU, Amount: 1})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "a cart cannot have more than 2 different books")
+	})
+
+	s.Run("first book of a guest creates the cart", func() {
+		var token string
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, book.SKU).Return(book, nil).Times(1)
+		s.repo.EXPECT().CreateGuestCart(ctx, s.tx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ pgx.Tx, t string) (*entity.Cart, error) {
+				token = t
+				return &entity.Cart{ID: 8, Token: t}, nil
+			}).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(8)).Return([]entity.CartItem{}, nil).Times(1)
+		s.repo.EXPECT().SetCartItem(ctx, s.tx, int64(8), entity.CartItem{SKU: book.SKU, Amount: 2, Price: 9000}).
+			Return(nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(8)).
+			Return([]entity.CartItem{{SKU: book.SKU, Amount: 2, Price: 9000, AddedPrice: 9000, Stock: 10}}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{SKU: book.SKU, Amount: 2})
+		s.Require().Nil(err)
+		s.Assert().Len(token, 64)
+		s.Assert().Equal(token, result.Token)
+		s.Assert().Equal(int64(18000), result.Total)
+	})
+
+	s.Run("set amount of a book in the cart", func() {
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, book.SKU).Return(book, nil).Times(1)
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{{SKU: "BK00000100", Amount: 1}, {SKU: book.SKU, Amount: 4}}, nil).Times(1)
+		s.repo.EXPECT().SetCartItem(ctx, s.tx, int64(7), entity.CartItem{SKU: book.SKU, Amount: 5, Price: 9000}).
+			Return(nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{
+				{SKU: "BK00000100", Amount: 1, Price: 1000, AddedPrice: 1000, Stock: 3},
+				{SKU: book.SKU, Amount: 5, Price: 9000, AddedPrice: 9000, Stock: 10},
+			}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetCartItem(ctx, entity.SetCartItemParams{Owner: owner, SKU: book.SKU, Amount: 5})
+		s.Require().Nil(err)
+		s.Assert().Len(result.Items, 2)
+		s.Assert().Equal(int64(46000), result.Total)
+	})
+}
+
+func (s *CartServiceTestSuite) TestRemoveCartItem() {
+	ctx := context.Background()
+	svc := s.newService(service.DefaultOrderLimits)
+	owner := entity.CartOwner{UserID: 123}
+
+	s.Run("guest without cart", func() {
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.RemoveCartItem(ctx, entity.RemoveCartItemParams{SKU: "BK00000099"})
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("book not in cart", func() {
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().DeleteCartItem(ctx, s.tx, int64(7), "BK00000099").Return(int64(0), nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.RemoveCartItem(ctx, entity.RemoveCartItemParams{Owner: owner, SKU: "BK00000099"})
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("remove successful", func() {
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().DeleteCartItem(ctx, s.tx, int64(7), "BK00000099").Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).Return([]entity.CartItem{}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.RemoveCartItem(ctx, entity.RemoveCartItemParams{Owner: owner, SKU: "BK00000099"})
+		s.Assert().Nil(err)
+		s.Assert().Empty(result.Items)
+	})
+}
+
+func (s *CartServiceTestSuite) TestClearCart() {
+	ctx := context.Background()
+	svc := s.newService(service.DefaultOrderLimits)
+
+	s.Run("guest without cart", func() {
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.Assert().Nil(svc.ClearCart(ctx, entity.CartOwner{}))
+	})
+
+	s.Run("clear successful", func() {
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().ClearCart(ctx, s.tx, int64(7)).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.Assert().Nil(svc.ClearCart(ctx, entity.CartOwner{UserID: 123}))
+	})
+}
+
+func (s *CartServiceTestSuite) TestCheckout() {
+	ctx := context.Background()
+	svc := s.newService(service.DefaultOrderLimits)
+	owner := entity.CartOwner{UserID: 123}
+	book := &entity.Book{ID: 99, SKU: "BK00000099", Status: entity.BookStatusActive}
+	customerID := int64(123)
+
+	s.Run("guest", func() {
+		result, err := svc.Checkout(ctx, entity.CartOwner{Token: "guest-token"})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeUnauthorized, goxErr.Code)
+	})
+
+	s.Run("cart is empty", func() {
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).Return([]entity.CartItem{}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.Checkout(ctx, owner)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "cart is empty")
+	})
+
+	s.Run("price changed", func() {
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{{SKU: book.SKU, Amount: 2, Price: 9500, AddedPrice: 9000}}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.Checkout(ctx, owner)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "price of book BK00000099 has changed from 9000 to 9500")
+	})
+
+	s.Run("out of stock keeps the cart", func() {
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{{SKU: book.SKU, Amount: 2, Price: 9000, AddedPrice: 9000}}, nil).Times(1)
+		s.orderRepo.EXPECT().FindBookBySKU(ctx, s.tx, book.SKU).Return(book, nil).Times(1)
+		s.orderRepo.EXPECT().CreateOrder(ctx, s.tx, gomock.Any()).
+			Return(&entity.Order{ID: 1, UserID: 123, Status: entity.OrderStatusPendingPayment}, nil).Times(1)
+		s.orderRepo.EXPECT().CreateOrderItem(ctx, s.tx, entity.CreateOrderItemParams{OrderID: 1, SKU: book.SKU, Amount: 2}).
+			Return(&entity.OrderItem{ID: 29, OrderID: 1, BookID: 99, SKU: book.SKU, Amount: 2}, nil).Times(1)
+		s.orderRepo.EXPECT().DecrementBookStock(ctx, s.tx, book.SKU, int64(2)).Return(int64(0), nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.Checkout(ctx, owner)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "book BK00000099 is out of stock")
+	})
+
+	s.Run("checkout successful", func() {
+		s.repo.EXPECT().FindGuestCart(ctx, s.tx, "guest-token").
+			Return(nil, errorx.ErrNotFound("cart cannot be found")).Times(1)
+		s.repo.EXPECT().GetUserCart(ctx, s.tx, int64(123)).
+			Return(&entity.Cart{ID: 7, UserID: 123}, nil).Times(1)
+		s.repo.EXPECT().GetCartItems(ctx, s.tx, int64(7)).
+			Return([]entity.CartItem{{SKU: book.SKU, Amount: 2, Price: 9000, AddedPrice: 9000}}, nil).Times(1)
+		s.orderRepo.EXPECT().FindBookBySKU(ctx, s.tx, book.SKU).Return(book, nil).Times(1)
+		s.orderRepo.EXPECT().CreateOrder(ctx, s.tx, entity.CreateOrderParams{
+			UserID:         123,
+			Items:          []entity.CreateOrderItemParams{{SKU: book.SKU, Amount: 2}},
+			ReservationTTL: service.DefaultOrderLimits.ReservationTTL,
+		}).Return(&entity.Order{ID: 1, UserID: 123, Status: entity.OrderStatusPendingPayment}, nil).Times(1)
+		s.orderRepo.EXPECT().CreateOrderItem(ctx, s.tx, entity.CreateOrderItemParams{OrderID: 1, SKU: book.SKU, Amount: 2}).
+			Return(&entity.OrderItem{ID: 29, OrderID: 1, BookID: 99, SKU: book.SKU, Amount: 2}, nil).Times(1)
+		s.orderRepo.EXPECT().DecrementBookStock(ctx, s.tx, book.SKU, int64(2)).Return(int64(1), nil).Times(1)
+		s.orderRepo.EXPECT().CreateOrderStatusChange(ctx, s.tx, entity.OrderStatusChange{
+			OrderID:   1,
+			To:        entity.OrderStatusPendingPayment,
+			ActorID:   &customerID,
+			ActorRole: entity.UserRoleCustomer,
+		}).Return(&entity.OrderStatusChange{OrderID: 1, To: entity.OrderStatusPendingPayment}, nil).Times(1)
+		s.repo.EXPECT().ClearCart(ctx, s.tx, int64(7)).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.Checkout(ctx, entity.CartOwner{UserID: 123, Token: "guest-token"})
+		s.Require().Nil(err)
+		s.Assert().Equal(int64(1), result.ID)
+		s.Assert().Len(result.Items, 1)
+		s.Assert().Len(result.History, 1)
+	})
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+)
+
+// slugSeparators matches what slugify turns into a single dash, the same expression the categories migration used.
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+type CategoryService struct {
+	repo      CategoryRepository
+	validator *validator.Validate
+	txStarter repository.TxStarter
+}
+
+func NewCategoryService(repo CategoryRepository, txStarter repository.TxStarter) *CategoryService {
+	return &CategoryService{
+		repo:      repo,
+		validator: validator.New(),
+		txStarter: txStarter,
+	}
+}
+
+// GetCategoryTree returns the top level categories with their descendants nested as children, siblings sorted by name.
+func (s *CategoryService) GetCategoryTree(ctx context.Context) (*entity.CategoryList, error) {
+	categories, err := s.repo.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]*entity.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	// categories come sorted by name, appending in that order keeps every level sorted
+	roots := []*entity.Category{}
+	for _, c := range categories {
+		parent, exist := (*entity.Category)(nil), false
+		if c.ParentID != nil {
+			parent, exist = byID[*c.ParentID]
+		}
+		if !exist {
+			roots = append(roots, c)
+			continue
+		}
+		parent.Children = append(parent.Children, c)
+	}
+
+	return &entity.CategoryList{Data: roots}, nil
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, id int64) (*entity.Category, error) {
+	if id <= 0 {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	return s.repo.FindCategory(ctx, id)
+}
+
+// CreateCategory adds a category under params.ParentID, or at the top level without one. The slug is derived from the
+// name when it is not given, it has to be unique among its siblings.
+func (s *CategoryService) CreateCategory(ctx context.Context, params entity.CreateCategoryParams) (*entity.Category, error) {
+	params.Name = strings.TrimSpace(params.Name)
+	params.BISACCode = strings.ToUpper(strings.TrimSpace(params.BISACCode))
+	params.ThemaCode = strings.ToUpper(strings.TrimSpace(params.ThemaCode))
+	if params.Slug == "" {
+		params.Slug = params.Name
+	}
+	params.Slug = slugify(params.Slug)
+
+	if err := s.validator.Struct(params); err != nil || params.Slug == "" {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	return s.repo.CreateCategory(ctx, params)
+}
+
+// SetBookCategories replaces the categories of a book and returns the ones it ends up in.
+func (s *CategoryService) SetBookCategories(ctx context.Context, params entity.SetBookCategoriesParams) ([]*entity.Category, error) {
+	var err error
+	if err = s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	categoryIDs := make([]int64, 0, len(params.CategoryIDs))
+	seen := make(map[int64]bool, len(params.CategoryIDs))
+	for _, id := range params.CategoryIDs {
+		if !seen[id] {
+			seen[id] = true
+			categoryIDs = append(categoryIDs, id)
+		}
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = s.repo.FindBook(ctx, tx, params.BookID)
+	if err != nil {
+		return nil, err
+	}
+
+	var linked int64
+	linked, err = s.repo.SetBookCategories(ctx, tx, params.BookID, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if linked != int64(len(categoryIDs)) {
+		err = customerror.ErrUnprocessableEntity("category cannot be found")
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetBookCategories(ctx, params.BookID)
+}
+
+func slugify(s string) string {
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
@@ -0,0 +1,147 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+	mock_repository "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/repository"
+	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
+)
+
+type CategoryServiceTestSuite struct {
+	suite.Suite
+
+	repo   *mock_service.MockCategoryRepository
+	txFunc repository.TxStarter
+	tx     *mock_repository.MockTransactionable
+}
+
+func (s *CategoryServiceTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_service.NewMockCategoryRepository(ctrl)
+	s.tx = mock_repository.NewMockTransactionable(ctrl)
+	s.txFunc = func(ctx context.Context) (pgx.Tx, error) {
+		return s.tx, nil
+	}
+}
+
+func TestCategoryService(t *testing.T) {
+	suite.Run(t, new(CategoryServiceTestSuite))
+}
+
+func (s *CategoryServiceTestSuite) TestGetCategoryTree() {
+	ctx := context.Background()
+	svc := service.NewCategoryService(s.repo, s.txFunc)
+
+	s.Run("error from repo", func() {
+		s.repo.EXPECT().GetCategories(ctx).Return(nil, errors.New("db down")).Times(1)
+
+		result, err := svc.GetCategoryTree(ctx)
+		s.Assert().Nil(result)
+		s.Assert().Error(err)
+	})
+
+	s.Run("nests children under their parents", func() {
+		fiction, mystery := int64(1), int64(3)
+		s.repo.EXPECT().GetCategories(ctx).Return([]*entity.Category{
+			{ID: 4, ParentID: &mystery, Name: "Cozy", Slug: "cozy"},
+			{ID: 1, Name: "Fiction", Slug: "fiction"},
+			{ID: 2, Name: "History", Slug: "history"},
+			{ID: 3, ParentID: &fiction, Name: "Mystery", Slug: "mystery"},
+		}, nil).Times(1)
+
+		result, err := svc.GetCategoryTree(ctx)
+		s.Require().NoError(err)
+		s.Assert().Equal(&entity.CategoryList{Data: []*entity.Category{
+			{ID: 1, Name: "Fiction", Slug: "fiction", Children: []*entity.Category{
+				{ID: 3, ParentID: &fiction, Name: "Mystery", Slug: "mystery", Children: []*entity.Category{
+					{ID: 4, ParentID: &mystery, Name: "Cozy", Slug: "cozy"},
+				}},
+			}},
+			{ID: 2, Name: "History", Slug: "history"},
+		}}, result)
+	})
+}
+
+func (s *CategoryServiceTestSuite) TestCreateCategory() {
+	ctx := context.Background()
+	svc := service.NewCategoryService(s.repo, s.txFunc)
+
+	s.Run("name without letters or digits", func() {
+		result, err := svc.CreateCategory(ctx, entity.CreateCategoryParams{Name: "???"})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("slug derived from the name", func() {
+		parentID := int64(1)
+		expected := &entity.Category{ID: 5, ParentID: &parentID, Name: "Sci-Fi & Fantasy", Slug: "sci-fi-fantasy"}
+		s.repo.EXPECT().CreateCategory(ctx, entity.CreateCategoryParams{
+			ParentID:  &parentID,
+			Name:      "Sci-Fi & Fantasy",
+			Slug:      "sci-fi-fantasy",
+			BISACCode: "FIC028000",
+		}).Return(expected, nil).Times(1)
+
+		result, err := svc.CreateCategory(ctx, entity.CreateCategoryParams{
+			ParentID:  &parentID,
+			Name:      " Sci-Fi & Fantasy ",
+			BISACCode: "fic028000",
+		})
+		s.Assert().Nil(err)
+		s.Assert().Equal(expected, result)
+	})
+}
+
+func (s *CategoryServiceTestSuite) TestSetBookCategories() {
+	ctx := context.Background()
+	svc := service.NewCategoryService(s.repo, s.txFunc)
+
+	s.Run("book not found", func() {
+		s.repo.EXPECT().FindBook(ctx, s.tx, int64(7)).
+			Return(nil, errorx.ErrNotFound("book cannot be found")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetBookCategories(ctx, entity.SetBookCategoriesParams{BookID: 7, CategoryIDs: []int64{1}})
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("unknown category", func() {
+		s.repo.EXPECT().FindBook(ctx, s.tx, int64(7)).Return(&entity.Book{ID: 7}, nil).Times(1)
+		s.repo.EXPECT().SetBookCategories(ctx, s.tx, int64(7), []int64{1, 99}).Return(int64(1), nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.SetBookCategories(ctx, entity.SetBookCategoriesParams{BookID: 7, CategoryIDs: []int64{1, 99}})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+	})
+
+	s.Run("successful with duplicates", func() {
+		categories := []*entity.Category{{ID: 1, Name: "Fiction", Slug: "fiction"}, {ID: 3, Name: "Mystery", Slug: "mystery"}}
+		s.repo.EXPECT().FindBook(ctx, s.tx, int64(7)).Return(&entity.Book{ID: 7}, nil).Times(1)
+		s.repo.EXPECT().SetBookCategories(ctx, s.tx, int64(7), []int64{3, 1}).Return(int64(2), nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().GetBookCategories(ctx, int64(7)).Return(categories, nil).Times(1)
+
+		result, err := svc.SetBookCategories(ctx, entity.SetBookCategoriesParams{BookID: 7, CategoryIDs: []int64{3, 1, 3}})
+		s.Assert().Nil(err)
+		s.Assert().Equal(categories, result)
+	})
+}
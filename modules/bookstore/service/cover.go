@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+const (
+	// MaxCoverDimension bounds the width and height of an uploaded cover, checked before the image is decoded.
+	MaxCoverDimension = 6000
+	// CoverThumbnailQuality is the JPEG quality thumbnails are encoded with.
+	CoverThumbnailQuality = 85
+)
+
+// CoverThumbnails are the widths the thumbnails are scaled down to, the height keeps the aspect ratio. Covers
+// narrower than a width are stored at their own size.
+var CoverThumbnails = []struct {
+	Rendition string
+	Width     int
+}{
+	{Rendition: entity.CoverSmall, Width: 160},
+	{Rendition: entity.CoverMedium, Width: 320},
+	{Rendition: entity.CoverLarge, Width: 640},
+}
+
+// coverContentTypes are the sniffed content types accepted for a cover.
+var coverContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
+type CoverService struct {
+	repo  CoverRepository
+	store BlobStore
+}
+
+func NewCoverService(repo CoverRepository, store BlobStore) *CoverService {
+	return &CoverService{
+		repo:  repo,
+		store: store,
+	}
+}
+
+// UploadCover stores a JPEG or PNG cover with its thumbnails and attaches it to the book. Every rendition is keyed by
+// the SHA-256 of the upload, uploading the same image again rewrites identical blobs.
+func (s *CoverService) UploadCover(ctx context.Context, bookID int64, r io.Reader) (*entity.Book, error) {
+	if bookID <= 0 {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(data)
+	if !coverContentTypes[contentType] {
+		return nil, errorx.ErrInvalidParameter("cover must be a JPEG or PNG image")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errorx.ErrInvalidParameter("cover cannot be decoded")
+	}
+	if config.Width > MaxCoverDimension || config.Height > MaxCoverDimension {
+		return nil, errorx.ErrInvalidParameter("cover is too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errorx.ErrInvalidParameter("cover cannot be decoded")
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	if err = s.store.Put(ctx, entity.CoverKey(hash, entity.CoverOriginal), bytes.NewReader(data), contentType); err != nil {
+		return nil, err
+	}
+
+	for _, thumbnail := range CoverThumbnails {
+		var buf bytes.Buffer
+		if err = jpeg.Encode(&buf, scaleToWidth(img, thumbnail.Width), &jpeg.Options{Quality: CoverThumbnailQuality}); err != nil {
+			return nil, errorx.Wrap(err, errorx.CodeInternal, "internal server error")
+		}
+
+		if err = s.store.Put(ctx, entity.CoverKey(hash, thumbnail.Rendition), &buf, "image/jpeg"); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.SetBookCover(ctx, bookID, hash)
+}
+
+// scaleToWidth shrinks src to the given width by averaging the source pixels behind every target pixel. Transparent
+// areas are flattened onto white since thumbnails are JPEG.
+func scaleToWidth(src image.Image, width int) image.Image {
+	b := src.Bounds()
+	if b.Dx() < width {
+		width = b.Dx()
+	}
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/height, b.Min.Y+(y+1)*b.Dy()/height
+		for x := 0; x < width; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/width, b.Min.X+(x+1)*b.Dx()/width
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+
+			// colors are alpha premultiplied, adding the missing coverage as white composes over a white background
+			white := 0xffff - a/n
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r/n + white),
+				G: uint16(g/n + white),
+				B: uint16(bl/n + white),
+				A: 0xffff,
+			})
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
+)
+
+type CoverServiceTestSuite struct {
+	suite.Suite
+
+	repo  *mock_service.MockCoverRepository
+	store *mock_service.MockBlobStore
+}
+
+func (s *CoverServiceTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_service.NewMockCoverRepository(ctrl)
+	s.store = mock_service.NewMockBlobStore(ctrl)
+}
+
+func TestCoverService(t *testing.T) {
+	suite.Run(t, new(CoverServiceTestSuite))
+}
+
+func (s *CoverServiceTestSuite) TestUploadCover() {
+	ctx := context.Background()
+	svc := service.NewCoverService(s.repo, s.store)
+
+	s.Run("not an image", func() {
+		result, err := svc.UploadCover(ctx, 7, strings.NewReader("title,isbn\n"))
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "cover must be a JPEG or PNG image")
+	})
+
+	s.Run("image too large", func() {
+		result, err := svc.UploadCover(ctx, 7, bytes.NewReader(encodePNG(s.T(), service.MaxCoverDimension+1, 1)))
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "cover is too large")
+	})
+
+	s.Run("store error", func() {
+		s.store.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), "image/png").
+			Return(errors.New("disk full")).Times(1)
+
+		result, err := svc.UploadCover(ctx, 7, bytes.NewReader(encodePNG(s.T(), 40, 60)))
+		s.Assert().Nil(result)
+		s.Assert().Error(err)
+	})
+
+	s.Run("stores the original and its thumbnails under the content hash", func() {
+		data := encodePNG(s.T(), 800, 1200)
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+
+		s.store.EXPECT().Put(ctx, hash+"/original", gomock.Any(), "image/png").
+			DoAndReturn(func(_ context.Context, _ string, r io.Reader, _ string) error {
+				stored, err := io.ReadAll(r)
+				s.Require().NoError(err)
+				s.Assert().Equal(data, stored)
+				return nil
+			}).Times(1)
+
+		widths := map[string]int{}
+		s.store.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), "image/jpeg").
+			DoAndReturn(func(_ context.Context, key string, r io.Reader, _ string) error {
+				thumbnail, err := jpeg.Decode(r)
+				s.Require().NoError(err)
+				s.Assert().Equal(thumbnail.Bounds().Dx()*3/2, thumbnail.Bounds().Dy())
+				widths[key] = thumbnail.Bounds().Dx()
+				return nil
+			}).Times(3)
+
+		book := &entity.Book{ID: 7, Cover: entity.NewBookCover(hash)}
+		s.repo.EXPECT().SetBookCover(ctx, int64(7), hash).Return(book, nil).Times(1)
+
+		result, err := svc.UploadCover(ctx, 7, bytes.NewReader(data))
+		s.Assert().Nil(err)
+		s.Assert().Equal(book, result)
+		s.Assert().Equal(map[string]int{hash + "/small": 160, hash + "/medium": 320, hash + "/large": 640}, widths)
+	})
+
+	s.Run("small covers are not scaled up", func() {
+		data := encodePNG(s.T(), 100, 150)
+		s.store.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), "image/png").Return(nil).Times(1)
+		s.store.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), "image/jpeg").
+			DoAndReturn(func(_ context.Context, _ string, r io.Reader, _ string) error {
+				thumbnail, err := jpeg.Decode(r)
+				s.Require().NoError(err)
+				s.Assert().Equal(100, thumbnail.Bounds().Dx())
+				return nil
+			}).Times(3)
+		s.repo.EXPECT().SetBookCover(ctx, int64(7), gomock.Any()).Return(&entity.Book{ID: 7}, nil).Times(1)
+
+		_, err := svc.UploadCover(ctx, 7, bytes.NewReader(data))
+		s.Assert().Nil(err)
+	})
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -2,34 +2,140 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
 	"github.com/raymondwongso/gogox/errorx"
 
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/pagination"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
 )
 
+// OrderLimits bound the size of a single order and how long it may wait for payment. Items of the same edition are
+// merged into one line before the limits are checked.
+type OrderLimits struct {
+	// MaxLineQuantity is the most copies of one edition an order may have
+	MaxLineQuantity int64
+	// MaxOrderQuantity is the most copies an order may have in total
+	MaxOrderQuantity int64
+	// MaxLines is the most distinct editions an order may have
+	MaxLines int
+	// ReservationTTL is how long an unpaid order holds the stock of its items
+	ReservationTTL time.Duration
+}
+
+var DefaultOrderLimits = OrderLimits{
+	MaxLineQuantity:  20,
+	MaxOrderQuantity: 100,
+	MaxLines:         50,
+	ReservationTTL:   30 * time.Minute,
+}
+
+// orderItemFields names the fields of entity.CreateOrderItemParams in item errors.
+var orderItemFields = map[string]string{
+	"SKU":    "sku",
+	"BookID": "book_id",
+	"Amount": "amount",
+}
+
 type OrderService struct {
 	repo      OrderRepository
 	validator *validator.Validate
 	txStarter repository.TxStarter
+	payments  PaymentProvider
+	limits    OrderLimits
 }
 
-func NewOrderService(repo OrderRepository, txStarter repository.TxStarter) *OrderService {
+func NewOrderService(repo OrderRepository, txStarter repository.TxStarter, payments PaymentProvider, limits OrderLimits) *OrderService {
 	return &OrderService{
 		repo:      repo,
 		validator: validator.New(),
 		txStarter: txStarter,
+		payments:  payments,
+		limits:    limits,
 	}
 }
 
-func (s *OrderService) GetOrders(ctx context.Context, params entity.GetMyOrdersParams) ([]entity.Order, error) {
+func (s *OrderService) GetOrders(ctx context.Context, params entity.GetMyOrdersParams) (*entity.OrderList, error) {
 	if err := s.validator.Struct(params); err != nil {
 		return nil, errorx.ErrInvalidParameter("Input is invalid")
 	}
 
-	return s.repo.GetMyOrders(ctx, params)
+	if params.Cursor != "" {
+		if params.Offset != 0 {
+			return nil, errorx.ErrInvalidParameter("cursor cannot be combined with offset")
+		}
+
+		cursor, err := pagination.Decode(params.Cursor)
+		if err != nil || cursor.Sort != "" {
+			return nil, errorx.ErrInvalidParameter("cursor invalid")
+		}
+		params.BeforeID = cursor.ID
+	}
+
+	// one extra row tells whether there is a next page without a separate count
+	limit := params.Limit
+	params.Limit++
+
+	orders, err := s.repo.GetMyOrders(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &entity.OrderList{Data: orders}
+	if int64(len(orders)) > limit {
+		list.Data = orders[:limit]
+		list.NextCursor = pagination.Cursor{ID: list.Data[limit-1].ID}.Encode()
+		list.HasMore = true
+	}
+
+	return list, nil
+}
+
+// GetOrder returns an order of the customer. Orders of other customers are reported as missing, so order IDs do not
+// tell anything about other customers.
+func (s *OrderService) GetOrder(ctx context.Context, params entity.GetOrderParams) (*entity.Order, error) {
+	if err := s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	order, err := s.repo.FindOrder(ctx, params.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ownedOrder(order, params.UserID); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ownedOrder checks that the order belongs to the user. Someone else's order is reported the same as a missing one.
+func ownedOrder(order *entity.Order, userID int64) error {
+	if order.UserID != userID {
+		return errorx.ErrNotFound("order cannot be found")
+	}
+
+	return nil
+}
+
+func (s *OrderService) CountOrders(ctx context.Context, userID int64) (*entity.TotalCount, error) {
+	if userID <= 0 {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	count, err := s.repo.CountMyOrders(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TotalCount{Count: count}, nil
 }
 
 func (s *OrderService) CreateOrder(ctx context.Context, params entity.CreateOrderParams) (*entity.Order, error) {
@@ -50,40 +156,159 @@ func (s *OrderService) CreateOrder(ctx context.Context, params entity.CreateOrde
 		}
 	}()
 
-	for _, d := range params.Items {
-		if err = s.validator.Struct(d); err != nil {
-			return nil, errorx.ErrInvalidParameter("Input is invalid")
-		}
+	var order *entity.Order
+	order, err = s.createOrder(ctx, tx, params)
+	if err != nil {
+		return nil, err
+	}
 
-		if _, err = s.repo.FindBook(ctx, tx, d.BookID); err != nil {
-			return nil, err
-		}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	var order *entity.Order
-	order, err = s.repo.CreateOrder(ctx, tx, params)
+	return order, nil
+}
+
+// createOrder places the order within tx, which the caller commits. Callers validate params first. The stock taken
+// by the order is reserved for it until the reservation expires unpaid.
+func (s *OrderService) createOrder(ctx context.Context, tx pgx.Tx, params entity.CreateOrderParams) (*entity.Order, error) {
+	lines, err := s.orderLines(ctx, tx, params.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	params.ReservationTTL = s.limits.ReservationTTL
+
+	order, err := s.repo.CreateOrder(ctx, tx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, itemInParam := range params.Items {
-		var item *entity.OrderItem
-		item, err = s.repo.CreateOrderItem(ctx, tx, entity.CreateOrderItemParams{
-			OrderID: order.ID,
-			BookID:  itemInParam.BookID,
-			Amount:  itemInParam.Amount,
-		})
+	for _, line := range lines {
+		line.OrderID = order.ID
+
+		item, err := s.repo.CreateOrderItem(ctx, tx, line)
 		if err != nil {
 			return nil, err
 		}
 
 		order.Items = append(order.Items, *item)
+
+		decremented, err := s.repo.DecrementBookStock(ctx, tx, line.SKU, line.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if decremented == 0 {
+			return nil, customerror.ErrUnprocessableEntity(fmt.Sprintf("book %s is out of stock", line.SKU))
+		}
 	}
 
-	err = tx.Commit(ctx)
+	change, err := s.repo.CreateOrderStatusChange(ctx, tx,
+		orderStatusChange(order.ID, "", order.Status, params.UserID, entity.UserRoleCustomer))
 	if err != nil {
 		return nil, err
 	}
+	order.History = []entity.OrderStatusChange{*change}
 
 	return order, nil
 }
+
+// orderLines resolves the ordered items to editions and merges the items of the same edition into one line, in the
+// order the editions first appear. Errors about an item name it by its index in items.
+func (s *OrderService) orderLines(ctx context.Context, tx pgx.Tx, items []entity.CreateOrderItemParams) ([]entity.CreateOrderItemParams, error) {
+	if len(items) == 0 {
+		return nil, errorx.ErrInvalidParameter("order has no items")
+	}
+
+	lines := make([]entity.CreateOrderItemParams, 0, len(items))
+	lineOf := map[string]int{}
+	// older clients still order by book id, which resolves to the SKU of that edition
+	skuOf := map[int64]string{}
+	var total int64
+
+	for i, item := range items {
+		if err := s.validator.Struct(item); err != nil {
+			message := "item is invalid"
+			var fieldErrs validator.ValidationErrors
+			if errors.As(err, &fieldErrs) {
+				message = fmt.Sprintf("%s is invalid", orderItemFields[fieldErrs[0].Field()])
+				if fieldErrs[0].Tag() == "required_without" {
+					message = "sku or book_id is required"
+				}
+			}
+			return nil, errorx.ErrInvalidParameter(fmt.Sprintf("items[%d]: %s", i, message))
+		}
+
+		sku, known := item.SKU, item.SKU != ""
+		if !known {
+			sku, known = skuOf[item.BookID]
+		}
+		if _, merged := lineOf[sku]; !known || !merged {
+			book, err := s.findOrderedBook(ctx, tx, item)
+			if err != nil {
+				return nil, orderItemError(i, err)
+			}
+			sku = book.SKU
+			skuOf[book.ID] = book.SKU
+		}
+
+		line, merged := lineOf[sku]
+		if !merged {
+			if len(lines) == s.limits.MaxLines {
+				return nil, customerror.ErrUnprocessableEntity(
+					fmt.Sprintf("items[%d]: an order cannot have more than %d different books", i, s.limits.MaxLines))
+			}
+			line = len(lines)
+			lineOf[sku] = line
+			lines = append(lines, entity.CreateOrderItemParams{SKU: sku})
+		}
+
+		lines[line].Amount += item.Amount
+		if lines[line].Amount > s.limits.MaxLineQuantity {
+			return nil, customerror.ErrUnprocessableEntity(
+				fmt.Sprintf("items[%d]: an order cannot have more than %d copies of book %s", i, s.limits.MaxLineQuantity, sku))
+		}
+
+		total += item.Amount
+		if total > s.limits.MaxOrderQuantity {
+			return nil, customerror.ErrUnprocessableEntity(
+				fmt.Sprintf("items[%d]: an order cannot have more than %d books in total", i, s.limits.MaxOrderQuantity))
+		}
+	}
+
+	return lines, nil
+}
+
+func (s *OrderService) findOrderedBook(ctx context.Context, tx pgx.Tx, item entity.CreateOrderItemParams) (*entity.Book, error) {
+	var book *entity.Book
+	var err error
+	if item.SKU != "" {
+		book, err = s.repo.FindBookBySKU(ctx, tx, item.SKU)
+	} else {
+		book, err = s.repo.FindBook(ctx, tx, item.BookID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch book.Status {
+	case entity.BookStatusHidden:
+		// hidden books are out of the catalog, customers cannot tell them apart from missing ones
+		return nil, errorx.ErrNotFound("book cannot be found")
+	case entity.BookStatusDiscontinued:
+		return nil, customerror.ErrUnprocessableEntity(fmt.Sprintf("book %s has been discontinued", book.SKU))
+	}
+
+	return book, nil
+}
+
+// orderItemError prefixes the message of err with the index of the item it is about, keeping its code.
+func orderItemError(i int, err error) error {
+	goxErr, ok := errorx.Parse(err)
+	if !ok {
+		return err
+	}
+
+	return errorx.Wrap(err, goxErr.Code, fmt.Sprintf("items[%d]: %s", i, goxErr.Message))
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+)
+
+// ExpireOrders cancels up to limit unpaid orders whose reservation expired and releases their stock, all in one
+// transaction. Orders another transaction is working on are left for a later sweep. It returns how many orders were
+// cancelled.
+func (s *OrderService) ExpireOrders(ctx context.Context, limit int) (int, error) {
+	var err error
+	if limit <= 0 {
+		return 0, errorx.ErrInvalidParameter("limit invalid")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var orders []entity.Order
+	orders, err = s.repo.GetExpiredOrders(ctx, tx, int64(limit))
+	if err != nil {
+		return 0, err
+	}
+
+	var refunds []*entity.PaymentRefund
+	for i := range orders {
+		var refund *entity.PaymentRefund
+		_, refund, err = s.applyOrderTransition(ctx, tx, &orders[i], entity.TransitionOrderParams{
+			OrderID:   orders[i].ID,
+			Status:    entity.OrderStatusCancelled,
+			ActorRole: entity.OrderActorSystem,
+		})
+		if err != nil {
+			return 0, err
+		}
+		refunds = append(refunds, refund)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, refund := range refunds {
+		s.sendRecordedRefund(ctx, refund)
+	}
+
+	return len(orders), nil
+}
+
+// ReservationSweeper cancels unpaid orders once their reservation expires, sends refunds again that the provider did
+// not take and purges expired Idempotency-Keys. Every API instance may run one, each sweep only takes orders and refunds
+// no other sweeper holds.
+type ReservationSweeper struct {
+	orders    *OrderService
+	keys      IdempotencyKeyRepository
+	interval  time.Duration
+	batchSize int
+}
+
+func NewReservationSweeper(orders *OrderService, keys IdempotencyKeyRepository, interval time.Duration, batchSize int) *ReservationSweeper {
+	return &ReservationSweeper{
+		orders:    orders,
+		keys:      keys,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run sweeps every interval until ctx is done.
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep expires orders batch by batch until a batch comes back short, so a backlog is worked off in one sweep, then
+// sends a batch of pending refunds and purges expired Idempotency-Keys the same way as orders. Errors are logged, the
+// next sweep tries again.
+func (s *ReservationSweeper) Sweep(ctx context.Context) {
+	s.expireOrders(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if _, err := s.orders.RetryRefunds(ctx, s.batchSize); err != nil {
+		fmt.Println(errorx.ParseAndWrap(err, "cannot retry refunds").LogError())
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.purgeIdempotencyKeys(ctx)
+}
+
+func (s *ReservationSweeper) expireOrders(ctx context.Context) {
+	for ctx.Err() == nil {
+		expired, err := s.orders.ExpireOrders(ctx, s.batchSize)
+		if err != nil {
+			fmt.Println(errorx.ParseAndWrap(err, "cannot expire orders").LogError())
+			return
+		}
+
+		if expired < s.batchSize {
+			return
+		}
+	}
+}
+
+func (s *ReservationSweeper) purgeIdempotencyKeys(ctx context.Context) {
+	for ctx.Err() == nil {
+		deleted, err := s.keys.DeleteExpiredIdempotencyKeys(ctx, int64(s.batchSize))
+		if err != nil {
+			fmt.Println(errorx.ParseAndWrap(err, "cannot purge idempotency keys").LogError())
+			return
+		}
+
+		if deleted < int64(s.batchSize) {
+			return
+		}
+	}
+}
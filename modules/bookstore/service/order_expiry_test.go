@@ -0,0 +1,173 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+)
+
+func (s *OrderServiceTestSuite) TestExpireOrders() {
+	ctx := context.Background()
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
+	expired := []entity.Order{
+		{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment},
+		{ID: 4, UserID: 6, Status: entity.OrderStatusPendingPayment},
+	}
+
+	s.Run("invalid limit", func() {
+		result, err := svc.ExpireOrders(ctx, 0)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("nothing expired", func() {
+		s.repo.EXPECT().GetExpiredOrders(ctx, s.tx, int64(10)).Return([]entity.Order{}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.ExpireOrders(ctx, 10)
+		s.Assert().Nil(err)
+		s.Assert().Zero(result)
+	})
+
+	s.Run("expired orders are cancelled and release their stock", func() {
+		s.repo.EXPECT().GetExpiredOrders(ctx, s.tx, int64(10)).Return(expired, nil).Times(1)
+		for _, order := range expired {
+			s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, order.ID, entity.OrderStatusPendingPayment, entity.OrderStatusCancelled).
+				Return(&entity.Order{ID: order.ID, UserID: order.UserID, Status: entity.OrderStatusCancelled}, nil).Times(1)
+			s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, entity.OrderStatusChange{
+				OrderID:   order.ID,
+				From:      entity.OrderStatusPendingPayment,
+				To:        entity.OrderStatusCancelled,
+				ActorRole: entity.OrderActorSystem,
+			}).Return(&entity.OrderStatusChange{}, nil).Times(1)
+			s.repo.EXPECT().ReleaseOrderStock(ctx, s.tx, order.ID).Return(int64(1), nil).Times(1)
+		}
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := svc.ExpireOrders(ctx, 10)
+		s.Assert().Nil(err)
+		s.Assert().Equal(2, result)
+	})
+
+	s.Run("release stock got repo error", func() {
+		s.repo.EXPECT().GetExpiredOrders(ctx, s.tx, int64(10)).Return(expired[:1], nil).Times(1)
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusPendingPayment, entity.OrderStatusCancelled).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusCancelled}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, gomock.Any()).Return(&entity.OrderStatusChange{}, nil).Times(1)
+		s.repo.EXPECT().ReleaseOrderStock(ctx, s.tx, int64(3)).Return(int64(0), errors.New("repo error")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.ExpireOrders(ctx, 10)
+		s.Assert().Zero(result)
+		s.Assert().EqualError(err, "repo error")
+	})
+}
+
+func (s *OrderServiceTestSuite) TestReservationSweeper() {
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
+	pending := func(id int64) entity.Order {
+		return entity.Order{ID: id, UserID: 5, Status: entity.OrderStatusPendingPayment}
+	}
+	expectCancelled := func(ctx context.Context, id int64) {
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, id, entity.OrderStatusPendingPayment, entity.OrderStatusCancelled).
+			Return(&entity.Order{ID: id, UserID: 5, Status: entity.OrderStatusCancelled}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, gomock.Any()).Return(&entity.OrderStatusChange{}, nil).Times(1)
+		s.repo.EXPECT().ReleaseOrderStock(ctx, s.tx, id).Return(int64(1), nil).Times(1)
+	}
+
+	s.Run("sweep works off a backlog batch by batch", func() {
+		ctx := context.Background()
+		sweeper := service.NewReservationSweeper(svc, s.keys, time.Minute, 2)
+
+		gomock.InOrder(
+			s.repo.EXPECT().GetExpiredOrders(ctx, s.tx, int64(2)).Return([]entity.Order{pending(3), pending(4)}, nil).Times(1),
+			s.repo.EXPECT().GetExpiredOrders(ctx, s.tx, int64(2)).Return([]entity.Order{pending(5)}, nil).Times(1),
+		)
+		for _, id := range []int64{3, 4, 5} {
+			expectCancelled(ctx, id)
+		}
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(2)
+		s.repo.EXPECT().GetPendingPaymentRefunds(ctx, gomock.Any(), int64(2)).Return([]int64{}, nil).Times(1)
+		gomock.InOrder(
+			s.keys.EXPECT().DeleteExpiredIdempotencyKeys(ctx, int64(2)).Return(int64(2), nil).Times(1),
+			s.keys.EXPECT().DeleteExpiredIdempotencyKeys(ctx, int64(2)).Return(int64(1), nil).Times(1),
+		)
+
+		sweeper.Sweep(ctx)
+	})
+
+	s.Run("sweep stops expiring on error and still sends refunds and purges keys", func() {
+		ctx := context.Background()
+		sweeper := service.NewReservationSweeper(svc, s.keys, time.Minute, 2)
+
+		s.repo.EXPECT().GetExpiredOrders(ctx, s.tx, int64(2)).Return(nil, errors.New("repo error")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().GetPendingPaymentRefunds(ctx, gomock.Any(), int64(2)).Return([]int64{21}, nil).Times(1)
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).
+			Return(&entity.PaymentRefund{ID: 21, Reference: "pi_3", Amount: 19000, Status: entity.PaymentRefundStatusPending}, nil).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(nil).Times(1)
+		s.repo.EXPECT().UpdatePaymentRefund(ctx, s.tx, entity.PaymentRefund{
+			ID:        21,
+			Reference: "pi_3",
+			Amount:    19000,
+			Status:    entity.PaymentRefundStatusSucceeded,
+			Attempts:  1,
+		}).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.keys.EXPECT().DeleteExpiredIdempotencyKeys(ctx, int64(2)).Return(int64(0), nil).Times(1)
+
+		sweeper.Sweep(ctx)
+	})
+
+	s.Run("purge stops on error", func() {
+		ctx := context.Background()
+		sweeper := service.NewReservationSweeper(svc, s.keys, time.Minute, 2)
+
+		s.repo.EXPECT().GetExpiredOrders(ctx, s.tx, int64(2)).Return([]entity.Order{}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().GetPendingPaymentRefunds(ctx, gomock.Any(), int64(2)).Return([]int64{}, nil).Times(1)
+		s.keys.EXPECT().DeleteExpiredIdempotencyKeys(ctx, int64(2)).Return(int64(0), errors.New("repo error")).Times(1)
+
+		sweeper.Sweep(ctx)
+	})
+
+	s.Run("run sweeps until cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		sweeper := service.NewReservationSweeper(svc, s.keys, time.Millisecond, 2)
+
+		sweeps := 0
+		s.repo.EXPECT().GetExpiredOrders(ctx, s.tx, int64(2)).
+			DoAndReturn(func(context.Context, interface{}, int64) ([]entity.Order, error) {
+				sweeps++
+				if sweeps == 3 {
+					cancel()
+				}
+				return []entity.Order{}, nil
+			}).Times(3)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(3)
+		s.repo.EXPECT().GetPendingPaymentRefunds(ctx, gomock.Any(), int64(2)).Return([]int64{}, nil).Times(2)
+		s.keys.EXPECT().DeleteExpiredIdempotencyKeys(ctx, int64(2)).Return(int64(0), nil).Times(2)
+
+		done := make(chan struct{})
+		go func() {
+			sweeper.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			s.Fail("sweeper did not stop")
+		}
+		s.Assert().Equal(3, sweeps)
+	})
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+)
+
+// orderTransitions is the order lifecycle, the statuses an order may move to from each status. Cancelled and refunded
+// orders are final.
+var orderTransitions = map[string][]string{
+	entity.OrderStatusPendingPayment: {entity.OrderStatusPaid, entity.OrderStatusCancelled},
+	entity.OrderStatusPaid:           {entity.OrderStatusFulfilling, entity.OrderStatusCancelled, entity.OrderStatusRefunded},
+	entity.OrderStatusFulfilling:     {entity.OrderStatusShipped, entity.OrderStatusRefunded},
+	entity.OrderStatusShipped:        {entity.OrderStatusDelivered, entity.OrderStatusRefunded},
+	entity.OrderStatusDelivered:      {entity.OrderStatusRefunded},
+	entity.OrderStatusCancelled:      {},
+	entity.OrderStatusRefunded:       {},
+}
+
+// refundRetryDelay is how long a pending refund is left alone before the sweeper sends it again, so it does not race the
+// request that recorded it.
+const refundRetryDelay = time.Minute
+
+func canTransitionOrder(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionOrder moves an order to params.Status when the lifecycle allows it and records the change with its actor.
+// Orders are only paid by capturing their payment, they cannot be moved to paid by hand. The returned order carries its
+// whole history.
+func (s *OrderService) TransitionOrder(ctx context.Context, params entity.TransitionOrderParams) (*entity.Order, error) {
+	var err error
+	if err = s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	if params.Status == entity.OrderStatusPaid {
+		return nil, customerror.ErrUnprocessableEntity("order is paid once its payment is captured")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var order *entity.Order
+	order, err = s.repo.FindOrderForUpdate(ctx, tx, params.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canTransitionOrder(order.Status, params.Status) {
+		err = customerror.ErrUnprocessableEntity(fmt.Sprintf("order cannot move from %s to %s", order.Status, params.Status))
+		return nil, err
+	}
+
+	var refund *entity.PaymentRefund
+	order, refund, err = s.applyOrderTransition(ctx, tx, order, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendRecordedRefund(ctx, refund)
+
+	order.History, err = s.repo.GetOrderStatusChanges(ctx, []int64{params.OrderID})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CancelOrder cancels an order of the customer while it is still pending payment or paid. The stock comes back on the
+// shelf and a paid order is refunded. Cancelling an order again returns it as it is.
+func (s *OrderService) CancelOrder(ctx context.Context, params entity.CancelOrderParams) (*entity.Order, error) {
+	var err error
+	if err = s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var order *entity.Order
+	order, err = s.repo.FindOrderForUpdate(ctx, tx, params.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ownedOrder(order, params.UserID); err != nil {
+		return nil, err
+	}
+
+	var refund *entity.PaymentRefund
+	if order.Status != entity.OrderStatusCancelled {
+		if !canTransitionOrder(order.Status, entity.OrderStatusCancelled) {
+			err = customerror.ErrUnprocessableEntity(fmt.Sprintf("order is %s and can no longer be cancelled", order.Status))
+			return nil, err
+		}
+
+		order, refund, err = s.applyOrderTransition(ctx, tx, order, entity.TransitionOrderParams{
+			OrderID:   params.OrderID,
+			Status:    entity.OrderStatusCancelled,
+			ActorID:   params.UserID,
+			ActorRole: entity.UserRoleCustomer,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendRecordedRefund(ctx, refund)
+
+	order.History, err = s.repo.GetOrderStatusChanges(ctx, []int64{params.OrderID})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// applyOrderTransition moves the locked order to params.Status within tx and records the change. Cancelled and refunded
+// orders record a refund of what is left of their captured payment, and release their stock when it never left the
+// warehouse. The refund is returned for the caller to send once tx committed, so the provider is never called while the
+// order is locked.
+func (s *OrderService) applyOrderTransition(ctx context.Context, tx pgx.Tx, order *entity.Order, params entity.TransitionOrderParams) (*entity.Order, *entity.PaymentRefund, error) {
+	from := order.Status
+	order, err := s.repo.UpdateOrderStatus(ctx, tx, params.OrderID, from, params.Status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	change := orderStatusChange(params.OrderID, from, params.Status, params.ActorID, params.ActorRole)
+	_, err = s.repo.CreateOrderStatusChange(ctx, tx, change)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if params.Status != entity.OrderStatusCancelled && params.Status != entity.OrderStatusRefunded {
+		return order, nil, nil
+	}
+
+	// goods that went out come back to stock only when their return is received
+	if from == entity.OrderStatusPendingPayment || from == entity.OrderStatusPaid {
+		_, err = s.repo.ReleaseOrderStock(ctx, tx, params.OrderID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// a payment is only captured when the order moves to paid, unpaid orders have nothing to refund
+	if from == entity.OrderStatusPendingPayment {
+		return order, nil, nil
+	}
+
+	refund, err := s.refundOrder(ctx, tx, params.OrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return order, refund, nil
+}
+
+// refundOrder records a refund of what is left of the captured payment of the order.
+func (s *OrderService) refundOrder(ctx context.Context, tx pgx.Tx, orderID int64) (*entity.PaymentRefund, error) {
+	payment, err := s.capturedPayment(ctx, tx, orderID)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+
+	return s.refundPayment(ctx, tx, *payment, payment.Amount-payment.RefundedAmount)
+}
+
+// capturedPayment returns the captured payment of the order. It is nil for orders paid before payments went through
+// the store, they are refunded by hand.
+func (s *OrderService) capturedPayment(ctx context.Context, tx pgx.Tx, orderID int64) (*entity.Payment, error) {
+	payment, err := s.repo.FindOrderPayment(ctx, tx, orderID, entity.PaymentStatusCaptured)
+	if err != nil {
+		if customerror.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// refundPayment records a pending refund of amount of the payment and counts it as refunded right away, the payment is
+// refunded once nothing is left of it. The caller holds the lock of the order, so refunds of the same payment are
+// recorded one after the other.
+func (s *OrderService) refundPayment(ctx context.Context, tx pgx.Tx, payment entity.Payment, amount int64) (*entity.PaymentRefund, error) {
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	payment.RefundedAmount += amount
+	if payment.RefundedAmount >= payment.Amount {
+		payment.Status = entity.PaymentStatusRefunded
+	}
+
+	_, err := s.repo.UpdatePaymentAttempt(ctx, tx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreatePaymentRefund(ctx, tx, entity.PaymentRefund{
+		PaymentID: payment.ID,
+		Amount:    amount,
+		Status:    entity.PaymentRefundStatusPending,
+	})
+}
+
+// sendRecordedRefund sends a refund recorded by a change that committed. The change stands either way, a refund the
+// provider did not take is logged and left pending for the sweeper.
+func (s *OrderService) sendRecordedRefund(ctx context.Context, refund *entity.PaymentRefund) {
+	if refund == nil {
+		return
+	}
+
+	if err := s.SendRefund(ctx, refund.ID); err != nil {
+		fmt.Println(errorx.ParseAndWrap(err, "cannot send refund").LogError())
+	}
+}
+
+// SendRefund sends a pending refund to the payment provider, keyed by its id so the provider pays it back once however
+// often it is sent. A refund that was sent already or that another sender holds is left alone. When the provider fails,
+// the refund stays pending with the error for a later try, as it does while no provider is configured.
+func (s *OrderService) SendRefund(ctx context.Context, id int64) error {
+	if s.payments == nil {
+		return errorx.ErrInternal("payment provider is not configured")
+	}
+
+	var err error
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var refund *entity.PaymentRefund
+	refund, err = s.repo.FindPendingPaymentRefundForUpdate(ctx, tx, id)
+	if err != nil {
+		if customerror.IsErrNotFound(err) {
+			err = tx.Commit(ctx)
+		}
+		return err
+	}
+
+	refund.Attempts++
+	sendErr := s.payments.Refund(ctx, refund.Reference, fmt.Sprintf("refund-%d", refund.ID), refund.Amount)
+	if sendErr != nil {
+		refund.LastError = sendErr.Error()
+	} else {
+		refund.Status = entity.PaymentRefundStatusSucceeded
+		refund.LastError = ""
+	}
+
+	err = s.repo.UpdatePaymentRefund(ctx, tx, *refund)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sendErr != nil {
+		return errorx.Wrap(sendErr, errorx.CodeInternal, "refund cannot be sent")
+	}
+
+	return nil
+}
+
+// RetryRefunds sends up to limit refunds that are still pending after refundRetryDelay again, failures are logged and
+// tried again on a later call. Nothing is sent while no provider is configured. It returns how many refunds were sent.
+func (s *OrderService) RetryRefunds(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		return 0, errorx.ErrInvalidParameter("limit invalid")
+	}
+
+	if s.payments == nil {
+		return 0, nil
+	}
+
+	ids, err := s.repo.GetPendingPaymentRefunds(ctx, time.Now().Add(-refundRetryDelay), int64(limit))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if err = s.SendRefund(ctx, id); err != nil {
+			fmt.Println(errorx.ParseAndWrap(err, "cannot send refund").LogError())
+		}
+	}
+
+	return len(ids), nil
+}
+
+func orderStatusChange(orderID int64, from, to string, actorID int64, actorRole string) entity.OrderStatusChange {
+	change := entity.OrderStatusChange{
+		OrderID:   orderID,
+		From:      from,
+		To:        to,
+		ActorRole: actorRole,
+	}
+	if actorID != 0 {
+		change.ActorID = &actorID
+	}
+
+	return change
+}
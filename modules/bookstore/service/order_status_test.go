@@ -0,0 +1,439 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+)
+
+func (s *OrderServiceTestSuite) TestTransitionOrder() {
+	ctx := context.Background()
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
+	adminID := int64(1)
+
+	s.Run("unknown status", func() {
+		result, err := svc.TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: "lost", ActorID: adminID, ActorRole: entity.UserRoleAdmin,
+		})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("order not found", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(nil, errorx.ErrNotFound("order cannot be found")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: entity.OrderStatusFulfilling, ActorID: adminID, ActorRole: entity.UserRoleAdmin,
+		})
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("orders are not paid by hand", func() {
+		result, err := svc.TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: entity.OrderStatusPaid, ActorID: adminID, ActorRole: entity.UserRoleAdmin,
+		})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "order is paid once its payment is captured")
+	})
+
+	s.Run("transition not allowed", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, Status: entity.OrderStatusCancelled}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: entity.OrderStatusShipped, ActorID: adminID, ActorRole: entity.UserRoleAdmin,
+		})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "order cannot move from cancelled to shipped")
+	})
+
+	s.Run("records the change with its actor", func() {
+		history := []entity.OrderStatusChange{
+			{OrderID: 3, To: entity.OrderStatusPendingPayment, ActorRole: entity.UserRoleCustomer},
+			{OrderID: 3, From: entity.OrderStatusPendingPayment, To: entity.OrderStatusPaid, ActorRole: entity.OrderActorSystem},
+			{OrderID: 3, From: entity.OrderStatusPaid, To: entity.OrderStatusFulfilling, ActorID: &adminID, ActorRole: entity.UserRoleAdmin},
+		}
+		change := entity.OrderStatusChange{
+			OrderID:   3,
+			From:      entity.OrderStatusPaid,
+			To:        entity.OrderStatusFulfilling,
+			ActorID:   &adminID,
+			ActorRole: entity.UserRoleAdmin,
+		}
+
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusPaid, entity.OrderStatusFulfilling).
+			Return(&entity.Order{ID: 3, Status: entity.OrderStatusFulfilling}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, change).Return(&change, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).Return(history, nil).Times(1)
+
+		result, err := svc.TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: entity.OrderStatusFulfilling, ActorID: adminID, ActorRole: entity.UserRoleAdmin,
+		})
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Order{ID: 3, Status: entity.OrderStatusFulfilling, History: history}, result)
+	})
+
+	s.Run("refunded paid order is restocked and refunded after commit", func() {
+		change := entity.OrderStatusChange{
+			OrderID:   3,
+			From:      entity.OrderStatusPaid,
+			To:        entity.OrderStatusRefunded,
+			ActorID:   &adminID,
+			ActorRole: entity.UserRoleAdmin,
+		}
+		refund := entity.PaymentRefund{ID: 21, PaymentID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}
+
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusPaid, entity.OrderStatusRefunded).
+			Return(&entity.Order{ID: 3, Status: entity.OrderStatusRefunded}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, change).Return(&change, nil).Times(1)
+		s.repo.EXPECT().ReleaseOrderStock(ctx, s.tx, int64(3)).Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).
+			Return(&entity.Payment{ID: 11, OrderID: 3, Reference: "pi_3", Amount: 20000, RefundedAmount: 1000, Status: entity.PaymentStatusCaptured}, nil).Times(1)
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, entity.Payment{
+			ID:             11,
+			OrderID:        3,
+			Reference:      "pi_3",
+			Amount:         20000,
+			RefundedAmount: 20000,
+			Status:         entity.PaymentStatusRefunded,
+		}).Return(&entity.Payment{}, nil).Times(1)
+		s.repo.EXPECT().CreatePaymentRefund(ctx, s.tx, entity.PaymentRefund{PaymentID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}).
+			Return(&refund, nil).Times(1)
+
+		sent := refund
+		sent.Reference = "pi_3"
+		found := sent
+		commit := s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&found, nil).After(commit).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(nil).After(commit).Times(1)
+		sent.Status = entity.PaymentRefundStatusSucceeded
+		sent.Attempts = 1
+		s.repo.EXPECT().UpdatePaymentRefund(ctx, s.tx, sent).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).After(commit).Times(1)
+		s.repo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).Return([]entity.OrderStatusChange{change}, nil).Times(1)
+
+		result, err := svc.TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: entity.OrderStatusRefunded, ActorID: adminID, ActorRole: entity.UserRoleAdmin,
+		})
+		s.Assert().Nil(err)
+		s.Assert().Equal(entity.OrderStatusRefunded, result.Status)
+	})
+
+	s.Run("refunded delivered order is refunded without restocking", func() {
+		change := entity.OrderStatusChange{
+			OrderID:   3,
+			From:      entity.OrderStatusDelivered,
+			To:        entity.OrderStatusRefunded,
+			ActorID:   &adminID,
+			ActorRole: entity.UserRoleAdmin,
+		}
+		refund := entity.PaymentRefund{ID: 21, PaymentID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}
+
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, Status: entity.OrderStatusDelivered}, nil).Times(1)
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusDelivered, entity.OrderStatusRefunded).
+			Return(&entity.Order{ID: 3, Status: entity.OrderStatusRefunded}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, change).Return(&change, nil).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).
+			Return(&entity.Payment{ID: 11, OrderID: 3, Reference: "pi_3", Amount: 20000, RefundedAmount: 1000, Status: entity.PaymentStatusCaptured}, nil).Times(1)
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, entity.Payment{
+			ID:             11,
+			OrderID:        3,
+			Reference:      "pi_3",
+			Amount:         20000,
+			RefundedAmount: 20000,
+			Status:         entity.PaymentStatusRefunded,
+		}).Return(&entity.Payment{}, nil).Times(1)
+		s.repo.EXPECT().CreatePaymentRefund(ctx, s.tx, entity.PaymentRefund{PaymentID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}).
+			Return(&refund, nil).Times(1)
+
+		sent := refund
+		sent.Reference = "pi_3"
+		found := sent
+		commit := s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&found, nil).After(commit).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(nil).After(commit).Times(1)
+		sent.Status = entity.PaymentRefundStatusSucceeded
+		sent.Attempts = 1
+		s.repo.EXPECT().UpdatePaymentRefund(ctx, s.tx, sent).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).After(commit).Times(1)
+		s.repo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).Return([]entity.OrderStatusChange{change}, nil).Times(1)
+
+		result, err := svc.TransitionOrder(ctx, entity.TransitionOrderParams{
+			OrderID: 3, Status: entity.OrderStatusRefunded, ActorID: adminID, ActorRole: entity.UserRoleAdmin,
+		})
+		s.Assert().Nil(err)
+		s.Assert().Equal(entity.OrderStatusRefunded, result.Status)
+	})
+}
+
+func (s *OrderServiceTestSuite) TestCancelOrder() {
+	ctx := context.Background()
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
+	customerID := int64(123)
+	params := entity.CancelOrderParams{OrderID: 3, UserID: customerID}
+	change := entity.OrderStatusChange{
+		OrderID:   3,
+		From:      entity.OrderStatusPaid,
+		To:        entity.OrderStatusCancelled,
+		ActorID:   &customerID,
+		ActorRole: entity.UserRoleCustomer,
+	}
+	history := []entity.OrderStatusChange{change}
+	captured := entity.Payment{ID: 11, OrderID: 3, Reference: "pi_3", Amount: 20000, RefundedAmount: 1000, Status: entity.PaymentStatusCaptured}
+	refund := entity.PaymentRefund{ID: 21, PaymentID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}
+
+	s.Run("order of another customer", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 7, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.CancelOrder(ctx, params)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("order already shipped", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusShipped}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.CancelOrder(ctx, params)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "order is shipped and can no longer be cancelled")
+	})
+
+	s.Run("refund the provider fails stays pending and the order cancelled", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusPaid, entity.OrderStatusCancelled).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusCancelled}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, change).Return(&change, nil).Times(1)
+		s.repo.EXPECT().ReleaseOrderStock(ctx, s.tx, int64(3)).Return(int64(1), nil).Times(1)
+		payment := captured
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).Return(&payment, nil).Times(1)
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, gomock.Any()).Return(&entity.Payment{}, nil).Times(1)
+		s.repo.EXPECT().CreatePaymentRefund(ctx, s.tx, gomock.Any()).Return(&refund, nil).Times(1)
+		sent := refund
+		sent.Reference = "pi_3"
+		found := sent
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&found, nil).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(errors.New("provider down")).Times(1)
+		failed := sent
+		failed.Attempts = 1
+		failed.LastError = "provider down"
+		s.repo.EXPECT().UpdatePaymentRefund(ctx, s.tx, failed).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(2)
+		s.repo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).Return(history, nil).Times(1)
+
+		result, err := svc.CancelOrder(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusCancelled, History: history}, result)
+	})
+
+	s.Run("paid order is restocked and refunded", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusPaid, entity.OrderStatusCancelled).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusCancelled}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, change).Return(&change, nil).Times(1)
+		s.repo.EXPECT().ReleaseOrderStock(ctx, s.tx, int64(3)).Return(int64(1), nil).Times(1)
+		payment := captured
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).Return(&payment, nil).Times(1)
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, entity.Payment{
+			ID:             11,
+			OrderID:        3,
+			Reference:      "pi_3",
+			Amount:         20000,
+			RefundedAmount: 20000,
+			Status:         entity.PaymentStatusRefunded,
+		}).Return(&entity.Payment{}, nil).Times(1)
+		s.repo.EXPECT().CreatePaymentRefund(ctx, s.tx, entity.PaymentRefund{PaymentID: 11, Amount: 19000, Status: entity.PaymentRefundStatusPending}).
+			Return(&refund, nil).Times(1)
+		sent := refund
+		sent.Reference = "pi_3"
+		found := sent
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&found, nil).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(nil).Times(1)
+		succeeded := sent
+		succeeded.Status = entity.PaymentRefundStatusSucceeded
+		succeeded.Attempts = 1
+		s.repo.EXPECT().UpdatePaymentRefund(ctx, s.tx, succeeded).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(2)
+		s.repo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).Return(history, nil).Times(1)
+
+		result, err := svc.CancelOrder(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusCancelled, History: history}, result)
+	})
+
+	s.Run("order paid outside the store is restocked only", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusPaid, entity.OrderStatusCancelled).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusCancelled}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, change).Return(&change, nil).Times(1)
+		s.repo.EXPECT().ReleaseOrderStock(ctx, s.tx, int64(3)).Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).
+			Return(nil, errorx.ErrNotFound("payment cannot be found")).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).Return(history, nil).Times(1)
+
+		result, err := svc.CancelOrder(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(entity.OrderStatusCancelled, result.Status)
+	})
+
+	s.Run("unpaid order is restocked only", func() {
+		unpaid := change
+		unpaid.From = entity.OrderStatusPendingPayment
+
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusPendingPayment}, nil).Times(1)
+		s.repo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusPendingPayment, entity.OrderStatusCancelled).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusCancelled}, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, unpaid).Return(&unpaid, nil).Times(1)
+		s.repo.EXPECT().ReleaseOrderStock(ctx, s.tx, int64(3)).Return(int64(1), nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).Return([]entity.OrderStatusChange{unpaid}, nil).Times(1)
+
+		result, err := svc.CancelOrder(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(entity.OrderStatusCancelled, result.Status)
+	})
+
+	s.Run("cancelling again changes nothing", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusCancelled}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().GetOrderStatusChanges(ctx, []int64{3}).Return(history, nil).Times(1)
+
+		result, err := svc.CancelOrder(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Order{ID: 3, UserID: customerID, Status: entity.OrderStatusCancelled, History: history}, result)
+	})
+}
+
+func (s *OrderServiceTestSuite) TestSendRefund() {
+	ctx := context.Background()
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
+	pending := entity.PaymentRefund{ID: 21, PaymentID: 11, Reference: "pi_3", Amount: 19000, Status: entity.PaymentRefundStatusPending, Attempts: 1}
+
+	s.Run("no provider configured", func() {
+		withoutProvider := service.NewOrderService(s.repo, s.txFunc, nil, service.DefaultOrderLimits)
+
+		goxErr, ok := errorx.Parse(withoutProvider.SendRefund(ctx, 21))
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("refund sent already or held by another sender", func() {
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).
+			Return(nil, errorx.ErrNotFound("refund cannot be found")).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.Assert().Nil(svc.SendRefund(ctx, 21))
+	})
+
+	s.Run("provider fails", func() {
+		refund := pending
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&refund, nil).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(errors.New("provider down")).Times(1)
+		failed := pending
+		failed.Attempts = 2
+		failed.LastError = "provider down"
+		s.repo.EXPECT().UpdatePaymentRefund(ctx, s.tx, failed).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		goxErr, ok := errorx.Parse(svc.SendRefund(ctx, 21))
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("sent again after a failure", func() {
+		refund := pending
+		refund.LastError = "provider down"
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&refund, nil).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(nil).Times(1)
+		succeeded := pending
+		succeeded.Attempts = 2
+		succeeded.Status = entity.PaymentRefundStatusSucceeded
+		s.repo.EXPECT().UpdatePaymentRefund(ctx, s.tx, succeeded).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.Assert().Nil(svc.SendRefund(ctx, 21))
+	})
+}
+
+func (s *OrderServiceTestSuite) TestRetryRefunds() {
+	ctx := context.Background()
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
+
+	s.Run("invalid limit", func() {
+		result, err := svc.RetryRefunds(ctx, 0)
+		s.Assert().Zero(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("no provider configured", func() {
+		withoutProvider := service.NewOrderService(s.repo, s.txFunc, nil, service.DefaultOrderLimits)
+
+		result, err := withoutProvider.RetryRefunds(ctx, 10)
+		s.Assert().Nil(err)
+		s.Assert().Zero(result)
+	})
+
+	s.Run("only refunds left alone for a while are sent again", func() {
+		s.repo.EXPECT().GetPendingPaymentRefunds(ctx, gomock.Any(), int64(10)).
+			DoAndReturn(func(_ context.Context, before time.Time, _ int64) ([]int64, error) {
+				s.Assert().WithinDuration(time.Now().Add(-time.Minute), before, time.Second)
+				return []int64{21, 22}, nil
+			}).Times(1)
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).
+			Return(&entity.PaymentRefund{ID: 21, Reference: "pi_3", Amount: 19000, Status: entity.PaymentRefundStatusPending}, nil).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(errors.New("provider down")).Times(1)
+		s.repo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(22)).
+			Return(&entity.PaymentRefund{ID: 22, Reference: "pi_4", Amount: 500, Status: entity.PaymentRefundStatusPending}, nil).Times(1)
+		s.payments.EXPECT().Refund(ctx, "pi_4", "refund-22", int64(500)).Return(nil).Times(1)
+		s.repo.EXPECT().UpdatePaymentRefund(ctx, s.tx, gomock.Any()).Return(nil).Times(2)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(2)
+
+		result, err := svc.RetryRefunds(ctx, 10)
+		s.Assert().Nil(err)
+		s.Assert().Equal(2, result)
+	})
+}
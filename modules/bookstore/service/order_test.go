@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -11,7 +12,9 @@ import (
 	"github.com/raymondwongso/gogox/errorx"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/pagination"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
 	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
 	mock_repository "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/repository"
@@ -21,14 +24,18 @@ import (
 type OrderServiceTestSuite struct {
 	suite.Suite
 
-	repo   *mock_service.MockOrderRepository
-	txFunc repository.TxStarter
-	tx     *mock_repository.MockTransactionable
+	repo     *mock_service.MockOrderRepository
+	payments *mock_service.MockPaymentProvider
+	keys     *mock_service.MockIdempotencyKeyRepository
+	txFunc   repository.TxStarter
+	tx       *mock_repository.MockTransactionable
 }
 
 func (s *OrderServiceTestSuite) SetupSuite() {
 	ctrl := gomock.NewController(s.T())
 	s.repo = mock_service.NewMockOrderRepository(ctrl)
+	s.payments = mock_service.NewMockPaymentProvider(ctrl)
+	s.keys = mock_service.NewMockIdempotencyKeyRepository(ctrl)
 	s.tx = mock_repository.NewMockTransactionable(ctrl)
 	s.txFunc = func(ctx context.Context) (pgx.Tx, error) {
 		return s.tx, nil
@@ -41,7 +48,7 @@ func TestOrderServiceRepo(t *testing.T) {
 
 func (s *OrderServiceTestSuite) TestGetOrders() {
 	ctx := context.Background()
-	svc := service.NewOrderService(s.repo, s.txFunc)
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
 	now := time.Now()
 
 	svcParams := entity.GetMyOrdersParams{
@@ -49,6 +56,11 @@ func (s *OrderServiceTestSuite) TestGetOrders() {
 		Limit:  10,
 		Offset: 0,
 	}
+	repoParams := entity.GetMyOrdersParams{
+		UserID: 123,
+		Limit:  11,
+		Offset: 0,
+	}
 
 	rowFromDB := []entity.Order{
 		{
@@ -81,7 +93,7 @@ func (s *OrderServiceTestSuite) TestGetOrders() {
 	})
 
 	s.Run("get order repo error", func() {
-		s.repo.EXPECT().GetMyOrders(ctx, svcParams).
+		s.repo.EXPECT().GetMyOrders(ctx, repoParams).
 			Return(nil, errors.New("repo error")).Times(1)
 
 		result, err := svc.GetOrders(ctx, svcParams)
@@ -90,18 +102,117 @@ func (s *OrderServiceTestSuite) TestGetOrders() {
 	})
 
 	s.Run("get order success", func() {
-		s.repo.EXPECT().GetMyOrders(ctx, svcParams).
+		s.repo.EXPECT().GetMyOrders(ctx, repoParams).
 			Return(rowFromDB, nil).Times(1)
 
 		result, err := svc.GetOrders(ctx, svcParams)
 		s.Assert().Nil(err)
-		s.Assert().Equal(int64(99), result[0].Items[0].BookID)
+		s.Assert().Equal(int64(99), result.Data[0].Items[0].BookID)
+		s.Assert().Empty(result.NextCursor)
+	})
+
+	s.Run("get order invalid cursor", func() {
+		result, err := svc.GetOrders(ctx, entity.GetMyOrdersParams{
+			UserID: 123,
+			Cursor: "garbage",
+			Limit:  10,
+		})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "cursor invalid")
+	})
+
+	s.Run("get order with cursor continues before the last seen order", func() {
+		cursor := pagination.Cursor{ID: 40}.Encode()
+		s.repo.EXPECT().GetMyOrders(ctx, entity.GetMyOrdersParams{
+			UserID:   123,
+			Cursor:   cursor,
+			BeforeID: 40,
+			Limit:    2,
+		}).Return([]entity.Order{{ID: 39}, {ID: 35}}, nil).Times(1)
+
+		result, err := svc.GetOrders(ctx, entity.GetMyOrdersParams{
+			UserID: 123,
+			Cursor: cursor,
+			Limit:  1,
+		})
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.Order{{ID: 39}}, result.Data)
+		s.Assert().True(result.HasMore)
+		s.Assert().Equal(pagination.Cursor{ID: 39}.Encode(), result.NextCursor)
+	})
+}
+
+func (s *OrderServiceTestSuite) TestGetOrder() {
+	ctx := context.Background()
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
+
+	s.Run("get order validation error", func() {
+		result, err := svc.GetOrder(ctx, entity.GetOrderParams{OrderID: 0, UserID: 123})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("order of another customer", func() {
+		s.repo.EXPECT().FindOrder(ctx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 7}, nil).Times(1)
+
+		result, err := svc.GetOrder(ctx, entity.GetOrderParams{OrderID: 3, UserID: 123})
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("get order success", func() {
+		order := &entity.Order{ID: 3, UserID: 123, Status: entity.OrderStatusPaid}
+		s.repo.EXPECT().FindOrder(ctx, int64(3)).
+			Return(order, nil).Times(1)
+
+		result, err := svc.GetOrder(ctx, entity.GetOrderParams{OrderID: 3, UserID: 123})
+		s.Assert().Nil(err)
+		s.Assert().Equal(order, result)
+	})
+}
+
+func (s *OrderServiceTestSuite) TestCountOrders() {
+	ctx := context.Background()
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
+
+	s.Run("count orders without user", func() {
+		result, err := svc.CountOrders(ctx, 0)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().EqualError(goxErr, "Input is invalid")
+	})
+
+	s.Run("count orders repo error", func() {
+		s.repo.EXPECT().CountMyOrders(ctx, int64(123)).
+			Return(int64(0), errors.New("repo error")).Times(1)
+
+		result, err := svc.CountOrders(ctx, 123)
+		s.Assert().Nil(result)
+		s.Assert().Contains(err.Error(), "repo error")
+	})
+
+	s.Run("count orders success", func() {
+		s.repo.EXPECT().CountMyOrders(ctx, int64(123)).
+			Return(int64(7), nil).Times(1)
+
+		result, err := svc.CountOrders(ctx, 123)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.TotalCount{Count: 7}, result)
 	})
 }
 
 func (s *OrderServiceTestSuite) TestCreateOrder() {
 	ctx := context.Background()
-	svc := service.NewOrderService(s.repo, s.txFunc)
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.DefaultOrderLimits)
 	now := time.Now()
 
 	svcParams := entity.CreateOrderParams{
@@ -114,16 +225,32 @@ func (s *OrderServiceTestSuite) TestCreateOrder() {
 		},
 	}
 
+	// the order holds its stock for the configured time
+	repoParams := svcParams
+	repoParams.ReservationTTL = service.DefaultOrderLimits.ReservationTTL
+
 	rowFromDB := &entity.Order{
 		ID:        1,
 		UserID:    123,
 		Email:     "someone@test.com",
+		Status:    entity.OrderStatusPendingPayment,
 		CreatedAt: now,
 	}
 
+	customerID := int64(123)
+	createdChange := entity.OrderStatusChange{
+		OrderID:   1,
+		To:        entity.OrderStatusPendingPayment,
+		ActorID:   &customerID,
+		ActorRole: entity.UserRoleCustomer,
+		CreatedAt: now,
+	}
+
+	book := &entity.Book{ID: 99, SKU: "BK00000099", Status: entity.BookStatusActive}
+
 	itemParams := entity.CreateOrderItemParams{
 		OrderID: rowFromDB.ID,
-		BookID:  svcParams.Items[0].BookID,
+		SKU:     book.SKU,
 		Amount:  svcParams.Items[0].Amount,
 	}
 
@@ -131,6 +258,7 @@ func (s *OrderServiceTestSuite) TestCreateOrder() {
 		ID:        29,
 		OrderID:   rowFromDB.ID,
 		BookID:    svcParams.Items[0].BookID,
+		SKU:       book.SKU,
 		Amount:    svcParams.Items[0].Amount,
 		CreatedAt: now,
 	}
@@ -139,15 +267,18 @@ func (s *OrderServiceTestSuite) TestCreateOrder() {
 		ID:     1,
 		UserID: 123,
 		Email:  "someone@test.com",
+		Status: entity.OrderStatusPendingPayment,
 		Items: []entity.OrderItem{
 			{
 				ID:        29,
 				OrderID:   rowFromDB.ID,
 				BookID:    svcParams.Items[0].BookID,
+				SKU:       book.SKU,
 				Amount:    svcParams.Items[0].Amount,
 				CreatedAt: now,
 			},
 		},
+		History:   []entity.OrderStatusChange{createdChange},
 		CreatedAt: now,
 	}
 
@@ -192,7 +323,7 @@ func (s *OrderServiceTestSuite) TestCreateOrder() {
 
 		goxErr, ok := errorx.Parse(err)
 		s.Require().True(ok)
-		s.Assert().EqualError(goxErr, "Input is invalid")
+		s.Assert().EqualError(goxErr, "items[0]: sku or book_id is required")
 	})
 
 	s.Run("create order fail to find book", func() {
@@ -207,7 +338,39 @@ func (s *OrderServiceTestSuite) TestCreateOrder() {
 
 		goxErr, ok := errorx.Parse(err)
 		s.Require().True(ok)
-		s.Assert().EqualError(goxErr, "book not found")
+		s.Assert().EqualError(goxErr, "items[0]: book not found")
+	})
+
+	s.Run("create order with discontinued book", func() {
+		s.tx.EXPECT().Begin(ctx).Return(s.tx, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		s.repo.EXPECT().FindBook(ctx, s.tx, svcParams.Items[0].BookID).
+			Return(&entity.Book{ID: book.ID, SKU: book.SKU, Status: entity.BookStatusDiscontinued}, nil).Times(1)
+
+		result, err := svc.CreateOrder(ctx, svcParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, fmt.Sprintf("items[0]: book %s has been discontinued", book.SKU))
+	})
+
+	s.Run("create order with hidden book", func() {
+		s.tx.EXPECT().Begin(ctx).Return(s.tx, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		s.repo.EXPECT().FindBook(ctx, s.tx, svcParams.Items[0].BookID).
+			Return(&entity.Book{ID: book.ID, SKU: book.SKU, Status: entity.BookStatusHidden}, nil).Times(1)
+
+		result, err := svc.CreateOrder(ctx, svcParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeNotFound, goxErr.Code)
+		s.Assert().EqualError(goxErr, "items[0]: book cannot be found")
 	})
 
 	s.Run("create order repo error", func() {
@@ -215,8 +378,8 @@ func (s *OrderServiceTestSuite) TestCreateOrder() {
 		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
 
 		s.repo.EXPECT().FindBook(ctx, s.tx, svcParams.Items[0].BookID).
-			Return(&entity.Book{}, nil).Times(1)
-		s.repo.EXPECT().CreateOrder(ctx, s.tx, svcParams).
+			Return(book, nil).Times(1)
+		s.repo.EXPECT().CreateOrder(ctx, s.tx, repoParams).
 			Return(nil, errors.New("repo error")).Times(1)
 
 		result, err := svc.CreateOrder(ctx, svcParams)
@@ -229,8 +392,8 @@ func (s *OrderServiceTestSuite) TestCreateOrder() {
 		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
 
 		s.repo.EXPECT().FindBook(ctx, s.tx, svcParams.Items[0].BookID).
-			Return(&entity.Book{}, nil).Times(1)
-		s.repo.EXPECT().CreateOrder(ctx, s.tx, svcParams).
+			Return(book, nil).Times(1)
+		s.repo.EXPECT().CreateOrder(ctx, s.tx, repoParams).
 			Return(rowFromDB, nil).Times(1)
 		s.repo.EXPECT().CreateOrderItem(ctx, s.tx, itemParams).
 			Return(nil, errors.New("repo error")).Times(1)
@@ -240,19 +403,187 @@ func (s *OrderServiceTestSuite) TestCreateOrder() {
 		s.Assert().Contains(err.Error(), "repo error")
 	})
 
+	s.Run("create order out of stock", func() {
+		rowFromDB := &entity.Order{ID: 1, UserID: 123, Status: entity.OrderStatusPendingPayment, CreatedAt: now}
+
+		s.tx.EXPECT().Begin(ctx).Return(s.tx, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		s.repo.EXPECT().FindBook(ctx, s.tx, svcParams.Items[0].BookID).
+			Return(book, nil).Times(1)
+		s.repo.EXPECT().CreateOrder(ctx, s.tx, repoParams).
+			Return(rowFromDB, nil).Times(1)
+		s.repo.EXPECT().CreateOrderItem(ctx, s.tx, itemParams).
+			Return(rowOrderItemFromDB, nil).Times(1)
+		s.repo.EXPECT().DecrementBookStock(ctx, s.tx, book.SKU, int64(1)).
+			Return(int64(0), nil).Times(1)
+
+		result, err := svc.CreateOrder(ctx, svcParams)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, fmt.Sprintf("book %s is out of stock", book.SKU))
+	})
+
 	s.Run("create order success", func() {
 		s.tx.EXPECT().Begin(ctx).Return(s.tx, nil).Times(1)
 		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
 
-		s.repo.EXPECT().CreateOrder(ctx, s.tx, svcParams).
+		s.repo.EXPECT().CreateOrder(ctx, s.tx, repoParams).
 			Return(rowFromDB, nil).Times(1)
 		s.repo.EXPECT().CreateOrderItem(ctx, s.tx, itemParams).
 			Return(rowOrderItemFromDB, nil).Times(1)
+		s.repo.EXPECT().DecrementBookStock(ctx, s.tx, book.SKU, int64(1)).
+			Return(int64(1), nil).Times(1)
 		s.repo.EXPECT().FindBook(ctx, s.tx, svcParams.Items[0].BookID).
-			Return(&entity.Book{}, nil).Times(1)
+			Return(book, nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, entity.OrderStatusChange{
+			OrderID:   1,
+			To:        entity.OrderStatusPendingPayment,
+			ActorID:   &customerID,
+			ActorRole: entity.UserRoleCustomer,
+		}).Return(&createdChange, nil).Times(1)
 
 		result, err := svc.CreateOrder(ctx, svcParams)
 		s.Assert().Nil(err)
 		s.Assert().Equal(expectedOrder, result)
 	})
+
+	s.Run("create order by sku", func() {
+		bySKU := entity.CreateOrderParams{
+			UserID:         123,
+			Items:          []entity.CreateOrderItemParams{{SKU: book.SKU, Amount: 1}},
+			ReservationTTL: service.DefaultOrderLimits.ReservationTTL,
+		}
+		rowFromDB := &entity.Order{ID: 1, UserID: 123, Email: "someone@test.com", Status: entity.OrderStatusPendingPayment, CreatedAt: now}
+
+		s.tx.EXPECT().Begin(ctx).Return(s.tx, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, book.SKU).
+			Return(book, nil).Times(1)
+		s.repo.EXPECT().CreateOrder(ctx, s.tx, bySKU).
+			Return(rowFromDB, nil).Times(1)
+		s.repo.EXPECT().CreateOrderItem(ctx, s.tx, itemParams).
+			Return(rowOrderItemFromDB, nil).Times(1)
+		s.repo.EXPECT().DecrementBookStock(ctx, s.tx, book.SKU, int64(1)).
+			Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, gomock.Any()).
+			Return(&createdChange, nil).Times(1)
+
+		result, err := svc.CreateOrder(ctx, bySKU)
+		s.Assert().Nil(err)
+		s.Assert().Equal(expectedOrder, result)
+	})
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrderLines() {
+	ctx := context.Background()
+	svc := service.NewOrderService(s.repo, s.txFunc, s.payments, service.OrderLimits{
+		MaxLineQuantity:  5,
+		MaxOrderQuantity: 8,
+		MaxLines:         2,
+	})
+	dune := &entity.Book{ID: 99, SKU: "BK00000099", Status: entity.BookStatusActive}
+	emma := &entity.Book{ID: 27, SKU: "BK00000027", Status: entity.BookStatusActive}
+	order := func() *entity.Order {
+		return &entity.Order{ID: 1, UserID: 123, Status: entity.OrderStatusPendingPayment}
+	}
+
+	expectRejected := func(params entity.CreateOrderParams, code, message string) {
+		s.tx.EXPECT().Begin(ctx).Return(s.tx, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := svc.CreateOrder(ctx, params)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(code, goxErr.Code)
+		s.Assert().EqualError(goxErr, message)
+	}
+
+	s.Run("order without items", func() {
+		expectRejected(entity.CreateOrderParams{UserID: 123}, errorx.CodeInvalidParameter, "order has no items")
+	})
+
+	s.Run("invalid item names its index", func() {
+		s.repo.EXPECT().FindBook(ctx, s.tx, dune.ID).Return(dune, nil).Times(1)
+
+		expectRejected(entity.CreateOrderParams{
+			UserID: 123,
+			Items:  []entity.CreateOrderItemParams{{BookID: dune.ID, Amount: 1}, {SKU: emma.SKU, Amount: -1}},
+		}, errorx.CodeInvalidParameter, "items[1]: amount is invalid")
+	})
+
+	s.Run("duplicate lines are merged", func() {
+		params := entity.CreateOrderParams{
+			UserID: 123,
+			Items: []entity.CreateOrderItemParams{
+				{BookID: dune.ID, Amount: 2},
+				{SKU: emma.SKU, Amount: 1},
+				{SKU: dune.SKU, Amount: 1},
+				{BookID: dune.ID, Amount: 2},
+			},
+		}
+		duneLine := entity.CreateOrderItemParams{OrderID: 1, SKU: dune.SKU, Amount: 5}
+		emmaLine := entity.CreateOrderItemParams{OrderID: 1, SKU: emma.SKU, Amount: 1}
+
+		s.tx.EXPECT().Begin(ctx).Return(s.tx, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.repo.EXPECT().FindBook(ctx, s.tx, dune.ID).Return(dune, nil).Times(1)
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, emma.SKU).Return(emma, nil).Times(1)
+		s.repo.EXPECT().CreateOrder(ctx, s.tx, params).Return(order(), nil).Times(1)
+		gomock.InOrder(
+			s.repo.EXPECT().CreateOrderItem(ctx, s.tx, duneLine).
+				Return(&entity.OrderItem{ID: 1, OrderID: 1, BookID: dune.ID, SKU: dune.SKU, Amount: 5}, nil).Times(1),
+			s.repo.EXPECT().CreateOrderItem(ctx, s.tx, emmaLine).
+				Return(&entity.OrderItem{ID: 2, OrderID: 1, BookID: emma.ID, SKU: emma.SKU, Amount: 1}, nil).Times(1),
+		)
+		s.repo.EXPECT().DecrementBookStock(ctx, s.tx, dune.SKU, int64(5)).Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().DecrementBookStock(ctx, s.tx, emma.SKU, int64(1)).Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().CreateOrderStatusChange(ctx, s.tx, gomock.Any()).
+			Return(&entity.OrderStatusChange{OrderID: 1, To: entity.OrderStatusPendingPayment}, nil).Times(1)
+
+		result, err := svc.CreateOrder(ctx, params)
+		s.Require().Nil(err)
+		s.Assert().Len(result.Items, 2)
+	})
+
+	s.Run("too many copies of one book", func() {
+		s.repo.EXPECT().FindBook(ctx, s.tx, dune.ID).Return(dune, nil).Times(1)
+
+		expectRejected(entity.CreateOrderParams{
+			UserID: 123,
+			Items:  []entity.CreateOrderItemParams{{BookID: dune.ID, Amount: 4}, {BookID: dune.ID, Amount: 2}},
+		}, customerror.CodeUnprocessableEntity, "items[1]: an order cannot have more than 5 copies of book BK00000099")
+	})
+
+	s.Run("too many books in total", func() {
+		s.repo.EXPECT().FindBook(ctx, s.tx, dune.ID).Return(dune, nil).Times(1)
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, emma.SKU).Return(emma, nil).Times(1)
+
+		expectRejected(entity.CreateOrderParams{
+			UserID: 123,
+			Items:  []entity.CreateOrderItemParams{{BookID: dune.ID, Amount: 5}, {SKU: emma.SKU, Amount: 4}},
+		}, customerror.CodeUnprocessableEntity, "items[1]: an order cannot have more than 8 books in total")
+	})
+
+	s.Run("too many different books", func() {
+		s.repo.EXPECT().FindBook(ctx, s.tx, dune.ID).Return(dune, nil).Times(1)
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, emma.SKU).Return(emma, nil).Times(1)
+		s.repo.EXPECT().FindBookBySKU(ctx, s.tx, "BK00000031").
+			Return(&entity.Book{ID: 31, SKU: "BK00000031", Status: entity.BookStatusActive}, nil).Times(1)
+
+		expectRejected(entity.CreateOrderParams{
+			UserID: 123,
+			Items: []entity.CreateOrderItemParams{
+				{BookID: dune.ID, Amount: 1},
+				{SKU: emma.SKU, Amount: 1},
+				{SKU: "BK00000031", Amount: 1},
+			},
+		}, customerror.CodeUnprocessableEntity, "items[2]: an order cannot have more than 2 different books")
+	})
 }
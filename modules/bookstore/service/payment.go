@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+)
+
+// PaymentService takes payments for orders through the PaymentProvider and keeps every attempt for reconciliation. An
+// order is only paid once the provider reported an authorized payment on the webhook and the payment was captured.
+type PaymentService struct {
+	repo      PaymentRepository
+	orders    *OrderService
+	provider  PaymentProvider
+	validator *validator.Validate
+	txStarter repository.TxStarter
+}
+
+func NewPaymentService(repo PaymentRepository, orders *OrderService, provider PaymentProvider, txStarter repository.TxStarter) *PaymentService {
+	return &PaymentService{
+		repo:      repo,
+		orders:    orders,
+		provider:  provider,
+		validator: validator.New(),
+		txStarter: txStarter,
+	}
+}
+
+// PayOrder prepares a payment of the order total with the provider for an order of the customer that awaits payment.
+// An earlier attempt still waiting for the customer is failed, so at most one attempt of an order can be captured.
+func (s *PaymentService) PayOrder(ctx context.Context, params entity.PayOrderParams) (*entity.Payment, error) {
+	var err error
+	if err = s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var order *entity.Order
+	order, err = s.repo.FindOrderForUpdate(ctx, tx, params.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ownedOrder(order, params.UserID); err != nil {
+		return nil, err
+	}
+
+	if order.Status != entity.OrderStatusPendingPayment {
+		err = customerror.ErrUnprocessableEntity(fmt.Sprintf("order is %s and cannot be paid", order.Status))
+		return nil, err
+	}
+
+	var previous *entity.Payment
+	previous, err = s.repo.FindOrderPayment(ctx, tx, params.OrderID, entity.PaymentStatusPending)
+	if err != nil && !customerror.IsErrNotFound(err) {
+		return nil, err
+	}
+	if previous != nil {
+		previous.Status = entity.PaymentStatusFailed
+		previous.FailureReason = "replaced by a later attempt"
+		_, err = s.repo.UpdatePaymentAttempt(ctx, tx, *previous)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the order is paid by the attempt being captured, once the capture went through
+	var capturing *entity.Payment
+	capturing, err = s.repo.FindOrderPayment(ctx, tx, params.OrderID, entity.PaymentStatusCapturing)
+	if err != nil && !customerror.IsErrNotFound(err) {
+		return nil, err
+	}
+	if capturing != nil {
+		err = customerror.ErrUnprocessableEntity("order payment is being captured")
+		return nil, err
+	}
+
+	var total int64
+	total, err = s.repo.GetOrderTotal(ctx, tx, params.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var intent *entity.PaymentIntent
+	intent, err = s.provider.CreateIntent(ctx, entity.PaymentIntentParams{
+		OrderID: params.OrderID,
+		Amount:  total,
+	})
+	if err != nil {
+		err = errorx.Wrap(err, errorx.CodeInternal, "payment cannot be started")
+		return nil, err
+	}
+
+	var payment *entity.Payment
+	payment, err = s.repo.CreatePaymentAttempt(ctx, tx, entity.Payment{
+		OrderID:   params.OrderID,
+		Provider:  s.provider.Name(),
+		Reference: intent.Reference,
+		Amount:    total,
+		Status:    entity.PaymentStatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payment.ClientSecret = intent.ClientSecret
+	return payment, nil
+}
+
+// HandleWebhook applies a payment event the provider sent. An authorized payment of an order still awaiting payment is
+// marked capturing and captured once that committed, so the provider is never called while the order is locked. Any
+// other authorization is left to lapse. Events about attempts that were handled before are ignored, an attempt still
+// capturing is captured again, so the provider may deliver an event more than once.
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeUnauthorized, "webhook signature invalid")
+	}
+
+	if event.Type != entity.PaymentEventAuthorized && event.Type != entity.PaymentEventFailed {
+		return nil
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var payment *entity.Payment
+	payment, err = s.repo.FindPaymentAttempt(ctx, tx, s.provider.Name(), event.Reference)
+	if err != nil {
+		return err
+	}
+
+	// attempts change under the lock of their order, read the attempt again once it is held
+	var order *entity.Order
+	order, err = s.repo.FindOrderForUpdate(ctx, tx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+
+	payment, err = s.repo.FindPaymentAttempt(ctx, tx, s.provider.Name(), event.Reference)
+	if err != nil {
+		return err
+	}
+
+	if payment.Status != entity.PaymentStatusPending && payment.Status != entity.PaymentStatusCapturing {
+		err = tx.Commit(ctx)
+		return err
+	}
+
+	switch {
+	case event.Type == entity.PaymentEventFailed:
+		payment.Status = entity.PaymentStatusFailed
+		payment.FailureReason = event.Reason
+	case event.Amount != payment.Amount:
+		payment.Status = entity.PaymentStatusFailed
+		payment.FailureReason = fmt.Sprintf("authorized %d instead of %d", event.Amount, payment.Amount)
+	case order.Status != entity.OrderStatusPendingPayment:
+		payment.Status = entity.PaymentStatusFailed
+		payment.FailureReason = fmt.Sprintf("order is %s", order.Status)
+	default:
+		payment.Status = entity.PaymentStatusCapturing
+	}
+
+	_, err = s.repo.UpdatePaymentAttempt(ctx, tx, *payment)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil || payment.Status != entity.PaymentStatusCapturing {
+		return err
+	}
+
+	return s.capturePayment(ctx, *payment)
+}
+
+// capturePayment captures an attempt marked capturing with the provider, then records it and moves the order to paid.
+// A failed capture leaves the attempt capturing for the provider to deliver the event again. An order that was
+// cancelled while the capture was in flight gets the payment refunded instead.
+func (s *PaymentService) capturePayment(ctx context.Context, payment entity.Payment) error {
+	err := s.provider.Capture(ctx, payment.Reference)
+	if err != nil {
+		return errorx.Wrap(err, errorx.CodeInternal, "payment cannot be captured")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var order *entity.Order
+	order, err = s.repo.FindOrderForUpdate(ctx, tx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+
+	var captured *entity.Payment
+	captured, err = s.repo.FindPaymentAttempt(ctx, tx, payment.Provider, payment.Reference)
+	if err != nil {
+		return err
+	}
+
+	// another delivery of the event recorded the capture already
+	if captured.Status != entity.PaymentStatusCapturing {
+		err = tx.Commit(ctx)
+		return err
+	}
+	captured.Status = entity.PaymentStatusCaptured
+
+	var refund *entity.PaymentRefund
+	if order.Status == entity.OrderStatusPendingPayment {
+		_, err = s.repo.UpdatePaymentAttempt(ctx, tx, *captured)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = s.orders.applyOrderTransition(ctx, tx, order, entity.TransitionOrderParams{
+			OrderID:   order.ID,
+			Status:    entity.OrderStatusPaid,
+			ActorRole: entity.OrderActorSystem,
+		})
+	} else {
+		refund, err = s.orders.refundPayment(ctx, tx, *captured, captured.Amount)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.orders.sendRecordedRefund(ctx, refund)
+	return nil
+}
@@ -0,0 +1,335 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+	mock_repository "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/repository"
+	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
+)
+
+type PaymentServiceTestSuite struct {
+	suite.Suite
+
+	repo      *mock_service.MockPaymentRepository
+	orderRepo *mock_service.MockOrderRepository
+	provider  *mock_service.MockPaymentProvider
+	txFunc    repository.TxStarter
+	tx        *mock_repository.MockTransactionable
+	svc       *service.PaymentService
+}
+
+func (s *PaymentServiceTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_service.NewMockPaymentRepository(ctrl)
+	s.orderRepo = mock_service.NewMockOrderRepository(ctrl)
+	s.provider = mock_service.NewMockPaymentProvider(ctrl)
+	s.tx = mock_repository.NewMockTransactionable(ctrl)
+	s.txFunc = func(ctx context.Context) (pgx.Tx, error) {
+		return s.tx, nil
+	}
+
+	s.provider.EXPECT().Name().Return("mock").AnyTimes()
+
+	orders := service.NewOrderService(s.orderRepo, s.txFunc, s.provider, service.DefaultOrderLimits)
+	s.svc = service.NewPaymentService(s.repo, orders, s.provider, s.txFunc)
+}
+
+func TestPaymentService(t *testing.T) {
+	suite.Run(t, new(PaymentServiceTestSuite))
+}
+
+func (s *PaymentServiceTestSuite) TestPayOrder() {
+	ctx := context.Background()
+	params := entity.PayOrderParams{OrderID: 3, UserID: 5}
+	pending := &entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment}
+
+	s.Run("invalid params", func() {
+		result, err := s.svc.PayOrder(ctx, entity.PayOrderParams{OrderID: 3})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("order of another customer", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 7, Status: entity.OrderStatusPendingPayment}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.PayOrder(ctx, params)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("order already paid", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.PayOrder(ctx, params)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "order is paid and cannot be paid")
+	})
+
+	s.Run("payment being captured", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(pending, nil).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusPending).
+			Return(nil, errorx.ErrNotFound("payment cannot be found")).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCapturing).
+			Return(&entity.Payment{ID: 10, OrderID: 3, Reference: "pi_old", Amount: 20000, Status: entity.PaymentStatusCapturing}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.PayOrder(ctx, params)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "order payment is being captured")
+	})
+
+	s.Run("provider error", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(pending, nil).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusPending).
+			Return(nil, errorx.ErrNotFound("payment cannot be found")).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCapturing).
+			Return(nil, errorx.ErrNotFound("payment cannot be found")).Times(1)
+		s.repo.EXPECT().GetOrderTotal(ctx, s.tx, int64(3)).Return(int64(20000), nil).Times(1)
+		s.provider.EXPECT().CreateIntent(ctx, entity.PaymentIntentParams{OrderID: 3, Amount: 20000}).
+			Return(nil, errors.New("provider down")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.PayOrder(ctx, params)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("earlier attempt is replaced", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(pending, nil).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusPending).
+			Return(&entity.Payment{ID: 10, OrderID: 3, Reference: "pi_old", Amount: 20000, Status: entity.PaymentStatusPending}, nil).Times(1)
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, entity.Payment{
+			ID:            10,
+			OrderID:       3,
+			Reference:     "pi_old",
+			Amount:        20000,
+			Status:        entity.PaymentStatusFailed,
+			FailureReason: "replaced by a later attempt",
+		}).Return(&entity.Payment{}, nil).Times(1)
+		s.repo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCapturing).
+			Return(nil, errorx.ErrNotFound("payment cannot be found")).Times(1)
+		s.repo.EXPECT().GetOrderTotal(ctx, s.tx, int64(3)).Return(int64(20000), nil).Times(1)
+		s.provider.EXPECT().CreateIntent(ctx, entity.PaymentIntentParams{OrderID: 3, Amount: 20000}).
+			Return(&entity.PaymentIntent{Reference: "pi_new", ClientSecret: "pi_new_secret"}, nil).Times(1)
+		s.repo.EXPECT().CreatePaymentAttempt(ctx, s.tx, entity.Payment{
+			OrderID:   3,
+			Provider:  "mock",
+			Reference: "pi_new",
+			Amount:    20000,
+			Status:    entity.PaymentStatusPending,
+		}).Return(&entity.Payment{ID: 11, OrderID: 3, Provider: "mock", Reference: "pi_new", Amount: 20000, Status: entity.PaymentStatusPending}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.PayOrder(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Payment{
+			ID:           11,
+			OrderID:      3,
+			Provider:     "mock",
+			Reference:    "pi_new",
+			Amount:       20000,
+			Status:       entity.PaymentStatusPending,
+			ClientSecret: "pi_new_secret",
+		}, result)
+	})
+}
+
+func (s *PaymentServiceTestSuite) TestHandleWebhook() {
+	ctx := context.Background()
+	payload := []byte(`{"type":"payment.authorized","reference":"pi_3","amount":20000}`)
+	authorized := &entity.PaymentEvent{Type: entity.PaymentEventAuthorized, Reference: "pi_3", Amount: 20000}
+	attempt := func(status string) *entity.Payment {
+		return &entity.Payment{ID: 11, OrderID: 3, Provider: "mock", Reference: "pi_3", Amount: 20000, Status: status}
+	}
+	expectLocked := func(order *entity.Order, payment *entity.Payment) {
+		s.repo.EXPECT().FindPaymentAttempt(ctx, s.tx, "mock", "pi_3").Return(payment, nil).Times(2)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(order, nil).Times(1)
+	}
+
+	s.Run("invalid signature", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "forged").
+			Return(nil, errorx.ErrUnauthorized("webhook signature invalid")).Times(1)
+
+		err := s.svc.HandleWebhook(ctx, payload, "forged")
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeUnauthorized, goxErr.Code)
+	})
+
+	s.Run("other events are ignored", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").
+			Return(&entity.PaymentEvent{Type: "payment.created", Reference: "pi_3"}, nil).Times(1)
+
+		s.Assert().Nil(s.svc.HandleWebhook(ctx, payload, "signature"))
+	})
+
+	s.Run("unknown payment", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").Return(authorized, nil).Times(1)
+		s.repo.EXPECT().FindPaymentAttempt(ctx, s.tx, "mock", "pi_3").
+			Return(nil, errorx.ErrNotFound("payment cannot be found")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		err := s.svc.HandleWebhook(ctx, payload, "signature")
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("event delivered again", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").Return(authorized, nil).Times(1)
+		expectLocked(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPaid}, attempt(entity.PaymentStatusCaptured))
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.Assert().Nil(s.svc.HandleWebhook(ctx, payload, "signature"))
+	})
+
+	s.Run("failed payment", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").
+			Return(&entity.PaymentEvent{Type: entity.PaymentEventFailed, Reference: "pi_3", Reason: "card declined"}, nil).Times(1)
+		expectLocked(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment}, attempt(entity.PaymentStatusPending))
+		failed := attempt(entity.PaymentStatusFailed)
+		failed.FailureReason = "card declined"
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *failed).Return(failed, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.Assert().Nil(s.svc.HandleWebhook(ctx, payload, "signature"))
+	})
+
+	s.Run("authorized amount does not match", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").
+			Return(&entity.PaymentEvent{Type: entity.PaymentEventAuthorized, Reference: "pi_3", Amount: 100}, nil).Times(1)
+		expectLocked(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment}, attempt(entity.PaymentStatusPending))
+		failed := attempt(entity.PaymentStatusFailed)
+		failed.FailureReason = "authorized 100 instead of 20000"
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *failed).Return(failed, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.Assert().Nil(s.svc.HandleWebhook(ctx, payload, "signature"))
+	})
+
+	s.Run("order cancelled before the payment was authorized", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").Return(authorized, nil).Times(1)
+		expectLocked(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusCancelled}, attempt(entity.PaymentStatusPending))
+		failed := attempt(entity.PaymentStatusFailed)
+		failed.FailureReason = "order is cancelled"
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *failed).Return(failed, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		s.Assert().Nil(s.svc.HandleWebhook(ctx, payload, "signature"))
+	})
+
+	s.Run("capture fails", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").Return(authorized, nil).Times(1)
+		expectLocked(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment}, attempt(entity.PaymentStatusPending))
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *attempt(entity.PaymentStatusCapturing)).
+			Return(attempt(entity.PaymentStatusCapturing), nil).Times(1)
+		commit := s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		s.provider.EXPECT().Capture(ctx, "pi_3").Return(errors.New("provider down")).After(commit).Times(1)
+
+		err := s.svc.HandleWebhook(ctx, payload, "signature")
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInternal, goxErr.Code)
+	})
+
+	s.Run("authorized payment is captured after commit and the order paid", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").Return(authorized, nil).Times(1)
+		expectLocked(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment}, attempt(entity.PaymentStatusPending))
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *attempt(entity.PaymentStatusCapturing)).
+			Return(attempt(entity.PaymentStatusCapturing), nil).Times(1)
+		commit := s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		capture := s.provider.EXPECT().Capture(ctx, "pi_3").Return(nil).After(commit).Times(1)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment}, nil).After(capture).Times(1)
+		s.repo.EXPECT().FindPaymentAttempt(ctx, s.tx, "mock", "pi_3").Return(attempt(entity.PaymentStatusCapturing), nil).After(capture).Times(1)
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *attempt(entity.PaymentStatusCaptured)).
+			Return(attempt(entity.PaymentStatusCaptured), nil).Times(1)
+		s.orderRepo.EXPECT().UpdateOrderStatus(ctx, s.tx, int64(3), entity.OrderStatusPendingPayment, entity.OrderStatusPaid).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPaid}, nil).Times(1)
+		s.orderRepo.EXPECT().CreateOrderStatusChange(ctx, s.tx, entity.OrderStatusChange{
+			OrderID:   3,
+			From:      entity.OrderStatusPendingPayment,
+			To:        entity.OrderStatusPaid,
+			ActorRole: entity.OrderActorSystem,
+		}).Return(&entity.OrderStatusChange{}, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).After(capture).Times(1)
+
+		s.Assert().Nil(s.svc.HandleWebhook(ctx, payload, "signature"))
+	})
+
+	s.Run("event delivered again while capturing captures again", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").Return(authorized, nil).Times(1)
+		expectLocked(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment}, attempt(entity.PaymentStatusCapturing))
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *attempt(entity.PaymentStatusCapturing)).
+			Return(attempt(entity.PaymentStatusCapturing), nil).Times(1)
+		commit := s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		capture := s.provider.EXPECT().Capture(ctx, "pi_3").Return(nil).After(commit).Times(1)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPaid}, nil).After(capture).Times(1)
+		// the first delivery recorded the capture in the meantime
+		s.repo.EXPECT().FindPaymentAttempt(ctx, s.tx, "mock", "pi_3").Return(attempt(entity.PaymentStatusCaptured), nil).After(capture).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).After(capture).Times(1)
+
+		s.Assert().Nil(s.svc.HandleWebhook(ctx, payload, "signature"))
+	})
+
+	s.Run("order cancelled while the capture was in flight is refunded", func() {
+		s.provider.EXPECT().VerifyWebhook(payload, "signature").Return(authorized, nil).Times(1)
+		expectLocked(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusPendingPayment}, attempt(entity.PaymentStatusPending))
+		s.repo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *attempt(entity.PaymentStatusCapturing)).
+			Return(attempt(entity.PaymentStatusCapturing), nil).Times(1)
+		commit := s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+		capture := s.provider.EXPECT().Capture(ctx, "pi_3").Return(nil).After(commit).Times(1)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusCancelled}, nil).After(capture).Times(1)
+		s.repo.EXPECT().FindPaymentAttempt(ctx, s.tx, "mock", "pi_3").Return(attempt(entity.PaymentStatusCapturing), nil).After(capture).Times(1)
+		refunded := attempt(entity.PaymentStatusRefunded)
+		refunded.RefundedAmount = 20000
+		s.orderRepo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, *refunded).Return(refunded, nil).Times(1)
+		refund := entity.PaymentRefund{ID: 21, PaymentID: 11, Amount: 20000, Status: entity.PaymentRefundStatusPending}
+		s.orderRepo.EXPECT().CreatePaymentRefund(ctx, s.tx, entity.PaymentRefund{PaymentID: 11, Amount: 20000, Status: entity.PaymentRefundStatusPending}).
+			Return(&refund, nil).Times(1)
+		recorded := s.tx.EXPECT().Commit(ctx).Return(nil).After(capture).Times(1)
+
+		sent := refund
+		sent.Reference = "pi_3"
+		found := sent
+		s.orderRepo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&found, nil).After(recorded).Times(1)
+		s.provider.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(20000)).Return(nil).After(recorded).Times(1)
+		sent.Status = entity.PaymentRefundStatusSucceeded
+		sent.Attempts = 1
+		s.orderRepo.EXPECT().UpdatePaymentRefund(ctx, s.tx, sent).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).After(recorded).Times(1)
+
+		s.Assert().Nil(s.svc.HandleWebhook(ctx, payload, "signature"))
+	})
+}
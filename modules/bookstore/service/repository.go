@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -14,11 +16,127 @@ type UserRepository interface {
 
 type BookRepository interface {
 	GetBooks(ctx context.Context, arg entity.GetBooksParams) ([]entity.Book, error)
+	CountBooks(ctx context.Context, arg entity.GetBooksParams) (int64, error)
+	EstimateBooksCount(ctx context.Context) (int64, error)
+	GetBookFacets(ctx context.Context, arg entity.GetBooksParams) (*entity.BookFacets, error)
+	SuggestBooks(ctx context.Context, arg entity.SuggestBooksParams) ([]entity.BookSuggestion, error)
+	CreateBook(ctx context.Context, arg entity.CreateBookParams) (*entity.Book, error)
+	UpdateBook(ctx context.Context, arg entity.UpdateBookParams) (*entity.Book, error)
+	DeleteBook(ctx context.Context, arg entity.DeleteBookParams) error
+	FindWork(ctx context.Context, id int64) (*entity.Work, error)
+	GetWorkEditions(ctx context.Context, workID int64) ([]entity.Book, error)
 }
 
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, tx pgx.Tx, arg entity.CreateOrderParams) (*entity.Order, error)
 	CreateOrderItem(ctx context.Context, tx pgx.Tx, params entity.CreateOrderItemParams) (*entity.OrderItem, error)
 	GetMyOrders(ctx context.Context, arg entity.GetMyOrdersParams) ([]entity.Order, error)
+	CountMyOrders(ctx context.Context, userID int64) (int64, error)
 	FindBook(ctx context.Context, tx pgx.Tx, id int64) (*entity.Book, error)
+	FindBookBySKU(ctx context.Context, tx pgx.Tx, sku string) (*entity.Book, error)
+	DecrementBookStock(ctx context.Context, tx pgx.Tx, sku string, amount int64) (int64, error)
+	ReleaseOrderStock(ctx context.Context, tx pgx.Tx, orderID int64) (int64, error)
+	FindOrder(ctx context.Context, id int64) (*entity.Order, error)
+	FindOrderForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Order, error)
+	GetExpiredOrders(ctx context.Context, tx pgx.Tx, limit int64) ([]entity.Order, error)
+	UpdateOrderStatus(ctx context.Context, tx pgx.Tx, id int64, from, to string) (*entity.Order, error)
+	CreateOrderStatusChange(ctx context.Context, tx pgx.Tx, change entity.OrderStatusChange) (*entity.OrderStatusChange, error)
+	GetOrderStatusChanges(ctx context.Context, orderIDs []int64) ([]entity.OrderStatusChange, error)
+	FindOrderPayment(ctx context.Context, tx pgx.Tx, orderID int64, status string) (*entity.Payment, error)
+	UpdatePaymentAttempt(ctx context.Context, tx pgx.Tx, payment entity.Payment) (*entity.Payment, error)
+	CreatePaymentRefund(ctx context.Context, tx pgx.Tx, refund entity.PaymentRefund) (*entity.PaymentRefund, error)
+	FindPendingPaymentRefundForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.PaymentRefund, error)
+	GetPendingPaymentRefunds(ctx context.Context, before time.Time, limit int64) ([]int64, error)
+	UpdatePaymentRefund(ctx context.Context, tx pgx.Tx, refund entity.PaymentRefund) error
+}
+
+type PaymentRepository interface {
+	FindOrderForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Order, error)
+	GetOrderTotal(ctx context.Context, tx pgx.Tx, orderID int64) (int64, error)
+	CreatePaymentAttempt(ctx context.Context, tx pgx.Tx, payment entity.Payment) (*entity.Payment, error)
+	FindPaymentAttempt(ctx context.Context, tx pgx.Tx, provider, reference string) (*entity.Payment, error)
+	FindOrderPayment(ctx context.Context, tx pgx.Tx, orderID int64, status string) (*entity.Payment, error)
+	UpdatePaymentAttempt(ctx context.Context, tx pgx.Tx, payment entity.Payment) (*entity.Payment, error)
+}
+
+type ReturnRepository interface {
+	FindOrderForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Order, error)
+	FindOrderItem(ctx context.Context, tx pgx.Tx, orderID, id int64) (*entity.OrderItem, error)
+	GetReturnedAmount(ctx context.Context, tx pgx.Tx, orderID, orderItemID int64) (int64, error)
+	CreateReturn(ctx context.Context, tx pgx.Tx, ret entity.Return) (*entity.Return, error)
+	FindReturnForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Return, error)
+	UpdateReturn(ctx context.Context, tx pgx.Tx, ret entity.Return) (*entity.Return, error)
+	GetReturns(ctx context.Context, arg entity.GetReturnsParams) ([]entity.Return, error)
+	IncrementBookStock(ctx context.Context, tx pgx.Tx, sku string, amount int64) (int64, error)
+}
+
+type CartRepository interface {
+	GetUserCart(ctx context.Context, tx pgx.Tx, userID int64) (*entity.Cart, error)
+	FindGuestCart(ctx context.Context, tx pgx.Tx, token string) (*entity.Cart, error)
+	CreateGuestCart(ctx context.Context, tx pgx.Tx, token string) (*entity.Cart, error)
+	MergeCart(ctx context.Context, tx pgx.Tx, from, to int64, items []entity.CartItem) error
+	GetCartItems(ctx context.Context, tx pgx.Tx, cartID int64) ([]entity.CartItem, error)
+	SetCartItem(ctx context.Context, tx pgx.Tx, cartID int64, item entity.CartItem) error
+	DeleteCartItem(ctx context.Context, tx pgx.Tx, cartID int64, sku string) (int64, error)
+	ClearCart(ctx context.Context, tx pgx.Tx, cartID int64) error
+	FindBookBySKU(ctx context.Context, tx pgx.Tx, sku string) (*entity.Book, error)
+}
+
+type ImportRepository interface {
+	CopyBooksToStaging(ctx context.Context, tx pgx.Tx, batchID string, rows []entity.BookImportRow) (int64, error)
+	UpsertBooksFromStaging(ctx context.Context, tx pgx.Tx, batchID string) (*entity.BookUpsertCount, error)
+	LinkStagedBookCategories(ctx context.Context, tx pgx.Tx, batchID string) error
+	DiscontinueBooksByISBN(ctx context.Context, tx pgx.Tx, isbns []string) (int64, error)
+	ClearBooksStaging(ctx context.Context, tx pgx.Tx, batchID string) error
+}
+
+type CategoryRepository interface {
+	GetCategories(ctx context.Context) ([]*entity.Category, error)
+	FindCategory(ctx context.Context, id int64) (*entity.Category, error)
+	CreateCategory(ctx context.Context, arg entity.CreateCategoryParams) (*entity.Category, error)
+	GetBookCategories(ctx context.Context, bookID int64) ([]*entity.Category, error)
+	SetBookCategories(ctx context.Context, tx pgx.Tx, bookID int64, categoryIDs []int64) (int64, error)
+	FindBook(ctx context.Context, tx pgx.Tx, id int64) (*entity.Book, error)
+}
+
+type SeriesRepository interface {
+	FindSeries(ctx context.Context, id int64) (*entity.Series, error)
+	CreateSeries(ctx context.Context, arg entity.CreateSeriesParams) (*entity.Series, error)
+	GetSeriesVolumes(ctx context.Context, seriesIDs []int64) ([]entity.Book, error)
+	GetSeriesOfBooks(ctx context.Context, bookIDs []int64) ([]*entity.Series, error)
+	GetWorkIDsOfBooks(ctx context.Context, bookIDs []int64) ([]int64, error)
+	GetMyOrders(ctx context.Context, arg entity.GetMyOrdersParams) ([]entity.Order, error)
+}
+
+type CoverRepository interface {
+	SetBookCover(ctx context.Context, id int64, coverKey string) (*entity.Book, error)
+}
+
+// BlobStore keeps binary objects, such as cover images, under a key.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+}
+
+// PaymentProvider moves money for orders. A payment starts as an intent the customer authorizes with the provider, the
+// provider reports the outcome to the webhook and authorized payments are captured by the store.
+type PaymentProvider interface {
+	// Name identifies the provider in stored payment attempts
+	Name() string
+	CreateIntent(ctx context.Context, params entity.PaymentIntentParams) (*entity.PaymentIntent, error)
+	// Capture takes the authorized payment, capturing it again is not an error so a retried webhook can finish
+	Capture(ctx context.Context, reference string) error
+	// Refund pays amount of a captured payment back. A refund sent again with the same key is paid back once
+	Refund(ctx context.Context, reference, key string, amount int64) error
+	// VerifyWebhook checks the signature of a webhook call and returns the event it carries
+	VerifyWebhook(payload []byte, signature string) (*entity.PaymentEvent, error)
+}
+
+// IdempotencyKeyRepository holds the Idempotency-Key reservations of the idempotency middleware.
+type IdempotencyKeyRepository interface {
+	// DeleteExpiredIdempotencyKeys removes up to limit expired keys and returns how many it removed
+	DeleteExpiredIdempotencyKeys(ctx context.Context, limit int64) (int64, error)
+}
+
+type ExportRepository interface {
+	ExportBooks(ctx context.Context, arg entity.ExportBooksParams) ([]entity.Book, error)
 }
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+)
+
+// returnTransitions is the return lifecycle, the statuses a return may move to from each status. Rejected and received
+// returns are final.
+var returnTransitions = map[string][]string{
+	entity.ReturnStatusRequested: {entity.ReturnStatusApproved, entity.ReturnStatusRejected},
+	entity.ReturnStatusApproved:  {entity.ReturnStatusReceived},
+	entity.ReturnStatusRejected:  {},
+	entity.ReturnStatusReceived:  {},
+}
+
+func canTransitionReturn(from, to string) bool {
+	for _, next := range returnTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ReturnService handles returns of delivered books. Customers request a return per order item, staff approve or reject
+// it and receive the books, which restocks them and refunds their price through the payment provider of the order.
+type ReturnService struct {
+	repo      ReturnRepository
+	orders    *OrderService
+	validator *validator.Validate
+	txStarter repository.TxStarter
+}
+
+func NewReturnService(repo ReturnRepository, orders *OrderService, txStarter repository.TxStarter) *ReturnService {
+	return &ReturnService{
+		repo:      repo,
+		orders:    orders,
+		validator: validator.New(),
+		txStarter: txStarter,
+	}
+}
+
+// RequestReturn requests a return of copies of an item of a delivered order of the customer. Copies asked back by
+// earlier returns that were not rejected count against the ordered amount, so no more copies are returned than were
+// ordered.
+func (s *ReturnService) RequestReturn(ctx context.Context, params entity.CreateReturnParams) (*entity.Return, error) {
+	var err error
+	if err = s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	// the order lock keeps concurrent returns of the same item from exceeding the ordered amount together
+	var order *entity.Order
+	order, err = s.repo.FindOrderForUpdate(ctx, tx, params.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ownedOrder(order, params.UserID); err != nil {
+		return nil, err
+	}
+
+	if order.Status != entity.OrderStatusDelivered {
+		err = customerror.ErrUnprocessableEntity(fmt.Sprintf("order is %s, only delivered orders can be returned", order.Status))
+		return nil, err
+	}
+
+	var item *entity.OrderItem
+	item, err = s.repo.FindOrderItem(ctx, tx, params.OrderID, params.OrderItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var returned int64
+	returned, err = s.repo.GetReturnedAmount(ctx, tx, params.OrderID, params.OrderItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if returned+params.Amount > item.Amount {
+		err = customerror.ErrUnprocessableEntity(fmt.Sprintf("only %d copies of the item can still be returned", item.Amount-returned))
+		return nil, err
+	}
+
+	var ret *entity.Return
+	ret, err = s.repo.CreateReturn(ctx, tx, entity.Return{
+		OrderID:     params.OrderID,
+		OrderItemID: params.OrderItemID,
+		Amount:      params.Amount,
+		Reason:      params.Reason,
+		Note:        params.Note,
+		Status:      entity.ReturnStatusRequested,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *ReturnService) GetReturns(ctx context.Context, params entity.GetReturnsParams) ([]entity.Return, error) {
+	if err := s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	return s.repo.GetReturns(ctx, params)
+}
+
+// TransitionReturn moves a return to params.Status when the lifecycle allows it, on behalf of a staff member. Receiving
+// a return restocks its books and records their refund in the same transaction, the refund is sent to the provider once
+// the return is received.
+func (s *ReturnService) TransitionReturn(ctx context.Context, params entity.TransitionReturnParams) (*entity.Return, error) {
+	var err error
+	if err = s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	var tx repository.Transactionable
+	tx, err = s.txStarter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	var ret *entity.Return
+	ret, err = s.repo.FindReturnForUpdate(ctx, tx, params.ReturnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canTransitionReturn(ret.Status, params.Status) {
+		err = customerror.ErrUnprocessableEntity(fmt.Sprintf("return cannot move from %s to %s", ret.Status, params.Status))
+		return nil, err
+	}
+
+	var refund *entity.PaymentRefund
+	if params.Status == entity.ReturnStatusReceived {
+		refund, err = s.receiveReturn(ctx, tx, ret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret.Status = params.Status
+	ret.HandledBy = &params.ActorID
+	if params.StaffNote != "" {
+		ret.StaffNote = params.StaffNote
+	}
+
+	ret, err = s.repo.UpdateReturn(ctx, tx, *ret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.orders.sendRecordedRefund(ctx, refund)
+
+	return ret, nil
+}
+
+// receiveReturn puts the returned copies back on the shelf and records their refund at the price they were ordered at,
+// setting the refund of ret. Returns of cancelled and refunded orders cannot be received, their stock and money went
+// back with the order. Items ordered before prices were kept and orders paid before payments went through the store are
+// refunded by hand.
+func (s *ReturnService) receiveReturn(ctx context.Context, tx pgx.Tx, ret *entity.Return) (*entity.PaymentRefund, error) {
+	// refunds of the same order change the same payment, the order lock applies them one after the other
+	order, err := s.repo.FindOrderForUpdate(ctx, tx, ret.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == entity.OrderStatusCancelled || order.Status == entity.OrderStatusRefunded {
+		return nil, customerror.ErrUnprocessableEntity(fmt.Sprintf("order is %s and its returns cannot be received", order.Status))
+	}
+
+	item, err := s.repo.FindOrderItem(ctx, tx, ret.OrderID, ret.OrderItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.SKU != "" {
+		if _, err = s.repo.IncrementBookStock(ctx, tx, item.SKU, ret.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if item.Price == nil {
+		return nil, nil
+	}
+	ret.RefundAmount = *item.Price * ret.Amount
+
+	payment, err := s.orders.capturedPayment(ctx, tx, ret.OrderID)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+
+	if ret.RefundAmount > payment.Amount-payment.RefundedAmount {
+		return nil, customerror.ErrUnprocessableEntity("refund exceeds what is left of the payment")
+	}
+
+	return s.orders.refundPayment(ctx, tx, *payment, ret.RefundAmount)
+}
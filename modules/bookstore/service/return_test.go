@@ -0,0 +1,365 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/repository"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+	mock_repository "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/repository"
+	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
+)
+
+type ReturnServiceTestSuite struct {
+	suite.Suite
+
+	repo      *mock_service.MockReturnRepository
+	orderRepo *mock_service.MockOrderRepository
+	provider  *mock_service.MockPaymentProvider
+	txFunc    repository.TxStarter
+	tx        *mock_repository.MockTransactionable
+	svc       *service.ReturnService
+}
+
+func (s *ReturnServiceTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_service.NewMockReturnRepository(ctrl)
+	s.orderRepo = mock_service.NewMockOrderRepository(ctrl)
+	s.provider = mock_service.NewMockPaymentProvider(ctrl)
+	s.tx = mock_repository.NewMockTransactionable(ctrl)
+	s.txFunc = func(ctx context.Context) (pgx.Tx, error) {
+		return s.tx, nil
+	}
+
+	orders := service.NewOrderService(s.orderRepo, s.txFunc, s.provider, service.DefaultOrderLimits)
+	s.svc = service.NewReturnService(s.repo, orders, s.txFunc)
+}
+
+func TestReturnService(t *testing.T) {
+	suite.Run(t, new(ReturnServiceTestSuite))
+}
+
+func (s *ReturnServiceTestSuite) TestRequestReturn() {
+	ctx := context.Background()
+	params := entity.CreateReturnParams{
+		OrderID:     3,
+		UserID:      5,
+		OrderItemID: 8,
+		Amount:      2,
+		Reason:      entity.ReturnReasonDamaged,
+		Note:        "cover torn",
+	}
+	delivered := &entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusDelivered}
+	item := &entity.OrderItem{ID: 8, OrderID: 3, BookID: 1, SKU: "SKU-1", Amount: 3}
+
+	s.Run("invalid params", func() {
+		invalid := params
+		invalid.Reason = "changed my mind"
+
+		result, err := s.svc.RequestReturn(ctx, invalid)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("order of another customer", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 7, Status: entity.OrderStatusDelivered}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.RequestReturn(ctx, params)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("order not delivered", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusShipped}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.RequestReturn(ctx, params)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "order is shipped, only delivered orders can be returned")
+	})
+
+	s.Run("more copies than are left to return", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(delivered, nil).Times(1)
+		s.repo.EXPECT().FindOrderItem(ctx, s.tx, int64(3), int64(8)).Return(item, nil).Times(1)
+		s.repo.EXPECT().GetReturnedAmount(ctx, s.tx, int64(3), int64(8)).Return(int64(2), nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.RequestReturn(ctx, params)
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "only 1 copies of the item can still be returned")
+	})
+
+	s.Run("order item got repo error", func() {
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(delivered, nil).Times(1)
+		s.repo.EXPECT().FindOrderItem(ctx, s.tx, int64(3), int64(8)).
+			Return(nil, errorx.ErrNotFound("order item cannot be found")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.RequestReturn(ctx, params)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("success", func() {
+		expected := &entity.Return{
+			ID:          11,
+			OrderID:     3,
+			OrderItemID: 8,
+			Amount:      2,
+			Reason:      entity.ReturnReasonDamaged,
+			Note:        "cover torn",
+			Status:      entity.ReturnStatusRequested,
+		}
+
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(delivered, nil).Times(1)
+		s.repo.EXPECT().FindOrderItem(ctx, s.tx, int64(3), int64(8)).Return(item, nil).Times(1)
+		s.repo.EXPECT().GetReturnedAmount(ctx, s.tx, int64(3), int64(8)).Return(int64(1), nil).Times(1)
+		s.repo.EXPECT().CreateReturn(ctx, s.tx, entity.Return{
+			OrderID:     3,
+			OrderItemID: 8,
+			Amount:      2,
+			Reason:      entity.ReturnReasonDamaged,
+			Note:        "cover torn",
+			Status:      entity.ReturnStatusRequested,
+		}).Return(expected, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.RequestReturn(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(expected, result)
+	})
+}
+
+func (s *ReturnServiceTestSuite) TestGetReturns() {
+	ctx := context.Background()
+
+	s.Run("invalid status", func() {
+		result, err := s.svc.GetReturns(ctx, entity.GetReturnsParams{Status: "lost", Limit: 10})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("success", func() {
+		params := entity.GetReturnsParams{Status: entity.ReturnStatusRequested, Limit: 10}
+		expected := []entity.Return{{ID: 11, Status: entity.ReturnStatusRequested}}
+		s.repo.EXPECT().GetReturns(ctx, params).Return(expected, nil).Times(1)
+
+		result, err := s.svc.GetReturns(ctx, params)
+		s.Assert().Nil(err)
+		s.Assert().Equal(expected, result)
+	})
+}
+
+func (s *ReturnServiceTestSuite) TestTransitionReturn() {
+	ctx := context.Background()
+	actorID := int64(2)
+	price := int64(9500)
+	requested := func() *entity.Return {
+		return &entity.Return{ID: 11, OrderID: 3, OrderItemID: 8, Amount: 2, Reason: entity.ReturnReasonDamaged, Status: entity.ReturnStatusRequested}
+	}
+	approved := func() *entity.Return {
+		ret := requested()
+		ret.Status = entity.ReturnStatusApproved
+		ret.HandledBy = &actorID
+		return ret
+	}
+	delivered := &entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusDelivered}
+	item := &entity.OrderItem{ID: 8, OrderID: 3, BookID: 1, SKU: "SKU-1", Amount: 3, Price: &price}
+
+	s.Run("invalid params", func() {
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{ReturnID: 11, Status: entity.ReturnStatusRequested, ActorID: 2})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("return not found", func() {
+		s.repo.EXPECT().FindReturnForUpdate(ctx, s.tx, int64(11)).
+			Return(nil, errorx.ErrNotFound("return cannot be found")).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{ReturnID: 11, Status: entity.ReturnStatusApproved, ActorID: 2})
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("receive a return that was not approved", func() {
+		s.repo.EXPECT().FindReturnForUpdate(ctx, s.tx, int64(11)).Return(requested(), nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{ReturnID: 11, Status: entity.ReturnStatusReceived, ActorID: 2})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "return cannot move from requested to received")
+	})
+
+	s.Run("approve", func() {
+		expected := approved()
+		expected.StaffNote = "send it back"
+
+		s.repo.EXPECT().FindReturnForUpdate(ctx, s.tx, int64(11)).Return(requested(), nil).Times(1)
+		s.repo.EXPECT().UpdateReturn(ctx, s.tx, *expected).Return(expected, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{
+			ReturnID:  11,
+			Status:    entity.ReturnStatusApproved,
+			StaffNote: "send it back",
+			ActorID:   2,
+		})
+		s.Assert().Nil(err)
+		s.Assert().Equal(expected, result)
+	})
+
+	s.Run("receive restocks and refunds part of the payment", func() {
+		expected := approved()
+		expected.Status = entity.ReturnStatusReceived
+		expected.RefundAmount = 19000
+		refund := entity.PaymentRefund{ID: 21, PaymentID: 4, Amount: 19000, Status: entity.PaymentRefundStatusPending}
+
+		s.repo.EXPECT().FindReturnForUpdate(ctx, s.tx, int64(11)).Return(approved(), nil).Times(1)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(delivered, nil).Times(1)
+		s.repo.EXPECT().FindOrderItem(ctx, s.tx, int64(3), int64(8)).Return(item, nil).Times(1)
+		s.repo.EXPECT().IncrementBookStock(ctx, s.tx, "SKU-1", int64(2)).Return(int64(12), nil).Times(1)
+		s.orderRepo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).
+			Return(&entity.Payment{ID: 4, OrderID: 3, Reference: "pi_3", Amount: 28500, Status: entity.PaymentStatusCaptured}, nil).Times(1)
+		s.orderRepo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, entity.Payment{
+			ID:             4,
+			OrderID:        3,
+			Reference:      "pi_3",
+			Amount:         28500,
+			RefundedAmount: 19000,
+			Status:         entity.PaymentStatusCaptured,
+		}).Return(&entity.Payment{}, nil).Times(1)
+		s.orderRepo.EXPECT().CreatePaymentRefund(ctx, s.tx, entity.PaymentRefund{PaymentID: 4, Amount: 19000, Status: entity.PaymentRefundStatusPending}).
+			Return(&refund, nil).Times(1)
+		s.repo.EXPECT().UpdateReturn(ctx, s.tx, *expected).Return(expected, nil).Times(1)
+
+		sent := refund
+		sent.Reference = "pi_3"
+		found := sent
+		s.orderRepo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&found, nil).Times(1)
+		s.provider.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(nil).Times(1)
+		sent.Status = entity.PaymentRefundStatusSucceeded
+		sent.Attempts = 1
+		s.orderRepo.EXPECT().UpdatePaymentRefund(ctx, s.tx, sent).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(2)
+
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{ReturnID: 11, Status: entity.ReturnStatusReceived, ActorID: 2})
+		s.Assert().Nil(err)
+		s.Assert().Equal(expected, result)
+	})
+
+	s.Run("receive without a captured payment only restocks", func() {
+		expected := approved()
+		expected.Status = entity.ReturnStatusReceived
+		expected.RefundAmount = 19000
+
+		s.repo.EXPECT().FindReturnForUpdate(ctx, s.tx, int64(11)).Return(approved(), nil).Times(1)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(delivered, nil).Times(1)
+		s.repo.EXPECT().FindOrderItem(ctx, s.tx, int64(3), int64(8)).Return(item, nil).Times(1)
+		s.repo.EXPECT().IncrementBookStock(ctx, s.tx, "SKU-1", int64(2)).Return(int64(12), nil).Times(1)
+		s.orderRepo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).
+			Return(nil, errorx.ErrNotFound("payment cannot be found")).Times(1)
+		s.repo.EXPECT().UpdateReturn(ctx, s.tx, *expected).Return(expected, nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{ReturnID: 11, Status: entity.ReturnStatusReceived, ActorID: 2})
+		s.Assert().Nil(err)
+		s.Assert().Equal(expected, result)
+	})
+
+	s.Run("return of a refunded order", func() {
+		s.repo.EXPECT().FindReturnForUpdate(ctx, s.tx, int64(11)).Return(approved(), nil).Times(1)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).
+			Return(&entity.Order{ID: 3, UserID: 5, Status: entity.OrderStatusRefunded}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{ReturnID: 11, Status: entity.ReturnStatusReceived, ActorID: 2})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "order is refunded and its returns cannot be received")
+	})
+
+	s.Run("refund exceeds what is left of the payment", func() {
+		s.repo.EXPECT().FindReturnForUpdate(ctx, s.tx, int64(11)).Return(approved(), nil).Times(1)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(delivered, nil).Times(1)
+		s.repo.EXPECT().FindOrderItem(ctx, s.tx, int64(3), int64(8)).Return(item, nil).Times(1)
+		s.repo.EXPECT().IncrementBookStock(ctx, s.tx, "SKU-1", int64(2)).Return(int64(12), nil).Times(1)
+		s.orderRepo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).
+			Return(&entity.Payment{ID: 4, OrderID: 3, Reference: "pi_3", Amount: 28500, RefundedAmount: 19000, Status: entity.PaymentStatusCaptured}, nil).Times(1)
+		s.tx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{ReturnID: 11, Status: entity.ReturnStatusReceived, ActorID: 2})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(customerror.CodeUnprocessableEntity, goxErr.Code)
+		s.Assert().EqualError(goxErr, "refund exceeds what is left of the payment")
+	})
+
+	s.Run("refund the provider fails stays pending and the return received", func() {
+		expected := approved()
+		expected.Status = entity.ReturnStatusReceived
+		expected.RefundAmount = 19000
+		refund := entity.PaymentRefund{ID: 21, PaymentID: 4, Amount: 19000, Status: entity.PaymentRefundStatusPending}
+
+		s.repo.EXPECT().FindReturnForUpdate(ctx, s.tx, int64(11)).Return(approved(), nil).Times(1)
+		s.repo.EXPECT().FindOrderForUpdate(ctx, s.tx, int64(3)).Return(delivered, nil).Times(1)
+		s.repo.EXPECT().FindOrderItem(ctx, s.tx, int64(3), int64(8)).Return(item, nil).Times(1)
+		s.repo.EXPECT().IncrementBookStock(ctx, s.tx, "SKU-1", int64(2)).Return(int64(12), nil).Times(1)
+		s.orderRepo.EXPECT().FindOrderPayment(ctx, s.tx, int64(3), entity.PaymentStatusCaptured).
+			Return(&entity.Payment{ID: 4, OrderID: 3, Reference: "pi_3", Amount: 28500, Status: entity.PaymentStatusCaptured}, nil).Times(1)
+		s.orderRepo.EXPECT().UpdatePaymentAttempt(ctx, s.tx, gomock.Any()).Return(&entity.Payment{}, nil).Times(1)
+		s.orderRepo.EXPECT().CreatePaymentRefund(ctx, s.tx, gomock.Any()).Return(&refund, nil).Times(1)
+		s.repo.EXPECT().UpdateReturn(ctx, s.tx, *expected).Return(expected, nil).Times(1)
+
+		sent := refund
+		sent.Reference = "pi_3"
+		found := sent
+		s.orderRepo.EXPECT().FindPendingPaymentRefundForUpdate(ctx, s.tx, int64(21)).Return(&found, nil).Times(1)
+		s.provider.EXPECT().Refund(ctx, "pi_3", "refund-21", int64(19000)).Return(errors.New("provider error")).Times(1)
+		sent.Attempts = 1
+		sent.LastError = "provider error"
+		s.orderRepo.EXPECT().UpdatePaymentRefund(ctx, s.tx, sent).Return(nil).Times(1)
+		s.tx.EXPECT().Commit(ctx).Return(nil).Times(2)
+
+		result, err := s.svc.TransitionReturn(ctx, entity.TransitionReturnParams{ReturnID: 11, Status: entity.ReturnStatusReceived, ActorID: 2})
+		s.Assert().Nil(err)
+		s.Assert().Equal(expected, result)
+	})
+}
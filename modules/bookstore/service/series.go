@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/raymondwongso/gogox/errorx"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+// SeriesOrderBatchSize is how many orders are read at a time while collecting what a customer has bought.
+const SeriesOrderBatchSize = 100
+
+// boughtOrderStatuses are the statuses of orders whose books the customer keeps.
+var boughtOrderStatuses = map[string]bool{
+	entity.OrderStatusPaid:       true,
+	entity.OrderStatusFulfilling: true,
+	entity.OrderStatusShipped:    true,
+	entity.OrderStatusDelivered:  true,
+}
+
+type SeriesService struct {
+	repo      SeriesRepository
+	validator *validator.Validate
+}
+
+func NewSeriesService(repo SeriesRepository) *SeriesService {
+	return &SeriesService{
+		repo:      repo,
+		validator: validator.New(),
+	}
+}
+
+// GetSeries returns a series with its volumes in reading order.
+func (s *SeriesService) GetSeries(ctx context.Context, id int64) (*entity.Series, error) {
+	if id <= 0 {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	series, err := s.repo.FindSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Volumes, err = s.repo.GetSeriesVolumes(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (s *SeriesService) CreateSeries(ctx context.Context, params entity.CreateSeriesParams) (*entity.Series, error) {
+	params.Name = strings.TrimSpace(params.Name)
+	params.Description = strings.TrimSpace(params.Description)
+	if err := s.validator.Struct(params); err != nil {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	return s.repo.CreateSeries(ctx, params)
+}
+
+// GetSeriesToComplete walks the order history of a user and, for every series they bought from, lists the active
+// volumes they do not own yet. A volume is owned once any edition of its work was bought in an order that went through,
+// series they already own entirely are left out.
+func (s *SeriesService) GetSeriesToComplete(ctx context.Context, userID int64) ([]entity.SeriesCompletion, error) {
+	if userID <= 0 {
+		return nil, errorx.ErrInvalidParameter("Input is invalid")
+	}
+
+	bought, err := s.boughtBookIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	completions := []entity.SeriesCompletion{}
+	if len(bought) == 0 {
+		return completions, nil
+	}
+
+	bookIDs := make([]int64, 0, len(bought))
+	for id := range bought {
+		bookIDs = append(bookIDs, id)
+	}
+	sort.Slice(bookIDs, func(i, j int) bool { return bookIDs[i] < bookIDs[j] })
+
+	series, err := s.repo.GetSeriesOfBooks(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 {
+		return completions, nil
+	}
+
+	workIDs, err := s.repo.GetWorkIDsOfBooks(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[int64]bool, len(workIDs))
+	for _, id := range workIDs {
+		owned[id] = true
+	}
+
+	seriesIDs := make([]int64, 0, len(series))
+	for _, se := range series {
+		seriesIDs = append(seriesIDs, se.ID)
+	}
+
+	volumes, err := s.repo.GetSeriesVolumes(ctx, seriesIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bySeries := make(map[int64]*entity.SeriesCompletion, len(series))
+	for _, se := range series {
+		bySeries[se.ID] = &entity.SeriesCompletion{ID: se.ID, Name: se.Name, MissingVolumes: []entity.Book{}}
+	}
+
+	// every edition of a volume is listed, an owned work is counted once
+	counted := make(map[int64]bool, len(owned))
+	for _, volume := range volumes {
+		if volume.Series == nil {
+			continue
+		}
+		completion, exist := bySeries[volume.Series.ID]
+		if !exist {
+			continue
+		}
+
+		switch {
+		case owned[volume.WorkID]:
+			if !counted[volume.WorkID] {
+				counted[volume.WorkID] = true
+				completion.OwnedVolumes++
+			}
+		case volume.Status == entity.BookStatusActive:
+			volume.Series.Name = completion.Name
+			completion.MissingVolumes = append(completion.MissingVolumes, volume)
+		}
+	}
+
+	// series keep the name order they were returned in
+	for _, se := range series {
+		if completion := bySeries[se.ID]; len(completion.MissingVolumes) > 0 {
+			completions = append(completions, *completion)
+		}
+	}
+
+	return completions, nil
+}
+
+// boughtBookIDs pages through every order of the user, newest first, and collects the books of the ones that were paid
+// and not cancelled or refunded since.
+func (s *SeriesService) boughtBookIDs(ctx context.Context, userID int64) (map[int64]bool, error) {
+	bought := make(map[int64]bool)
+	params := entity.GetMyOrdersParams{UserID: userID, Limit: SeriesOrderBatchSize}
+
+	for {
+		orders, err := s.repo.GetMyOrders(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, order := range orders {
+			if !boughtOrderStatuses[order.Status] {
+				continue
+			}
+			for _, item := range order.Items {
+				bought[item.BookID] = true
+			}
+		}
+
+		if int64(len(orders)) < params.Limit {
+			return bought, nil
+		}
+		params.BeforeID = orders[len(orders)-1].ID
+	}
+}
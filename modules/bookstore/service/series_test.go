@@ -0,0 +1,186 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/raymondwongso/gogox/errorx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/customerror"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/service"
+	mock_service "github.com/swallowstalker/online-book-store/test/mock/modules/bookstore/service"
+)
+
+type SeriesServiceTestSuite struct {
+	suite.Suite
+
+	repo *mock_service.MockSeriesRepository
+}
+
+func (s *SeriesServiceTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_service.NewMockSeriesRepository(ctrl)
+}
+
+func TestSeriesService(t *testing.T) {
+	suite.Run(t, new(SeriesServiceTestSuite))
+}
+
+func (s *SeriesServiceTestSuite) TestGetSeries() {
+	ctx := context.Background()
+	svc := service.NewSeriesService(s.repo)
+
+	s.Run("series not found", func() {
+		s.repo.EXPECT().FindSeries(ctx, int64(9)).
+			Return(nil, errorx.ErrNotFound("series cannot be found")).Times(1)
+
+		result, err := svc.GetSeries(ctx, 9)
+		s.Assert().Nil(result)
+		s.Assert().True(customerror.IsErrNotFound(err))
+	})
+
+	s.Run("successful", func() {
+		volumes := []entity.Book{
+			{ID: 4, Name: "Foundation", Series: &entity.BookSeries{ID: 2, Volume: 1}},
+			{ID: 5, Name: "Foundation and Empire", Series: &entity.BookSeries{ID: 2, Volume: 2}},
+		}
+		s.repo.EXPECT().FindSeries(ctx, int64(2)).
+			Return(&entity.Series{ID: 2, Name: "Foundation"}, nil).Times(1)
+		s.repo.EXPECT().GetSeriesVolumes(ctx, []int64{2}).
+			Return(volumes, nil).Times(1)
+
+		result, err := svc.GetSeries(ctx, 2)
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Series{ID: 2, Name: "Foundation", Volumes: volumes}, result)
+	})
+}
+
+func (s *SeriesServiceTestSuite) TestCreateSeries() {
+	ctx := context.Background()
+	svc := service.NewSeriesService(s.repo)
+
+	s.Run("name is required", func() {
+		result, err := svc.CreateSeries(ctx, entity.CreateSeriesParams{Name: "  "})
+		s.Assert().Nil(result)
+
+		goxErr, ok := errorx.Parse(err)
+		s.Require().True(ok)
+		s.Assert().Equal(errorx.CodeInvalidParameter, goxErr.Code)
+	})
+
+	s.Run("successful", func() {
+		s.repo.EXPECT().CreateSeries(ctx, entity.CreateSeriesParams{Name: "Discworld"}).
+			Return(&entity.Series{ID: 3, Name: "Discworld"}, nil).Times(1)
+
+		result, err := svc.CreateSeries(ctx, entity.CreateSeriesParams{Name: " Discworld "})
+		s.Assert().Nil(err)
+		s.Assert().Equal(&entity.Series{ID: 3, Name: "Discworld"}, result)
+	})
+}
+
+func (s *SeriesServiceTestSuite) TestGetSeriesToComplete() {
+	ctx := context.Background()
+	svc := service.NewSeriesService(s.repo)
+
+	s.Run("order history error", func() {
+		s.repo.EXPECT().GetMyOrders(ctx, entity.GetMyOrdersParams{UserID: 1, Limit: service.SeriesOrderBatchSize}).
+			Return(nil, errors.New("db down")).Times(1)
+
+		result, err := svc.GetSeriesToComplete(ctx, 1)
+		s.Assert().Nil(result)
+		s.Assert().Error(err)
+	})
+
+	s.Run("nothing bought yet", func() {
+		s.repo.EXPECT().GetMyOrders(ctx, entity.GetMyOrdersParams{UserID: 1, Limit: service.SeriesOrderBatchSize}).
+			Return([]entity.Order{}, nil).Times(1)
+
+		result, err := svc.GetSeriesToComplete(ctx, 1)
+		s.Assert().Nil(err)
+		s.Assert().Empty(result)
+	})
+
+	s.Run("lists the missing active volumes", func() {
+		orders := make([]entity.Order, service.SeriesOrderBatchSize)
+		for i := range orders {
+			orders[i] = entity.Order{ID: int64(300 - i), Status: entity.OrderStatusDelivered, Items: []entity.OrderItem{{BookID: 99}}}
+		}
+		orders[0].Items = []entity.OrderItem{{BookID: 4}, {BookID: 10}}
+
+		s.repo.EXPECT().GetMyOrders(ctx, entity.GetMyOrdersParams{UserID: 1, Limit: service.SeriesOrderBatchSize}).
+			Return(orders, nil).Times(1)
+		s.repo.EXPECT().GetMyOrders(ctx, entity.GetMyOrdersParams{UserID: 1, Limit: service.SeriesOrderBatchSize, BeforeID: 201}).
+			Return([]entity.Order{{ID: 7, Status: entity.OrderStatusPaid, Items: []entity.OrderItem{{BookID: 11}}}}, nil).Times(1)
+		s.repo.EXPECT().GetSeriesOfBooks(ctx, []int64{4, 10, 11, 99}).
+			Return([]*entity.Series{{ID: 2, Name: "Foundation"}, {ID: 3, Name: "Trilogy Done"}}, nil).Times(1)
+		s.repo.EXPECT().GetWorkIDsOfBooks(ctx, []int64{4, 10, 11, 99}).Return([]int64{4, 10, 11, 99}, nil).Times(1)
+		s.repo.EXPECT().GetSeriesVolumes(ctx, []int64{2, 3}).
+			Return([]entity.Book{
+				{ID: 4, WorkID: 4, Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 2, Volume: 1}},
+				{ID: 5, WorkID: 5, Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 2, Volume: 2}},
+				{ID: 6, WorkID: 6, Status: entity.BookStatusDiscontinued, Series: &entity.BookSeries{ID: 2, Volume: 3}},
+				{ID: 10, WorkID: 10, Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 3, Volume: 1}},
+				{ID: 11, WorkID: 11, Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 3, Volume: 2}},
+			}, nil).Times(1)
+
+		result, err := svc.GetSeriesToComplete(ctx, 1)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.SeriesCompletion{
+			{
+				ID:           2,
+				Name:         "Foundation",
+				OwnedVolumes: 1,
+				MissingVolumes: []entity.Book{
+					{ID: 5, WorkID: 5, Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 2, Name: "Foundation", Volume: 2}},
+				},
+			},
+		}, result)
+	})
+
+	s.Run("only orders that went through count", func() {
+		s.repo.EXPECT().GetMyOrders(ctx, entity.GetMyOrdersParams{UserID: 1, Limit: service.SeriesOrderBatchSize}).
+			Return([]entity.Order{
+				{ID: 9, Status: entity.OrderStatusPendingPayment, Items: []entity.OrderItem{{BookID: 5}}},
+				{ID: 8, Status: entity.OrderStatusCancelled, Items: []entity.OrderItem{{BookID: 6}}},
+				{ID: 7, Status: entity.OrderStatusRefunded, Items: []entity.OrderItem{{BookID: 7}}},
+			}, nil).Times(1)
+
+		result, err := svc.GetSeriesToComplete(ctx, 1)
+		s.Assert().Nil(err)
+		s.Assert().Empty(result)
+	})
+
+	s.Run("another edition of an owned volume is not missing", func() {
+		s.repo.EXPECT().GetMyOrders(ctx, entity.GetMyOrdersParams{UserID: 1, Limit: service.SeriesOrderBatchSize}).
+			Return([]entity.Order{
+				{ID: 9, Status: entity.OrderStatusShipped, Items: []entity.OrderItem{{BookID: 4}}},
+				{ID: 8, Status: entity.OrderStatusFulfilling, Items: []entity.OrderItem{{BookID: 12}}},
+			}, nil).Times(1)
+		s.repo.EXPECT().GetSeriesOfBooks(ctx, []int64{4, 12}).Return([]*entity.Series{{ID: 2, Name: "Foundation"}}, nil).Times(1)
+		s.repo.EXPECT().GetWorkIDsOfBooks(ctx, []int64{4, 12}).Return([]int64{4}, nil).Times(1)
+		s.repo.EXPECT().GetSeriesVolumes(ctx, []int64{2}).
+			Return([]entity.Book{
+				{ID: 4, WorkID: 4, Format: "paperback", Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 2, Volume: 1}},
+				{ID: 12, WorkID: 4, Format: "ebook", Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 2, Volume: 1}},
+				{ID: 13, WorkID: 4, Format: "hardcover", Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 2, Volume: 1}},
+				{ID: 5, WorkID: 5, Format: "paperback", Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 2, Volume: 2}},
+			}, nil).Times(1)
+
+		result, err := svc.GetSeriesToComplete(ctx, 1)
+		s.Assert().Nil(err)
+		s.Assert().Equal([]entity.SeriesCompletion{
+			{
+				ID:           2,
+				Name:         "Foundation",
+				OwnedVolumes: 1,
+				MissingVolumes: []entity.Book{
+					{ID: 5, WorkID: 5, Format: "paperback", Status: entity.BookStatusActive, Series: &entity.BookSeries{ID: 2, Name: "Foundation", Volume: 2}},
+				},
+			},
+		}, result)
+	})
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+const (
+	DefaultSuggestionCacheSize = 1000
+	DefaultSuggestionCacheTTL  = time.Minute
+)
+
+// suggestionCache is a small in-process LRU for autocomplete results, so that hot prefixes typed by many
+// customers at once do not all hit the database.
+type suggestionCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type suggestionCacheEntry struct {
+	key       string
+	value     []entity.BookSuggestion
+	expiresAt time.Time
+}
+
+func newSuggestionCache(capacity int, ttl time.Duration) *suggestionCache {
+	return &suggestionCache{
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (c *suggestionCache) get(key string) ([]entity.BookSuggestion, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, exist := c.entries[key]
+	if !exist {
+		return nil, false
+	}
+
+	entry := elem.Value.(*suggestionCacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (c *suggestionCache) set(key string, value []entity.BookSuggestion) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, exist := c.entries[key]; exist {
+		entry := elem.Value.(*suggestionCacheEntry)
+		entry.value = value
+		entry.expiresAt = c.now().Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&suggestionCacheEntry{
+		key:       key,
+		value:     value,
+		expiresAt: c.now().Add(c.ttl),
+	})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*suggestionCacheEntry).key)
+	}
+}
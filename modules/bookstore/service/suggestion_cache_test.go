@@ -0,0 +1,119 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swallowstalker/online-book-store/modules/bookstore/entity"
+)
+
+type SuggestionCacheTestSuite struct {
+	suite.Suite
+
+	now time.Time
+}
+
+func TestSuggestionCache(t *testing.T) {
+	suite.Run(t, new(SuggestionCacheTestSuite))
+}
+
+// newCache returns a cache whose clock only moves when the test advances s.now.
+func (s *SuggestionCacheTestSuite) newCache(capacity int, ttl time.Duration) *suggestionCache {
+	s.now = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	cache := newSuggestionCache(capacity, ttl)
+	cache.now = func() time.Time { return s.now }
+
+	return cache
+}
+
+func suggestions(name string) []entity.BookSuggestion {
+	return []entity.BookSuggestion{{ID: 1, Name: name}}
+}
+
+func (s *SuggestionCacheTestSuite) TestExpiry() {
+	s.Run("entry is served until its ttl passed", func() {
+		cache := s.newCache(10, time.Minute)
+		cache.set("deb", suggestions("Debugging"))
+
+		s.now = s.now.Add(time.Minute)
+		result, ok := cache.get("deb")
+		s.Assert().True(ok)
+		s.Assert().Equal(suggestions("Debugging"), result)
+	})
+
+	s.Run("expired entry is a miss and is dropped", func() {
+		cache := s.newCache(10, time.Minute)
+		cache.set("deb", suggestions("Debugging"))
+
+		s.now = s.now.Add(time.Minute + time.Second)
+		result, ok := cache.get("deb")
+		s.Assert().False(ok)
+		s.Assert().Nil(result)
+		s.Assert().Equal(0, cache.order.Len())
+		s.Assert().NotContains(cache.entries, "deb")
+	})
+
+	s.Run("setting an entry again restarts its ttl", func() {
+		cache := s.newCache(10, time.Minute)
+		cache.set("deb", suggestions("Debugging"))
+
+		s.now = s.now.Add(50 * time.Second)
+		cache.set("deb", suggestions("Debugging Go"))
+
+		s.now = s.now.Add(50 * time.Second)
+		result, ok := cache.get("deb")
+		s.Assert().True(ok)
+		s.Assert().Equal(suggestions("Debugging Go"), result)
+	})
+}
+
+func (s *SuggestionCacheTestSuite) TestEviction() {
+	s.Run("least recently set entry is evicted at capacity", func() {
+		cache := s.newCache(2, time.Minute)
+		cache.set("a", suggestions("A"))
+		cache.set("b", suggestions("B"))
+		cache.set("c", suggestions("C"))
+
+		_, ok := cache.get("a")
+		s.Assert().False(ok)
+		_, ok = cache.get("b")
+		s.Assert().True(ok)
+		_, ok = cache.get("c")
+		s.Assert().True(ok)
+		s.Assert().Equal(2, cache.order.Len())
+	})
+
+	s.Run("hit refreshes recency", func() {
+		cache := s.newCache(2, time.Minute)
+		cache.set("a", suggestions("A"))
+		cache.set("b", suggestions("B"))
+
+		_, ok := cache.get("a")
+		s.Require().True(ok)
+		cache.set("c", suggestions("C"))
+
+		_, ok = cache.get("b")
+		s.Assert().False(ok)
+		_, ok = cache.get("a")
+		s.Assert().True(ok)
+		_, ok = cache.get("c")
+		s.Assert().True(ok)
+	})
+
+	s.Run("setting an existing entry refreshes recency without growing", func() {
+		cache := s.newCache(2, time.Minute)
+		cache.set("a", suggestions("A"))
+		cache.set("b", suggestions("B"))
+		cache.set("a", suggestions("A2"))
+		cache.set("c", suggestions("C"))
+
+		_, ok := cache.get("b")
+		s.Assert().False(ok)
+		result, ok := cache.get("a")
+		s.Assert().True(ok)
+		s.Assert().Equal(suggestions("A2"), result)
+		s.Assert().Equal(2, cache.order.Len())
+	})
+}
@@ -73,11 +73,70 @@ func (m *MockBookService) EXPECT() *MockBookServiceMockRecorder {
 	return m.recorder
 }
 
+// CountBooks mocks base method.
+func (m *MockBookService) CountBooks(ctx context.Context, params entity.GetBooksParams, estimate bool) (*entity.TotalCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBooks", ctx, params, estimate)
+	ret0, _ := ret[0].(*entity.TotalCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBooks indicates an expected call of CountBooks.
+func (mr *MockBookServiceMockRecorder) CountBooks(ctx, params, estimate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBooks", reflect.TypeOf((*MockBookService)(nil).CountBooks), ctx, params, estimate)
+}
+
+// CreateBook mocks base method.
+func (m *MockBookService) CreateBook(ctx context.Context, params entity.CreateBookParams) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBook", ctx, params)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBook indicates an expected call of CreateBook.
+func (mr *MockBookServiceMockRecorder) CreateBook(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockBookService)(nil).CreateBook), ctx, params)
+}
+
+// DeleteBook mocks base method.
+func (m *MockBookService) DeleteBook(ctx context.Context, params entity.DeleteBookParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBook indicates an expected call of DeleteBook.
+func (mr *MockBookServiceMockRecorder) DeleteBook(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookService)(nil).DeleteBook), ctx, params)
+}
+
+// GetBookFacets mocks base method.
+func (m *MockBookService) GetBookFacets(ctx context.Context, params entity.GetBooksParams) (*entity.BookFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookFacets", ctx, params)
+	ret0, _ := ret[0].(*entity.BookFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookFacets indicates an expected call of GetBookFacets.
+func (mr *MockBookServiceMockRecorder) GetBookFacets(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookFacets", reflect.TypeOf((*MockBookService)(nil).GetBookFacets), ctx, params)
+}
+
 // GetBooks mocks base method.
-func (m *MockBookService) GetBooks(ctx context.Context, params entity.GetBooksParams) ([]entity.Book, error) {
+func (m *MockBookService) GetBooks(ctx context.Context, params entity.GetBooksParams) (*entity.BookList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBooks", ctx, params)
-	ret0, _ := ret[0].([]entity.Book)
+	ret0, _ := ret[0].(*entity.BookList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -88,6 +147,51 @@ func (mr *MockBookServiceMockRecorder) GetBooks(ctx, params interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookService)(nil).GetBooks), ctx, params)
 }
 
+// GetWork mocks base method.
+func (m *MockBookService) GetWork(ctx context.Context, id int64) (*entity.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWork", ctx, id)
+	ret0, _ := ret[0].(*entity.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWork indicates an expected call of GetWork.
+func (mr *MockBookServiceMockRecorder) GetWork(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWork", reflect.TypeOf((*MockBookService)(nil).GetWork), ctx, id)
+}
+
+// SuggestBooks mocks base method.
+func (m *MockBookService) SuggestBooks(ctx context.Context, params entity.SuggestBooksParams) ([]entity.BookSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestBooks", ctx, params)
+	ret0, _ := ret[0].([]entity.BookSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestBooks indicates an expected call of SuggestBooks.
+func (mr *MockBookServiceMockRecorder) SuggestBooks(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestBooks", reflect.TypeOf((*MockBookService)(nil).SuggestBooks), ctx, params)
+}
+
+// UpdateBook mocks base method.
+func (m *MockBookService) UpdateBook(ctx context.Context, params entity.UpdateBookParams) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, params)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBook indicates an expected call of UpdateBook.
+func (mr *MockBookServiceMockRecorder) UpdateBook(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookService)(nil).UpdateBook), ctx, params)
+}
+
 // MockOrderService is a mock of OrderService interface.
 type MockOrderService struct {
 	ctrl     *gomock.Controller
@@ -111,6 +215,36 @@ func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrderService) CancelOrder(ctx context.Context, params entity.CancelOrderParams) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, params)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderServiceMockRecorder) CancelOrder(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderService)(nil).CancelOrder), ctx, params)
+}
+
+// CountOrders mocks base method.
+func (m *MockOrderService) CountOrders(ctx context.Context, userID int64) (*entity.TotalCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOrders", ctx, userID)
+	ret0, _ := ret[0].(*entity.TotalCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOrders indicates an expected call of CountOrders.
+func (mr *MockOrderServiceMockRecorder) CountOrders(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOrders", reflect.TypeOf((*MockOrderService)(nil).CountOrders), ctx, userID)
+}
+
 // CreateOrder mocks base method.
 func (m *MockOrderService) CreateOrder(ctx context.Context, params entity.CreateOrderParams) (*entity.Order, error) {
 	m.ctrl.T.Helper()
@@ -126,11 +260,26 @@ func (mr *MockOrderServiceMockRecorder) CreateOrder(ctx, params interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderService)(nil).CreateOrder), ctx, params)
 }
 
+// GetOrder mocks base method.
+func (m *MockOrderService) GetOrder(ctx context.Context, params entity.GetOrderParams) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, params)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderServiceMockRecorder) GetOrder(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderService)(nil).GetOrder), ctx, params)
+}
+
 // GetOrders mocks base method.
-func (m *MockOrderService) GetOrders(ctx context.Context, params entity.GetMyOrdersParams) ([]entity.Order, error) {
+func (m *MockOrderService) GetOrders(ctx context.Context, params entity.GetMyOrdersParams) (*entity.OrderList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetOrders", ctx, params)
-	ret0, _ := ret[0].([]entity.Order)
+	ret0, _ := ret[0].(*entity.OrderList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -140,3 +289,18 @@ func (mr *MockOrderServiceMockRecorder) GetOrders(ctx, params interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderService)(nil).GetOrders), ctx, params)
 }
+
+// TransitionOrder mocks base method.
+func (m *MockOrderService) TransitionOrder(ctx context.Context, params entity.TransitionOrderParams) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionOrder", ctx, params)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionOrder indicates an expected call of TransitionOrder.
+func (mr *MockOrderServiceMockRecorder) TransitionOrder(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionOrder", reflect.TypeOf((*MockOrderService)(nil).TransitionOrder), ctx, params)
+}
@@ -37,19 +37,152 @@ func (m *MockQuerierWithTx) EXPECT() *MockQuerierWithTxMockRecorder {
 	return m.recorder
 }
 
+// AddBookCategories mocks base method.
+func (m *MockQuerierWithTx) AddBookCategories(ctx context.Context, arg db.AddBookCategoriesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBookCategories", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBookCategories indicates an expected call of AddBookCategories.
+func (mr *MockQuerierWithTxMockRecorder) AddBookCategories(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBookCategories", reflect.TypeOf((*MockQuerierWithTx)(nil).AddBookCategories), ctx, arg)
+}
+
+// ClearBooksStaging mocks base method.
+func (m *MockQuerierWithTx) ClearBooksStaging(ctx context.Context, batchID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearBooksStaging", ctx, batchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearBooksStaging indicates an expected call of ClearBooksStaging.
+func (mr *MockQuerierWithTxMockRecorder) ClearBooksStaging(ctx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearBooksStaging", reflect.TypeOf((*MockQuerierWithTx)(nil).ClearBooksStaging), ctx, batchID)
+}
+
+// ClearCart mocks base method.
+func (m *MockQuerierWithTx) ClearCart(ctx context.Context, cartID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearCart", ctx, cartID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearCart indicates an expected call of ClearCart.
+func (mr *MockQuerierWithTxMockRecorder) ClearCart(ctx, cartID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCart", reflect.TypeOf((*MockQuerierWithTx)(nil).ClearCart), ctx, cartID)
+}
+
+// CopyBooksToStaging mocks base method.
+func (m *MockQuerierWithTx) CopyBooksToStaging(ctx context.Context, arg []db.CopyBooksToStagingParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyBooksToStaging", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyBooksToStaging indicates an expected call of CopyBooksToStaging.
+func (mr *MockQuerierWithTxMockRecorder) CopyBooksToStaging(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyBooksToStaging", reflect.TypeOf((*MockQuerierWithTx)(nil).CopyBooksToStaging), ctx, arg)
+}
+
+// CountBooks mocks base method.
+func (m *MockQuerierWithTx) CountBooks(ctx context.Context, arg db.CountBooksParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBooks", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBooks indicates an expected call of CountBooks.
+func (mr *MockQuerierWithTxMockRecorder) CountBooks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBooks", reflect.TypeOf((*MockQuerierWithTx)(nil).CountBooks), ctx, arg)
+}
+
+// CountMyOrders mocks base method.
+func (m *MockQuerierWithTx) CountMyOrders(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMyOrders", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMyOrders indicates an expected call of CountMyOrders.
+func (mr *MockQuerierWithTxMockRecorder) CountMyOrders(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMyOrders", reflect.TypeOf((*MockQuerierWithTx)(nil).CountMyOrders), ctx, userID)
+}
+
+// CreateBook mocks base method.
+func (m *MockQuerierWithTx) CreateBook(ctx context.Context, arg db.CreateBookParams) (*db.CreateBookRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBook", ctx, arg)
+	ret0, _ := ret[0].(*db.CreateBookRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBook indicates an expected call of CreateBook.
+func (mr *MockQuerierWithTxMockRecorder) CreateBook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateBook), ctx, arg)
+}
+
+// CreateCategory mocks base method.
+func (m *MockQuerierWithTx) CreateCategory(ctx context.Context, arg db.CreateCategoryParams) (*db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", ctx, arg)
+	ret0, _ := ret[0].(*db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockQuerierWithTxMockRecorder) CreateCategory(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateCategory), ctx, arg)
+}
+
+// CreateGuestCart mocks base method.
+func (m *MockQuerierWithTx) CreateGuestCart(ctx context.Context, token pgtype.Text) (*db.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGuestCart", ctx, token)
+	ret0, _ := ret[0].(*db.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGuestCart indicates an expected call of CreateGuestCart.
+func (mr *MockQuerierWithTxMockRecorder) CreateGuestCart(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGuestCart", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateGuestCart), ctx, token)
+}
+
 // CreateOrder mocks base method.
-func (m *MockQuerierWithTx) CreateOrder(ctx context.Context, userID int64) (*db.CreateOrderRow, error) {
+func (m *MockQuerierWithTx) CreateOrder(ctx context.Context, arg db.CreateOrderParams) (*db.CreateOrderRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrder", ctx, userID)
+	ret := m.ctrl.Call(m, "CreateOrder", ctx, arg)
 	ret0, _ := ret[0].(*db.CreateOrderRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateOrder indicates an expected call of CreateOrder.
-func (mr *MockQuerierWithTxMockRecorder) CreateOrder(ctx, userID interface{}) *gomock.Call {
+func (mr *MockQuerierWithTxMockRecorder) CreateOrder(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateOrder), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateOrder), ctx, arg)
 }
 
 // CreateOrderItem mocks base method.
@@ -67,6 +200,81 @@ func (mr *MockQuerierWithTxMockRecorder) CreateOrderItem(ctx, arg interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderItem", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateOrderItem), ctx, arg)
 }
 
+// CreateOrderStatusChange mocks base method.
+func (m *MockQuerierWithTx) CreateOrderStatusChange(ctx context.Context, arg db.CreateOrderStatusChangeParams) (*db.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrderStatusChange", ctx, arg)
+	ret0, _ := ret[0].(*db.OrderStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrderStatusChange indicates an expected call of CreateOrderStatusChange.
+func (mr *MockQuerierWithTxMockRecorder) CreateOrderStatusChange(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderStatusChange", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateOrderStatusChange), ctx, arg)
+}
+
+// CreatePaymentAttempt mocks base method.
+func (m *MockQuerierWithTx) CreatePaymentAttempt(ctx context.Context, arg db.CreatePaymentAttemptParams) (*db.PaymentAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentAttempt", ctx, arg)
+	ret0, _ := ret[0].(*db.PaymentAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentAttempt indicates an expected call of CreatePaymentAttempt.
+func (mr *MockQuerierWithTxMockRecorder) CreatePaymentAttempt(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentAttempt", reflect.TypeOf((*MockQuerierWithTx)(nil).CreatePaymentAttempt), ctx, arg)
+}
+
+// CreatePaymentRefund mocks base method.
+func (m *MockQuerierWithTx) CreatePaymentRefund(ctx context.Context, arg db.CreatePaymentRefundParams) (*db.PaymentRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRefund", ctx, arg)
+	ret0, _ := ret[0].(*db.PaymentRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRefund indicates an expected call of CreatePaymentRefund.
+func (mr *MockQuerierWithTxMockRecorder) CreatePaymentRefund(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRefund", reflect.TypeOf((*MockQuerierWithTx)(nil).CreatePaymentRefund), ctx, arg)
+}
+
+// CreateReturn mocks base method.
+func (m *MockQuerierWithTx) CreateReturn(ctx context.Context, arg db.CreateReturnParams) (*db.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReturn", ctx, arg)
+	ret0, _ := ret[0].(*db.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReturn indicates an expected call of CreateReturn.
+func (mr *MockQuerierWithTxMockRecorder) CreateReturn(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReturn", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateReturn), ctx, arg)
+}
+
+// CreateSeries mocks base method.
+func (m *MockQuerierWithTx) CreateSeries(ctx context.Context, arg db.CreateSeriesParams) (*db.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSeries", ctx, arg)
+	ret0, _ := ret[0].(*db.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSeries indicates an expected call of CreateSeries.
+func (mr *MockQuerierWithTxMockRecorder) CreateSeries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeries", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateSeries), ctx, arg)
+}
+
 // CreateUser mocks base method.
 func (m *MockQuerierWithTx) CreateUser(ctx context.Context, email string) (*db.User, error) {
 	m.ctrl.T.Helper()
@@ -82,6 +290,153 @@ func (mr *MockQuerierWithTxMockRecorder) CreateUser(ctx, email interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockQuerierWithTx)(nil).CreateUser), ctx, email)
 }
 
+// DecrementBookStock mocks base method.
+func (m *MockQuerierWithTx) DecrementBookStock(ctx context.Context, arg db.DecrementBookStockParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementBookStock", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementBookStock indicates an expected call of DecrementBookStock.
+func (mr *MockQuerierWithTxMockRecorder) DecrementBookStock(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementBookStock", reflect.TypeOf((*MockQuerierWithTx)(nil).DecrementBookStock), ctx, arg)
+}
+
+// DeleteBookCategories mocks base method.
+func (m *MockQuerierWithTx) DeleteBookCategories(ctx context.Context, bookID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBookCategories", ctx, bookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBookCategories indicates an expected call of DeleteBookCategories.
+func (mr *MockQuerierWithTxMockRecorder) DeleteBookCategories(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBookCategories", reflect.TypeOf((*MockQuerierWithTx)(nil).DeleteBookCategories), ctx, bookID)
+}
+
+// DeleteCart mocks base method.
+func (m *MockQuerierWithTx) DeleteCart(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCart", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCart indicates an expected call of DeleteCart.
+func (mr *MockQuerierWithTxMockRecorder) DeleteCart(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCart", reflect.TypeOf((*MockQuerierWithTx)(nil).DeleteCart), ctx, id)
+}
+
+// DeleteCartItem mocks base method.
+func (m *MockQuerierWithTx) DeleteCartItem(ctx context.Context, arg db.DeleteCartItemParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartItem", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCartItem indicates an expected call of DeleteCartItem.
+func (mr *MockQuerierWithTxMockRecorder) DeleteCartItem(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartItem", reflect.TypeOf((*MockQuerierWithTx)(nil).DeleteCartItem), ctx, arg)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockQuerierWithTx) DeleteExpiredIdempotencyKeys(ctx context.Context, limit int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockQuerierWithTxMockRecorder) DeleteExpiredIdempotencyKeys(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockQuerierWithTx)(nil).DeleteExpiredIdempotencyKeys), ctx, limit)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockQuerierWithTx) DeleteIdempotencyKey(ctx context.Context, arg db.DeleteIdempotencyKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockQuerierWithTxMockRecorder) DeleteIdempotencyKey(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockQuerierWithTx)(nil).DeleteIdempotencyKey), ctx, arg)
+}
+
+// DiscontinueBook mocks base method.
+func (m *MockQuerierWithTx) DiscontinueBook(ctx context.Context, arg db.DiscontinueBookParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscontinueBook", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiscontinueBook indicates an expected call of DiscontinueBook.
+func (mr *MockQuerierWithTxMockRecorder) DiscontinueBook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscontinueBook", reflect.TypeOf((*MockQuerierWithTx)(nil).DiscontinueBook), ctx, arg)
+}
+
+// DiscontinueBooksByISBN mocks base method.
+func (m *MockQuerierWithTx) DiscontinueBooksByISBN(ctx context.Context, isbns []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscontinueBooksByISBN", ctx, isbns)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiscontinueBooksByISBN indicates an expected call of DiscontinueBooksByISBN.
+func (mr *MockQuerierWithTxMockRecorder) DiscontinueBooksByISBN(ctx, isbns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscontinueBooksByISBN", reflect.TypeOf((*MockQuerierWithTx)(nil).DiscontinueBooksByISBN), ctx, isbns)
+}
+
+// EstimateBooksCount mocks base method.
+func (m *MockQuerierWithTx) EstimateBooksCount(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateBooksCount", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateBooksCount indicates an expected call of EstimateBooksCount.
+func (mr *MockQuerierWithTxMockRecorder) EstimateBooksCount(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateBooksCount", reflect.TypeOf((*MockQuerierWithTx)(nil).EstimateBooksCount), ctx)
+}
+
+// ExportBooks mocks base method.
+func (m *MockQuerierWithTx) ExportBooks(ctx context.Context, arg db.ExportBooksParams) ([]*db.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, arg)
+	ret0, _ := ret[0].([]*db.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockQuerierWithTxMockRecorder) ExportBooks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockQuerierWithTx)(nil).ExportBooks), ctx, arg)
+}
+
 // FindBook mocks base method.
 func (m *MockQuerierWithTx) FindBook(ctx context.Context, id int64) (*db.Book, error) {
 	m.ctrl.T.Helper()
@@ -97,79 +452,734 @@ func (mr *MockQuerierWithTxMockRecorder) FindBook(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBook", reflect.TypeOf((*MockQuerierWithTx)(nil).FindBook), ctx, id)
 }
 
-// FindUser mocks base method.
-func (m *MockQuerierWithTx) FindUser(ctx context.Context, email string) (*db.User, error) {
+// FindBookBySKU mocks base method.
+func (m *MockQuerierWithTx) FindBookBySKU(ctx context.Context, sku string) (*db.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindUser", ctx, email)
-	ret0, _ := ret[0].(*db.User)
+	ret := m.ctrl.Call(m, "FindBookBySKU", ctx, sku)
+	ret0, _ := ret[0].(*db.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindUser indicates an expected call of FindUser.
-func (mr *MockQuerierWithTxMockRecorder) FindUser(ctx, email interface{}) *gomock.Call {
+// FindBookBySKU indicates an expected call of FindBookBySKU.
+func (mr *MockQuerierWithTxMockRecorder) FindBookBySKU(ctx, sku interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUser", reflect.TypeOf((*MockQuerierWithTx)(nil).FindUser), ctx, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBookBySKU", reflect.TypeOf((*MockQuerierWithTx)(nil).FindBookBySKU), ctx, sku)
 }
 
-// FindUserByToken mocks base method.
-func (m *MockQuerierWithTx) FindUserByToken(ctx context.Context, token pgtype.Text) (*db.User, error) {
+// FindCategory mocks base method.
+func (m *MockQuerierWithTx) FindCategory(ctx context.Context, id int64) (*db.Category, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindUserByToken", ctx, token)
-	ret0, _ := ret[0].(*db.User)
+	ret := m.ctrl.Call(m, "FindCategory", ctx, id)
+	ret0, _ := ret[0].(*db.Category)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FindUserByToken indicates an expected call of FindUserByToken.
-func (mr *MockQuerierWithTxMockRecorder) FindUserByToken(ctx, token interface{}) *gomock.Call {
+// FindCategory indicates an expected call of FindCategory.
+func (mr *MockQuerierWithTxMockRecorder) FindCategory(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByToken", reflect.TypeOf((*MockQuerierWithTx)(nil).FindUserByToken), ctx, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCategory", reflect.TypeOf((*MockQuerierWithTx)(nil).FindCategory), ctx, id)
 }
 
-// GetBooks mocks base method.
-func (m *MockQuerierWithTx) GetBooks(ctx context.Context, arg db.GetBooksParams) ([]*db.Book, error) {
+// FindGuestCart mocks base method.
+func (m *MockQuerierWithTx) FindGuestCart(ctx context.Context, token pgtype.Text) (*db.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBooks", ctx, arg)
-	ret0, _ := ret[0].([]*db.Book)
+	ret := m.ctrl.Call(m, "FindGuestCart", ctx, token)
+	ret0, _ := ret[0].(*db.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBooks indicates an expected call of GetBooks.
-func (mr *MockQuerierWithTxMockRecorder) GetBooks(ctx, arg interface{}) *gomock.Call {
+// FindGuestCart indicates an expected call of FindGuestCart.
+func (mr *MockQuerierWithTxMockRecorder) FindGuestCart(ctx, token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockQuerierWithTx)(nil).GetBooks), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGuestCart", reflect.TypeOf((*MockQuerierWithTx)(nil).FindGuestCart), ctx, token)
 }
 
-// GetMyOrderItems mocks base method.
-func (m *MockQuerierWithTx) GetMyOrderItems(ctx context.Context, orderID int64) ([]*db.OrderItem, error) {
+// FindIdempotencyKey mocks base method.
+func (m *MockQuerierWithTx) FindIdempotencyKey(ctx context.Context, arg db.FindIdempotencyKeyParams) (*db.IdempotencyKey, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMyOrderItems", ctx, orderID)
-	ret0, _ := ret[0].([]*db.OrderItem)
+	ret := m.ctrl.Call(m, "FindIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(*db.IdempotencyKey)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMyOrderItems indicates an expected call of GetMyOrderItems.
-func (mr *MockQuerierWithTxMockRecorder) GetMyOrderItems(ctx, orderID interface{}) *gomock.Call {
+// FindIdempotencyKey indicates an expected call of FindIdempotencyKey.
+func (mr *MockQuerierWithTxMockRecorder) FindIdempotencyKey(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyOrderItems", reflect.TypeOf((*MockQuerierWithTx)(nil).GetMyOrderItems), ctx, orderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdempotencyKey", reflect.TypeOf((*MockQuerierWithTx)(nil).FindIdempotencyKey), ctx, arg)
 }
 
-// GetMyOrders mocks base method.
-func (m *MockQuerierWithTx) GetMyOrders(ctx context.Context, arg db.GetMyOrdersParams) ([]*db.GetMyOrdersRow, error) {
+// FindOrder mocks base method.
+func (m *MockQuerierWithTx) FindOrder(ctx context.Context, id int64) (*db.FindOrderRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMyOrders", ctx, arg)
-	ret0, _ := ret[0].([]*db.GetMyOrdersRow)
+	ret := m.ctrl.Call(m, "FindOrder", ctx, id)
+	ret0, _ := ret[0].(*db.FindOrderRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetMyOrders indicates an expected call of GetMyOrders.
-func (mr *MockQuerierWithTxMockRecorder) GetMyOrders(ctx, arg interface{}) *gomock.Call {
+// FindOrder indicates an expected call of FindOrder.
+func (mr *MockQuerierWithTxMockRecorder) FindOrder(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyOrders", reflect.TypeOf((*MockQuerierWithTx)(nil).GetMyOrders), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrder", reflect.TypeOf((*MockQuerierWithTx)(nil).FindOrder), ctx, id)
+}
+
+// FindOrderForUpdate mocks base method.
+func (m *MockQuerierWithTx) FindOrderForUpdate(ctx context.Context, id int64) (*db.FindOrderForUpdateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderForUpdate", ctx, id)
+	ret0, _ := ret[0].(*db.FindOrderForUpdateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderForUpdate indicates an expected call of FindOrderForUpdate.
+func (mr *MockQuerierWithTxMockRecorder) FindOrderForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderForUpdate", reflect.TypeOf((*MockQuerierWithTx)(nil).FindOrderForUpdate), ctx, id)
+}
+
+// FindOrderItem mocks base method.
+func (m *MockQuerierWithTx) FindOrderItem(ctx context.Context, arg db.FindOrderItemParams) (*db.OrderItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderItem", ctx, arg)
+	ret0, _ := ret[0].(*db.OrderItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderItem indicates an expected call of FindOrderItem.
+func (mr *MockQuerierWithTxMockRecorder) FindOrderItem(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderItem", reflect.TypeOf((*MockQuerierWithTx)(nil).FindOrderItem), ctx, arg)
+}
+
+// FindOrderPaymentAttempt mocks base method.
+func (m *MockQuerierWithTx) FindOrderPaymentAttempt(ctx context.Context, arg db.FindOrderPaymentAttemptParams) (*db.PaymentAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderPaymentAttempt", ctx, arg)
+	ret0, _ := ret[0].(*db.PaymentAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderPaymentAttempt indicates an expected call of FindOrderPaymentAttempt.
+func (mr *MockQuerierWithTxMockRecorder) FindOrderPaymentAttempt(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderPaymentAttempt", reflect.TypeOf((*MockQuerierWithTx)(nil).FindOrderPaymentAttempt), ctx, arg)
+}
+
+// FindPaymentAttempt mocks base method.
+func (m *MockQuerierWithTx) FindPaymentAttempt(ctx context.Context, arg db.FindPaymentAttemptParams) (*db.PaymentAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPaymentAttempt", ctx, arg)
+	ret0, _ := ret[0].(*db.PaymentAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPaymentAttempt indicates an expected call of FindPaymentAttempt.
+func (mr *MockQuerierWithTxMockRecorder) FindPaymentAttempt(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPaymentAttempt", reflect.TypeOf((*MockQuerierWithTx)(nil).FindPaymentAttempt), ctx, arg)
+}
+
+// FindPendingPaymentRefundForUpdate mocks base method.
+func (m *MockQuerierWithTx) FindPendingPaymentRefundForUpdate(ctx context.Context, id int64) (*db.FindPendingPaymentRefundForUpdateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingPaymentRefundForUpdate", ctx, id)
+	ret0, _ := ret[0].(*db.FindPendingPaymentRefundForUpdateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingPaymentRefundForUpdate indicates an expected call of FindPendingPaymentRefundForUpdate.
+func (mr *MockQuerierWithTxMockRecorder) FindPendingPaymentRefundForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingPaymentRefundForUpdate", reflect.TypeOf((*MockQuerierWithTx)(nil).FindPendingPaymentRefundForUpdate), ctx, id)
+}
+
+// FindReturnForUpdate mocks base method.
+func (m *MockQuerierWithTx) FindReturnForUpdate(ctx context.Context, id int64) (*db.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReturnForUpdate", ctx, id)
+	ret0, _ := ret[0].(*db.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReturnForUpdate indicates an expected call of FindReturnForUpdate.
+func (mr *MockQuerierWithTxMockRecorder) FindReturnForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReturnForUpdate", reflect.TypeOf((*MockQuerierWithTx)(nil).FindReturnForUpdate), ctx, id)
+}
+
+// FindSeries mocks base method.
+func (m *MockQuerierWithTx) FindSeries(ctx context.Context, id int64) (*db.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSeries", ctx, id)
+	ret0, _ := ret[0].(*db.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSeries indicates an expected call of FindSeries.
+func (mr *MockQuerierWithTxMockRecorder) FindSeries(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSeries", reflect.TypeOf((*MockQuerierWithTx)(nil).FindSeries), ctx, id)
+}
+
+// FindUser mocks base method.
+func (m *MockQuerierWithTx) FindUser(ctx context.Context, email string) (*db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUser", ctx, email)
+	ret0, _ := ret[0].(*db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUser indicates an expected call of FindUser.
+func (mr *MockQuerierWithTxMockRecorder) FindUser(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUser", reflect.TypeOf((*MockQuerierWithTx)(nil).FindUser), ctx, email)
+}
+
+// FindUserByToken mocks base method.
+func (m *MockQuerierWithTx) FindUserByToken(ctx context.Context, token pgtype.Text) (*db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserByToken", ctx, token)
+	ret0, _ := ret[0].(*db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserByToken indicates an expected call of FindUserByToken.
+func (mr *MockQuerierWithTxMockRecorder) FindUserByToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByToken", reflect.TypeOf((*MockQuerierWithTx)(nil).FindUserByToken), ctx, token)
+}
+
+// FindWork mocks base method.
+func (m *MockQuerierWithTx) FindWork(ctx context.Context, id int64) (*db.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWork", ctx, id)
+	ret0, _ := ret[0].(*db.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWork indicates an expected call of FindWork.
+func (mr *MockQuerierWithTxMockRecorder) FindWork(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWork", reflect.TypeOf((*MockQuerierWithTx)(nil).FindWork), ctx, id)
+}
+
+// GetBookCategories mocks base method.
+func (m *MockQuerierWithTx) GetBookCategories(ctx context.Context, bookID int64) ([]*db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookCategories", ctx, bookID)
+	ret0, _ := ret[0].([]*db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookCategories indicates an expected call of GetBookCategories.
+func (mr *MockQuerierWithTxMockRecorder) GetBookCategories(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCategories", reflect.TypeOf((*MockQuerierWithTx)(nil).GetBookCategories), ctx, bookID)
+}
+
+// GetBookFacets mocks base method.
+func (m *MockQuerierWithTx) GetBookFacets(ctx context.Context, arg db.GetBookFacetsParams) ([]*db.GetBookFacetsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookFacets", ctx, arg)
+	ret0, _ := ret[0].([]*db.GetBookFacetsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookFacets indicates an expected call of GetBookFacets.
+func (mr *MockQuerierWithTxMockRecorder) GetBookFacets(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookFacets", reflect.TypeOf((*MockQuerierWithTx)(nil).GetBookFacets), ctx, arg)
+}
+
+// GetBooks mocks base method.
+func (m *MockQuerierWithTx) GetBooks(ctx context.Context, arg db.GetBooksParams) ([]*db.GetBooksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooks", ctx, arg)
+	ret0, _ := ret[0].([]*db.GetBooksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooks indicates an expected call of GetBooks.
+func (mr *MockQuerierWithTxMockRecorder) GetBooks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockQuerierWithTx)(nil).GetBooks), ctx, arg)
+}
+
+// GetCartItems mocks base method.
+func (m *MockQuerierWithTx) GetCartItems(ctx context.Context, cartID int64) ([]*db.GetCartItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCartItems", ctx, cartID)
+	ret0, _ := ret[0].([]*db.GetCartItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCartItems indicates an expected call of GetCartItems.
+func (mr *MockQuerierWithTxMockRecorder) GetCartItems(ctx, cartID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartItems", reflect.TypeOf((*MockQuerierWithTx)(nil).GetCartItems), ctx, cartID)
+}
+
+// GetCategories mocks base method.
+func (m *MockQuerierWithTx) GetCategories(ctx context.Context) ([]*db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategories", ctx)
+	ret0, _ := ret[0].([]*db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategories indicates an expected call of GetCategories.
+func (mr *MockQuerierWithTxMockRecorder) GetCategories(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockQuerierWithTx)(nil).GetCategories), ctx)
+}
+
+// GetExpiredOrders mocks base method.
+func (m *MockQuerierWithTx) GetExpiredOrders(ctx context.Context, limit int64) ([]*db.GetExpiredOrdersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredOrders", ctx, limit)
+	ret0, _ := ret[0].([]*db.GetExpiredOrdersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredOrders indicates an expected call of GetExpiredOrders.
+func (mr *MockQuerierWithTxMockRecorder) GetExpiredOrders(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredOrders", reflect.TypeOf((*MockQuerierWithTx)(nil).GetExpiredOrders), ctx, limit)
+}
+
+// GetMyOrders mocks base method.
+func (m *MockQuerierWithTx) GetMyOrders(ctx context.Context, arg db.GetMyOrdersParams) ([]*db.GetMyOrdersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMyOrders", ctx, arg)
+	ret0, _ := ret[0].([]*db.GetMyOrdersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMyOrders indicates an expected call of GetMyOrders.
+func (mr *MockQuerierWithTxMockRecorder) GetMyOrders(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyOrders", reflect.TypeOf((*MockQuerierWithTx)(nil).GetMyOrders), ctx, arg)
+}
+
+// GetOrCreateUserCart mocks base method.
+func (m *MockQuerierWithTx) GetOrCreateUserCart(ctx context.Context, userID pgtype.Int8) (*db.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateUserCart", ctx, userID)
+	ret0, _ := ret[0].(*db.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreateUserCart indicates an expected call of GetOrCreateUserCart.
+func (mr *MockQuerierWithTxMockRecorder) GetOrCreateUserCart(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateUserCart", reflect.TypeOf((*MockQuerierWithTx)(nil).GetOrCreateUserCart), ctx, userID)
+}
+
+// GetOrderItems mocks base method.
+func (m *MockQuerierWithTx) GetOrderItems(ctx context.Context, orderIds []int64) ([]*db.GetOrderItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderItems", ctx, orderIds)
+	ret0, _ := ret[0].([]*db.GetOrderItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderItems indicates an expected call of GetOrderItems.
+func (mr *MockQuerierWithTxMockRecorder) GetOrderItems(ctx, orderIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderItems", reflect.TypeOf((*MockQuerierWithTx)(nil).GetOrderItems), ctx, orderIds)
+}
+
+// GetOrderReturns mocks base method.
+func (m *MockQuerierWithTx) GetOrderReturns(ctx context.Context, orderIds []int64) ([]*db.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderReturns", ctx, orderIds)
+	ret0, _ := ret[0].([]*db.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderReturns indicates an expected call of GetOrderReturns.
+func (mr *MockQuerierWithTxMockRecorder) GetOrderReturns(ctx, orderIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderReturns", reflect.TypeOf((*MockQuerierWithTx)(nil).GetOrderReturns), ctx, orderIds)
+}
+
+// GetOrderStatusChanges mocks base method.
+func (m *MockQuerierWithTx) GetOrderStatusChanges(ctx context.Context, orderIds []int64) ([]*db.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderStatusChanges", ctx, orderIds)
+	ret0, _ := ret[0].([]*db.OrderStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderStatusChanges indicates an expected call of GetOrderStatusChanges.
+func (mr *MockQuerierWithTxMockRecorder) GetOrderStatusChanges(ctx, orderIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStatusChanges", reflect.TypeOf((*MockQuerierWithTx)(nil).GetOrderStatusChanges), ctx, orderIds)
+}
+
+// GetOrderTotal mocks base method.
+func (m *MockQuerierWithTx) GetOrderTotal(ctx context.Context, orderID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderTotal", ctx, orderID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderTotal indicates an expected call of GetOrderTotal.
+func (mr *MockQuerierWithTxMockRecorder) GetOrderTotal(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderTotal", reflect.TypeOf((*MockQuerierWithTx)(nil).GetOrderTotal), ctx, orderID)
+}
+
+// GetPendingPaymentRefunds mocks base method.
+func (m *MockQuerierWithTx) GetPendingPaymentRefunds(ctx context.Context, arg db.GetPendingPaymentRefundsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingPaymentRefunds", ctx, arg)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingPaymentRefunds indicates an expected call of GetPendingPaymentRefunds.
+func (mr *MockQuerierWithTxMockRecorder) GetPendingPaymentRefunds(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingPaymentRefunds", reflect.TypeOf((*MockQuerierWithTx)(nil).GetPendingPaymentRefunds), ctx, arg)
+}
+
+// GetReturnedAmount mocks base method.
+func (m *MockQuerierWithTx) GetReturnedAmount(ctx context.Context, arg db.GetReturnedAmountParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReturnedAmount", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReturnedAmount indicates an expected call of GetReturnedAmount.
+func (mr *MockQuerierWithTxMockRecorder) GetReturnedAmount(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReturnedAmount", reflect.TypeOf((*MockQuerierWithTx)(nil).GetReturnedAmount), ctx, arg)
+}
+
+// GetReturns mocks base method.
+func (m *MockQuerierWithTx) GetReturns(ctx context.Context, arg db.GetReturnsParams) ([]*db.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReturns", ctx, arg)
+	ret0, _ := ret[0].([]*db.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReturns indicates an expected call of GetReturns.
+func (mr *MockQuerierWithTxMockRecorder) GetReturns(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReturns", reflect.TypeOf((*MockQuerierWithTx)(nil).GetReturns), ctx, arg)
+}
+
+// GetSeriesOfBooks mocks base method.
+func (m *MockQuerierWithTx) GetSeriesOfBooks(ctx context.Context, bookIds []int64) ([]*db.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeriesOfBooks", ctx, bookIds)
+	ret0, _ := ret[0].([]*db.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeriesOfBooks indicates an expected call of GetSeriesOfBooks.
+func (mr *MockQuerierWithTxMockRecorder) GetSeriesOfBooks(ctx, bookIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeriesOfBooks", reflect.TypeOf((*MockQuerierWithTx)(nil).GetSeriesOfBooks), ctx, bookIds)
+}
+
+// GetSeriesVolumes mocks base method.
+func (m *MockQuerierWithTx) GetSeriesVolumes(ctx context.Context, seriesIds []int64) ([]*db.GetSeriesVolumesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeriesVolumes", ctx, seriesIds)
+	ret0, _ := ret[0].([]*db.GetSeriesVolumesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeriesVolumes indicates an expected call of GetSeriesVolumes.
+func (mr *MockQuerierWithTxMockRecorder) GetSeriesVolumes(ctx, seriesIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeriesVolumes", reflect.TypeOf((*MockQuerierWithTx)(nil).GetSeriesVolumes), ctx, seriesIds)
+}
+
+// GetWorkEditions mocks base method.
+func (m *MockQuerierWithTx) GetWorkEditions(ctx context.Context, workID int64) ([]*db.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkEditions", ctx, workID)
+	ret0, _ := ret[0].([]*db.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkEditions indicates an expected call of GetWorkEditions.
+func (mr *MockQuerierWithTxMockRecorder) GetWorkEditions(ctx, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkEditions", reflect.TypeOf((*MockQuerierWithTx)(nil).GetWorkEditions), ctx, workID)
+}
+
+// GetWorkIDsOfBooks mocks base method.
+func (m *MockQuerierWithTx) GetWorkIDsOfBooks(ctx context.Context, bookIds []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkIDsOfBooks", ctx, bookIds)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkIDsOfBooks indicates an expected call of GetWorkIDsOfBooks.
+func (mr *MockQuerierWithTxMockRecorder) GetWorkIDsOfBooks(ctx, bookIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkIDsOfBooks", reflect.TypeOf((*MockQuerierWithTx)(nil).GetWorkIDsOfBooks), ctx, bookIds)
+}
+
+// IncrementBookStock mocks base method.
+func (m *MockQuerierWithTx) IncrementBookStock(ctx context.Context, arg db.IncrementBookStockParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementBookStock", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementBookStock indicates an expected call of IncrementBookStock.
+func (mr *MockQuerierWithTxMockRecorder) IncrementBookStock(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementBookStock", reflect.TypeOf((*MockQuerierWithTx)(nil).IncrementBookStock), ctx, arg)
+}
+
+// LinkStagedBookCategories mocks base method.
+func (m *MockQuerierWithTx) LinkStagedBookCategories(ctx context.Context, batchID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkStagedBookCategories", ctx, batchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkStagedBookCategories indicates an expected call of LinkStagedBookCategories.
+func (mr *MockQuerierWithTxMockRecorder) LinkStagedBookCategories(ctx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkStagedBookCategories", reflect.TypeOf((*MockQuerierWithTx)(nil).LinkStagedBookCategories), ctx, batchID)
+}
+
+// MergeCartItems mocks base method.
+func (m *MockQuerierWithTx) MergeCartItems(ctx context.Context, arg db.MergeCartItemsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeCartItems", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeCartItems indicates an expected call of MergeCartItems.
+func (mr *MockQuerierWithTxMockRecorder) MergeCartItems(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCartItems", reflect.TypeOf((*MockQuerierWithTx)(nil).MergeCartItems), ctx, arg)
+}
+
+// ReleaseOrderStock mocks base method.
+func (m *MockQuerierWithTx) ReleaseOrderStock(ctx context.Context, id int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseOrderStock", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseOrderStock indicates an expected call of ReleaseOrderStock.
+func (mr *MockQuerierWithTxMockRecorder) ReleaseOrderStock(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseOrderStock", reflect.TypeOf((*MockQuerierWithTx)(nil).ReleaseOrderStock), ctx, id)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockQuerierWithTx) ReserveIdempotencyKey(ctx context.Context, arg db.ReserveIdempotencyKeyParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockQuerierWithTxMockRecorder) ReserveIdempotencyKey(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockQuerierWithTx)(nil).ReserveIdempotencyKey), ctx, arg)
+}
+
+// SaveIdempotencyResponse mocks base method.
+func (m *MockQuerierWithTx) SaveIdempotencyResponse(ctx context.Context, arg db.SaveIdempotencyResponseParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotencyResponse", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyResponse indicates an expected call of SaveIdempotencyResponse.
+func (mr *MockQuerierWithTxMockRecorder) SaveIdempotencyResponse(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyResponse", reflect.TypeOf((*MockQuerierWithTx)(nil).SaveIdempotencyResponse), ctx, arg)
+}
+
+// SetBookCover mocks base method.
+func (m *MockQuerierWithTx) SetBookCover(ctx context.Context, arg db.SetBookCoverParams) (*db.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookCover", ctx, arg)
+	ret0, _ := ret[0].(*db.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookCover indicates an expected call of SetBookCover.
+func (mr *MockQuerierWithTxMockRecorder) SetBookCover(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookCover", reflect.TypeOf((*MockQuerierWithTx)(nil).SetBookCover), ctx, arg)
+}
+
+// SetCartItem mocks base method.
+func (m *MockQuerierWithTx) SetCartItem(ctx context.Context, arg db.SetCartItemParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCartItem", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCartItem indicates an expected call of SetCartItem.
+func (mr *MockQuerierWithTxMockRecorder) SetCartItem(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCartItem", reflect.TypeOf((*MockQuerierWithTx)(nil).SetCartItem), ctx, arg)
+}
+
+// SuggestBooks mocks base method.
+func (m *MockQuerierWithTx) SuggestBooks(ctx context.Context, arg db.SuggestBooksParams) ([]*db.SuggestBooksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestBooks", ctx, arg)
+	ret0, _ := ret[0].([]*db.SuggestBooksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestBooks indicates an expected call of SuggestBooks.
+func (mr *MockQuerierWithTxMockRecorder) SuggestBooks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestBooks", reflect.TypeOf((*MockQuerierWithTx)(nil).SuggestBooks), ctx, arg)
+}
+
+// UpdateBook mocks base method.
+func (m *MockQuerierWithTx) UpdateBook(ctx context.Context, arg db.UpdateBookParams) (*db.UpdateBookRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, arg)
+	ret0, _ := ret[0].(*db.UpdateBookRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBook indicates an expected call of UpdateBook.
+func (mr *MockQuerierWithTxMockRecorder) UpdateBook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockQuerierWithTx)(nil).UpdateBook), ctx, arg)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockQuerierWithTx) UpdateOrderStatus(ctx context.Context, arg db.UpdateOrderStatusParams) (*db.UpdateOrderStatusRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, arg)
+	ret0, _ := ret[0].(*db.UpdateOrderStatusRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockQuerierWithTxMockRecorder) UpdateOrderStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockQuerierWithTx)(nil).UpdateOrderStatus), ctx, arg)
+}
+
+// UpdatePaymentAttempt mocks base method.
+func (m *MockQuerierWithTx) UpdatePaymentAttempt(ctx context.Context, arg db.UpdatePaymentAttemptParams) (*db.PaymentAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentAttempt", ctx, arg)
+	ret0, _ := ret[0].(*db.PaymentAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentAttempt indicates an expected call of UpdatePaymentAttempt.
+func (mr *MockQuerierWithTxMockRecorder) UpdatePaymentAttempt(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentAttempt", reflect.TypeOf((*MockQuerierWithTx)(nil).UpdatePaymentAttempt), ctx, arg)
+}
+
+// UpdatePaymentRefund mocks base method.
+func (m *MockQuerierWithTx) UpdatePaymentRefund(ctx context.Context, arg db.UpdatePaymentRefundParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentRefund", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePaymentRefund indicates an expected call of UpdatePaymentRefund.
+func (mr *MockQuerierWithTxMockRecorder) UpdatePaymentRefund(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentRefund", reflect.TypeOf((*MockQuerierWithTx)(nil).UpdatePaymentRefund), ctx, arg)
+}
+
+// UpdateReturn mocks base method.
+func (m *MockQuerierWithTx) UpdateReturn(ctx context.Context, arg db.UpdateReturnParams) (*db.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReturn", ctx, arg)
+	ret0, _ := ret[0].(*db.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReturn indicates an expected call of UpdateReturn.
+func (mr *MockQuerierWithTxMockRecorder) UpdateReturn(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReturn", reflect.TypeOf((*MockQuerierWithTx)(nil).UpdateReturn), ctx, arg)
+}
+
+// UpsertBooksFromStaging mocks base method.
+func (m *MockQuerierWithTx) UpsertBooksFromStaging(ctx context.Context, batchID string) (*db.UpsertBooksFromStagingRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertBooksFromStaging", ctx, batchID)
+	ret0, _ := ret[0].(*db.UpsertBooksFromStagingRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertBooksFromStaging indicates an expected call of UpsertBooksFromStaging.
+func (mr *MockQuerierWithTxMockRecorder) UpsertBooksFromStaging(ctx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBooksFromStaging", reflect.TypeOf((*MockQuerierWithTx)(nil).UpsertBooksFromStaging), ctx, batchID)
 }
 
 // WrapTx mocks base method.
@@ -6,7 +6,9 @@ package mock_service
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	pgx "github.com/jackc/pgx/v5"
@@ -74,6 +76,95 @@ func (m *MockBookRepository) EXPECT() *MockBookRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountBooks mocks base method.
+func (m *MockBookRepository) CountBooks(ctx context.Context, arg entity.GetBooksParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBooks", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBooks indicates an expected call of CountBooks.
+func (mr *MockBookRepositoryMockRecorder) CountBooks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBooks", reflect.TypeOf((*MockBookRepository)(nil).CountBooks), ctx, arg)
+}
+
+// CreateBook mocks base method.
+func (m *MockBookRepository) CreateBook(ctx context.Context, arg entity.CreateBookParams) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBook", ctx, arg)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBook indicates an expected call of CreateBook.
+func (mr *MockBookRepositoryMockRecorder) CreateBook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockBookRepository)(nil).CreateBook), ctx, arg)
+}
+
+// DeleteBook mocks base method.
+func (m *MockBookRepository) DeleteBook(ctx context.Context, arg entity.DeleteBookParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBook indicates an expected call of DeleteBook.
+func (mr *MockBookRepositoryMockRecorder) DeleteBook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookRepository)(nil).DeleteBook), ctx, arg)
+}
+
+// EstimateBooksCount mocks base method.
+func (m *MockBookRepository) EstimateBooksCount(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateBooksCount", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateBooksCount indicates an expected call of EstimateBooksCount.
+func (mr *MockBookRepositoryMockRecorder) EstimateBooksCount(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateBooksCount", reflect.TypeOf((*MockBookRepository)(nil).EstimateBooksCount), ctx)
+}
+
+// FindWork mocks base method.
+func (m *MockBookRepository) FindWork(ctx context.Context, id int64) (*entity.Work, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWork", ctx, id)
+	ret0, _ := ret[0].(*entity.Work)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWork indicates an expected call of FindWork.
+func (mr *MockBookRepositoryMockRecorder) FindWork(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWork", reflect.TypeOf((*MockBookRepository)(nil).FindWork), ctx, id)
+}
+
+// GetBookFacets mocks base method.
+func (m *MockBookRepository) GetBookFacets(ctx context.Context, arg entity.GetBooksParams) (*entity.BookFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookFacets", ctx, arg)
+	ret0, _ := ret[0].(*entity.BookFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookFacets indicates an expected call of GetBookFacets.
+func (mr *MockBookRepositoryMockRecorder) GetBookFacets(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookFacets", reflect.TypeOf((*MockBookRepository)(nil).GetBookFacets), ctx, arg)
+}
+
 // GetBooks mocks base method.
 func (m *MockBookRepository) GetBooks(ctx context.Context, arg entity.GetBooksParams) ([]entity.Book, error) {
 	m.ctrl.T.Helper()
@@ -89,6 +180,51 @@ func (mr *MockBookRepositoryMockRecorder) GetBooks(ctx, arg interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookRepository)(nil).GetBooks), ctx, arg)
 }
 
+// GetWorkEditions mocks base method.
+func (m *MockBookRepository) GetWorkEditions(ctx context.Context, workID int64) ([]entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkEditions", ctx, workID)
+	ret0, _ := ret[0].([]entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkEditions indicates an expected call of GetWorkEditions.
+func (mr *MockBookRepositoryMockRecorder) GetWorkEditions(ctx, workID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkEditions", reflect.TypeOf((*MockBookRepository)(nil).GetWorkEditions), ctx, workID)
+}
+
+// SuggestBooks mocks base method.
+func (m *MockBookRepository) SuggestBooks(ctx context.Context, arg entity.SuggestBooksParams) ([]entity.BookSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestBooks", ctx, arg)
+	ret0, _ := ret[0].([]entity.BookSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestBooks indicates an expected call of SuggestBooks.
+func (mr *MockBookRepositoryMockRecorder) SuggestBooks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestBooks", reflect.TypeOf((*MockBookRepository)(nil).SuggestBooks), ctx, arg)
+}
+
+// UpdateBook mocks base method.
+func (m *MockBookRepository) UpdateBook(ctx context.Context, arg entity.UpdateBookParams) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, arg)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBook indicates an expected call of UpdateBook.
+func (mr *MockBookRepositoryMockRecorder) UpdateBook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookRepository)(nil).UpdateBook), ctx, arg)
+}
+
 // MockOrderRepository is a mock of OrderRepository interface.
 type MockOrderRepository struct {
 	ctrl     *gomock.Controller
@@ -112,6 +248,21 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountMyOrders mocks base method.
+func (m *MockOrderRepository) CountMyOrders(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMyOrders", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMyOrders indicates an expected call of CountMyOrders.
+func (mr *MockOrderRepositoryMockRecorder) CountMyOrders(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMyOrders", reflect.TypeOf((*MockOrderRepository)(nil).CountMyOrders), ctx, userID)
+}
+
 // CreateOrder mocks base method.
 func (m *MockOrderRepository) CreateOrder(ctx context.Context, tx pgx.Tx, arg entity.CreateOrderParams) (*entity.Order, error) {
 	m.ctrl.T.Helper()
@@ -142,6 +293,51 @@ func (mr *MockOrderRepositoryMockRecorder) CreateOrderItem(ctx, tx, params inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderItem", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrderItem), ctx, tx, params)
 }
 
+// CreateOrderStatusChange mocks base method.
+func (m *MockOrderRepository) CreateOrderStatusChange(ctx context.Context, tx pgx.Tx, change entity.OrderStatusChange) (*entity.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrderStatusChange", ctx, tx, change)
+	ret0, _ := ret[0].(*entity.OrderStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrderStatusChange indicates an expected call of CreateOrderStatusChange.
+func (mr *MockOrderRepositoryMockRecorder) CreateOrderStatusChange(ctx, tx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderStatusChange", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrderStatusChange), ctx, tx, change)
+}
+
+// CreatePaymentRefund mocks base method.
+func (m *MockOrderRepository) CreatePaymentRefund(ctx context.Context, tx pgx.Tx, refund entity.PaymentRefund) (*entity.PaymentRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRefund", ctx, tx, refund)
+	ret0, _ := ret[0].(*entity.PaymentRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRefund indicates an expected call of CreatePaymentRefund.
+func (mr *MockOrderRepositoryMockRecorder) CreatePaymentRefund(ctx, tx, refund interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRefund", reflect.TypeOf((*MockOrderRepository)(nil).CreatePaymentRefund), ctx, tx, refund)
+}
+
+// DecrementBookStock mocks base method.
+func (m *MockOrderRepository) DecrementBookStock(ctx context.Context, tx pgx.Tx, sku string, amount int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementBookStock", ctx, tx, sku, amount)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementBookStock indicates an expected call of DecrementBookStock.
+func (mr *MockOrderRepositoryMockRecorder) DecrementBookStock(ctx, tx, sku, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementBookStock", reflect.TypeOf((*MockOrderRepository)(nil).DecrementBookStock), ctx, tx, sku, amount)
+}
+
 // FindBook mocks base method.
 func (m *MockOrderRepository) FindBook(ctx context.Context, tx pgx.Tx, id int64) (*entity.Book, error) {
 	m.ctrl.T.Helper()
@@ -157,6 +353,96 @@ func (mr *MockOrderRepositoryMockRecorder) FindBook(ctx, tx, id interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBook", reflect.TypeOf((*MockOrderRepository)(nil).FindBook), ctx, tx, id)
 }
 
+// FindBookBySKU mocks base method.
+func (m *MockOrderRepository) FindBookBySKU(ctx context.Context, tx pgx.Tx, sku string) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBookBySKU", ctx, tx, sku)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBookBySKU indicates an expected call of FindBookBySKU.
+func (mr *MockOrderRepositoryMockRecorder) FindBookBySKU(ctx, tx, sku interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBookBySKU", reflect.TypeOf((*MockOrderRepository)(nil).FindBookBySKU), ctx, tx, sku)
+}
+
+// FindOrder mocks base method.
+func (m *MockOrderRepository) FindOrder(ctx context.Context, id int64) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrder", ctx, id)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrder indicates an expected call of FindOrder.
+func (mr *MockOrderRepositoryMockRecorder) FindOrder(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrder", reflect.TypeOf((*MockOrderRepository)(nil).FindOrder), ctx, id)
+}
+
+// FindOrderForUpdate mocks base method.
+func (m *MockOrderRepository) FindOrderForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderForUpdate", ctx, tx, id)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderForUpdate indicates an expected call of FindOrderForUpdate.
+func (mr *MockOrderRepositoryMockRecorder) FindOrderForUpdate(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderForUpdate", reflect.TypeOf((*MockOrderRepository)(nil).FindOrderForUpdate), ctx, tx, id)
+}
+
+// FindOrderPayment mocks base method.
+func (m *MockOrderRepository) FindOrderPayment(ctx context.Context, tx pgx.Tx, orderID int64, status string) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderPayment", ctx, tx, orderID, status)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderPayment indicates an expected call of FindOrderPayment.
+func (mr *MockOrderRepositoryMockRecorder) FindOrderPayment(ctx, tx, orderID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderPayment", reflect.TypeOf((*MockOrderRepository)(nil).FindOrderPayment), ctx, tx, orderID, status)
+}
+
+// FindPendingPaymentRefundForUpdate mocks base method.
+func (m *MockOrderRepository) FindPendingPaymentRefundForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.PaymentRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingPaymentRefundForUpdate", ctx, tx, id)
+	ret0, _ := ret[0].(*entity.PaymentRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingPaymentRefundForUpdate indicates an expected call of FindPendingPaymentRefundForUpdate.
+func (mr *MockOrderRepositoryMockRecorder) FindPendingPaymentRefundForUpdate(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingPaymentRefundForUpdate", reflect.TypeOf((*MockOrderRepository)(nil).FindPendingPaymentRefundForUpdate), ctx, tx, id)
+}
+
+// GetExpiredOrders mocks base method.
+func (m *MockOrderRepository) GetExpiredOrders(ctx context.Context, tx pgx.Tx, limit int64) ([]entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredOrders", ctx, tx, limit)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredOrders indicates an expected call of GetExpiredOrders.
+func (mr *MockOrderRepositoryMockRecorder) GetExpiredOrders(ctx, tx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetExpiredOrders), ctx, tx, limit)
+}
+
 // GetMyOrders mocks base method.
 func (m *MockOrderRepository) GetMyOrders(ctx context.Context, arg entity.GetMyOrdersParams) ([]entity.Order, error) {
 	m.ctrl.T.Helper()
@@ -171,3 +457,1071 @@ func (mr *MockOrderRepositoryMockRecorder) GetMyOrders(ctx, arg interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetMyOrders), ctx, arg)
 }
+
+// GetOrderStatusChanges mocks base method.
+func (m *MockOrderRepository) GetOrderStatusChanges(ctx context.Context, orderIDs []int64) ([]entity.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderStatusChanges", ctx, orderIDs)
+	ret0, _ := ret[0].([]entity.OrderStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderStatusChanges indicates an expected call of GetOrderStatusChanges.
+func (mr *MockOrderRepositoryMockRecorder) GetOrderStatusChanges(ctx, orderIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStatusChanges", reflect.TypeOf((*MockOrderRepository)(nil).GetOrderStatusChanges), ctx, orderIDs)
+}
+
+// GetPendingPaymentRefunds mocks base method.
+func (m *MockOrderRepository) GetPendingPaymentRefunds(ctx context.Context, before time.Time, limit int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingPaymentRefunds", ctx, before, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingPaymentRefunds indicates an expected call of GetPendingPaymentRefunds.
+func (mr *MockOrderRepositoryMockRecorder) GetPendingPaymentRefunds(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingPaymentRefunds", reflect.TypeOf((*MockOrderRepository)(nil).GetPendingPaymentRefunds), ctx, before, limit)
+}
+
+// ReleaseOrderStock mocks base method.
+func (m *MockOrderRepository) ReleaseOrderStock(ctx context.Context, tx pgx.Tx, orderID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseOrderStock", ctx, tx, orderID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseOrderStock indicates an expected call of ReleaseOrderStock.
+func (mr *MockOrderRepositoryMockRecorder) ReleaseOrderStock(ctx, tx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseOrderStock", reflect.TypeOf((*MockOrderRepository)(nil).ReleaseOrderStock), ctx, tx, orderID)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderRepository) UpdateOrderStatus(ctx context.Context, tx pgx.Tx, id int64, from, to string) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, tx, id, from, to)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdateOrderStatus(ctx, tx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateOrderStatus), ctx, tx, id, from, to)
+}
+
+// UpdatePaymentAttempt mocks base method.
+func (m *MockOrderRepository) UpdatePaymentAttempt(ctx context.Context, tx pgx.Tx, payment entity.Payment) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentAttempt", ctx, tx, payment)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentAttempt indicates an expected call of UpdatePaymentAttempt.
+func (mr *MockOrderRepositoryMockRecorder) UpdatePaymentAttempt(ctx, tx, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentAttempt", reflect.TypeOf((*MockOrderRepository)(nil).UpdatePaymentAttempt), ctx, tx, payment)
+}
+
+// UpdatePaymentRefund mocks base method.
+func (m *MockOrderRepository) UpdatePaymentRefund(ctx context.Context, tx pgx.Tx, refund entity.PaymentRefund) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentRefund", ctx, tx, refund)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePaymentRefund indicates an expected call of UpdatePaymentRefund.
+func (mr *MockOrderRepositoryMockRecorder) UpdatePaymentRefund(ctx, tx, refund interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentRefund", reflect.TypeOf((*MockOrderRepository)(nil).UpdatePaymentRefund), ctx, tx, refund)
+}
+
+// MockPaymentRepository is a mock of PaymentRepository interface.
+type MockPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRepositoryMockRecorder
+}
+
+// MockPaymentRepositoryMockRecorder is the mock recorder for MockPaymentRepository.
+type MockPaymentRepositoryMockRecorder struct {
+	mock *MockPaymentRepository
+}
+
+// NewMockPaymentRepository creates a new mock instance.
+func NewMockPaymentRepository(ctrl *gomock.Controller) *MockPaymentRepository {
+	mock := &MockPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePaymentAttempt mocks base method.
+func (m *MockPaymentRepository) CreatePaymentAttempt(ctx context.Context, tx pgx.Tx, payment entity.Payment) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentAttempt", ctx, tx, payment)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentAttempt indicates an expected call of CreatePaymentAttempt.
+func (mr *MockPaymentRepositoryMockRecorder) CreatePaymentAttempt(ctx, tx, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentAttempt", reflect.TypeOf((*MockPaymentRepository)(nil).CreatePaymentAttempt), ctx, tx, payment)
+}
+
+// FindOrderForUpdate mocks base method.
+func (m *MockPaymentRepository) FindOrderForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderForUpdate", ctx, tx, id)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderForUpdate indicates an expected call of FindOrderForUpdate.
+func (mr *MockPaymentRepositoryMockRecorder) FindOrderForUpdate(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderForUpdate", reflect.TypeOf((*MockPaymentRepository)(nil).FindOrderForUpdate), ctx, tx, id)
+}
+
+// FindOrderPayment mocks base method.
+func (m *MockPaymentRepository) FindOrderPayment(ctx context.Context, tx pgx.Tx, orderID int64, status string) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderPayment", ctx, tx, orderID, status)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderPayment indicates an expected call of FindOrderPayment.
+func (mr *MockPaymentRepositoryMockRecorder) FindOrderPayment(ctx, tx, orderID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderPayment", reflect.TypeOf((*MockPaymentRepository)(nil).FindOrderPayment), ctx, tx, orderID, status)
+}
+
+// FindPaymentAttempt mocks base method.
+func (m *MockPaymentRepository) FindPaymentAttempt(ctx context.Context, tx pgx.Tx, provider, reference string) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPaymentAttempt", ctx, tx, provider, reference)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPaymentAttempt indicates an expected call of FindPaymentAttempt.
+func (mr *MockPaymentRepositoryMockRecorder) FindPaymentAttempt(ctx, tx, provider, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPaymentAttempt", reflect.TypeOf((*MockPaymentRepository)(nil).FindPaymentAttempt), ctx, tx, provider, reference)
+}
+
+// GetOrderTotal mocks base method.
+func (m *MockPaymentRepository) GetOrderTotal(ctx context.Context, tx pgx.Tx, orderID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderTotal", ctx, tx, orderID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderTotal indicates an expected call of GetOrderTotal.
+func (mr *MockPaymentRepositoryMockRecorder) GetOrderTotal(ctx, tx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderTotal", reflect.TypeOf((*MockPaymentRepository)(nil).GetOrderTotal), ctx, tx, orderID)
+}
+
+// UpdatePaymentAttempt mocks base method.
+func (m *MockPaymentRepository) UpdatePaymentAttempt(ctx context.Context, tx pgx.Tx, payment entity.Payment) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentAttempt", ctx, tx, payment)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentAttempt indicates an expected call of UpdatePaymentAttempt.
+func (mr *MockPaymentRepositoryMockRecorder) UpdatePaymentAttempt(ctx, tx, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentAttempt", reflect.TypeOf((*MockPaymentRepository)(nil).UpdatePaymentAttempt), ctx, tx, payment)
+}
+
+// MockReturnRepository is a mock of ReturnRepository interface.
+type MockReturnRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReturnRepositoryMockRecorder
+}
+
+// MockReturnRepositoryMockRecorder is the mock recorder for MockReturnRepository.
+type MockReturnRepositoryMockRecorder struct {
+	mock *MockReturnRepository
+}
+
+// NewMockReturnRepository creates a new mock instance.
+func NewMockReturnRepository(ctrl *gomock.Controller) *MockReturnRepository {
+	mock := &MockReturnRepository{ctrl: ctrl}
+	mock.recorder = &MockReturnRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReturnRepository) EXPECT() *MockReturnRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateReturn mocks base method.
+func (m *MockReturnRepository) CreateReturn(ctx context.Context, tx pgx.Tx, ret entity.Return) (*entity.Return, error) {
+	m.ctrl.T.Helper()
+	ret_2 := m.ctrl.Call(m, "CreateReturn", ctx, tx, ret)
+	ret0, _ := ret_2[0].(*entity.Return)
+	ret1, _ := ret_2[1].(error)
+	return ret0, ret1
+}
+
+// CreateReturn indicates an expected call of CreateReturn.
+func (mr *MockReturnRepositoryMockRecorder) CreateReturn(ctx, tx, ret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReturn", reflect.TypeOf((*MockReturnRepository)(nil).CreateReturn), ctx, tx, ret)
+}
+
+// FindOrderForUpdate mocks base method.
+func (m *MockReturnRepository) FindOrderForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderForUpdate", ctx, tx, id)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderForUpdate indicates an expected call of FindOrderForUpdate.
+func (mr *MockReturnRepositoryMockRecorder) FindOrderForUpdate(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderForUpdate", reflect.TypeOf((*MockReturnRepository)(nil).FindOrderForUpdate), ctx, tx, id)
+}
+
+// FindOrderItem mocks base method.
+func (m *MockReturnRepository) FindOrderItem(ctx context.Context, tx pgx.Tx, orderID, id int64) (*entity.OrderItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderItem", ctx, tx, orderID, id)
+	ret0, _ := ret[0].(*entity.OrderItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderItem indicates an expected call of FindOrderItem.
+func (mr *MockReturnRepositoryMockRecorder) FindOrderItem(ctx, tx, orderID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderItem", reflect.TypeOf((*MockReturnRepository)(nil).FindOrderItem), ctx, tx, orderID, id)
+}
+
+// FindReturnForUpdate mocks base method.
+func (m *MockReturnRepository) FindReturnForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*entity.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReturnForUpdate", ctx, tx, id)
+	ret0, _ := ret[0].(*entity.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReturnForUpdate indicates an expected call of FindReturnForUpdate.
+func (mr *MockReturnRepositoryMockRecorder) FindReturnForUpdate(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReturnForUpdate", reflect.TypeOf((*MockReturnRepository)(nil).FindReturnForUpdate), ctx, tx, id)
+}
+
+// GetReturnedAmount mocks base method.
+func (m *MockReturnRepository) GetReturnedAmount(ctx context.Context, tx pgx.Tx, orderID, orderItemID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReturnedAmount", ctx, tx, orderID, orderItemID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReturnedAmount indicates an expected call of GetReturnedAmount.
+func (mr *MockReturnRepositoryMockRecorder) GetReturnedAmount(ctx, tx, orderID, orderItemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReturnedAmount", reflect.TypeOf((*MockReturnRepository)(nil).GetReturnedAmount), ctx, tx, orderID, orderItemID)
+}
+
+// GetReturns mocks base method.
+func (m *MockReturnRepository) GetReturns(ctx context.Context, arg entity.GetReturnsParams) ([]entity.Return, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReturns", ctx, arg)
+	ret0, _ := ret[0].([]entity.Return)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReturns indicates an expected call of GetReturns.
+func (mr *MockReturnRepositoryMockRecorder) GetReturns(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReturns", reflect.TypeOf((*MockReturnRepository)(nil).GetReturns), ctx, arg)
+}
+
+// IncrementBookStock mocks base method.
+func (m *MockReturnRepository) IncrementBookStock(ctx context.Context, tx pgx.Tx, sku string, amount int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementBookStock", ctx, tx, sku, amount)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementBookStock indicates an expected call of IncrementBookStock.
+func (mr *MockReturnRepositoryMockRecorder) IncrementBookStock(ctx, tx, sku, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementBookStock", reflect.TypeOf((*MockReturnRepository)(nil).IncrementBookStock), ctx, tx, sku, amount)
+}
+
+// UpdateReturn mocks base method.
+func (m *MockReturnRepository) UpdateReturn(ctx context.Context, tx pgx.Tx, ret entity.Return) (*entity.Return, error) {
+	m.ctrl.T.Helper()
+	ret_2 := m.ctrl.Call(m, "UpdateReturn", ctx, tx, ret)
+	ret0, _ := ret_2[0].(*entity.Return)
+	ret1, _ := ret_2[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReturn indicates an expected call of UpdateReturn.
+func (mr *MockReturnRepositoryMockRecorder) UpdateReturn(ctx, tx, ret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReturn", reflect.TypeOf((*MockReturnRepository)(nil).UpdateReturn), ctx, tx, ret)
+}
+
+// MockCartRepository is a mock of CartRepository interface.
+type MockCartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartRepositoryMockRecorder
+}
+
+// MockCartRepositoryMockRecorder is the mock recorder for MockCartRepository.
+type MockCartRepositoryMockRecorder struct {
+	mock *MockCartRepository
+}
+
+// NewMockCartRepository creates a new mock instance.
+func NewMockCartRepository(ctrl *gomock.Controller) *MockCartRepository {
+	mock := &MockCartRepository{ctrl: ctrl}
+	mock.recorder = &MockCartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartRepository) EXPECT() *MockCartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClearCart mocks base method.
+func (m *MockCartRepository) ClearCart(ctx context.Context, tx pgx.Tx, cartID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearCart", ctx, tx, cartID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearCart indicates an expected call of ClearCart.
+func (mr *MockCartRepositoryMockRecorder) ClearCart(ctx, tx, cartID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCart", reflect.TypeOf((*MockCartRepository)(nil).ClearCart), ctx, tx, cartID)
+}
+
+// CreateGuestCart mocks base method.
+func (m *MockCartRepository) CreateGuestCart(ctx context.Context, tx pgx.Tx, token string) (*entity.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGuestCart", ctx, tx, token)
+	ret0, _ := ret[0].(*entity.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGuestCart indicates an expected call of CreateGuestCart.
+func (mr *MockCartRepositoryMockRecorder) CreateGuestCart(ctx, tx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGuestCart", reflect.TypeOf((*MockCartRepository)(nil).CreateGuestCart), ctx, tx, token)
+}
+
+// DeleteCartItem mocks base method.
+func (m *MockCartRepository) DeleteCartItem(ctx context.Context, tx pgx.Tx, cartID int64, sku string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartItem", ctx, tx, cartID, sku)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCartItem indicates an expected call of DeleteCartItem.
+func (mr *MockCartRepositoryMockRecorder) DeleteCartItem(ctx, tx, cartID, sku interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartItem", reflect.TypeOf((*MockCartRepository)(nil).DeleteCartItem), ctx, tx, cartID, sku)
+}
+
+// FindBookBySKU mocks base method.
+func (m *MockCartRepository) FindBookBySKU(ctx context.Context, tx pgx.Tx, sku string) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBookBySKU", ctx, tx, sku)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBookBySKU indicates an expected call of FindBookBySKU.
+func (mr *MockCartRepositoryMockRecorder) FindBookBySKU(ctx, tx, sku interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBookBySKU", reflect.TypeOf((*MockCartRepository)(nil).FindBookBySKU), ctx, tx, sku)
+}
+
+// FindGuestCart mocks base method.
+func (m *MockCartRepository) FindGuestCart(ctx context.Context, tx pgx.Tx, token string) (*entity.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGuestCart", ctx, tx, token)
+	ret0, _ := ret[0].(*entity.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGuestCart indicates an expected call of FindGuestCart.
+func (mr *MockCartRepositoryMockRecorder) FindGuestCart(ctx, tx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGuestCart", reflect.TypeOf((*MockCartRepository)(nil).FindGuestCart), ctx, tx, token)
+}
+
+// GetCartItems mocks base method.
+func (m *MockCartRepository) GetCartItems(ctx context.Context, tx pgx.Tx, cartID int64) ([]entity.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCartItems", ctx, tx, cartID)
+	ret0, _ := ret[0].([]entity.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCartItems indicates an expected call of GetCartItems.
+func (mr *MockCartRepositoryMockRecorder) GetCartItems(ctx, tx, cartID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartItems", reflect.TypeOf((*MockCartRepository)(nil).GetCartItems), ctx, tx, cartID)
+}
+
+// GetUserCart mocks base method.
+func (m *MockCartRepository) GetUserCart(ctx context.Context, tx pgx.Tx, userID int64) (*entity.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCart", ctx, tx, userID)
+	ret0, _ := ret[0].(*entity.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCart indicates an expected call of GetUserCart.
+func (mr *MockCartRepositoryMockRecorder) GetUserCart(ctx, tx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCart", reflect.TypeOf((*MockCartRepository)(nil).GetUserCart), ctx, tx, userID)
+}
+
+// MergeCart mocks base method.
+func (m *MockCartRepository) MergeCart(ctx context.Context, tx pgx.Tx, from, to int64, items []entity.CartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeCart", ctx, tx, from, to, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeCart indicates an expected call of MergeCart.
+func (mr *MockCartRepositoryMockRecorder) MergeCart(ctx, tx, from, to, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCart", reflect.TypeOf((*MockCartRepository)(nil).MergeCart), ctx, tx, from, to, items)
+}
+
+// SetCartItem mocks base method.
+func (m *MockCartRepository) SetCartItem(ctx context.Context, tx pgx.Tx, cartID int64, item entity.CartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCartItem", ctx, tx, cartID, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCartItem indicates an expected call of SetCartItem.
+func (mr *MockCartRepositoryMockRecorder) SetCartItem(ctx, tx, cartID, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCartItem", reflect.TypeOf((*MockCartRepository)(nil).SetCartItem), ctx, tx, cartID, item)
+}
+
+// MockImportRepository is a mock of ImportRepository interface.
+type MockImportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportRepositoryMockRecorder
+}
+
+// MockImportRepositoryMockRecorder is the mock recorder for MockImportRepository.
+type MockImportRepositoryMockRecorder struct {
+	mock *MockImportRepository
+}
+
+// NewMockImportRepository creates a new mock instance.
+func NewMockImportRepository(ctrl *gomock.Controller) *MockImportRepository {
+	mock := &MockImportRepository{ctrl: ctrl}
+	mock.recorder = &MockImportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportRepository) EXPECT() *MockImportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClearBooksStaging mocks base method.
+func (m *MockImportRepository) ClearBooksStaging(ctx context.Context, tx pgx.Tx, batchID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearBooksStaging", ctx, tx, batchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearBooksStaging indicates an expected call of ClearBooksStaging.
+func (mr *MockImportRepositoryMockRecorder) ClearBooksStaging(ctx, tx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearBooksStaging", reflect.TypeOf((*MockImportRepository)(nil).ClearBooksStaging), ctx, tx, batchID)
+}
+
+// CopyBooksToStaging mocks base method.
+func (m *MockImportRepository) CopyBooksToStaging(ctx context.Context, tx pgx.Tx, batchID string, rows []entity.BookImportRow) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyBooksToStaging", ctx, tx, batchID, rows)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyBooksToStaging indicates an expected call of CopyBooksToStaging.
+func (mr *MockImportRepositoryMockRecorder) CopyBooksToStaging(ctx, tx, batchID, rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyBooksToStaging", reflect.TypeOf((*MockImportRepository)(nil).CopyBooksToStaging), ctx, tx, batchID, rows)
+}
+
+// DiscontinueBooksByISBN mocks base method.
+func (m *MockImportRepository) DiscontinueBooksByISBN(ctx context.Context, tx pgx.Tx, isbns []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscontinueBooksByISBN", ctx, tx, isbns)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiscontinueBooksByISBN indicates an expected call of DiscontinueBooksByISBN.
+func (mr *MockImportRepositoryMockRecorder) DiscontinueBooksByISBN(ctx, tx, isbns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscontinueBooksByISBN", reflect.TypeOf((*MockImportRepository)(nil).DiscontinueBooksByISBN), ctx, tx, isbns)
+}
+
+// LinkStagedBookCategories mocks base method.
+func (m *MockImportRepository) LinkStagedBookCategories(ctx context.Context, tx pgx.Tx, batchID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkStagedBookCategories", ctx, tx, batchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkStagedBookCategories indicates an expected call of LinkStagedBookCategories.
+func (mr *MockImportRepositoryMockRecorder) LinkStagedBookCategories(ctx, tx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkStagedBookCategories", reflect.TypeOf((*MockImportRepository)(nil).LinkStagedBookCategories), ctx, tx, batchID)
+}
+
+// UpsertBooksFromStaging mocks base method.
+func (m *MockImportRepository) UpsertBooksFromStaging(ctx context.Context, tx pgx.Tx, batchID string) (*entity.BookUpsertCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertBooksFromStaging", ctx, tx, batchID)
+	ret0, _ := ret[0].(*entity.BookUpsertCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertBooksFromStaging indicates an expected call of UpsertBooksFromStaging.
+func (mr *MockImportRepositoryMockRecorder) UpsertBooksFromStaging(ctx, tx, batchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBooksFromStaging", reflect.TypeOf((*MockImportRepository)(nil).UpsertBooksFromStaging), ctx, tx, batchID)
+}
+
+// MockCategoryRepository is a mock of CategoryRepository interface.
+type MockCategoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryRepositoryMockRecorder
+}
+
+// MockCategoryRepositoryMockRecorder is the mock recorder for MockCategoryRepository.
+type MockCategoryRepositoryMockRecorder struct {
+	mock *MockCategoryRepository
+}
+
+// NewMockCategoryRepository creates a new mock instance.
+func NewMockCategoryRepository(ctrl *gomock.Controller) *MockCategoryRepository {
+	mock := &MockCategoryRepository{ctrl: ctrl}
+	mock.recorder = &MockCategoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryRepository) EXPECT() *MockCategoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCategory mocks base method.
+func (m *MockCategoryRepository) CreateCategory(ctx context.Context, arg entity.CreateCategoryParams) (*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", ctx, arg)
+	ret0, _ := ret[0].(*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockCategoryRepositoryMockRecorder) CreateCategory(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockCategoryRepository)(nil).CreateCategory), ctx, arg)
+}
+
+// FindBook mocks base method.
+func (m *MockCategoryRepository) FindBook(ctx context.Context, tx pgx.Tx, id int64) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBook", ctx, tx, id)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBook indicates an expected call of FindBook.
+func (mr *MockCategoryRepositoryMockRecorder) FindBook(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBook", reflect.TypeOf((*MockCategoryRepository)(nil).FindBook), ctx, tx, id)
+}
+
+// FindCategory mocks base method.
+func (m *MockCategoryRepository) FindCategory(ctx context.Context, id int64) (*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCategory", ctx, id)
+	ret0, _ := ret[0].(*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCategory indicates an expected call of FindCategory.
+func (mr *MockCategoryRepositoryMockRecorder) FindCategory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCategory", reflect.TypeOf((*MockCategoryRepository)(nil).FindCategory), ctx, id)
+}
+
+// GetBookCategories mocks base method.
+func (m *MockCategoryRepository) GetBookCategories(ctx context.Context, bookID int64) ([]*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookCategories", ctx, bookID)
+	ret0, _ := ret[0].([]*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookCategories indicates an expected call of GetBookCategories.
+func (mr *MockCategoryRepositoryMockRecorder) GetBookCategories(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCategories", reflect.TypeOf((*MockCategoryRepository)(nil).GetBookCategories), ctx, bookID)
+}
+
+// GetCategories mocks base method.
+func (m *MockCategoryRepository) GetCategories(ctx context.Context) ([]*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategories", ctx)
+	ret0, _ := ret[0].([]*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategories indicates an expected call of GetCategories.
+func (mr *MockCategoryRepositoryMockRecorder) GetCategories(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockCategoryRepository)(nil).GetCategories), ctx)
+}
+
+// SetBookCategories mocks base method.
+func (m *MockCategoryRepository) SetBookCategories(ctx context.Context, tx pgx.Tx, bookID int64, categoryIDs []int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookCategories", ctx, tx, bookID, categoryIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookCategories indicates an expected call of SetBookCategories.
+func (mr *MockCategoryRepositoryMockRecorder) SetBookCategories(ctx, tx, bookID, categoryIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookCategories", reflect.TypeOf((*MockCategoryRepository)(nil).SetBookCategories), ctx, tx, bookID, categoryIDs)
+}
+
+// MockSeriesRepository is a mock of SeriesRepository interface.
+type MockSeriesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesRepositoryMockRecorder
+}
+
+// MockSeriesRepositoryMockRecorder is the mock recorder for MockSeriesRepository.
+type MockSeriesRepositoryMockRecorder struct {
+	mock *MockSeriesRepository
+}
+
+// NewMockSeriesRepository creates a new mock instance.
+func NewMockSeriesRepository(ctrl *gomock.Controller) *MockSeriesRepository {
+	mock := &MockSeriesRepository{ctrl: ctrl}
+	mock.recorder = &MockSeriesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesRepository) EXPECT() *MockSeriesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSeries mocks base method.
+func (m *MockSeriesRepository) CreateSeries(ctx context.Context, arg entity.CreateSeriesParams) (*entity.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSeries", ctx, arg)
+	ret0, _ := ret[0].(*entity.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSeries indicates an expected call of CreateSeries.
+func (mr *MockSeriesRepositoryMockRecorder) CreateSeries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeries", reflect.TypeOf((*MockSeriesRepository)(nil).CreateSeries), ctx, arg)
+}
+
+// FindSeries mocks base method.
+func (m *MockSeriesRepository) FindSeries(ctx context.Context, id int64) (*entity.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSeries", ctx, id)
+	ret0, _ := ret[0].(*entity.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSeries indicates an expected call of FindSeries.
+func (mr *MockSeriesRepositoryMockRecorder) FindSeries(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSeries", reflect.TypeOf((*MockSeriesRepository)(nil).FindSeries), ctx, id)
+}
+
+// GetMyOrders mocks base method.
+func (m *MockSeriesRepository) GetMyOrders(ctx context.Context, arg entity.GetMyOrdersParams) ([]entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMyOrders", ctx, arg)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMyOrders indicates an expected call of GetMyOrders.
+func (mr *MockSeriesRepositoryMockRecorder) GetMyOrders(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyOrders", reflect.TypeOf((*MockSeriesRepository)(nil).GetMyOrders), ctx, arg)
+}
+
+// GetSeriesOfBooks mocks base method.
+func (m *MockSeriesRepository) GetSeriesOfBooks(ctx context.Context, bookIDs []int64) ([]*entity.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeriesOfBooks", ctx, bookIDs)
+	ret0, _ := ret[0].([]*entity.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeriesOfBooks indicates an expected call of GetSeriesOfBooks.
+func (mr *MockSeriesRepositoryMockRecorder) GetSeriesOfBooks(ctx, bookIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeriesOfBooks", reflect.TypeOf((*MockSeriesRepository)(nil).GetSeriesOfBooks), ctx, bookIDs)
+}
+
+// GetSeriesVolumes mocks base method.
+func (m *MockSeriesRepository) GetSeriesVolumes(ctx context.Context, seriesIDs []int64) ([]entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeriesVolumes", ctx, seriesIDs)
+	ret0, _ := ret[0].([]entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeriesVolumes indicates an expected call of GetSeriesVolumes.
+func (mr *MockSeriesRepositoryMockRecorder) GetSeriesVolumes(ctx, seriesIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeriesVolumes", reflect.TypeOf((*MockSeriesRepository)(nil).GetSeriesVolumes), ctx, seriesIDs)
+}
+
+// GetWorkIDsOfBooks mocks base method.
+func (m *MockSeriesRepository) GetWorkIDsOfBooks(ctx context.Context, bookIDs []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkIDsOfBooks", ctx, bookIDs)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkIDsOfBooks indicates an expected call of GetWorkIDsOfBooks.
+func (mr *MockSeriesRepositoryMockRecorder) GetWorkIDsOfBooks(ctx, bookIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkIDsOfBooks", reflect.TypeOf((*MockSeriesRepository)(nil).GetWorkIDsOfBooks), ctx, bookIDs)
+}
+
+// MockCoverRepository is a mock of CoverRepository interface.
+type MockCoverRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoverRepositoryMockRecorder
+}
+
+// MockCoverRepositoryMockRecorder is the mock recorder for MockCoverRepository.
+type MockCoverRepositoryMockRecorder struct {
+	mock *MockCoverRepository
+}
+
+// NewMockCoverRepository creates a new mock instance.
+func NewMockCoverRepository(ctrl *gomock.Controller) *MockCoverRepository {
+	mock := &MockCoverRepository{ctrl: ctrl}
+	mock.recorder = &MockCoverRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoverRepository) EXPECT() *MockCoverRepositoryMockRecorder {
+	return m.recorder
+}
+
+// SetBookCover mocks base method.
+func (m *MockCoverRepository) SetBookCover(ctx context.Context, id int64, coverKey string) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookCover", ctx, id, coverKey)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBookCover indicates an expected call of SetBookCover.
+func (mr *MockCoverRepositoryMockRecorder) SetBookCover(ctx, id, coverKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookCover", reflect.TypeOf((*MockCoverRepository)(nil).SetBookCover), ctx, id, coverKey)
+}
+
+// MockBlobStore is a mock of BlobStore interface.
+type MockBlobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStoreMockRecorder
+}
+
+// MockBlobStoreMockRecorder is the mock recorder for MockBlobStore.
+type MockBlobStoreMockRecorder struct {
+	mock *MockBlobStore
+}
+
+// NewMockBlobStore creates a new mock instance.
+func NewMockBlobStore(ctrl *gomock.Controller) *MockBlobStore {
+	mock := &MockBlobStore{ctrl: ctrl}
+	mock.recorder = &MockBlobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStore) EXPECT() *MockBlobStoreMockRecorder {
+	return m.recorder
+}
+
+// Put mocks base method.
+func (m *MockBlobStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, r, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockBlobStoreMockRecorder) Put(ctx, key, r, contentType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBlobStore)(nil).Put), ctx, key, r, contentType)
+}
+
+// MockPaymentProvider is a mock of PaymentProvider interface.
+type MockPaymentProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentProviderMockRecorder
+}
+
+// MockPaymentProviderMockRecorder is the mock recorder for MockPaymentProvider.
+type MockPaymentProviderMockRecorder struct {
+	mock *MockPaymentProvider
+}
+
+// NewMockPaymentProvider creates a new mock instance.
+func NewMockPaymentProvider(ctrl *gomock.Controller) *MockPaymentProvider {
+	mock := &MockPaymentProvider{ctrl: ctrl}
+	mock.recorder = &MockPaymentProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentProvider) EXPECT() *MockPaymentProviderMockRecorder {
+	return m.recorder
+}
+
+// Capture mocks base method.
+func (m *MockPaymentProvider) Capture(ctx context.Context, reference string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, reference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockPaymentProviderMockRecorder) Capture(ctx, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockPaymentProvider)(nil).Capture), ctx, reference)
+}
+
+// CreateIntent mocks base method.
+func (m *MockPaymentProvider) CreateIntent(ctx context.Context, params entity.PaymentIntentParams) (*entity.PaymentIntent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIntent", ctx, params)
+	ret0, _ := ret[0].(*entity.PaymentIntent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIntent indicates an expected call of CreateIntent.
+func (mr *MockPaymentProviderMockRecorder) CreateIntent(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIntent", reflect.TypeOf((*MockPaymentProvider)(nil).CreateIntent), ctx, params)
+}
+
+// Name mocks base method.
+func (m *MockPaymentProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockPaymentProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockPaymentProvider)(nil).Name))
+}
+
+// Refund mocks base method.
+func (m *MockPaymentProvider) Refund(ctx context.Context, reference, key string, amount int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, reference, key, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPaymentProviderMockRecorder) Refund(ctx, reference, key, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPaymentProvider)(nil).Refund), ctx, reference, key, amount)
+}
+
+// VerifyWebhook mocks base method.
+func (m *MockPaymentProvider) VerifyWebhook(payload []byte, signature string) (*entity.PaymentEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyWebhook", payload, signature)
+	ret0, _ := ret[0].(*entity.PaymentEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyWebhook indicates an expected call of VerifyWebhook.
+func (mr *MockPaymentProviderMockRecorder) VerifyWebhook(payload, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyWebhook", reflect.TypeOf((*MockPaymentProvider)(nil).VerifyWebhook), payload, signature)
+}
+
+// MockIdempotencyKeyRepository is a mock of IdempotencyKeyRepository interface.
+type MockIdempotencyKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyKeyRepositoryMockRecorder
+}
+
+// MockIdempotencyKeyRepositoryMockRecorder is the mock recorder for MockIdempotencyKeyRepository.
+type MockIdempotencyKeyRepositoryMockRecorder struct {
+	mock *MockIdempotencyKeyRepository
+}
+
+// NewMockIdempotencyKeyRepository creates a new mock instance.
+func NewMockIdempotencyKeyRepository(ctrl *gomock.Controller) *MockIdempotencyKeyRepository {
+	mock := &MockIdempotencyKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyKeyRepository) EXPECT() *MockIdempotencyKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockIdempotencyKeyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, limit int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", ctx, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockIdempotencyKeyRepositoryMockRecorder) DeleteExpiredIdempotencyKeys(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockIdempotencyKeyRepository)(nil).DeleteExpiredIdempotencyKeys), ctx, limit)
+}
+
+// MockExportRepository is a mock of ExportRepository interface.
+type MockExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportRepositoryMockRecorder
+}
+
+// MockExportRepositoryMockRecorder is the mock recorder for MockExportRepository.
+type MockExportRepositoryMockRecorder struct {
+	mock *MockExportRepository
+}
+
+// NewMockExportRepository creates a new mock instance.
+func NewMockExportRepository(ctrl *gomock.Controller) *MockExportRepository {
+	mock := &MockExportRepository{ctrl: ctrl}
+	mock.recorder = &MockExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportRepository) EXPECT() *MockExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExportBooks mocks base method.
+func (m *MockExportRepository) ExportBooks(ctx context.Context, arg entity.ExportBooksParams) ([]entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, arg)
+	ret0, _ := ret[0].([]entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockExportRepositoryMockRecorder) ExportBooks(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockExportRepository)(nil).ExportBooks), ctx, arg)
+}